@@ -0,0 +1,324 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const defaultOHLCCandles = 100
+const maxOHLCCandles = 1000
+
+const (
+	// the gains are not computed for the accounts with more confirmed transactions, the scan of the history would be too slow
+	maxFiatHistoryTxs = 10000
+	// maximum number of cached fiat histories of the accounts
+	fiatHistoryCacheSize = 1000
+)
+
+// fiatConverter converts amounts to a fiat currency using the current rate and the stored rates at the time of transactions
+type fiatConverter struct {
+	w           *Worker
+	currency    string
+	currentRate float64
+	divisor     float64
+	rates       map[int64]float64
+}
+
+func (w *Worker) newFiatConverter(currency string) (*fiatConverter, error) {
+	currency = strings.ToLower(currency)
+	ticker, err := w.db.FiatRatesFindLastTicker()
+	if err != nil {
+		return nil, err
+	}
+	if ticker == nil {
+		return nil, NewAPIError("No fiat rates available", true)
+	}
+	rate, found := ticker.Rates[currency]
+	if !found {
+		return nil, NewAPIError(fmt.Sprintf("Currency '%v' is not available", currency), true)
+	}
+	return &fiatConverter{
+		w:           w,
+		currency:    currency,
+		currentRate: rate,
+		divisor:     math.Pow10(w.chainParser.AmountDecimals()),
+		rates:       make(map[int64]float64),
+	}, nil
+}
+
+// rateAt returns the rate of the first stored ticker not older than the unix time t, 0 if there is no such rate
+func (fc *fiatConverter) rateAt(t int64) float64 {
+	if t <= 0 {
+		return 0
+	}
+	if rate, found := fc.rates[t]; found {
+		return rate
+	}
+	var rate float64
+	tm := time.Unix(t, 0)
+	ticker, err := fc.w.db.FiatRatesFindTicker(&tm)
+	if err != nil {
+		glog.Errorf("Error finding ticker by date %v. Error: %v", tm, err)
+	} else if ticker != nil {
+		rate = ticker.Rates[fc.currency]
+	}
+	fc.rates[t] = rate
+	return rate
+}
+
+// amount returns the amount in coins
+func (fc *fiatConverter) amount(a *Amount) float64 {
+	if a == nil {
+		return 0
+	}
+	f, _ := new(big.Float).SetInt((*big.Int)(a)).Float64()
+	return f / fc.divisor
+}
+
+func (fc *fiatConverter) value(a *Amount, rate float64) *FiatValue {
+	v := fc.amount(a)
+	fv := &FiatValue{CurrentValue: v * fc.currentRate}
+	if rate > 0 {
+		historical := v * rate
+		fv.Value = &historical
+	}
+	return fv
+}
+
+func (fc *fiatConverter) setTxFiat(tx *Tx) {
+	rate := fc.rateAt(tx.Blocktime)
+	tf := &TxFiat{
+		FiatRate: FiatRate{
+			Currency:    fc.currency,
+			Rate:        rate,
+			CurrentRate: fc.currentRate,
+		},
+		Value: *fc.value(tx.ValueOutSat, rate),
+	}
+	if tx.ValueInSat != nil {
+		tf.ValueIn = fc.value(tx.ValueInSat, rate)
+	}
+	if tx.FeesSat != nil {
+		tf.Fees = fc.value(tx.FeesSat, rate)
+	}
+	for i := range tx.Vin {
+		if tx.Vin[i].ValueSat != nil {
+			tx.Vin[i].Fiat = fc.value(tx.Vin[i].ValueSat, rate)
+		}
+	}
+	for i := range tx.Vout {
+		if tx.Vout[i].ValueSat != nil {
+			tx.Vout[i].Fiat = fc.value(tx.Vout[i].ValueSat, rate)
+		}
+	}
+	tx.Fiat = tf
+}
+
+// fiatHistory is the result of the scan of the confirmed history of an account in a fiat currency
+// The figures are unknown if the rate at the time of some transaction is not stored
+type fiatHistory struct {
+	height        uint32
+	known         bool
+	totalReceived float64
+	totalSent     float64
+	costBasis     float64
+	realized      float64
+	holding       float64
+}
+
+// history computes the fiat figures of the account from its balance history, aggregated per transaction
+func (fc *fiatConverter) history(bhs BalanceHistories) *fiatHistory {
+	fh := &fiatHistory{}
+	for i := range bhs {
+		bh := &bhs[i]
+		rate := bh.FiatRates[fc.currency]
+		if rate <= 0 {
+			// the rate at the time of the transaction is not stored, using another rate would distort the gains
+			return fh
+		}
+		received := fc.amount(bh.ReceivedSat)
+		sent := fc.amount(bh.SentSat)
+		fh.totalReceived += received * rate
+		fh.totalSent += sent * rate
+		net := received - sent
+		if net > 0 {
+			fh.holding += net
+			fh.costBasis += net * rate
+		} else if net < 0 && fh.holding > 0 {
+			disposed := math.Min(-net, fh.holding)
+			averageCost := fh.costBasis / fh.holding
+			fh.realized += disposed * (rate - averageCost)
+			fh.costBasis -= disposed * averageCost
+			fh.holding -= disposed
+		}
+	}
+	fh.known = true
+	return fh
+}
+
+// setAccountFiat sets the fiat values of the account, the figures from the history are omitted if fh is nil or unknown
+func (fc *fiatConverter) setAccountFiat(a *Address, fh *fiatHistory) {
+	af := &AccountFiat{
+		Currency:           fc.currency,
+		Rate:               fc.currentRate,
+		Balance:            fc.amount(a.BalanceSat) * fc.currentRate,
+		UnconfirmedBalance: fc.amount(a.UnconfirmedBalanceSat) * fc.currentRate,
+	}
+	if fh != nil && fh.known {
+		// copies, the history can be shared by the cache
+		totalReceived, totalSent, costBasis, realized := fh.totalReceived, fh.totalSent, fh.costBasis, fh.realized
+		unrealized := fh.holding*fc.currentRate - fh.costBasis
+		af.TotalReceived = &totalReceived
+		af.TotalSent = &totalSent
+		af.CostBasis = &costBasis
+		af.Realized = &realized
+		af.Unrealized = &unrealized
+	}
+	for _, tx := range a.Transactions {
+		fc.setTxFiat(tx)
+	}
+	a.Fiat = af
+}
+
+// fiatHistoryCache keeps the fiat histories of the accounts computed at the best height
+type fiatHistoryCache struct {
+	lock    sync.Mutex
+	entries map[string]*fiatHistory
+}
+
+func newFiatHistoryCache() *fiatHistoryCache {
+	return &fiatHistoryCache{entries: make(map[string]*fiatHistory)}
+}
+
+func (c *fiatHistoryCache) get(key string, height uint32) *fiatHistory {
+	if c == nil {
+		return nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if fh, found := c.entries[key]; found && fh.height == height {
+		return fh
+	}
+	return nil
+}
+
+func (c *fiatHistoryCache) set(key string, fh *fiatHistory) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.entries) >= fiatHistoryCacheSize {
+		c.entries = make(map[string]*fiatHistory)
+	}
+	c.entries[key] = fh
+}
+
+// accountFiatHistory returns the fiat history of the account from the cache or computed from the balance history
+// returned by load, nil if the account has too many transactions to scan its history
+func (w *Worker) accountFiatHistory(fc *fiatConverter, key string, txs int, load func() (BalanceHistories, error)) (*fiatHistory, error) {
+	if txs > maxFiatHistoryTxs {
+		return nil, nil
+	}
+	key = fc.currency + ":" + key
+	height, _, err := w.db.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	if fh := w.fiatHistories.get(key, height); fh != nil {
+		return fh, nil
+	}
+	bhs, err := load()
+	if err != nil {
+		return nil, err
+	}
+	fh := fc.history(bhs)
+	fh.height = height
+	w.fiatHistories.set(key, fh)
+	return fh, nil
+}
+
+// SetTxFiat sets values of the transaction in the fiat currency
+func (w *Worker) SetTxFiat(tx *Tx, currency string) error {
+	fc, err := w.newFiatConverter(currency)
+	if err != nil {
+		return err
+	}
+	fc.setTxFiat(tx)
+	return nil
+}
+
+// SetBlockFiat sets the rate at the time of the block and values of the returned transactions of the block in the fiat currency
+func (w *Worker) SetBlockFiat(b *Block, currency string) error {
+	fc, err := w.newFiatConverter(currency)
+	if err != nil {
+		return err
+	}
+	b.Fiat = &FiatRate{
+		Currency:    fc.currency,
+		Rate:        fc.rateAt(b.Time),
+		CurrentRate: fc.currentRate,
+	}
+	for _, tx := range b.Transactions {
+		fc.setTxFiat(tx)
+	}
+	return nil
+}
+
+// SetUtxosFiat sets values of the utxos in the fiat currency
+func (w *Worker) SetUtxosFiat(utxos Utxos, currency string) error {
+	fc, err := w.newFiatConverter(currency)
+	if err != nil {
+		return err
+	}
+	for i := range utxos {
+		u := &utxos[i]
+		var rate float64
+		if u.Height > 0 {
+			rate = fc.rateAt(int64(w.is.GetBlockTime(uint32(u.Height))))
+		}
+		u.Fiat = fc.value(u.AmountSat, rate)
+	}
+	return nil
+}
+
+// SetAddressFiat sets values of the address and its returned transactions in the fiat currency,
+// including the realized and unrealized gains computed from the whole confirmed history of the address
+func (w *Worker) SetAddressFiat(a *Address, currency string) error {
+	fc, err := w.newFiatConverter(currency)
+	if err != nil {
+		return err
+	}
+	fh, err := w.accountFiatHistory(fc, a.AddrStr, a.Txs, func() (BalanceHistories, error) {
+		return w.GetBalanceHistory(a.AddrStr, 0, 0, []string{fc.currency}, 1)
+	})
+	if err != nil {
+		return err
+	}
+	fc.setAccountFiat(a, fh)
+	return nil
+}
+
+// SetXpubFiat sets values of the xpub and its returned transactions in the fiat currency,
+// including the realized and unrealized gains computed from the whole confirmed history of the xpub
+func (w *Worker) SetXpubFiat(a *Address, xpub string, gap int, currency string) error {
+	fc, err := w.newFiatConverter(currency)
+	if err != nil {
+		return err
+	}
+	fh, err := w.accountFiatHistory(fc, xpub+":"+strconv.Itoa(gap), a.Txs, func() (BalanceHistories, error) {
+		return w.GetXpubBalanceHistory(xpub, 0, 0, []string{fc.currency}, gap, 1)
+	})
+	if err != nil {
+		return err
+	}
+	fc.setAccountFiat(a, fh)
+	return nil
+}
@@ -0,0 +1,168 @@
+// +build unittest
+
+package api
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func Test_fiatConverter_history(t *testing.T) {
+	bh := func(received, sent int64, rate float64) BalanceHistory {
+		h := BalanceHistory{
+			ReceivedSat: (*Amount)(big.NewInt(received)),
+			SentSat:     (*Amount)(big.NewInt(sent)),
+		}
+		if rate > 0 {
+			h.FiatRates = map[string]float64{"usd": rate}
+		}
+		return h
+	}
+	tests := []struct {
+		name          string
+		bhs           BalanceHistories
+		balance       int64
+		known         bool
+		totalReceived float64
+		totalSent     float64
+		costBasis     float64
+		realized      float64
+		unrealized    float64
+	}{
+		{
+			name:  "no transactions",
+			known: true,
+		},
+		{
+			name: "buys",
+			bhs: BalanceHistories{
+				bh(1e8, 0, 100),
+				bh(3e8, 0, 200),
+			},
+			balance:       4e8,
+			known:         true,
+			totalReceived: 700,
+			costBasis:     700,
+			unrealized:    4*300 - 700,
+		},
+		{
+			name: "buy and sell all",
+			bhs: BalanceHistories{
+				bh(2e8, 0, 100),
+				bh(0, 2e8, 150),
+			},
+			known:         true,
+			totalReceived: 200,
+			totalSent:     300,
+			realized:      100,
+		},
+		{
+			name: "partial spends at the average cost",
+			bhs: BalanceHistories{
+				bh(1e8, 0, 100),
+				bh(1e8, 0, 300),
+				bh(0, 5e7, 400),
+				bh(0, 1e8, 100),
+			},
+			balance:       5e7,
+			known:         true,
+			totalReceived: 400,
+			totalSent:     300,
+			costBasis:     100,
+			realized:      0.5*(400-200) + 1*(100-200),
+			unrealized:    0.5*300 - 100,
+		},
+		{
+			name: "spend with change counts the net amount",
+			bhs: BalanceHistories{
+				bh(2e8, 0, 100),
+				bh(5e7, 1e8, 200),
+			},
+			balance:       15e7,
+			known:         true,
+			totalReceived: 200 + 100,
+			totalSent:     200,
+			costBasis:     150,
+			realized:      50,
+			unrealized:    1.5*300 - 150,
+		},
+		{
+			name: "sent more than acquired",
+			bhs: BalanceHistories{
+				bh(1e8, 0, 100),
+				bh(0, 2e8, 200),
+			},
+			known:         true,
+			totalReceived: 100,
+			totalSent:     400,
+			realized:      100,
+		},
+		{
+			name: "missing rate",
+			bhs: BalanceHistories{
+				bh(1e8, 0, 100),
+				bh(0, 5e7, 0),
+			},
+			balance: 5e7,
+		},
+	}
+	fc := &fiatConverter{currency: "usd", currentRate: 300, divisor: 1e8}
+	equal := func(a, b float64) bool {
+		return math.Abs(a-b) < 1e-9
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fh := fc.history(tt.bhs)
+			if fh.known != tt.known {
+				t.Fatalf("known = %v, want %v", fh.known, tt.known)
+			}
+			a := &Address{BalanceSat: (*Amount)(big.NewInt(tt.balance))}
+			fc.setAccountFiat(a, fh)
+			af := a.Fiat
+			if !equal(af.Balance, float64(tt.balance)/1e8*300) {
+				t.Errorf("Balance = %v, want %v", af.Balance, float64(tt.balance)/1e8*300)
+			}
+			if !tt.known {
+				if af.TotalReceived != nil || af.TotalSent != nil || af.CostBasis != nil || af.Realized != nil || af.Unrealized != nil {
+					t.Errorf("history figures set although a rate is missing: %+v", af)
+				}
+				return
+			}
+			for _, f := range []struct {
+				name string
+				got  *float64
+				want float64
+			}{
+				{"TotalReceived", af.TotalReceived, tt.totalReceived},
+				{"TotalSent", af.TotalSent, tt.totalSent},
+				{"CostBasis", af.CostBasis, tt.costBasis},
+				{"Realized", af.Realized, tt.realized},
+				{"Unrealized", af.Unrealized, tt.unrealized},
+			} {
+				if f.got == nil {
+					t.Errorf("%s is nil, want %v", f.name, f.want)
+				} else if !equal(*f.got, f.want) {
+					t.Errorf("%s = %v, want %v", f.name, *f.got, f.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_fiatHistoryCache(t *testing.T) {
+	c := newFiatHistoryCache()
+	fh := &fiatHistory{height: 10, known: true}
+	c.set("usd:addr", fh)
+	if got := c.get("usd:addr", 10); got != fh {
+		t.Errorf("get at the same height = %v, want %v", got, fh)
+	}
+	if got := c.get("usd:addr", 11); got != nil {
+		t.Errorf("get at a new height = %v, want nil", got)
+	}
+	var nilCache *fiatHistoryCache
+	nilCache.set("usd:addr", fh)
+	if got := nilCache.get("usd:addr", 10); got != nil {
+		t.Errorf("nil cache get = %v, want nil", got)
+	}
+}
@@ -0,0 +1,374 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+)
+
+// estimated virtual sizes of transaction parts used by the coin selection
+const (
+	psbtTxOverheadVsize       = 11
+	psbtInputP2pkhVsize       = 148
+	psbtInputP2shP2wpkhVsize  = 91
+	psbtInputP2wpkhVsize      = 68
+	psbtOutputOverheadVsize   = 9
+	psbtMaxOutputs            = 1000
+	psbtSequenceFinal         = 0xffffffff
+	psbtSequenceLockTime      = 0xffffffff - 1
+	psbtSequenceReplaceable   = 0xffffffff - 2
+	psbtHardenedKeyStart      = 0x80000000
+	psbtDescriptorFingerprint = 8
+)
+
+// descriptor script types and the address descriptors (output scripts) they produce
+var psbtDescriptorScripts = map[string]func(ad bchain.AddressDescriptor) bool{
+	"pkh": func(ad bchain.AddressDescriptor) bool {
+		return len(ad) == 25 && ad[0] == 0x76 && ad[1] == 0xa9
+	},
+	"sh(wpkh": func(ad bchain.AddressDescriptor) bool {
+		return len(ad) == 23 && ad[0] == 0xa9 && ad[22] == 0x87
+	},
+	"wpkh": func(ad bchain.AddressDescriptor) bool {
+		return len(ad) == 22 && ad[0] == 0x00 && ad[1] == 0x14
+	},
+}
+
+type psbtUtxo struct {
+	Utxo
+	addrDesc bchain.AddressDescriptor
+	value    int64
+	change   uint32
+	index    uint32
+}
+
+// parseDerivationPath parses path in format 84'/0'/0' (h can be used instead of ')
+func parseDerivationPath(p string) ([]uint32, error) {
+	var path []uint32
+	for _, s := range strings.Split(p, "/") {
+		if s == "" || s == "m" {
+			continue
+		}
+		var h uint32
+		if strings.HasSuffix(s, "'") || strings.HasSuffix(s, "h") {
+			h = psbtHardenedKeyStart
+			s = s[:len(s)-1]
+		}
+		n, err := strconv.ParseUint(s, 10, 31)
+		if err != nil {
+			return nil, errors.Errorf("Invalid derivation path %v", p)
+		}
+		path = append(path, uint32(n)+h)
+	}
+	return path, nil
+}
+
+func formatDerivationPath(path []uint32) string {
+	var b strings.Builder
+	b.WriteString("m")
+	for _, n := range path {
+		if n >= psbtHardenedKeyStart {
+			fmt.Fprintf(&b, "/%d'", n-psbtHardenedKeyStart)
+		} else {
+			fmt.Fprintf(&b, "/%d", n)
+		}
+	}
+	return b.String()
+}
+
+// parseXpubDescriptor returns the xpub, the key origin and the script type from an output descriptor
+// in format wpkh([d34db33f/84'/0'/0']xpub.../<0;1>/*)#checksum, a plain xpub is returned without origin and script type
+// Both receiving and change addresses of the xpub are always used, regardless of the derivation suffix of the key
+func parseXpubDescriptor(descriptor string) (string, *bchain.XpubKeyOrigin, string, error) {
+	d := strings.TrimSpace(descriptor)
+	if i := strings.IndexByte(d, '#'); i >= 0 {
+		d = d[:i]
+	}
+	var scriptType string
+	for {
+		i := strings.IndexByte(d, '(')
+		if i < 0 {
+			break
+		}
+		if !strings.HasSuffix(d, ")") {
+			return "", nil, "", errors.New("Invalid descriptor")
+		}
+		if scriptType != "" {
+			scriptType += "("
+		}
+		scriptType += d[:i]
+		d = d[i+1 : len(d)-1]
+	}
+	if scriptType != "" && psbtDescriptorScripts[scriptType] == nil {
+		return "", nil, "", errors.Errorf("Unsupported descriptor type %v", scriptType)
+	}
+	var origin *bchain.XpubKeyOrigin
+	if strings.HasPrefix(d, "[") {
+		i := strings.IndexByte(d, ']')
+		if i < 0 {
+			return "", nil, "", errors.New("Invalid key origin")
+		}
+		o := d[1:i]
+		d = d[i+1:]
+		var p string
+		if i = strings.IndexByte(o, '/'); i >= 0 {
+			p = o[i+1:]
+			o = o[:i]
+		}
+		fp, err := hex.DecodeString(o)
+		if err != nil || len(o) != psbtDescriptorFingerprint {
+			return "", nil, "", errors.Errorf("Invalid key fingerprint %v", o)
+		}
+		path, err := parseDerivationPath(p)
+		if err != nil {
+			return "", nil, "", err
+		}
+		origin = &bchain.XpubKeyOrigin{Fingerprint: binary.BigEndian.Uint32(fp), Path: path}
+	}
+	if i := strings.IndexByte(d, '/'); i >= 0 {
+		d = d[:i]
+	}
+	return d, origin, scriptType, nil
+}
+
+// psbtInputVsize estimates the virtual size of an input spending the output script
+func psbtInputVsize(ad bchain.AddressDescriptor) int {
+	if psbtDescriptorScripts["wpkh"](ad) {
+		return psbtInputP2wpkhVsize
+	}
+	if psbtDescriptorScripts["sh(wpkh"](ad) {
+		return psbtInputP2shP2wpkhVsize
+	}
+	return psbtInputP2pkhVsize
+}
+
+// psbtChangeIndex returns the index of the first change address of the xpub without confirmed and mempool transactions
+func (w *Worker) psbtChangeIndex(xpub string, data *xpubData) (int, error) {
+	for i := range data.changeAddresses {
+		ad := &data.changeAddresses[i]
+		if ad.balance != nil {
+			continue
+		}
+		mtxs, err := w.mempool.GetAddrDescTransactions(ad.addrDesc)
+		if err != nil {
+			return 0, err
+		}
+		if len(mtxs) == 0 {
+			return i, nil
+		}
+	}
+	return 0, errors.Errorf("No unused change address for xpub %v", xpub)
+}
+
+// getPsbtUtxos returns spendable utxos of the xpub, sorted by value from the largest
+func (w *Worker) getPsbtUtxos(data *xpubData, onlyConfirmed bool) ([]psbtUtxo, error) {
+	r := make([]psbtUtxo, 0, 8)
+	for ci, da := range [][]xpubAddress{data.addresses, data.changeAddresses} {
+		for i := range da {
+			ad := &da[i]
+			onlyMempool := false
+			if ad.balance == nil {
+				if onlyConfirmed {
+					continue
+				}
+				onlyMempool = true
+			}
+			utxos, err := w.getAddrDescUtxo(ad.addrDesc, ad.balance, onlyConfirmed, onlyMempool)
+			if err != nil {
+				return nil, err
+			}
+			if len(utxos) > 0 {
+				t := w.tokenFromXpubAddress(data, ad, ci, i, AccountDetailsTokens)
+				for j := range utxos {
+					u := &utxos[j]
+					if u.Coinbase && u.Confirmations < w.chainParser.MinimumCoinbaseConfirmations() {
+						continue
+					}
+					u.Address = t.Name
+					u.Path = t.Path
+					r = append(r, psbtUtxo{
+						Utxo:     *u,
+						addrDesc: ad.addrDesc,
+						value:    u.AmountSat.AsInt64(),
+						change:   uint32(ci),
+						index:    uint32(i),
+					})
+				}
+			}
+		}
+	}
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].value > r[j].value
+	})
+	return r, nil
+}
+
+// psbtSelection is the result of the coin selection, vsize is the estimated virtual size of the whole transaction
+type psbtSelection struct {
+	selected []psbtUtxo
+	vsize    int
+	fee      int64
+	change   int64
+}
+
+// selectPsbtUtxos selects the utxos in the given order until they fund the target and the fee for the estimated vsize,
+// vsize is the size of the transaction without inputs, the change output is added only if it is not below changeDust
+func selectPsbtUtxos(utxos []psbtUtxo, target int64, vsize int, feeRate float64, changeVsize int, changeDust int64) (*psbtSelection, error) {
+	var sum int64
+	for i := range utxos {
+		sum += utxos[i].value
+		vsize += psbtInputVsize(utxos[i].addrDesc)
+		fee := int64(math.Ceil(float64(vsize) * feeRate))
+		if sum < target+fee {
+			continue
+		}
+		s := &psbtSelection{selected: utxos[:i+1], vsize: vsize}
+		change := sum - target - int64(math.Ceil(float64(vsize+changeVsize)*feeRate))
+		if change > 0 && change >= changeDust {
+			s.vsize += changeVsize
+			s.change = change
+		}
+		s.fee = sum - target - s.change
+		return s, nil
+	}
+	return nil, NewAPIError("Insufficient funds", true)
+}
+
+// CreatePsbt selects utxos of the xpub to fund the outputs and returns unsigned transaction in BIP174 format
+// The utxos are selected from the largest, change smaller than the dust limit is added to the fee
+func (w *Worker) CreatePsbt(req *PsbtRequest) (*Psbt, error) {
+	w, span := w.startSpan("CreatePsbt")
+	defer span.End()
+	start := time.Now()
+	if w.chainType != bchain.ChainBitcoinType {
+		return nil, ErrUnsupportedXpub
+	}
+	xpub, origin, scriptType, err := parseXpubDescriptor(req.Xpub)
+	if err != nil {
+		return nil, NewAPIError(err.Error(), true)
+	}
+	if len(req.Outputs) == 0 || len(req.Outputs) > psbtMaxOutputs {
+		return nil, NewAPIError("Invalid number of outputs", true)
+	}
+	var maxFeeRate big.Int
+	maxFeeRate.Exp(big.NewInt(10), big.NewInt(int64(w.chainParser.AmountDecimals()-1)), nil)
+	// the same limit as the default maxfeerate of bitcoind, 0.1 coin per kB
+	if req.FeeRate <= 0 || req.FeeRate*1000 > float64(maxFeeRate.Int64()) {
+		return nil, NewAPIError("Invalid fee rate", true)
+	}
+	ads, err := w.chainParser.DeriveAddressDescriptors(xpub, 0, []uint32{0})
+	if err != nil {
+		return nil, ErrUnsupportedXpub
+	}
+	if scriptType != "" && !psbtDescriptorScripts[scriptType](ads[0]) {
+		return nil, NewAPIError("Descriptor type "+scriptType+" does not match the xpub version", true)
+	}
+	outputs := make([]bchain.PsbtOutput, len(req.Outputs), len(req.Outputs)+1)
+	var target int64
+	vsize := psbtTxOverheadVsize
+	for i := range req.Outputs {
+		o := &outputs[i]
+		o.AddrDesc, err = w.chainParser.GetAddrDescFromAddress(req.Outputs[i].Address)
+		if err != nil {
+			return nil, NewAPIError(fmt.Sprintf("Output %d: invalid address %v", i, req.Outputs[i].Address), true)
+		}
+		o.ValueSat = req.Outputs[i].AmountSat.AsBigInt()
+		if !o.ValueSat.IsInt64() || o.ValueSat.Sign() <= 0 || o.ValueSat.Cmp(w.dustLimit(o.AddrDesc)) < 0 {
+			return nil, NewAPIError(fmt.Sprintf("Output %d: invalid value or value below the dust limit", i), true)
+		}
+		target += o.ValueSat.Int64()
+		vsize += psbtOutputOverheadVsize + len(o.AddrDesc)
+	}
+	data, _, err := w.getXpubData(xpub, 0, 1, AccountDetailsBasic, &AddressFilter{
+		Vout:          AddressFilterVoutOff,
+		OnlyConfirmed: req.OnlyConfirmed,
+	}, req.Gap)
+	if err != nil {
+		return nil, err
+	}
+	utxos, err := w.getPsbtUtxos(data, req.OnlyConfirmed)
+	if err != nil {
+		return nil, err
+	}
+	changeIndex, err := w.psbtChangeIndex(xpub, data)
+	if err != nil {
+		return nil, err
+	}
+	changeAddrDesc := data.changeAddresses[changeIndex].addrDesc
+	sel, err := selectPsbtUtxos(utxos, target, vsize, req.FeeRate, psbtOutputOverheadVsize+len(changeAddrDesc), w.dustLimit(changeAddrDesc).Int64())
+	if err != nil {
+		return nil, err
+	}
+	selected := sel.selected
+	rv := Psbt{
+		Vsize:   sel.vsize,
+		FeesSat: (*Amount)(big.NewInt(sel.fee)),
+		Inputs:  make(Utxos, len(selected)),
+	}
+	if sel.change > 0 {
+		outputs = append(outputs, bchain.PsbtOutput{
+			ValueSat: *big.NewInt(sel.change),
+			AddrDesc: changeAddrDesc,
+			IsChange: true,
+			Change:   1,
+			Index:    uint32(changeIndex),
+		})
+		t := w.tokenFromXpubAddress(data, &data.changeAddresses[changeIndex], 1, changeIndex, AccountDetailsBasic)
+		rv.ChangeAddress = t.Name
+		rv.ChangePath = t.Path
+		rv.ChangeSat = (*Amount)(big.NewInt(sel.change))
+	}
+	sequence := uint32(psbtSequenceFinal)
+	if req.Rbf {
+		sequence = psbtSequenceReplaceable
+	} else if req.LockTime > 0 {
+		sequence = psbtSequenceLockTime
+	}
+	inputs := make([]bchain.PsbtInput, len(selected))
+	for i := range selected {
+		u := &selected[i]
+		tx, _, err := w.txCache.GetTransaction(u.Txid)
+		if err != nil {
+			return nil, errors.Annotatef(err, "GetTransaction %v", u.Txid)
+		}
+		prevTx, err := hex.DecodeString(tx.Hex)
+		if err != nil {
+			return nil, errors.Annotatef(err, "Transaction %v", u.Txid)
+		}
+		inputs[i] = bchain.PsbtInput{
+			Txid:     u.Txid,
+			Vout:     uint32(u.Vout),
+			Sequence: sequence,
+			ValueSat: *big.NewInt(u.value),
+			AddrDesc: u.addrDesc,
+			PrevTx:   prevTx,
+			Change:   u.change,
+			Index:    u.index,
+		}
+		rv.Inputs[i] = u.Utxo
+		if origin != nil {
+			rv.Inputs[i].Path = fmt.Sprintf("%s/%d/%d", formatDerivationPath(origin.Path), u.change, u.index)
+		}
+	}
+	if origin != nil && rv.ChangePath != "" {
+		rv.ChangePath = fmt.Sprintf("%s/%d/%d", formatDerivationPath(origin.Path), 1, changeIndex)
+	}
+	b, err := w.chainParser.CreatePsbt(xpub, origin, inputs, outputs, req.LockTime)
+	if err != nil {
+		return nil, err
+	}
+	rv.Psbt = base64.StdEncoding.EncodeToString(b)
+	glog.Info("CreatePsbt ", xpub[:16], ", ", len(inputs), " inputs, ", len(outputs), " outputs, finished in ", time.Since(start))
+	return &rv, nil
+}
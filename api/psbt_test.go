@@ -0,0 +1,332 @@
+// +build unittest
+
+package api
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+	"github.com/scryptachain/blockbook-scrypta/db"
+)
+
+var (
+	testP2pkh      = bchain.AddressDescriptor(append(append([]byte{0x76, 0xa9, 0x14}, make([]byte, 20)...), 0x88, 0xac))
+	testP2shP2wpkh = bchain.AddressDescriptor(append(append([]byte{0xa9, 0x14}, make([]byte, 20)...), 0x87))
+	testP2wpkh     = bchain.AddressDescriptor(append([]byte{0x00, 0x14}, make([]byte, 20)...))
+)
+
+func Test_parseXpubDescriptor(t *testing.T) {
+	const xpub = "tpubDDsBUCwnzsg4rpAmNbJXn7zUabWUbhGb3BkyGvGHGm8HWxeg5r6DTLmsPNMhAFzgzCQrqfrVXQvDqtuemdoBvJ2qLpShDhHSpkPCSQhXkuh"
+	tests := []struct {
+		name       string
+		descriptor string
+		origin     *bchain.XpubKeyOrigin
+		scriptType string
+		wantErr    string
+	}{
+		{
+			name:       "plain xpub",
+			descriptor: xpub,
+		},
+		{
+			name:       "pkh",
+			descriptor: "pkh([d34db33f/44'/1'/0']" + xpub + "/<0;1>/*)",
+			origin:     &bchain.XpubKeyOrigin{Fingerprint: 0xd34db33f, Path: []uint32{44 + psbtHardenedKeyStart, 1 + psbtHardenedKeyStart, psbtHardenedKeyStart}},
+			scriptType: "pkh",
+		},
+		{
+			name:       "sh(wpkh) with checksum",
+			descriptor: "sh(wpkh([d34db33f/49h/1h/0h]" + xpub + "/0/*))#8r3gdmy2",
+			origin:     &bchain.XpubKeyOrigin{Fingerprint: 0xd34db33f, Path: []uint32{49 + psbtHardenedKeyStart, 1 + psbtHardenedKeyStart, psbtHardenedKeyStart}},
+			scriptType: "sh(wpkh",
+		},
+		{
+			name:       "wpkh without origin",
+			descriptor: " wpkh(" + xpub + ") ",
+			scriptType: "wpkh",
+		},
+		{
+			name:       "wpkh with origin without path",
+			descriptor: "wpkh([00000001]" + xpub + "/1/*)",
+			origin:     &bchain.XpubKeyOrigin{Fingerprint: 1},
+			scriptType: "wpkh",
+		},
+		{
+			name:       "unsupported type",
+			descriptor: "tr(" + xpub + ")",
+			wantErr:    "Unsupported descriptor type tr",
+		},
+		{
+			name:       "missing parenthesis",
+			descriptor: "wpkh(" + xpub,
+			wantErr:    "Invalid descriptor",
+		},
+		{
+			name:       "unterminated origin",
+			descriptor: "wpkh([d34db33f/84'" + xpub + ")",
+			wantErr:    "Invalid key origin",
+		},
+		{
+			name:       "invalid fingerprint",
+			descriptor: "wpkh([d34db3/84'/1'/0']" + xpub + ")",
+			wantErr:    "Invalid key fingerprint d34db3",
+		},
+		{
+			name:       "invalid path",
+			descriptor: "wpkh([d34db33f/84'/x/0']" + xpub + ")",
+			wantErr:    "Invalid derivation path 84'/x/0'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotXpub, origin, scriptType, err := parseXpubDescriptor(tt.descriptor)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("parseXpubDescriptor() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseXpubDescriptor() error = %v", err)
+			}
+			if gotXpub != xpub {
+				t.Errorf("parseXpubDescriptor() xpub = %v, want %v", gotXpub, xpub)
+			}
+			if !reflect.DeepEqual(origin, tt.origin) {
+				t.Errorf("parseXpubDescriptor() origin = %+v, want %+v", origin, tt.origin)
+			}
+			if scriptType != tt.scriptType {
+				t.Errorf("parseXpubDescriptor() scriptType = %v, want %v", scriptType, tt.scriptType)
+			}
+		})
+	}
+}
+
+func Test_psbtDescriptorScripts(t *testing.T) {
+	tests := []struct {
+		scriptType string
+		addrDesc   bchain.AddressDescriptor
+		vsize      int
+	}{
+		{"pkh", testP2pkh, psbtInputP2pkhVsize},
+		{"sh(wpkh", testP2shP2wpkh, psbtInputP2shP2wpkhVsize},
+		{"wpkh", testP2wpkh, psbtInputP2wpkhVsize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scriptType, func(t *testing.T) {
+			for _, other := range tests {
+				got := psbtDescriptorScripts[tt.scriptType](other.addrDesc)
+				if want := other.scriptType == tt.scriptType; got != want {
+					t.Errorf("%v matches %v = %v, want %v", tt.scriptType, other.scriptType, got, want)
+				}
+			}
+			if got := psbtInputVsize(tt.addrDesc); got != tt.vsize {
+				t.Errorf("psbtInputVsize() = %v, want %v", got, tt.vsize)
+			}
+		})
+	}
+}
+
+func Test_formatDerivationPath(t *testing.T) {
+	path, err := parseDerivationPath("m/84'/1h/0'/1/5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := formatDerivationPath(path), "m/84'/1'/0'/1/5"; got != want {
+		t.Errorf("formatDerivationPath() = %v, want %v", got, want)
+	}
+}
+
+func Test_selectPsbtUtxos(t *testing.T) {
+	utxo := func(value int64, addrDesc bchain.AddressDescriptor) psbtUtxo {
+		return psbtUtxo{addrDesc: addrDesc, value: value}
+	}
+	// one p2wpkh output of 10000 sat, the change output is p2wpkh with the dust limit 294 sat
+	const (
+		target      = 10000
+		vsize       = psbtTxOverheadVsize + psbtOutputOverheadVsize + 22
+		changeVsize = psbtOutputOverheadVsize + 22
+		changeDust  = 294
+	)
+	tests := []struct {
+		name     string
+		utxos    []psbtUtxo
+		feeRate  float64
+		selected int
+		vsize    int
+		fee      int64
+		change   int64
+		wantErr  string
+	}{
+		{
+			name:     "single input with change",
+			utxos:    []psbtUtxo{utxo(100000, testP2wpkh)},
+			feeRate:  1,
+			selected: 1,
+			vsize:    vsize + psbtInputP2wpkhVsize + changeVsize,
+			fee:      vsize + psbtInputP2wpkhVsize + changeVsize,
+			change:   100000 - target - (vsize + psbtInputP2wpkhVsize + changeVsize),
+		},
+		{
+			name:     "fractional fee rate is rounded up",
+			utxos:    []psbtUtxo{utxo(100000, testP2wpkh)},
+			feeRate:  1.5,
+			selected: 1,
+			vsize:    141,
+			fee:      212,
+			change:   100000 - target - 212,
+		},
+		{
+			name:     "change below the dust limit is added to the fee",
+			utxos:    []psbtUtxo{utxo(target+141+200, testP2wpkh)},
+			feeRate:  1,
+			selected: 1,
+			vsize:    110,
+			fee:      341,
+		},
+		{
+			name:     "no change if it would not pay the change output",
+			utxos:    []psbtUtxo{utxo(target+120, testP2wpkh)},
+			feeRate:  1,
+			selected: 1,
+			vsize:    110,
+			fee:      120,
+		},
+		{
+			name:     "more inputs, only the needed are selected",
+			utxos:    []psbtUtxo{utxo(6000, testP2wpkh), utxo(5000, testP2pkh), utxo(3000, testP2shP2wpkh)},
+			feeRate:  1,
+			selected: 2,
+			vsize:    vsize + psbtInputP2wpkhVsize + psbtInputP2pkhVsize + changeVsize,
+			fee:      289,
+			change:   11000 - target - 289,
+		},
+		{
+			name:     "input fee makes the next input necessary",
+			utxos:    []psbtUtxo{utxo(target+50, testP2wpkh), utxo(1000, testP2shP2wpkh)},
+			feeRate:  1,
+			selected: 2,
+			vsize:    vsize + psbtInputP2wpkhVsize + psbtInputP2shP2wpkhVsize + changeVsize,
+			fee:      232,
+			change:   target + 1050 - target - 232,
+		},
+		{
+			name:    "insufficient funds",
+			utxos:   []psbtUtxo{utxo(5000, testP2wpkh), utxo(4000, testP2wpkh)},
+			feeRate: 1,
+			wantErr: "Insufficient funds",
+		},
+		{
+			name:    "insufficient funds for the fee",
+			utxos:   []psbtUtxo{utxo(target+100, testP2wpkh)},
+			feeRate: 1,
+			wantErr: "Insufficient funds",
+		},
+		{
+			name:    "no utxos",
+			feeRate: 1,
+			wantErr: "Insufficient funds",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectPsbtUtxos(tt.utxos, target, vsize, tt.feeRate, changeVsize, changeDust)
+			if tt.wantErr != "" {
+				apiErr, ok := err.(*APIError)
+				if !ok || !apiErr.Public || apiErr.Text != tt.wantErr {
+					t.Fatalf("selectPsbtUtxos() error = %v, want public %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectPsbtUtxos() error = %v", err)
+			}
+			if len(got.selected) != tt.selected || got.vsize != tt.vsize || got.fee != tt.fee || got.change != tt.change {
+				t.Errorf("selectPsbtUtxos() = %d inputs, vsize %d, fee %d, change %d, want %d inputs, vsize %d, fee %d, change %d",
+					len(got.selected), got.vsize, got.fee, got.change, tt.selected, tt.vsize, tt.fee, tt.change)
+			}
+			var sum int64
+			for i := range got.selected {
+				sum += got.selected[i].value
+			}
+			if sum != target+got.fee+got.change {
+				t.Errorf("selectPsbtUtxos() inputs %d do not equal outputs %d and fee %d", sum, target+got.change, got.fee)
+			}
+		})
+	}
+}
+
+// testPsbtMempool returns the transactions of the address descriptors in the map
+type testPsbtMempool struct {
+	bchain.Mempool
+	txs map[string][]bchain.Outpoint
+}
+
+func (m *testPsbtMempool) GetAddrDescTransactions(addrDesc bchain.AddressDescriptor) ([]bchain.Outpoint, error) {
+	return m.txs[string(addrDesc)], nil
+}
+
+func TestWorker_psbtChangeIndex(t *testing.T) {
+	changeAddress := func(i byte, used bool) xpubAddress {
+		ad := xpubAddress{addrDesc: bchain.AddressDescriptor(append([]byte{0x00, 0x14}, make([]byte, 19)...))}
+		ad.addrDesc = append(ad.addrDesc, i)
+		if used {
+			ad.balance = &db.AddrBalance{Txs: 1}
+		}
+		return ad
+	}
+	tests := []struct {
+		name            string
+		changeAddresses []xpubAddress
+		mempool         []int
+		want            int
+		wantErr         bool
+	}{
+		{
+			name:            "first unused",
+			changeAddresses: []xpubAddress{changeAddress(0, false), changeAddress(1, false)},
+			want:            0,
+		},
+		{
+			name:            "skip confirmed",
+			changeAddresses: []xpubAddress{changeAddress(0, true), changeAddress(1, true), changeAddress(2, false)},
+			want:            2,
+		},
+		{
+			name:            "skip mempool",
+			changeAddresses: []xpubAddress{changeAddress(0, true), changeAddress(1, false), changeAddress(2, false)},
+			mempool:         []int{1},
+			want:            2,
+		},
+		{
+			name:            "all used",
+			changeAddresses: []xpubAddress{changeAddress(0, true), changeAddress(1, false)},
+			mempool:         []int{1},
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &testPsbtMempool{txs: make(map[string][]bchain.Outpoint)}
+			for _, i := range tt.mempool {
+				m.txs[string(tt.changeAddresses[i].addrDesc)] = []bchain.Outpoint{{Txid: "txid", Vout: 0}}
+			}
+			w := &Worker{mempool: m}
+			got, err := w.psbtChangeIndex("xpub", &xpubData{changeAddresses: tt.changeAddresses})
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "No unused change address") {
+					t.Fatalf("psbtChangeIndex() error = %v, want no unused change address", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("psbtChangeIndex() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("psbtChangeIndex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+	"github.com/scryptachain/blockbook-scrypta/db"
+	"github.com/scryptachain/blockbook-scrypta/tracing"
+)
+
+// WithContext returns the worker recording the tracing spans as children of the span in the context
+// If tracing is disabled or the context does not contain a span, the worker itself is returned
+func (w *Worker) WithContext(ctx context.Context) *Worker {
+	if tracing.FromContext(ctx) == nil {
+		return w
+	}
+	chain := w.chain
+	if tc, ok := chain.(*tracedChain); ok {
+		chain = tc.BlockChain
+	}
+	c := *w
+	c.ctx = ctx
+	c.chain = &tracedChain{BlockChain: chain, ctx: ctx}
+	c.txCache = w.txCache.WithChain(c.chain)
+	return &c
+}
+
+// startSpan starts the span of the worker method, the returned worker records the spans of its calls as children of the span
+func (w *Worker) startSpan(method string) (*Worker, *tracing.Span) {
+	ctx, span := tracing.StartChild(w.ctx, "api."+method, tracing.KindInternal)
+	if span == nil {
+		return w, nil
+	}
+	return w.WithContext(ctx), span
+}
+
+// getTxAddresses reads the addresses of the transaction from the db
+func (w *Worker) getTxAddresses(txid string) (*db.TxAddresses, error) {
+	_, span := tracing.StartChild(w.ctx, "db.GetTxAddresses", tracing.KindClient)
+	ta, err := w.db.GetTxAddresses(txid)
+	span.SetError(err)
+	span.End()
+	return ta, err
+}
+
+// getAddrDescTransactions reads the transactions of the address from the db, the span includes the processing of the callbacks
+func (w *Worker) getAddrDescTransactions(addrDesc bchain.AddressDescriptor, lower uint32, higher uint32, fn db.GetTransactionsCallback) error {
+	_, span := tracing.StartChild(w.ctx, "db.GetAddrDescTransactions", tracing.KindClient)
+	err := w.db.GetAddrDescTransactions(addrDesc, lower, higher, fn)
+	span.SetError(err)
+	span.End()
+	return err
+}
+
+// tracedChain records the backend requests made by the worker as spans
+type tracedChain struct {
+	bchain.BlockChain
+	ctx context.Context
+}
+
+func (c *tracedChain) startSpan(method string) *tracing.Span {
+	_, span := tracing.Start(c.ctx, "rpc."+method, tracing.KindClient)
+	return span
+}
+
+func endSpan(span *tracing.Span, err error) {
+	span.SetError(err)
+	span.End()
+}
+
+func (c *tracedChain) GetChainInfo() (v *bchain.ChainInfo, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("GetChainInfo"))
+	return c.BlockChain.GetChainInfo()
+}
+
+func (c *tracedChain) GetBestBlockHash() (v string, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("GetBestBlockHash"))
+	return c.BlockChain.GetBestBlockHash()
+}
+
+func (c *tracedChain) GetBestBlockHeight() (v uint32, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("GetBestBlockHeight"))
+	return c.BlockChain.GetBestBlockHeight()
+}
+
+func (c *tracedChain) GetBlockHash(height uint32) (v string, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("GetBlockHash"))
+	return c.BlockChain.GetBlockHash(height)
+}
+
+func (c *tracedChain) GetBlockHeader(hash string) (v *bchain.BlockHeader, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("GetBlockHeader"))
+	return c.BlockChain.GetBlockHeader(hash)
+}
+
+func (c *tracedChain) GetBlock(hash string, height uint32) (v *bchain.Block, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("GetBlock"))
+	return c.BlockChain.GetBlock(hash, height)
+}
+
+func (c *tracedChain) GetBlockInfo(hash string) (v *bchain.BlockInfo, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("GetBlockInfo"))
+	return c.BlockChain.GetBlockInfo(hash)
+}
+
+func (c *tracedChain) GetTransaction(txid string) (v *bchain.Tx, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("GetTransaction"))
+	return c.BlockChain.GetTransaction(txid)
+}
+
+func (c *tracedChain) GetTransactionSpecific(tx *bchain.Tx) (v json.RawMessage, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("GetTransactionSpecific"))
+	return c.BlockChain.GetTransactionSpecific(tx)
+}
+
+func (c *tracedChain) EstimateSmartFee(blocks int, conservative bool) (v big.Int, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("EstimateSmartFee"))
+	return c.BlockChain.EstimateSmartFee(blocks, conservative)
+}
+
+func (c *tracedChain) EstimateFee(blocks int) (v big.Int, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("EstimateFee"))
+	return c.BlockChain.EstimateFee(blocks)
+}
+
+func (c *tracedChain) SendRawTransaction(tx string) (v string, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("SendRawTransaction"))
+	return c.BlockChain.SendRawTransaction(tx)
+}
+
+func (c *tracedChain) GetMempoolEntry(txid string) (v *bchain.MempoolEntry, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("GetMempoolEntry"))
+	return c.BlockChain.GetMempoolEntry(txid)
+}
+
+func (c *tracedChain) EthereumTypeGetBalance(addrDesc bchain.AddressDescriptor) (v *big.Int, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("EthereumTypeGetBalance"))
+	return c.BlockChain.EthereumTypeGetBalance(addrDesc)
+}
+
+func (c *tracedChain) EthereumTypeGetNonce(addrDesc bchain.AddressDescriptor) (v uint64, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("EthereumTypeGetNonce"))
+	return c.BlockChain.EthereumTypeGetNonce(addrDesc)
+}
+
+func (c *tracedChain) EthereumTypeEstimateGas(params map[string]interface{}) (v uint64, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("EthereumTypeEstimateGas"))
+	return c.BlockChain.EthereumTypeEstimateGas(params)
+}
+
+func (c *tracedChain) EthereumTypeGetErc20ContractInfo(contractDesc bchain.AddressDescriptor) (v *bchain.Erc20Contract, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("EthereumTypeGetErc20ContractInfo"))
+	return c.BlockChain.EthereumTypeGetErc20ContractInfo(contractDesc)
+}
+
+func (c *tracedChain) EthereumTypeGetErc20ContractBalance(addrDesc, contractDesc bchain.AddressDescriptor) (v *big.Int, err error) {
+	defer func(span *tracing.Span) { endSpan(span, err) }(c.startSpan("EthereumTypeGetErc20ContractBalance"))
+	return c.BlockChain.EthereumTypeGetErc20ContractBalance(addrDesc, contractDesc)
+}
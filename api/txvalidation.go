@@ -0,0 +1,271 @@
+package api
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/martinboehm/btcd/wire"
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+)
+
+// issue codes returned by ValidateTransaction
+const (
+	TxIssueInvalidTx       = "invalid-tx"
+	TxIssueCoinbase        = "coinbase"
+	TxIssueDuplicateInput  = "duplicate-input"
+	TxIssueMissingInput    = "missing-input"
+	TxIssueSpentInput      = "spent-input"
+	TxIssueMempoolConflict = "mempool-conflict"
+	TxIssueRbfReplacement  = "rbf-replacement"
+	TxIssueNegativeFee     = "negative-fee"
+	TxIssueAbsurdFee       = "absurd-fee"
+	TxIssueDustOutput      = "dust-output"
+)
+
+func (v *TxValidation) addIssue(code string, severity TxValidationSeverity, input, output int, format string, a ...interface{}) {
+	issue := TxValidationIssue{
+		Code:     code,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, a...),
+	}
+	if input >= 0 {
+		issue.Input = &input
+	}
+	if output >= 0 {
+		issue.Output = &output
+	}
+	if severity == TxValidationError {
+		v.Valid = false
+	}
+	v.Issues = append(v.Issues, issue)
+}
+
+// errorMessages returns messages of issues with severity error
+func (v *TxValidation) errorMessages() string {
+	var m []string
+	for i := range v.Issues {
+		if v.Issues[i].Severity == TxValidationError {
+			m = append(m, v.Issues[i].Message)
+		}
+	}
+	return strings.Join(m, "; ")
+}
+
+// dustLimit returns the value of the output below which the backend does not relay the transaction, zero if the coin has no dust limit
+func (w *Worker) dustLimit(addrDesc bchain.AddressDescriptor) *big.Int {
+	if l := w.chainParser.DustLimit(addrDesc); l != nil {
+		return l
+	}
+	return big.NewInt(0)
+}
+
+// txVsize returns the virtual size of the serialized transaction, the witness data of segwit transactions count by a quarter
+func txVsize(b []byte) int {
+	// the segwit serialization has the marker 0x00 and the flag 0x01 after the version
+	if len(b) > 6 && b[4] == 0 && b[5] == 1 {
+		var mtx wire.MsgTx
+		if err := mtx.Deserialize(bytes.NewReader(b)); err == nil && mtx.HasWitness() {
+			return (mtx.SerializeSizeStripped()*3 + len(b) + 3) / 4
+		}
+	}
+	return len(b)
+}
+
+// getMempoolSpendingTx returns txid of a mempool transaction spending the outpoint and a flag if the spending tx signals BIP125 replaceability
+func (w *Worker) getMempoolSpendingTx(addrDesc bchain.AddressDescriptor, txid string, vout uint32, excludeTxid string) (string, bool, error) {
+	outpoints, err := w.mempool.GetAddrDescTransactions(addrDesc)
+	if err != nil {
+		return "", false, err
+	}
+	for _, o := range outpoints {
+		// inputs are stored in mempool index as the binary complement of the spent vout
+		if o.Vout != ^int32(vout) || o.Txid == excludeTxid {
+			continue
+		}
+		mtx, _, err := w.txCache.GetTransaction(o.Txid)
+		if err != nil {
+			if err == bchain.ErrTxNotFound {
+				continue
+			}
+			return "", false, err
+		}
+		rbf := false
+		spends := false
+		for i := range mtx.Vin {
+			if mtx.Vin[i].Txid == txid && mtx.Vin[i].Vout == vout {
+				spends = true
+			}
+			if mtx.Vin[i].Sequence < 0xffffffff-1 {
+				rbf = true
+			}
+		}
+		if spends {
+			return o.Txid, rbf, nil
+		}
+	}
+	return "", false, nil
+}
+
+// getOutpointForValidation returns address descriptor and value of the output spent by the input
+// found is false if the output does not exist neither in the index nor in the mempool, spent is true if the output is spent in the index
+func (w *Worker) getOutpointForValidation(txid string, vout uint32) (addrDesc bchain.AddressDescriptor, value *big.Int, found bool, spent bool, err error) {
+	ta, err := w.getTxAddresses(txid)
+	if err != nil {
+		return nil, nil, false, false, errors.Annotatef(err, "GetTxAddresses %v", txid)
+	}
+	if ta != nil {
+		if int(vout) >= len(ta.Outputs) {
+			return nil, nil, false, false, nil
+		}
+		o := &ta.Outputs[vout]
+		return o.AddrDesc, &o.ValueSat, true, o.Spent, nil
+	}
+	if w.mempool.GetTransactionTime(txid) == 0 {
+		return nil, nil, false, false, nil
+	}
+	mtx, _, err := w.txCache.GetTransaction(txid)
+	if err != nil {
+		if err == bchain.ErrTxNotFound {
+			return nil, nil, false, false, nil
+		}
+		return nil, nil, false, false, errors.Annotatef(err, "txCache.GetTransaction %v", txid)
+	}
+	if int(vout) >= len(mtx.Vout) {
+		return nil, nil, false, false, nil
+	}
+	addrDesc, err = w.chainParser.GetAddrDescFromVout(&mtx.Vout[vout])
+	if err != nil {
+		glog.Warning("GetAddrDescFromVout tx ", txid, ", vout ", vout, ": ", err)
+	}
+	return addrDesc, &mtx.Vout[vout].ValueSat, true, false, nil
+}
+
+// ValidateTransaction parses the transaction in hex format and checks it against the index and mempool:
+// inputs must be unspent, fee must be positive and not absurdly high, dust outputs and inputs unknown to the index are reported
+// The inputs unknown to the index and mempool are left to the backend, which may know them (e.g. a parent not yet in the mempool)
+func (w *Worker) ValidateTransaction(txHex string) (*TxValidation, error) {
+	w, span := w.startSpan("ValidateTransaction")
+	defer span.End()
+	if w.chainType != bchain.ChainBitcoinType {
+		return nil, NewAPIError("Not supported", true)
+	}
+	var valInSat, valOutSat big.Int
+	v := &TxValidation{
+		Valid:       true,
+		Size:        len(txHex) / 2,
+		ValueOutSat: (*Amount)(&valOutSat),
+	}
+	b, err := hex.DecodeString(txHex)
+	if err != nil {
+		v.addIssue(TxIssueInvalidTx, TxValidationError, -1, -1, "Invalid hex data: %v", err)
+		return v, nil
+	}
+	tx, err := w.chainParser.ParseTx(b)
+	if err != nil {
+		v.addIssue(TxIssueInvalidTx, TxValidationError, -1, -1, "Cannot parse transaction: %v", err)
+		return v, nil
+	}
+	v.Txid = tx.Txid
+	v.Vsize = txVsize(b)
+	allInputsKnown := true
+	outpoints := make(map[string]int, len(tx.Vin))
+	for i := range tx.Vin {
+		vin := &tx.Vin[i]
+		if vin.Coinbase != "" {
+			v.addIssue(TxIssueCoinbase, TxValidationError, i, -1, "Input %d: coinbase transaction cannot be broadcast", i)
+			allInputsKnown = false
+			continue
+		}
+		op := fmt.Sprintf("%s:%d", vin.Txid, vin.Vout)
+		if j, exists := outpoints[op]; exists {
+			v.addIssue(TxIssueDuplicateInput, TxValidationError, i, -1, "Input %d: outpoint %s already spent by input %d", i, op, j)
+			continue
+		}
+		outpoints[op] = i
+		addrDesc, value, found, spent, err := w.getOutpointForValidation(vin.Txid, vin.Vout)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			v.addIssue(TxIssueMissingInput, TxValidationWarning, i, -1, "Input %d: outpoint %s not found in the index nor in the mempool", i, op)
+			allInputsKnown = false
+			continue
+		}
+		if spent {
+			v.addIssue(TxIssueSpentInput, TxValidationError, i, -1, "Input %d: outpoint %s is already spent", i, op)
+		} else if len(addrDesc) > 0 {
+			spendingTxid, rbf, err := w.getMempoolSpendingTx(addrDesc, vin.Txid, vin.Vout, tx.Txid)
+			if err != nil {
+				return nil, err
+			}
+			if spendingTxid != "" {
+				if rbf {
+					v.addIssue(TxIssueRbfReplacement, TxValidationWarning, i, -1, "Input %d: outpoint %s is spent by replaceable mempool transaction %s", i, op, spendingTxid)
+				} else {
+					v.addIssue(TxIssueMempoolConflict, TxValidationError, i, -1, "Input %d: outpoint %s is already spent by mempool transaction %s", i, op, spendingTxid)
+				}
+			}
+		}
+		valInSat.Add(&valInSat, value)
+	}
+	for i := range tx.Vout {
+		vout := &tx.Vout[i]
+		valOutSat.Add(&valOutSat, &vout.ValueSat)
+		addrDesc, err := w.chainParser.GetAddrDescFromVout(vout)
+		if err != nil {
+			glog.V(2).Infof("GetAddrDescFromVout error %v, %v, output %v", err, tx.Txid, i)
+			continue
+		}
+		if dust := w.dustLimit(addrDesc); vout.ValueSat.Cmp(dust) < 0 {
+			v.addIssue(TxIssueDustOutput, TxValidationWarning, -1, i, "Output %d: value %s is below the dust limit %s", i,
+				w.chainParser.AmountToDecimalString(&vout.ValueSat), w.chainParser.AmountToDecimalString(dust))
+		}
+	}
+	if allInputsKnown {
+		var fee, feePerKb big.Int
+		fee.Sub(&valInSat, &valOutSat)
+		v.ValueInSat = (*Amount)(&valInSat)
+		v.FeesSat = (*Amount)(&fee)
+		if fee.Sign() < 0 {
+			v.addIssue(TxIssueNegativeFee, TxValidationError, -1, -1, "Value of outputs %s exceeds value of inputs %s",
+				w.chainParser.AmountToDecimalString(&valOutSat), w.chainParser.AmountToDecimalString(&valInSat))
+		} else if v.Vsize > 0 {
+			feePerKb.Mul(&fee, big.NewInt(1000))
+			feePerKb.Div(&feePerKb, big.NewInt(int64(v.Vsize)))
+			v.FeePerKb = (*Amount)(&feePerKb)
+			// the same limit as the default maxfeerate of bitcoind, 0.1 coin per kB
+			var maxFeePerKb big.Int
+			maxFeePerKb.Exp(big.NewInt(10), big.NewInt(int64(w.chainParser.AmountDecimals()-1)), nil)
+			if feePerKb.Cmp(&maxFeePerKb) > 0 {
+				v.addIssue(TxIssueAbsurdFee, TxValidationError, -1, -1, "Fee rate %s per kB exceeds the maximum %s per kB",
+					w.chainParser.AmountToDecimalString(&feePerKb), w.chainParser.AmountToDecimalString(&maxFeePerKb))
+			}
+		}
+	}
+	return v, nil
+}
+
+// SendTransaction validates the transaction and if there are no errors, broadcasts it using the backend
+// Transactions which cannot be parsed by Blockbook are passed to the backend, which makes the final decision
+func (w *Worker) SendTransaction(txHex string) (string, error) {
+	w, span := w.startSpan("SendTransaction")
+	defer span.End()
+	if w.chainType == bchain.ChainBitcoinType {
+		v, err := w.ValidateTransaction(txHex)
+		if err != nil {
+			glog.Warning("ValidateTransaction error ", err, ", sending transaction without validation")
+		} else if !v.Valid && (len(v.Issues) != 1 || v.Issues[0].Code != TxIssueInvalidTx) {
+			return "", NewAPIError(v.errorMessages(), true)
+		}
+	}
+	txid, err := w.chain.SendRawTransaction(txHex)
+	if err != nil {
+		return "", NewAPIError(err.Error(), true)
+	}
+	return txid, nil
+}
@@ -0,0 +1,48 @@
+// +build unittest
+
+package api
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/martinboehm/btcd/chaincfg/chainhash"
+	"github.com/martinboehm/btcd/wire"
+)
+
+func TestTxVsize(t *testing.T) {
+	p2pkh := []byte{0x76, 0xa9, 0x14, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 0x88, 0xac}
+	serialize := func(witness bool) []byte {
+		tx := wire.NewMsgTx(2)
+		in := wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil)
+		if witness {
+			in.Witness = wire.TxWitness{make([]byte, 71), make([]byte, 33)}
+		} else {
+			in.SignatureScript = make([]byte, 106)
+		}
+		tx.AddTxIn(in)
+		tx.AddTxOut(wire.NewTxOut(1000, p2pkh))
+		var b bytes.Buffer
+		if err := tx.Serialize(&b); err != nil {
+			t.Fatal(err)
+		}
+		return b.Bytes()
+	}
+	legacy := serialize(false)
+	if got := txVsize(legacy); got != len(legacy) {
+		t.Errorf("txVsize(legacy) = %v, want %v", got, len(legacy))
+	}
+	// 85 bytes without witness, 2 bytes of marker and flag and 107 bytes of witness count by a quarter
+	segwit := serialize(true)
+	if len(segwit) != 194 {
+		t.Fatalf("segwit tx size %v, want 194", len(segwit))
+	}
+	if got := txVsize(segwit); got != 113 {
+		t.Errorf("txVsize(segwit) = %v, want 113", got)
+	}
+	// data which only look like the segwit serialization are counted in full
+	fake := []byte{2, 0, 0, 0, 0, 1, 0xff}
+	if got := txVsize(fake); got != len(fake) {
+		t.Errorf("txVsize(invalid) = %v, want %v", got, len(fake))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"math/big"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/scryptachain/blockbook-scrypta/bchain"
@@ -71,6 +72,15 @@ func (a *Amount) MarshalJSON() (out []byte, err error) {
 	return []byte(`"` + (*big.Int)(a).String() + `"`), nil
 }
 
+// UnmarshalJSON Amount deserialization, the amount can be a string or a number in base units
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if _, ok := (*big.Int)(a).SetString(s, 10); !ok {
+		return errors.New("Invalid amount " + s)
+	}
+	return nil
+}
+
 func (a *Amount) String() string {
 	if a == nil {
 		return ""
@@ -114,6 +124,7 @@ type Vin struct {
 	Hex       string                   `json:"hex,omitempty"`
 	Asm       string                   `json:"asm,omitempty"`
 	Coinbase  string                   `json:"coinbase,omitempty"`
+	Fiat      *FiatValue               `json:"fiat,omitempty"`
 }
 
 // Vout contains information about single transaction output
@@ -130,6 +141,7 @@ type Vout struct {
 	Addresses   []string                 `json:"addresses"`
 	IsAddress   bool                     `json:"isAddress"`
 	Type        string                   `json:"type,omitempty"`
+	Fiat        *FiatValue               `json:"fiat,omitempty"`
 }
 
 // TokenType specifies type of token
@@ -199,6 +211,80 @@ type Tx struct {
 	CoinSpecificJSON json.RawMessage   `json:"-"`
 	TokenTransfers   []TokenTransfer   `json:"tokenTransfers,omitempty"`
 	EthereumSpecific *EthereumSpecific `json:"ethereumSpecific,omitempty"`
+	Fiat             *TxFiat           `json:"fiat,omitempty"`
+}
+
+// FiatRate contains the rate of the coin in a fiat currency at the time of a transaction or block
+// (omitted if there is no stored rate for the time) and the current rate
+type FiatRate struct {
+	Currency    string  `json:"currency"`
+	Rate        float64 `json:"rate,omitempty"`
+	CurrentRate float64 `json:"currentRate"`
+}
+
+// FiatValue contains an amount converted to a fiat currency using the rate at the time of the transaction
+// (omitted if there is no stored rate for the time) and using the current rate
+type FiatValue struct {
+	Value        *float64 `json:"value,omitempty"`
+	CurrentValue float64  `json:"currentValue"`
+}
+
+// TxFiat contains values of a transaction in a fiat currency
+type TxFiat struct {
+	FiatRate
+	Value   FiatValue  `json:"value"`
+	ValueIn *FiatValue `json:"valueIn,omitempty"`
+	Fees    *FiatValue `json:"fees,omitempty"`
+}
+
+// AccountFiat contains values of an address or xpub in a fiat currency
+// TotalReceived and TotalSent are sums of the confirmed transactions converted at the rates at the time of the transactions,
+// CostBasis, Realized and Unrealized are computed by the average cost method from the confirmed transactions,
+// the net incoming amount of a transaction is an acquisition, the net outgoing amount (including fees) is a disposal
+// The figures computed from the transactions are nil if the rate at the time of some transaction is not stored
+// or if the account has too many transactions
+type AccountFiat struct {
+	Currency           string   `json:"currency"`
+	Rate               float64  `json:"rate"`
+	Balance            float64  `json:"balance"`
+	UnconfirmedBalance float64  `json:"unconfirmedBalance,omitempty"`
+	TotalReceived      *float64 `json:"totalReceived,omitempty"`
+	TotalSent          *float64 `json:"totalSent,omitempty"`
+	CostBasis          *float64 `json:"costBasis,omitempty"`
+	Realized           *float64 `json:"realized,omitempty"`
+	Unrealized         *float64 `json:"unrealized,omitempty"`
+}
+
+// TxValidationSeverity specifies if the validation issue prevents the transaction from being broadcast
+type TxValidationSeverity string
+
+const (
+	// TxValidationError - the transaction would be rejected by the backend
+	TxValidationError TxValidationSeverity = "error"
+	// TxValidationWarning - the transaction is acceptable but probably not what the sender intended
+	TxValidationWarning TxValidationSeverity = "warning"
+)
+
+// TxValidationIssue describes one problem found by the transaction validation
+type TxValidationIssue struct {
+	Code     string               `json:"code"`
+	Severity TxValidationSeverity `json:"severity"`
+	Message  string               `json:"message"`
+	Input    *int                 `json:"input,omitempty"`
+	Output   *int                 `json:"output,omitempty"`
+}
+
+// TxValidation contains result of the validation of a transaction before broadcast
+type TxValidation struct {
+	Txid        string              `json:"txid,omitempty"`
+	Valid       bool                `json:"valid"`
+	Size        int                 `json:"size"`
+	Vsize       int                 `json:"vsize,omitempty"`
+	ValueInSat  *Amount             `json:"valueIn,omitempty"`
+	ValueOutSat *Amount             `json:"value"`
+	FeesSat     *Amount             `json:"fees,omitempty"`
+	FeePerKb    *Amount             `json:"feePerKb,omitempty"`
+	Issues      []TxValidationIssue `json:"issues,omitempty"`
 }
 
 // FeeStats contains detailed block fee statistics
@@ -265,6 +351,7 @@ type Address struct {
 	UsedTokens            int                   `json:"usedTokens,omitempty"`
 	Tokens                []Token               `json:"tokens,omitempty"`
 	Erc20Contract         *bchain.Erc20Contract `json:"erc20Contract,omitempty"`
+	Fiat                  *AccountFiat          `json:"fiat,omitempty"`
 	// helpers for explorer
 	Filter        string              `json:"-"`
 	XPubAddresses map[string]struct{} `json:"-"`
@@ -272,15 +359,16 @@ type Address struct {
 
 // Utxo is one unspent transaction output
 type Utxo struct {
-	Txid          string  `json:"txid"`
-	Vout          int32   `json:"vout"`
-	AmountSat     *Amount `json:"value"`
-	Height        int     `json:"height,omitempty"`
-	Confirmations int     `json:"confirmations"`
-	Address       string  `json:"address,omitempty"`
-	Path          string  `json:"path,omitempty"`
-	Locktime      uint32  `json:"lockTime,omitempty"`
-	Coinbase      bool    `json:"coinbase,omitempty"`
+	Txid          string     `json:"txid"`
+	Vout          int32      `json:"vout"`
+	AmountSat     *Amount    `json:"value"`
+	Height        int        `json:"height,omitempty"`
+	Confirmations int        `json:"confirmations"`
+	Address       string     `json:"address,omitempty"`
+	Path          string     `json:"path,omitempty"`
+	Locktime      uint32     `json:"lockTime,omitempty"`
+	Coinbase      bool       `json:"coinbase,omitempty"`
+	Fiat          *FiatValue `json:"fiat,omitempty"`
 }
 
 // Utxos is array of Utxo
@@ -301,6 +389,35 @@ func (a Utxos) Less(i, j int) bool {
 	return hi >= hj
 }
 
+// PsbtRecipient is a destination output of a transaction created by CreatePsbt
+type PsbtRecipient struct {
+	Address   string  `json:"address"`
+	AmountSat *Amount `json:"value"`
+}
+
+// PsbtRequest contains parameters of a transaction created by CreatePsbt
+// Xpub can be an xpub or an output descriptor with key origin, fee rate is in base units per virtual byte
+type PsbtRequest struct {
+	Xpub          string          `json:"xpub"`
+	Outputs       []PsbtRecipient `json:"outputs"`
+	FeeRate       float64         `json:"feeRate"`
+	Gap           int             `json:"gap,omitempty"`
+	OnlyConfirmed bool            `json:"confirmed,omitempty"`
+	LockTime      uint32          `json:"lockTime,omitempty"`
+	Rbf           bool            `json:"rbf,omitempty"`
+}
+
+// Psbt contains unsigned transaction in BIP174 format and the result of the coin selection
+type Psbt struct {
+	Psbt          string  `json:"psbt"`
+	Vsize         int     `json:"vsize"`
+	FeesSat       *Amount `json:"fees"`
+	Inputs        Utxos   `json:"inputs"`
+	ChangeAddress string  `json:"changeAddress,omitempty"`
+	ChangePath    string  `json:"changePath,omitempty"`
+	ChangeSat     *Amount `json:"changeValue,omitempty"`
+}
+
 // BalanceHistory contains info about one point in time of balance history
 type BalanceHistory struct {
 	Time          uint32             `json:"time"`
@@ -395,8 +512,9 @@ type BlockInfo struct {
 type Block struct {
 	Paging
 	BlockInfo
-	TxCount      int   `json:"txCount"`
-	Transactions []*Tx `json:"txs,omitempty"`
+	TxCount      int       `json:"txCount"`
+	Transactions []*Tx     `json:"txs,omitempty"`
+	Fiat         *FiatRate `json:"fiat,omitempty"`
 }
 
 // BlockbookInfo contains information about the running blockbook instance
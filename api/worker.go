@@ -2,6 +2,8 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -29,18 +31,23 @@ type Worker struct {
 	chainType   bchain.ChainType
 	mempool     bchain.Mempool
 	is          *common.InternalState
+	// fiatHistories caches the fiat gains of the accounts, shared by the copies of the worker
+	fiatHistories *fiatHistoryCache
+	// ctx is the context of the request, used for tracing
+	ctx context.Context
 }
 
 // NewWorker creates new api worker
 func NewWorker(db *db.RocksDB, chain bchain.BlockChain, mempool bchain.Mempool, txCache *db.TxCache, is *common.InternalState) (*Worker, error) {
 	w := &Worker{
-		db:          db,
-		txCache:     txCache,
-		chain:       chain,
-		chainParser: chain.GetChainParser(),
-		chainType:   chain.GetChainParser().GetChainType(),
-		mempool:     mempool,
-		is:          is,
+		db:            db,
+		txCache:       txCache,
+		chain:         chain,
+		chainParser:   chain.GetChainParser(),
+		chainType:     chain.GetChainParser().GetChainType(),
+		mempool:       mempool,
+		is:            is,
+		fiatHistories: newFiatHistoryCache(),
 	}
 	return w, nil
 }
@@ -57,12 +64,12 @@ func (w *Worker) getAddressesFromVout(vout *bchain.Vout) (bchain.AddressDescript
 // setSpendingTxToVout is helper function, that finds transaction that spent given output and sets it to the output
 // there is no direct index for the operation, it must be found using addresses -> txaddresses -> tx
 func (w *Worker) setSpendingTxToVout(vout *Vout, txid string, height uint32) error {
-	err := w.db.GetAddrDescTransactions(vout.AddrDesc, height, maxUint32, func(t string, height uint32, indexes []int32) error {
+	err := w.getAddrDescTransactions(vout.AddrDesc, height, maxUint32, func(t string, height uint32, indexes []int32) error {
 		for _, index := range indexes {
 			// take only inputs
 			if index < 0 {
 				index = ^index
-				tsp, err := w.db.GetTxAddresses(t)
+				tsp, err := w.getTxAddresses(t)
 				if err != nil {
 					return err
 				} else if tsp == nil {
@@ -93,6 +100,8 @@ func (w *Worker) setSpendingTxToVout(vout *Vout, txid string, height uint32) err
 
 // GetSpendingTxid returns transaction id of transaction that spent given output
 func (w *Worker) GetSpendingTxid(txid string, n int) (string, error) {
+	w, span := w.startSpan("GetSpendingTxid")
+	defer span.End()
 	start := time.Now()
 	tx, err := w.GetTransaction(txid, false, false)
 	if err != nil {
@@ -111,6 +120,8 @@ func (w *Worker) GetSpendingTxid(txid string, n int) (string, error) {
 
 // GetTransaction reads transaction data from txid
 func (w *Worker) GetTransaction(txid string, spendingTxs bool, specificJSON bool) (*Tx, error) {
+	w, span := w.startSpan("GetTransaction")
+	defer span.End()
 	bchainTx, height, err := w.txCache.GetTransaction(txid)
 	if err != nil {
 		if err == bchain.ErrTxNotFound {
@@ -130,7 +141,7 @@ func (w *Worker) GetTransactionFromBchainTx(bchainTx *bchain.Tx, height int, spe
 	var blockhash string
 	if bchainTx.Confirmations > 0 {
 		if w.chainType == bchain.ChainBitcoinType {
-			ta, err = w.db.GetTxAddresses(bchainTx.Txid)
+			ta, err = w.getTxAddresses(bchainTx.Txid)
 			if err != nil {
 				return nil, errors.Annotatef(err, "GetTxAddresses %v", bchainTx.Txid)
 			}
@@ -161,7 +172,7 @@ func (w *Worker) GetTransactionFromBchainTx(bchainTx *bchain.Tx, height int, spe
 			//  bchainVin.Txid=="" is coinbase transaction
 			if bchainVin.Txid != "" {
 				// load spending addresses from TxAddresses
-				tas, err := w.db.GetTxAddresses(bchainVin.Txid)
+				tas, err := w.getTxAddresses(bchainVin.Txid)
 				if err != nil {
 					return nil, errors.Annotatef(err, "GetTxAddresses %v", bchainVin.Txid)
 				}
@@ -404,6 +415,28 @@ func (w *Worker) GetTransactionFromMempoolTx(mempoolTx *bchain.MempoolTx) (*Tx,
 	return r, nil
 }
 
+// DecodeTransaction parses raw transaction in hex format and returns it in the same form as a mempool transaction
+// the inputs are resolved using the index, mempool or backend, the outputs contain script types
+func (w *Worker) DecodeTransaction(txHex string) (*Tx, error) {
+	b, err := hex.DecodeString(txHex)
+	if err != nil {
+		return nil, NewAPIError(fmt.Sprintf("Invalid hex data, %v", err), true)
+	}
+	bchainTx, err := w.chainParser.ParseTx(b)
+	if err != nil {
+		return nil, NewAPIError(fmt.Sprintf("Cannot parse transaction, %v", err), true)
+	}
+	bchainTx.Confirmations = 0
+	tx, err := w.GetTransactionFromBchainTx(bchainTx, 0, false, false)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tx.Vout {
+		tx.Vout[i].Type = w.chainParser.GetScriptTypeFromVout(&bchainTx.Vout[i])
+	}
+	return tx, nil
+}
+
 func (w *Worker) getTokensFromErc20(erc20 []bchain.Erc20Transfer) []TokenTransfer {
 	tokens := make([]TokenTransfer, len(erc20))
 	for i := range erc20 {
@@ -486,7 +519,7 @@ func (w *Worker) getAddressTxids(addrDesc bchain.AddressDescriptor, mempool bool
 		if to == 0 {
 			to = maxUint32
 		}
-		err = w.db.GetAddrDescTransactions(addrDesc, filter.FromHeight, to, callback)
+		err = w.getAddrDescTransactions(addrDesc, filter.FromHeight, to, callback)
 		if err != nil {
 			return nil, err
 		}
@@ -751,7 +784,7 @@ func (w *Worker) txFromTxid(txid string, bestheight uint32, option AccountDetail
 	var err error
 	// only ChainBitcoinType supports TxHistoryLight
 	if option == AccountDetailsTxHistoryLight && w.chainType == bchain.ChainBitcoinType {
-		ta, err := w.db.GetTxAddresses(txid)
+		ta, err := w.getTxAddresses(txid)
 		if err != nil {
 			return nil, errors.Annotatef(err, "GetTxAddresses %v", txid)
 		}
@@ -808,6 +841,8 @@ func (w *Worker) getAddrDescAndNormalizeAddress(address string) (bchain.AddressD
 
 // GetAddress computes address value and gets transactions for given address
 func (w *Worker) GetAddress(address string, page int, txsOnPage int, option AccountDetails, filter *AddressFilter) (*Address, error) {
+	w, span := w.startSpan("GetAddress")
+	defer span.End()
 	start := time.Now()
 	page--
 	if page < 0 {
@@ -971,7 +1006,7 @@ func (w *Worker) balanceHistoryForTxid(addrDesc bchain.AddressDescriptor, txid s
 	var bchainTx *bchain.Tx
 	var height uint32
 	if w.chainType == bchain.ChainBitcoinType {
-		ta, err = w.db.GetTxAddresses(txid)
+		ta, err = w.getTxAddresses(txid)
 		if err != nil {
 			return nil, err
 		}
@@ -1117,6 +1152,8 @@ func (w *Worker) setFiatRateToBalanceHistories(histories BalanceHistories, curre
 
 // GetBalanceHistory returns history of balance for given address
 func (w *Worker) GetBalanceHistory(address string, fromTimestamp, toTimestamp int64, currencies []string, groupBy uint32) (BalanceHistories, error) {
+	w, span := w.startSpan("GetBalanceHistory")
+	defer span.End()
 	currencies = removeEmpty(currencies)
 	bhs := make(BalanceHistories, 0)
 	start := time.Now()
@@ -1251,7 +1288,7 @@ func (w *Worker) getAddrDescUtxo(addrDesc bchain.AddressDescriptor, ba *db.AddrB
 					coinbase := false
 					// for performance reasons, check coinbase transactions only in minimum confirmantion range
 					if confirmations < w.chainParser.MinimumCoinbaseConfirmations() {
-						ta, err := w.db.GetTxAddresses(txid)
+						ta, err := w.getTxAddresses(txid)
 						if err != nil {
 							return nil, err
 						}
@@ -1283,6 +1320,8 @@ func (w *Worker) getAddrDescUtxo(addrDesc bchain.AddressDescriptor, ba *db.AddrB
 
 // GetAddressUtxo returns unspent outputs for given address
 func (w *Worker) GetAddressUtxo(address string, onlyConfirmed bool) (Utxos, error) {
+	w, span := w.startSpan("GetAddressUtxo")
+	defer span.End()
 	if w.chainType != bchain.ChainBitcoinType {
 		return nil, NewAPIError("Not supported", true)
 	}
@@ -1301,6 +1340,8 @@ func (w *Worker) GetAddressUtxo(address string, onlyConfirmed bool) (Utxos, erro
 
 // GetBlocks returns BlockInfo for blocks on given page
 func (w *Worker) GetBlocks(page int, blocksOnPage int) (*Blocks, error) {
+	w, span := w.startSpan("GetBlocks")
+	defer span.End()
 	start := time.Now()
 	page--
 	if page < 0 {
@@ -1348,14 +1389,22 @@ func (w *Worker) getFiatRatesResult(currencies []string, ticker *db.CurrencyRate
 		return &db.ResultTickerAsString{
 			Timestamp: ticker.Timestamp.UTC().Unix(),
 			Rates:     ticker.Rates,
+			Derived:   ticker.Derived,
 		}, nil
 	}
 	// Check if currencies from the list are available in the ticker rates
 	rates := make(map[string]float64)
+	var derived map[string]string
 	for _, currency := range currencies {
 		currency = strings.ToLower(currency)
 		if rate, found := ticker.Rates[currency]; found {
 			rates[currency] = rate
+			if method, found := ticker.Derived[currency]; found {
+				if derived == nil {
+					derived = make(map[string]string)
+				}
+				derived[currency] = method
+			}
 		} else {
 			rates[currency] = -1
 		}
@@ -1363,11 +1412,14 @@ func (w *Worker) getFiatRatesResult(currencies []string, ticker *db.CurrencyRate
 	return &db.ResultTickerAsString{
 		Timestamp: ticker.Timestamp.UTC().Unix(),
 		Rates:     rates,
+		Derived:   derived,
 	}, nil
 }
 
 // GetFiatRatesForBlockID returns fiat rates for block height or block hash
 func (w *Worker) GetFiatRatesForBlockID(bid string, currencies []string) (*db.ResultTickerAsString, error) {
+	w, span := w.startSpan("GetFiatRatesForBlockID")
+	defer span.End()
 	var ticker *db.CurrencyRatesTicker
 	bi, err := w.getBlockInfoFromBlockID(bid)
 	if err != nil {
@@ -1393,6 +1445,8 @@ func (w *Worker) GetFiatRatesForBlockID(bid string, currencies []string) (*db.Re
 
 // GetCurrentFiatRates returns last available fiat rates
 func (w *Worker) GetCurrentFiatRates(currencies []string) (*db.ResultTickerAsString, error) {
+	w, span := w.startSpan("GetCurrentFiatRates")
+	defer span.End()
 	ticker, err := w.db.FiatRatesFindLastTicker()
 	if err != nil {
 		return nil, NewAPIError(fmt.Sprintf("Error finding ticker: %v", err), false)
@@ -1418,6 +1472,8 @@ func makeErrorRates(currencies []string) map[string]float64 {
 
 // GetFiatRatesForTimestamps returns fiat rates for each of the provided dates
 func (w *Worker) GetFiatRatesForTimestamps(timestamps []int64, currencies []string) (*db.ResultTickersAsString, error) {
+	w, span := w.startSpan("GetFiatRatesForTimestamps")
+	defer span.End()
 	if len(timestamps) == 0 {
 		return nil, NewAPIError("No timestamps provided", true)
 	}
@@ -1446,6 +1502,43 @@ func (w *Worker) GetFiatRatesForTimestamps(timestamps []int64, currencies []stri
 	return ret, nil
 }
 
+// GetFiatRatesOHLC returns OHLC candles of the currency in the interval (hour, day or week) with start in the time range [from, to]
+// If to is zero, the current time is used, if from is zero, the last 100 candles are returned
+func (w *Worker) GetFiatRatesOHLC(currency string, interval string, from, to int64) (*db.ResultFiatRatesOHLC, error) {
+	currency = strings.ToLower(currency)
+	if currency == "" {
+		return nil, NewAPIError("Missing or empty \"currency\" parameter", true)
+	}
+	if interval == "" {
+		interval = "day"
+	}
+	i, err := db.ParseOHLCInterval(interval)
+	if err != nil {
+		return nil, NewAPIError(err.Error(), true)
+	}
+	if to == 0 {
+		to = time.Now().Unix()
+	}
+	if from == 0 {
+		from = to - (defaultOHLCCandles-1)*i.Seconds()
+	}
+	if from > to {
+		return nil, NewAPIError("Parameter \"from\" is greater than \"to\"", true)
+	}
+	if (to-from)/i.Seconds() >= maxOHLCCandles {
+		return nil, NewAPIError(fmt.Sprintf("Too many candles requested, the maximum is %d", maxOHLCCandles), true)
+	}
+	candles, err := w.db.FiatRatesGetOHLC(currency, i, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &db.ResultFiatRatesOHLC{
+		Currency: currency,
+		Interval: i.String(),
+		Candles:  candles,
+	}, nil
+}
+
 // GetFiatRatesTickersList returns the list of available fiatRates tickers
 func (w *Worker) GetFiatRatesTickersList(timestamp int64) (*db.ResultTickerListAsString, error) {
 	date := time.Unix(timestamp, 0)
@@ -1493,6 +1586,8 @@ func (w *Worker) getBlockInfoFromBlockID(bid string) (*bchain.BlockInfo, error)
 
 // GetFeeStats returns statistics about block fees
 func (w *Worker) GetFeeStats(bid string) (*FeeStats, error) {
+	w, span := w.startSpan("GetFeeStats")
+	defer span.End()
 	// txSpecific extends Tx with an additional Size and Vsize info
 	type txSpecific struct {
 		*bchain.Tx
@@ -1541,7 +1636,7 @@ func (w *Worker) GetFeeStats(bid string) (*FeeStats, error) {
 		}
 
 		// Get values of TX inputs and outputs
-		txAddresses, err := w.db.GetTxAddresses(txid)
+		txAddresses, err := w.getTxAddresses(txid)
 		if err != nil {
 			return nil, errors.Annotatef(err, "GetTxAddresses")
 		}
@@ -1599,6 +1694,8 @@ func (w *Worker) GetFeeStats(bid string) (*FeeStats, error) {
 
 // GetBlock returns paged data about block
 func (w *Worker) GetBlock(bid string, page int, txsOnPage int) (*Block, error) {
+	w, span := w.startSpan("GetBlock")
+	defer span.End()
 	start := time.Now()
 	page--
 	if page < 0 {
@@ -1724,6 +1821,8 @@ func (w *Worker) ComputeFeeStats(blockFrom, blockTo int, stopCompute chan os.Sig
 
 // GetSystemInfo returns information about system
 func (w *Worker) GetSystemInfo(internal bool) (*SystemInfo, error) {
+	w, span := w.startSpan("GetSystemInfo")
+	defer span.End()
 	start := time.Now()
 	vi := common.GetVersionInfo()
 	inSync, bestHeight, lastBlockTime := w.is.GetSyncState()
@@ -1784,6 +1883,8 @@ func (w *Worker) GetSystemInfo(internal bool) (*SystemInfo, error) {
 
 // GetMempool returns a page of mempool txids
 func (w *Worker) GetMempool(page int, itemsOnPage int) (*MempoolTxids, error) {
+	w, span := w.startSpan("GetMempool")
+	defer span.End()
 	page--
 	if page < 0 {
 		page = 0
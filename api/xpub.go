@@ -10,6 +10,7 @@ import (
 	"github.com/golang/glog"
 	"github.com/juju/errors"
 	"github.com/scryptachain/blockbook-scrypta/bchain"
+	"github.com/scryptachain/blockbook-scrypta/common"
 	"github.com/scryptachain/blockbook-scrypta/db"
 )
 
@@ -25,6 +26,16 @@ const xpubCacheExpirationSeconds = 7200
 var cachedXpubs = make(map[string]xpubData)
 var cachedXpubsMux sync.Mutex
 
+// xpubCacheMetrics reports the size and evictions of the xpub cache, if set
+var xpubCacheMetrics *common.Metrics
+
+// SetXpubCacheMetrics sets the metrics reporting the size and evictions of the xpub cache
+func SetXpubCacheMetrics(metrics *common.Metrics) {
+	cachedXpubsMux.Lock()
+	xpubCacheMetrics = metrics
+	cachedXpubsMux.Unlock()
+}
+
 type xpubTxid struct {
 	txid        string
 	height      uint32
@@ -111,7 +122,7 @@ func (w *Worker) xpubGetAddressTxids(addrDesc bchain.AddressDescriptor, mempool
 			}
 		}
 	} else {
-		err = w.db.GetAddrDescTransactions(addrDesc, fromHeight, toHeight, callback)
+		err = w.getAddrDescTransactions(addrDesc, fromHeight, toHeight, callback)
 		if err != nil {
 			return nil, false, err
 		}
@@ -247,6 +258,79 @@ func (w *Worker) tokenFromXpubAddress(data *xpubData, ad *xpubAddress, changeInd
 	}
 }
 
+// XpubDerivedAddress is an address derived from an xpub, Change is 0 for the receiving and 1 for the change chain
+type XpubDerivedAddress struct {
+	AddrDesc bchain.AddressDescriptor
+	Address  string
+	Path     string
+	Change   int
+	Index    int
+	Used     bool
+}
+
+// XpubDerivedAddresses contains addresses derived from an xpub,
+// on each chain there are at least Gap unused addresses after the last used address
+type XpubDerivedAddresses struct {
+	Gap       int
+	Addresses []XpubDerivedAddress
+}
+
+func (w *Worker) xpubDerivedAddress(basePath string, addrDesc bchain.AddressDescriptor, change int, index int) XpubDerivedAddress {
+	a, _, _ := w.chainParser.GetAddressesFromAddrDesc(addrDesc)
+	var address string
+	if len(a) > 0 {
+		address = a[0]
+	}
+	return XpubDerivedAddress{
+		AddrDesc: addrDesc,
+		Address:  address,
+		Path:     fmt.Sprintf("%s/%d/%d", basePath, change, index),
+		Change:   change,
+		Index:    index,
+	}
+}
+
+// GetXpubDerivedAddresses returns addresses derived from the xpub up to the gap of unused addresses, used by the confirmed transactions
+func (w *Worker) GetXpubDerivedAddresses(xpub string, gap int) (*XpubDerivedAddresses, error) {
+	data, _, err := w.getXpubData(xpub, 0, 1, AccountDetailsBasic, &AddressFilter{Vout: AddressFilterVoutOff}, gap)
+	if err != nil {
+		return nil, err
+	}
+	r := &XpubDerivedAddresses{
+		// getXpubData increases the gap by one
+		Gap:       data.gap - 1,
+		Addresses: make([]XpubDerivedAddress, 0, len(data.addresses)+len(data.changeAddresses)),
+	}
+	for change, da := range [][]xpubAddress{data.addresses, data.changeAddresses} {
+		for i := range da {
+			a := w.xpubDerivedAddress(data.basePath, da[i].addrDesc, change, i)
+			a.Used = da[i].balance != nil
+			r.Addresses = append(r.Addresses, a)
+		}
+	}
+	return r, nil
+}
+
+// DeriveXpubAddresses derives addresses of the xpub on the chain (0 receiving, 1 change) with indexes in the range [from, to)
+func (w *Worker) DeriveXpubAddresses(xpub string, change int, from int, to int) ([]XpubDerivedAddress, error) {
+	if w.chainType != bchain.ChainBitcoinType {
+		return nil, ErrUnsupportedXpub
+	}
+	basePath, err := w.chainParser.DerivationBasePath(xpub)
+	if err != nil {
+		return nil, err
+	}
+	descriptors, err := w.chainParser.DeriveAddressDescriptorsFromTo(xpub, uint32(change), uint32(from), uint32(to))
+	if err != nil {
+		return nil, err
+	}
+	r := make([]XpubDerivedAddress, len(descriptors))
+	for i, a := range descriptors {
+		r[i] = w.xpubDerivedAddress(basePath, a, change, from+i)
+	}
+	return r, nil
+}
+
 func evictXpubCacheItems() {
 	var oldestKey string
 	oldest := maxInt64
@@ -267,6 +351,9 @@ func evictXpubCacheItems() {
 		count++
 	}
 	glog.Info("Evicted ", count, " items from xpub cache, oldest item accessed at ", time.Unix(oldest, 0), ", cache size ", len(cachedXpubs))
+	if xpubCacheMetrics != nil {
+		xpubCacheMetrics.XpubCacheEvictions.Add(float64(count))
+	}
 }
 
 func (w *Worker) getXpubData(xpub string, page int, txsOnPage int, option AccountDetails, filter *AddressFilter, gap int) (*xpubData, uint32, error) {
@@ -349,12 +436,17 @@ func (w *Worker) getXpubData(xpub string, page int, txsOnPage int, option Accoun
 		evictXpubCacheItems()
 	}
 	cachedXpubs[xpub] = data
+	if xpubCacheMetrics != nil {
+		xpubCacheMetrics.XpubCacheSize.Set(float64(len(cachedXpubs)))
+	}
 	cachedXpubsMux.Unlock()
 	return &data, bestheight, nil
 }
 
 // GetXpubAddress computes address value and gets transactions for given address
 func (w *Worker) GetXpubAddress(xpub string, page int, txsOnPage int, option AccountDetails, filter *AddressFilter, gap int) (*Address, error) {
+	w, span := w.startSpan("GetXpubAddress")
+	defer span.End()
 	start := time.Now()
 	page--
 	if page < 0 {
@@ -550,6 +642,8 @@ func (w *Worker) GetXpubAddress(xpub string, page int, txsOnPage int, option Acc
 
 // GetXpubUtxo returns unspent outputs for given xpub
 func (w *Worker) GetXpubUtxo(xpub string, onlyConfirmed bool, gap int) (Utxos, error) {
+	w, span := w.startSpan("GetXpubUtxo")
+	defer span.End()
 	start := time.Now()
 	data, _, err := w.getXpubData(xpub, 0, 1, AccountDetailsBasic, &AddressFilter{
 		Vout:          AddressFilterVoutOff,
@@ -591,6 +685,8 @@ func (w *Worker) GetXpubUtxo(xpub string, onlyConfirmed bool, gap int) (Utxos, e
 
 // GetXpubBalanceHistory returns history of balance for given xpub
 func (w *Worker) GetXpubBalanceHistory(xpub string, fromTimestamp, toTimestamp int64, currencies []string, gap int, groupBy uint32) (BalanceHistories, error) {
+	w, span := w.startSpan("GetXpubBalanceHistory")
+	defer span.End()
 	bhs := make(BalanceHistories, 0)
 	start := time.Now()
 	fromUnix, fromHeight, toUnix, toHeight := w.balanceHistoryHeightsFromTo(fromTimestamp, toTimestamp)
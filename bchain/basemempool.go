@@ -116,6 +116,54 @@ func (m *BaseMempool) GetTransactionTime(txid string) uint32 {
 	return e.time
 }
 
+// ExportEntries returns all mempool entries including their address indexes so that they can be persisted
+func (m *BaseMempool) ExportEntries() []MempoolPersistedEntry {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	entries := make([]MempoolPersistedEntry, 0, len(m.txEntries))
+	for txid, entry := range m.txEntries {
+		ai := make([]MempoolAddrIndex, len(entry.addrIndexes))
+		for i := range entry.addrIndexes {
+			ai[i] = MempoolAddrIndex{
+				AddrDesc: AddressDescriptor(entry.addrIndexes[i].addrDesc),
+				N:        entry.addrIndexes[i].n,
+			}
+		}
+		entries = append(entries, MempoolPersistedEntry{
+			Txid:        txid,
+			Time:        entry.time,
+			AddrIndexes: ai,
+		})
+	}
+	return entries
+}
+
+// ImportEntries adds previously persisted entries to the mempool, entries already present in the mempool are skipped
+// The imported entries are reconciled with the backend mempool in the next Resync
+// Returns number of imported entries
+func (m *BaseMempool) ImportEntries(entries []MempoolPersistedEntry) int {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	imported := 0
+	for i := range entries {
+		e := &entries[i]
+		if _, exists := m.txEntries[e.Txid]; exists || len(e.AddrIndexes) == 0 {
+			continue
+		}
+		entry := txEntry{
+			addrIndexes: make([]addrIndex, len(e.AddrIndexes)),
+			time:        e.Time,
+		}
+		for j := range e.AddrIndexes {
+			entry.addrIndexes[j] = addrIndex{string(e.AddrIndexes[j].AddrDesc), e.AddrIndexes[j].N}
+			m.addrDescToTx[entry.addrIndexes[j].addrDesc] = append(m.addrDescToTx[entry.addrIndexes[j].addrDesc], Outpoint{e.Txid, e.AddrIndexes[j].N})
+		}
+		m.txEntries[e.Txid] = entry
+		imported++
+	}
+	return imported
+}
+
 func (m *BaseMempool) txToMempoolTx(tx *Tx) *MempoolTx {
 	mtx := MempoolTx{
 		Hex:              tx.Hex,
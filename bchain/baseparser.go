@@ -18,6 +18,11 @@ type BaseParser struct {
 	AmountDecimalPoint   int
 }
 
+// GetScriptTypeFromVout returns empty string, script types are not recognized by default
+func (p *BaseParser) GetScriptTypeFromVout(output *Vout) string {
+	return ""
+}
+
 // ParseBlock parses raw block to our Block struct - currently not implemented
 func (p *BaseParser) ParseBlock(b []byte) (*Block, error) {
 	return nil, errors.New("ParseBlock: not implemented")
@@ -167,6 +172,11 @@ func (p *BaseParser) MinimumCoinbaseConfirmations() int {
 	return 0
 }
 
+// DustLimit returns nil, the dust limit is not known by default
+func (p *BaseParser) DustLimit(addrDesc AddressDescriptor) *big.Int {
+	return nil
+}
+
 // PackTx packs transaction to byte array using protobuf
 func (p *BaseParser) PackTx(tx *Tx, height uint32, blockTime int64) ([]byte, error) {
 	var err error
@@ -295,6 +305,11 @@ func (p *BaseParser) DeriveAddressDescriptorsFromTo(xpub string, change uint32,
 	return nil, errors.New("Not supported")
 }
 
+// CreatePsbt is unsupported
+func (p *BaseParser) CreatePsbt(xpub string, origin *XpubKeyOrigin, inputs []PsbtInput, outputs []PsbtOutput, lockTime uint32) ([]byte, error) {
+	return nil, errors.New("Not supported")
+}
+
 // EthereumTypeGetErc20FromTx is unsupported
 func (p *BaseParser) EthereumTypeGetErc20FromTx(tx *Tx) ([]Erc20Transfer, error) {
 	return nil, errors.New("Not supported")
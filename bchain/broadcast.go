@@ -0,0 +1,193 @@
+package bchain
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// maximum number of tracked broadcasts, new transactions are not tracked if the limit is reached
+const maxTrackedBroadcasts = 10000
+
+// BroadcastStatus is the state of a transaction sent to the backend
+type BroadcastStatus string
+
+const (
+	// BroadcastPending - the transaction was sent but its presence was not yet checked
+	BroadcastPending BroadcastStatus = "pending"
+	// BroadcastInMempool - the transaction is in the mempool
+	BroadcastInMempool BroadcastStatus = "mempool"
+	// BroadcastConfirmed - the transaction is in a block
+	BroadcastConfirmed BroadcastStatus = "confirmed"
+	// BroadcastMissing - the transaction disappeared from the mempool and is being rebroadcast
+	BroadcastMissing BroadcastStatus = "missing"
+	// BroadcastExpired - the transaction was not confirmed within the rebroadcast window
+	BroadcastExpired BroadcastStatus = "expired"
+)
+
+// BroadcastEntry holds the state of a transaction sent to the backend
+type BroadcastEntry struct {
+	Txid          string          `json:"txid"`
+	Status        BroadcastStatus `json:"status"`
+	FirstSent     time.Time       `json:"firstSent"`
+	LastSent      time.Time       `json:"lastSent"`
+	LastChecked   time.Time       `json:"lastChecked"`
+	Rebroadcasts  int             `json:"rebroadcasts"`
+	Confirmations uint32          `json:"confirmations,omitempty"`
+	LastError     string          `json:"lastError,omitempty"`
+	hex           string
+}
+
+// BroadcastTracker wraps BlockChain, records transactions sent by SendRawTransaction
+// and rebroadcasts them if they disappear from the mempool before they are confirmed
+type BroadcastTracker struct {
+	BlockChain
+	mempool       Mempool
+	confirmations TxConfirmationsFunc
+	window        time.Duration
+	mux           sync.Mutex
+	entries       map[string]*BroadcastEntry
+}
+
+// NewBroadcastTracker creates new BroadcastTracker, transactions are rebroadcast for the time window after they were first sent
+// The confirmations of the transactions are looked up in the index by the function confirmations, which can be nil
+func NewBroadcastTracker(chain BlockChain, mempool Mempool, confirmations TxConfirmationsFunc, window time.Duration) *BroadcastTracker {
+	return &BroadcastTracker{
+		BlockChain:    chain,
+		mempool:       mempool,
+		confirmations: confirmations,
+		window:        window,
+		entries:       make(map[string]*BroadcastEntry),
+	}
+}
+
+// SendRawTransaction sends raw transaction to the backend and starts tracking it
+func (t *BroadcastTracker) SendRawTransaction(tx string) (string, error) {
+	txid, err := t.BlockChain.SendRawTransaction(tx)
+	if err != nil {
+		return txid, err
+	}
+	now := time.Now()
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if e, found := t.entries[txid]; found {
+		e.LastSent = now
+	} else if len(t.entries) < maxTrackedBroadcasts {
+		t.entries[txid] = &BroadcastEntry{
+			Txid:      txid,
+			Status:    BroadcastPending,
+			FirstSent: now,
+			LastSent:  now,
+			hex:       tx,
+		}
+	} else {
+		glog.Warning("broadcast tracker: limit of tracked transactions reached, not tracking ", txid)
+	}
+	return txid, nil
+}
+
+// GetBackendErrors returns the errors of the RPC calls reported by the wrapped blockchain
+func (t *BroadcastTracker) GetBackendErrors() (int, string, time.Time) {
+	if be, ok := t.BlockChain.(BackendErrors); ok {
+		return be.GetBackendErrors()
+	}
+	return 0, "", time.Time{}
+}
+
+// GetBroadcasts returns the tracked transactions, optionally filtered by status, sorted from the newest
+func (t *BroadcastTracker) GetBroadcasts(status BroadcastStatus) []BroadcastEntry {
+	t.mux.Lock()
+	rv := make([]BroadcastEntry, 0, len(t.entries))
+	for _, e := range t.entries {
+		if status == "" || e.Status == status {
+			rv = append(rv, *e)
+		}
+	}
+	t.mux.Unlock()
+	sort.Slice(rv, func(i, j int) bool {
+		return rv[i].FirstSent.After(rv[j].FirstSent)
+	})
+	return rv
+}
+
+// checkEntry determines the current status of the transaction, rebroadcasting it if it is missing
+// The index and the mempool are checked first, the backend is asked only about the transactions found in neither of them
+func (t *BroadcastTracker) checkEntry(e BroadcastEntry, now time.Time) BroadcastEntry {
+	e.LastChecked = now
+	if t.confirmations != nil {
+		confirmations, err := t.confirmations(e.Txid)
+		if err != nil {
+			glog.Warning("broadcast tracker: index lookup of ", e.Txid, ": ", err)
+		} else if confirmations > 0 {
+			e.Status = BroadcastConfirmed
+			e.Confirmations = confirmations
+			return e
+		}
+	}
+	if t.mempool != nil && t.mempool.GetTransactionTime(e.Txid) != 0 {
+		e.Status = BroadcastInMempool
+		return e
+	}
+	tx, err := t.BlockChain.GetTransaction(e.Txid)
+	if err == nil {
+		if tx.Confirmations > 0 {
+			e.Status = BroadcastConfirmed
+			e.Confirmations = tx.Confirmations
+		} else {
+			e.Status = BroadcastInMempool
+		}
+		return e
+	}
+	if err != ErrTxNotFound {
+		e.LastError = err.Error()
+		return e
+	}
+	if e.FirstSent.Add(t.window).Before(now) {
+		e.Status = BroadcastExpired
+		return e
+	}
+	e.Status = BroadcastMissing
+	glog.Info("broadcast tracker: rebroadcasting missing transaction ", e.Txid)
+	if _, err = t.BlockChain.SendRawTransaction(e.hex); err != nil {
+		e.LastError = err.Error()
+	} else {
+		e.LastError = ""
+	}
+	e.LastSent = now
+	e.Rebroadcasts++
+	return e
+}
+
+// CheckBroadcasts checks presence of the tracked transactions in the mempool or blockchain and rebroadcasts the missing ones
+// Confirmed and expired transactions are removed after twice the rebroadcast window
+func (t *BroadcastTracker) CheckBroadcasts() {
+	now := time.Now()
+	t.mux.Lock()
+	toCheck := make([]BroadcastEntry, 0, len(t.entries))
+	for txid, e := range t.entries {
+		if e.FirstSent.Add(2 * t.window).Before(now) {
+			delete(t.entries, txid)
+		} else if e.Status != BroadcastConfirmed && e.Status != BroadcastExpired {
+			toCheck = append(toCheck, *e)
+		}
+	}
+	t.mux.Unlock()
+	// check without holding the lock, the checks call the backend
+	for i := range toCheck {
+		checked := t.checkEntry(toCheck[i], now)
+		t.mux.Lock()
+		if e, found := t.entries[checked.Txid]; found {
+			// keep the time of possible resend in between
+			if e.LastSent.After(checked.LastSent) {
+				checked.LastSent = e.LastSent
+			}
+			*e = checked
+		}
+		t.mux.Unlock()
+	}
+	if len(toCheck) > 0 {
+		glog.Info("broadcast tracker: checked ", len(toCheck), " transactions")
+	}
+}
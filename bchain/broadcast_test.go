@@ -0,0 +1,104 @@
+// +build unittest
+
+package bchain
+
+import (
+	"testing"
+	"time"
+)
+
+type testBroadcastChain struct {
+	BlockChain
+	sent          map[string]int
+	confirmations map[string]uint32
+}
+
+func (c *testBroadcastChain) SendRawTransaction(tx string) (string, error) {
+	c.sent[tx]++
+	return "txid-" + tx, nil
+}
+
+func (c *testBroadcastChain) GetTransaction(txid string) (*Tx, error) {
+	confirmations, found := c.confirmations[txid]
+	if !found {
+		return nil, ErrTxNotFound
+	}
+	return &Tx{Txid: txid, Confirmations: confirmations}, nil
+}
+
+func TestBroadcastTracker(t *testing.T) {
+	chain := &testBroadcastChain{
+		sent:          make(map[string]int),
+		confirmations: make(map[string]uint32),
+	}
+	bt := NewBroadcastTracker(chain, nil, nil, time.Hour)
+	for _, tx := range []string{"a", "b", "c"} {
+		if _, err := bt.SendRawTransaction(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chain.confirmations["txid-a"] = 0
+	chain.confirmations["txid-b"] = 2
+	// c is missing, it was sent long time ago and is outside of the rebroadcast window
+	bt.entries["txid-c"].FirstSent = time.Now().Add(-90 * time.Minute)
+
+	bt.CheckBroadcasts()
+	want := map[string]BroadcastStatus{"txid-a": BroadcastInMempool, "txid-b": BroadcastConfirmed, "txid-c": BroadcastExpired}
+	for _, e := range bt.GetBroadcasts("") {
+		if e.Status != want[e.Txid] {
+			t.Errorf("%v: status %v, want %v", e.Txid, e.Status, want[e.Txid])
+		}
+	}
+
+	// a disappears from the mempool and is rebroadcast
+	delete(chain.confirmations, "txid-a")
+	bt.CheckBroadcasts()
+	got := bt.GetBroadcasts(BroadcastMissing)
+	if len(got) != 1 || got[0].Txid != "txid-a" || got[0].Rebroadcasts != 1 || chain.sent["a"] != 2 {
+		t.Errorf("GetBroadcasts(missing) = %+v, sent %v", got, chain.sent)
+	}
+
+	// entries older than twice the window are removed
+	bt.entries["txid-b"].FirstSent = time.Now().Add(-3 * time.Hour)
+	bt.CheckBroadcasts()
+	if len(bt.GetBroadcasts("")) != 2 {
+		t.Errorf("GetBroadcasts() = %+v, want 2 entries", bt.GetBroadcasts(""))
+	}
+}
+
+type testBroadcastMempool struct {
+	Mempool
+	txs map[string]uint32
+}
+
+func (m *testBroadcastMempool) GetTransactionTime(txid string) uint32 {
+	return m.txs[txid]
+}
+
+func TestBroadcastTracker_IndexFirst(t *testing.T) {
+	chain := &testBroadcastChain{
+		sent:          make(map[string]int),
+		confirmations: make(map[string]uint32),
+	}
+	mempool := &testBroadcastMempool{txs: map[string]uint32{"txid-b": 1600000000}}
+	indexed := map[string]uint32{"txid-a": 3}
+	bt := NewBroadcastTracker(chain, mempool, func(txid string) (uint32, error) {
+		return indexed[txid], nil
+	}, time.Hour)
+	for _, tx := range []string{"a", "b"} {
+		if _, err := bt.SendRawTransaction(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the backend does not know the transactions, the tracker must not ask it about the indexed and mempool transactions
+	bt.CheckBroadcasts()
+	want := map[string]BroadcastEntry{
+		"txid-a": {Status: BroadcastConfirmed, Confirmations: 3},
+		"txid-b": {Status: BroadcastInMempool},
+	}
+	for _, e := range bt.GetBroadcasts("") {
+		if e.Status != want[e.Txid].Status || e.Confirmations != want[e.Txid].Confirmations || e.Rebroadcasts != 0 {
+			t.Errorf("%v: status %v, confirmations %d, rebroadcasts %d, want %v", e.Txid, e.Status, e.Confirmations, e.Rebroadcasts, want[e.Txid])
+		}
+	}
+}
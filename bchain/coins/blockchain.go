@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"math/big"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -34,6 +35,7 @@ import (
 	"github.com/scryptachain/blockbook-scrypta/bchain/coins/koto"
 	"github.com/scryptachain/blockbook-scrypta/bchain/coins/liquid"
 	"github.com/scryptachain/blockbook-scrypta/bchain/coins/litecoin"
+	"github.com/scryptachain/blockbook-scrypta/bchain/coins/localchain"
 	"github.com/scryptachain/blockbook-scrypta/bchain/coins/monacoin"
 	"github.com/scryptachain/blockbook-scrypta/bchain/coins/monetaryunit"
 	"github.com/scryptachain/blockbook-scrypta/bchain/coins/myriad"
@@ -123,6 +125,7 @@ func init() {
 	BlockChainFactories["Harcomia"] = harcomia.NewHarcomiaRPC
 	BlockChainFactories["Knoxfs"] = knoxfs.NewKnoxfsRPC
 	BlockChainFactories["Scrypta"] = scrypta.NewScryptaRPC
+	BlockChainFactories["Localchain"] = localchain.NewLocalChain
 }
 
 // GetCoinNameFromConfig gets coin name and coin shortcut from config file
@@ -162,20 +165,55 @@ func NewBlockChain(coin string, configfile string, pushHandler func(bchain.Notif
 	if err != nil {
 		return nil, nil, err
 	}
+	if mo, ok := bc.(metricsObserver); ok {
+		mo.SetMetrics(metrics)
+	}
 	err = bc.Initialize()
 	if err != nil {
 		return nil, nil, err
 	}
-	mempool, err := bc.CreateMempool(bc)
+	// the mempool calls the backend through the metrics wrapper, so that its calls reach the metrics and the health checks
+	chain := newBlockChainWithMetrics(bc, metrics)
+	mempool, err := bc.CreateMempool(chain)
 	if err != nil {
 		return nil, nil, err
 	}
-	return &blockChainWithMetrics{b: bc, m: metrics}, &mempoolWithMetrics{mempool: mempool, m: metrics}, nil
+	return chain, &mempoolWithMetrics{mempool: mempool, m: metrics}, nil
+}
+
+// metricsObserver is implemented by the blockchains which observe metrics of their backend calls themselves
+type metricsObserver interface {
+	SetMetrics(metrics *common.Metrics)
+}
+
+// newBlockChainWithMetrics wraps the blockchain to observe the metrics of the backend calls,
+// the wrapper can get the mempool transactions in batches only if the blockchain can
+func newBlockChainWithMetrics(bc bchain.BlockChain, metrics *common.Metrics) bchain.BlockChain {
+	c := &blockChainWithMetrics{b: bc, m: metrics}
+	if _, ok := bc.(bchain.MempoolTransactionsBatcher); ok {
+		return &batchingBlockChainWithMetrics{c}
+	}
+	return c
+}
+
+// batchingBlockChainWithMetrics is the metrics wrapper of the blockchains which get the mempool transactions in batches
+type batchingBlockChainWithMetrics struct {
+	*blockChainWithMetrics
+}
+
+func (c *batchingBlockChainWithMetrics) GetTransactionsForMempool(txids []string) (v []*bchain.Tx, err error) {
+	defer func(s time.Time) { c.observeRPCLatency("GetTransactionsForMempool", s, err) }(time.Now())
+	return c.b.(bchain.MempoolTransactionsBatcher).GetTransactionsForMempool(txids)
 }
 
 type blockChainWithMetrics struct {
 	b bchain.BlockChain
 	m *common.Metrics
+	// errors of the backend calls for the health checks
+	errorsLock        sync.Mutex
+	consecutiveErrors int
+	lastError         string
+	lastErrorTime     time.Time
 }
 
 func (c *blockChainWithMetrics) observeRPCLatency(method string, start time.Time, err error) {
@@ -183,9 +221,51 @@ func (c *blockChainWithMetrics) observeRPCLatency(method string, start time.Time
 	if err != nil {
 		e = "failure"
 	}
+	// not found errors and error replies are caused by the requests, not by the backend
+	if !isRequestError(err) {
+		c.errorsLock.Lock()
+		if err != nil {
+			c.consecutiveErrors++
+			c.lastError = err.Error()
+			c.lastErrorTime = time.Now()
+		} else {
+			c.consecutiveErrors = 0
+		}
+		c.errorsLock.Unlock()
+	}
 	c.m.RPCLatency.With(common.Labels{"method": method, "error": e}).Observe(float64(time.Since(start)) / 1e6) // in milliseconds
 }
 
+// isRequestError returns true if the error is a reply of the backend to an invalid request,
+// these errors do not show that the backend is failing
+func isRequestError(err error) bool {
+	if err == bchain.ErrTxNotFound || err == bchain.ErrBlockNotFound {
+		return true
+	}
+	switch errors.Cause(err).(type) {
+	case *bchain.RPCError:
+		return true
+	case interface{ ErrorCode() int }:
+		// error replies of the ethereum rpc client
+		return true
+	}
+	return false
+}
+
+// GetBackendErrors returns the number of consecutive failed RPC calls, the last error and its time
+func (c *blockChainWithMetrics) GetBackendErrors() (int, string, time.Time) {
+	c.errorsLock.Lock()
+	defer c.errorsLock.Unlock()
+	return c.consecutiveErrors, c.lastError, c.lastErrorTime
+}
+
+// SetIndexedBlocks passes the index to the blockchain if it can connect to multiple backends
+func (c *blockChainWithMetrics) SetIndexedBlocks(index bchain.IndexedBlocks) {
+	if mb, ok := c.b.(bchain.MultipleBackends); ok {
+		mb.SetIndexedBlocks(index)
+	}
+}
+
 func (c *blockChainWithMetrics) Initialize() error {
 	return c.b.Initialize()
 }
@@ -366,3 +446,18 @@ func (c *mempoolWithMetrics) GetAllEntries() (v bchain.MempoolTxidEntries) {
 func (c *mempoolWithMetrics) GetTransactionTime(txid string) uint32 {
 	return c.mempool.GetTransactionTime(txid)
 }
+
+func (c *mempoolWithMetrics) ExportEntries() []bchain.MempoolPersistedEntry {
+	return c.mempool.ExportEntries()
+}
+
+func (c *mempoolWithMetrics) ImportEntries(entries []bchain.MempoolPersistedEntry) int {
+	return c.mempool.ImportEntries(entries)
+}
+
+// SetWorkers passes the number of sync workers to the mempool if it supports the change at runtime
+func (c *mempoolWithMetrics) SetWorkers(workers int, subworkers int) {
+	if m, ok := c.mempool.(bchain.MempoolWorkersSetter); ok {
+		m.SetWorkers(workers, subworkers)
+	}
+}
@@ -0,0 +1,105 @@
+// +build unittest
+
+package coins
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+	"github.com/scryptachain/blockbook-scrypta/common"
+)
+
+type testWorkersMempool struct {
+	bchain.Mempool
+	workers, subworkers int
+}
+
+func (m *testWorkersMempool) SetWorkers(workers int, subworkers int) {
+	m.workers, m.subworkers = workers, subworkers
+}
+
+func TestMempoolWithMetrics_SetWorkers(t *testing.T) {
+	inner := &testWorkersMempool{}
+	var mempool bchain.Mempool = &mempoolWithMetrics{mempool: inner}
+	s, ok := mempool.(bchain.MempoolWorkersSetter)
+	if !ok {
+		t.Fatal("mempoolWithMetrics does not implement MempoolWorkersSetter")
+	}
+	s.SetWorkers(4, 3)
+	if inner.workers != 4 || inner.subworkers != 3 {
+		t.Errorf("mempool workers %d*%d, want 4*3", inner.workers, inner.subworkers)
+	}
+}
+
+func TestBlockChainWithMetrics_BackendErrors(t *testing.T) {
+	m, err := common.GetMetrics("Testnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &blockChainWithMetrics{m: m}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"transport error", errors.New("connection refused"), 1},
+		{"daemon error", errors.Annotatef(errors.New("EOF"), "hash %v", "abcd"), 2},
+		{"rpc error reply", &bchain.RPCError{Code: -25, Message: "Missing inputs"}, 2},
+		{"annotated rpc error reply", errors.Annotatef(&bchain.RPCError{Code: -8, Message: "Invalid parameter"}, "txid %v", "abcd"), 2},
+		{"not found", bchain.ErrTxNotFound, 2},
+		{"success", nil, 0},
+	}
+	for _, tt := range tests {
+		c.observeRPCLatency("Test", time.Now(), tt.err)
+		if n, _, _ := c.GetBackendErrors(); n != tt.want {
+			t.Errorf("%s: consecutive errors %d, want %d", tt.name, n, tt.want)
+		}
+	}
+}
+
+type testPlainChain struct {
+	bchain.BlockChain
+}
+
+type testBatchingChain struct {
+	bchain.BlockChain
+	err error
+}
+
+func (c *testBatchingChain) GetTransactionsForMempool(txids []string) ([]*bchain.Tx, error) {
+	return make([]*bchain.Tx, len(txids)), c.err
+}
+
+func TestBlockChainWithMetrics_Batcher(t *testing.T) {
+	m := &common.Metrics{
+		RPCLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_rpc_latency"}, []string{"method", "error"}),
+	}
+	if _, ok := newBlockChainWithMetrics(&testPlainChain{}, m).(bchain.MempoolTransactionsBatcher); ok {
+		t.Error("wrapper of a chain without batches implements MempoolTransactionsBatcher")
+	}
+	inner := &testBatchingChain{err: errors.New("connection refused")}
+	chain := newBlockChainWithMetrics(inner, m)
+	b, ok := chain.(bchain.MempoolTransactionsBatcher)
+	if !ok {
+		t.Fatal("wrapper of a chain with batches does not implement MempoolTransactionsBatcher")
+	}
+	if _, err := b.GetTransactionsForMempool([]string{"a", "b"}); err == nil {
+		t.Fatal("error of the batch not returned")
+	}
+	// the failed batch reaches the health checks
+	if n, lastError, _ := chain.(interface {
+		GetBackendErrors() (int, string, time.Time)
+	}).GetBackendErrors(); n != 1 || lastError != "connection refused" {
+		t.Errorf("backend errors %d %q, want 1 \"connection refused\"", n, lastError)
+	}
+	inner.err = nil
+	if txs, err := b.GetTransactionsForMempool([]string{"a", "b"}); err != nil || len(txs) != 2 {
+		t.Fatalf("GetTransactionsForMempool = %v, %v", txs, err)
+	}
+	if n, _, _ := chain.(*batchingBlockChainWithMetrics).GetBackendErrors(); n != 0 {
+		t.Errorf("backend errors %d, want 0", n)
+	}
+}
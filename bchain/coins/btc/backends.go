@@ -0,0 +1,330 @@
+package btc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+)
+
+// default interval of probing of the backends in seconds
+const defaultBackendProbeInterval = 10
+
+// timeout of the shutdown of the ZeroMQ connection to the previous backend
+const mqShutdownTimeout = 5 * time.Second
+
+// rpcBackend is one of the backends used by BitcoinRPC
+type rpcBackend struct {
+	url       string
+	mqBinding string
+	healthy   bool
+	lastError string
+}
+
+// rpcBackends keeps the state of the backends of BitcoinRPC
+// All calls go to the active backend, the backends are preferred in the configured order
+type rpcBackends struct {
+	lock          sync.Mutex
+	backends      []*rpcBackend
+	active        int
+	index         bchain.IndexedBlocks
+	probeInterval time.Duration
+	probeOnce     sync.Once
+	stop          chan struct{}
+}
+
+// newRPCBackends creates the backends, mqBindings are the ZeroMQ bindings of the backends in the same order as urls,
+// the backends without the binding keep the ZeroMQ connection of the previously active backend
+func newRPCBackends(urls []string, mqBindings []string, probeInterval int) *rpcBackends {
+	if probeInterval <= 0 {
+		probeInterval = defaultBackendProbeInterval
+	}
+	r := &rpcBackends{
+		probeInterval: time.Duration(probeInterval) * time.Second,
+		stop:          make(chan struct{}),
+	}
+	for i, url := range urls {
+		be := &rpcBackend{url: url, healthy: true}
+		if i < len(mqBindings) {
+			be.mqBinding = mqBindings[i]
+		}
+		r.backends = append(r.backends, be)
+	}
+	return r
+}
+
+func (r *rpcBackends) activeURL() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.backends[r.active].url
+}
+
+// healthyURLs returns the url of the active backend followed by the other healthy backends
+func (r *rpcBackends) healthyURLs() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	urls := []string{r.backends[r.active].url}
+	for i, be := range r.backends {
+		if i != r.active && be.healthy {
+			urls = append(urls, be.url)
+		}
+	}
+	return urls
+}
+
+// setHealth records the result of a call to the backend
+func (r *rpcBackends) setHealth(url string, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, be := range r.backends {
+		if be.url == url {
+			if err != nil {
+				if be.healthy {
+					glog.Warning("rpc: backend ", url, " is not healthy: ", err)
+				}
+				be.healthy = false
+				be.lastError = err.Error()
+			} else {
+				if !be.healthy {
+					glog.Info("rpc: backend ", url, " is healthy")
+				}
+				be.healthy = true
+				be.lastError = ""
+			}
+			return
+		}
+	}
+}
+
+// candidates returns the healthy backends in the preferred order, which are not the active backend,
+// if onlyPreferred is set, only the backends preferred to the active backend are returned
+func (r *rpcBackends) candidates(onlyPreferred bool) []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var urls []string
+	for i, be := range r.backends {
+		if i == r.active {
+			if onlyPreferred && be.healthy {
+				break
+			}
+			continue
+		}
+		if be.healthy {
+			urls = append(urls, be.url)
+		}
+	}
+	return urls
+}
+
+// SetIndexedBlocks sets the index used to refuse switching to a backend with a chain conflicting with the index
+func (b *BitcoinRPC) SetIndexedBlocks(index bchain.IndexedBlocks) {
+	if b.backends != nil {
+		b.backends.lock.Lock()
+		b.backends.index = index
+		b.backends.lock.Unlock()
+	}
+}
+
+// backendBestBlock returns the height and hash of the best block of the backend
+func (b *BitcoinRPC) backendBestBlock(url string) (uint32, string, error) {
+	httpData, err := b.RPCMarshaler.Marshal(&CmdGetBlockCount{Method: "getblockcount"})
+	if err != nil {
+		return 0, "", err
+	}
+	resCount := ResGetBlockCount{}
+	if _, err = b.call(url, httpData, &resCount); err != nil {
+		return 0, "", err
+	}
+	if resCount.Error != nil {
+		return 0, "", resCount.Error
+	}
+	hash, err := b.backendBlockHash(url, resCount.Result)
+	if err != nil {
+		return 0, "", err
+	}
+	return resCount.Result, hash, nil
+}
+
+// backendBlockHash returns the hash of the block of the backend at the height
+func (b *BitcoinRPC) backendBlockHash(url string, height uint32) (string, error) {
+	req := CmdGetBlockHash{Method: "getblockhash"}
+	req.Params.Height = height
+	httpData, err := b.RPCMarshaler.Marshal(&req)
+	if err != nil {
+		return "", err
+	}
+	res := ResGetBlockHash{}
+	if _, err = b.call(url, httpData, &res); err != nil {
+		return "", err
+	}
+	if res.Error != nil {
+		return "", res.Error
+	}
+	return res.Result, nil
+}
+
+// checkBackend checks that the backend responds and that its chain does not conflict with the index,
+// the blocks are compared at the lower of the best heights of the backend and of the index
+func (b *BitcoinRPC) checkBackend(url string) error {
+	height, hash, err := b.backendBestBlock(url)
+	if err != nil {
+		return err
+	}
+	b.backends.lock.Lock()
+	index := b.backends.index
+	b.backends.lock.Unlock()
+	if index == nil {
+		return nil
+	}
+	indexedHeight, indexedHash, err := index.GetBestBlock()
+	if err != nil || indexedHash == "" {
+		return err
+	}
+	if height > indexedHeight {
+		// the backend is ahead of the index, it must contain the best indexed block
+		height = indexedHeight
+		if hash, err = b.backendBlockHash(url, height); err != nil {
+			return err
+		}
+	} else if indexedHash, err = index.GetBlockHash(height); err != nil {
+		return err
+	}
+	if indexedHash != "" && indexedHash != hash {
+		return errors.Errorf("Block %d %s conflicts with the indexed block %s", height, hash, indexedHash)
+	}
+	return nil
+}
+
+// switchBackend tries to switch to the first of the candidate backends which passes checkBackend, returns its url or empty string
+func (b *BitcoinRPC) switchBackend(candidates []string) string {
+	r := b.backends
+	for _, url := range candidates {
+		if err := b.checkBackend(url); err != nil {
+			glog.Warning("rpc: cannot switch to backend ", url, ": ", err)
+			continue
+		}
+		var mqBinding string
+		r.lock.Lock()
+		for i, be := range r.backends {
+			if be.url == url {
+				r.active = i
+				mqBinding = be.mqBinding
+			}
+		}
+		r.lock.Unlock()
+		glog.Warning("rpc: switched to backend ", url)
+		// the new backend may support the batch requests
+		atomic.StoreInt32(&b.batchUnsupported, 0)
+		if mqBinding != "" {
+			b.reconnectMQ(mqBinding)
+		}
+		// the new backend can be at a different block, synchronize the index
+		if b.pushHandler != nil {
+			b.pushHandler(bchain.NotificationNewBlock)
+		}
+		return url
+	}
+	return ""
+}
+
+// reconnectMQ moves the ZeroMQ subscription to the binding of the new backend,
+// the subscription is created only by InitializeMempool, before that only the binding is changed
+func (b *BitcoinRPC) reconnectMQ(binding string) {
+	b.mqLock.Lock()
+	defer b.mqLock.Unlock()
+	if b.mqBinding == binding {
+		return
+	}
+	b.mqBinding = binding
+	if b.mq == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), mqShutdownTimeout)
+	defer cancel()
+	if err := b.mq.Shutdown(ctx); err != nil {
+		glog.Error("mq: shutdown of the previous connection: ", err)
+	}
+	mq, err := bchain.NewMQ(binding, b.pushHandler)
+	if err != nil {
+		// without notifications the index is synchronized only periodically
+		glog.Error("mq: cannot connect to ", binding, ": ", err)
+		b.mq = nil
+		return
+	}
+	b.mq = mq
+}
+
+// failover marks the unreachable backend as unhealthy and returns the url of the backend to use instead, empty string if there is none
+func (b *BitcoinRPC) failover(url string, cause error) string {
+	r := b.backends
+	r.setHealth(url, cause)
+	// another call may have already switched the backend
+	if active := r.activeURL(); active != url {
+		return active
+	}
+	return b.switchBackend(r.candidates(false))
+}
+
+// probeBackends periodically checks the health of the backends and switches back to the preferred backend once it recovers
+func (b *BitcoinRPC) probeBackends() {
+	r := b.backends
+	ticker := time.NewTicker(r.probeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+		// the urls of the backends do not change, they can be read without the lock
+		for _, be := range r.backends {
+			_, _, err := b.backendBestBlock(be.url)
+			r.setHealth(be.url, err)
+		}
+		if candidates := r.candidates(true); len(candidates) > 0 {
+			b.switchBackend(candidates)
+		}
+	}
+}
+
+// sendRawTransactionToAll sends the transaction to all healthy backends, it succeeds if any of the backends accepts it
+func (b *BitcoinRPC) sendRawTransactionToAll(req *CmdSendRawTransaction) (string, error) {
+	httpData, err := b.RPCMarshaler.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	urls := b.backends.healthyURLs()
+	type result struct {
+		txid string
+		err  error
+	}
+	results := make([]result, len(urls))
+	var wg sync.WaitGroup
+	for i := range urls {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res := ResSendRawTransaction{}
+			_, err := b.call(urls[i], httpData, &res)
+			if err == nil && res.Error != nil {
+				err = res.Error
+			}
+			results[i] = result{txid: res.Result, err: err}
+		}(i)
+	}
+	wg.Wait()
+	for i := range results {
+		if results[i].err == nil {
+			return results[i].txid, nil
+		}
+	}
+	for i := range results {
+		glog.V(1).Info("rpc: sendrawtransaction to backend ", urls[i], " failed: ", results[i].err)
+	}
+	// report the error of the active backend
+	return "", results[0].err
+}
@@ -31,8 +31,12 @@ type BitcoinParser struct {
 	XPubMagicSegwitNative        uint32
 	Slip44                       uint32
 	minimumCoinbaseConfirmations int
+	dustRelayFee                 int64
 }
 
+// default fee rate in satoshi per kB used to compute the dust limit, DUST_RELAY_TX_FEE of bitcoind
+const defaultDustRelayFee = 3000
+
 // NewBitcoinParser returns new BitcoinParser instance
 func NewBitcoinParser(params *chaincfg.Params, c *Configuration) *BitcoinParser {
 	p := &BitcoinParser{
@@ -46,6 +50,10 @@ func NewBitcoinParser(params *chaincfg.Params, c *Configuration) *BitcoinParser
 		XPubMagicSegwitNative:        c.XPubMagicSegwitNative,
 		Slip44:                       c.Slip44,
 		minimumCoinbaseConfirmations: c.MinimumCoinbaseConfirmations,
+		dustRelayFee:                 c.DustRelayFee,
+	}
+	if p.dustRelayFee <= 0 {
+		p.dustRelayFee = defaultDustRelayFee
 	}
 	p.OutputScriptToAddressesFunc = p.outputScriptToAddresses
 	return p
@@ -102,6 +110,15 @@ func (p *BitcoinParser) IsAddrDescIndexable(addrDesc bchain.AddressDescriptor) b
 	return true
 }
 
+// GetScriptTypeFromVout returns the class of the output script (pubkeyhash, scripthash, nulldata etc.)
+func (p *BitcoinParser) GetScriptTypeFromVout(output *bchain.Vout) string {
+	script, err := hex.DecodeString(output.ScriptPubKey.Hex)
+	if err != nil {
+		return ""
+	}
+	return txscript.GetScriptClass(script).String()
+}
+
 // addressToOutputScript converts bitcoin address to ScriptPubKey
 func (p *BitcoinParser) addressToOutputScript(address string) ([]byte, error) {
 	da, err := btcutil.DecodeAddress(address, p.Params)
@@ -333,6 +350,23 @@ func (p *BitcoinParser) MinimumCoinbaseConfirmations() int {
 	return p.minimumCoinbaseConfirmations
 }
 
+// DustLimit returns the cost of spending the output at the dust relay fee rate, computed the same way as by bitcoind,
+// the outputs with lower value are not relayed, the unspendable OP_RETURN outputs do not have a dust limit
+func (p *BitcoinParser) DustLimit(addrDesc bchain.AddressDescriptor) *big.Int {
+	if len(addrDesc) == 0 || addrDesc[0] == txscript.OP_RETURN {
+		return big.NewInt(0)
+	}
+	// the output: value, script length and script
+	size := 8 + wire.VarIntSerializeSize(uint64(len(addrDesc))) + len(addrDesc)
+	// the input spending it: outpoint, script length, sequence and the typical signature script or witness
+	if txscript.IsWitnessProgram(addrDesc) {
+		size += 32 + 4 + 1 + 107/4 + 4
+	} else {
+		size += 32 + 4 + 1 + 107 + 4
+	}
+	return big.NewInt(int64(size) * p.dustRelayFee / 1000)
+}
+
 func (p *BitcoinParser) addrDescFromExtKey(extKey *hdkeychain.ExtendedKey) (bchain.AddressDescriptor, error) {
 	var a btcutil.Address
 	var err error
@@ -3,12 +3,14 @@
 package btc
 
 import (
+	"bytes"
 	"encoding/hex"
 	"math/big"
 	"os"
 	"reflect"
 	"testing"
 
+	"github.com/martinboehm/btcd/wire"
 	"github.com/martinboehm/btcutil/chaincfg"
 	"github.com/scryptachain/blockbook-scrypta/bchain"
 )
@@ -141,6 +143,60 @@ func TestGetAddrDescFromVout(t *testing.T) {
 	}
 }
 
+func TestGetScriptTypeFromVout(t *testing.T) {
+	tests := []struct {
+		name string
+		hex  string
+		want string
+	}{
+		{name: "P2PKH", hex: "76a914be027bf3eac907bd4ac8cb9c5293b6f37662722088ac", want: "pubkeyhash"},
+		{name: "P2PK", hex: "21020e46e79a2a8d12b9b5d12c7a91adb4e454edfae43c0a0cb805427d2ac7613fd9ac", want: "pubkey"},
+		{name: "P2SH", hex: "a9140394b3cf9a44782c10105b93962daa8dba304d7f87", want: "scripthash"},
+		{name: "P2WPKH", hex: "00141c12afc6b2602607fdbc209f2a053c54ecd2c673", want: "witness_v0_keyhash"},
+		{name: "P2WSH", hex: "002003973a40ec94c0d10f6f6f0e7a62ba2044b7d19db6ff2bf60651e17fb29d8d29", want: "witness_v0_scripthash"},
+		{name: "OP_RETURN", hex: "6a072020f1686f6a20", want: "nulldata"},
+		{name: "invalid hex", hex: "xyz", want: ""},
+	}
+	parser := NewBitcoinParser(GetChainParams("main"), &Configuration{})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parser.GetScriptTypeFromVout(&bchain.Vout{ScriptPubKey: bchain.ScriptPubKey{Hex: tt.hex}})
+			if got != tt.want {
+				t.Errorf("GetScriptTypeFromVout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDustLimit(t *testing.T) {
+	tests := []struct {
+		name         string
+		hex          string
+		dustRelayFee int64
+		want         int64
+	}{
+		{name: "P2PKH", hex: "76a914be027bf3eac907bd4ac8cb9c5293b6f37662722088ac", want: 546},
+		{name: "P2SH", hex: "a9140394b3cf9a44782c10105b93962daa8dba304d7f87", want: 540},
+		{name: "P2WPKH", hex: "00141c12afc6b2602607fdbc209f2a053c54ecd2c673", want: 294},
+		{name: "P2WSH", hex: "002003973a40ec94c0d10f6f6f0e7a62ba2044b7d19db6ff2bf60651e17fb29d8d29", want: 330},
+		{name: "OP_RETURN", hex: "6a072020f1686f6a20", want: 0},
+		{name: "P2PKH with dust relay fee", hex: "76a914be027bf3eac907bd4ac8cb9c5293b6f37662722088ac", dustRelayFee: 1000, want: 182},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewBitcoinParser(GetChainParams("main"), &Configuration{DustRelayFee: tt.dustRelayFee})
+			ad, err := hex.DecodeString(tt.hex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := parser.DustLimit(ad); got.Int64() != tt.want {
+				t.Errorf("DustLimit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetAddressesFromAddrDesc(t *testing.T) {
 	type args struct {
 		script string
@@ -680,3 +736,86 @@ func TestBitcoinParser_DerivationBasePath(t *testing.T) {
 		})
 	}
 }
+
+// readPsbtMaps splits PSBT to maps of key-value pairs, the first map is global, followed by the input and output maps
+func readPsbtMaps(t *testing.T, b []byte) []map[string]string {
+	if !bytes.HasPrefix(b, psbtMagic) {
+		t.Fatal("missing PSBT magic")
+	}
+	r := bytes.NewReader(b[len(psbtMagic):])
+	var maps []map[string]string
+	m := make(map[string]string)
+	for r.Len() > 0 {
+		k, err := wire.ReadVarBytes(r, 0, 1000, "key")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(k) == 0 {
+			maps = append(maps, m)
+			m = make(map[string]string)
+			continue
+		}
+		v, err := wire.ReadVarBytes(r, 0, 100000, "value")
+		if err != nil {
+			t.Fatal(err)
+		}
+		m[hex.EncodeToString(k)] = hex.EncodeToString(v)
+	}
+	return maps
+}
+
+func TestCreatePsbt(t *testing.T) {
+	// BIP84 test vectors, master key fingerprint 73c5da0a
+	btcMainParser := NewBitcoinParser(GetChainParams("main"), &Configuration{XPubMagic: 76067358, XPubMagicSegwitP2sh: 77429938, XPubMagicSegwitNative: 78792518})
+	xpub := "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+	inputAddrDesc, _ := btcMainParser.GetAddrDescFromAddress("bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu")
+	changeAddrDesc, _ := btcMainParser.GetAddrDescFromAddress("bc1q8c6fshw2dlwun7ekn9qwf37cu2rn755upcp6el")
+	outputAddrDesc, _ := btcMainParser.GetAddrDescFromAddress("1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2")
+	origin := &bchain.XpubKeyOrigin{Fingerprint: 0x73c5da0a, Path: []uint32{0x80000054, 0x80000000, 0x80000000}}
+	inputs := []bchain.PsbtInput{{
+		Txid:     "fdeb2e0f3c3a7ab5a3f2c4e1b5e9a5f4d8b3c1e9e7d6f5a4b3c2d1e0f9e8d7c6",
+		Vout:     1,
+		Sequence: 0xfffffffd,
+		ValueSat: *big.NewInt(100000),
+		AddrDesc: inputAddrDesc,
+	}}
+	outputs := []bchain.PsbtOutput{
+		{ValueSat: *big.NewInt(60000), AddrDesc: outputAddrDesc},
+		{ValueSat: *big.NewInt(39000), AddrDesc: changeAddrDesc, IsChange: true, Change: 1, Index: 0},
+	}
+	b, err := btcMainParser.CreatePsbt(xpub, origin, inputs, outputs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	maps := readPsbtMaps(t, b)
+	if len(maps) != 4 {
+		t.Fatalf("got %d maps, want 4", len(maps))
+	}
+	var tx wire.MsgTx
+	utx, _ := hex.DecodeString(maps[0]["00"])
+	if err := tx.Deserialize(bytes.NewReader(utx)); err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.TxIn) != 1 || tx.TxIn[0].PreviousOutPoint.String() != inputs[0].Txid+":1" || tx.TxIn[0].Sequence != 0xfffffffd || len(tx.TxOut) != 2 || tx.TxOut[1].Value != 39000 {
+		t.Errorf("unexpected unsigned transaction %+v", tx)
+	}
+	want := []map[string]string{
+		{
+			"01": "a086010000000000" + "16" + hex.EncodeToString(inputAddrDesc),
+			"06" + "0330d54fd0dd420a6e5f8d3624f5f3482cae350f79d5f0753bf5beef9c2d91af3c": "73c5da0a" + "54000080" + "00000080" + "00000080" + "00000000" + "00000000",
+		},
+		{},
+		{
+			"02" + "03025324888e429ab8e3dbaf1f7802648b9cd01e9b418485c5fa4c1b9b5700e1a6": "73c5da0a" + "54000080" + "00000080" + "00000080" + "01000000" + "00000000",
+		},
+	}
+	if !reflect.DeepEqual(maps[1:], want) {
+		t.Errorf("CreatePsbt() maps = %v, want %v", maps[1:], want)
+	}
+
+	// the change output does not belong to the xpub
+	outputs[1].Index = 1
+	if _, err = btcMainParser.CreatePsbt(xpub, origin, inputs, outputs, 0); err == nil {
+		t.Error("CreatePsbt() expected error for foreign change address")
+	}
+}
@@ -11,6 +11,9 @@ import (
 	"net"
 	"net/http"
 	"runtime/debug"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/glog"
@@ -25,40 +28,51 @@ type BitcoinRPC struct {
 	*bchain.BaseChain
 	client       http.Client
 	rpcURL       string
+	backends     *rpcBackends
 	user         string
 	password     string
 	Mempool      *bchain.MempoolBitcoinType
 	ParseBlocks  bool
 	pushHandler  func(bchain.NotificationType)
 	mq           *bchain.MQ
+	mqBinding    string
+	mqLock       sync.Mutex
 	ChainConfig  *Configuration
 	RPCMarshaler RPCMarshaler
+	metrics      *common.Metrics
+	// set to 1 if the backend rejects batch requests, the requests are then sent one by one
+	batchUnsupported int32
 }
 
 // Configuration represents json config file
 type Configuration struct {
-	CoinName                     string `json:"coin_name"`
-	CoinShortcut                 string `json:"coin_shortcut"`
-	RPCURL                       string `json:"rpc_url"`
-	RPCUser                      string `json:"rpc_user"`
-	RPCPass                      string `json:"rpc_pass"`
-	RPCTimeout                   int    `json:"rpc_timeout"`
-	Parse                        bool   `json:"parse"`
-	MessageQueueBinding          string `json:"message_queue_binding"`
-	Subversion                   string `json:"subversion"`
-	BlockAddressesToKeep         int    `json:"block_addresses_to_keep"`
-	MempoolWorkers               int    `json:"mempool_workers"`
-	MempoolSubWorkers            int    `json:"mempool_sub_workers"`
-	AddressFormat                string `json:"address_format"`
-	SupportsEstimateFee          bool   `json:"supports_estimate_fee"`
-	SupportsEstimateSmartFee     bool   `json:"supports_estimate_smart_fee"`
-	XPubMagic                    uint32 `json:"xpub_magic,omitempty"`
-	XPubMagicSegwitP2sh          uint32 `json:"xpub_magic_segwit_p2sh,omitempty"`
-	XPubMagicSegwitNative        uint32 `json:"xpub_magic_segwit_native,omitempty"`
-	Slip44                       uint32 `json:"slip44,omitempty"`
-	AlternativeEstimateFee       string `json:"alternative_estimate_fee,omitempty"`
-	AlternativeEstimateFeeParams string `json:"alternative_estimate_fee_params,omitempty"`
-	MinimumCoinbaseConfirmations int    `json:"minimumCoinbaseConfirmations,omitempty"`
+	CoinName                     string   `json:"coin_name"`
+	CoinShortcut                 string   `json:"coin_shortcut"`
+	RPCURL                       string   `json:"rpc_url"`
+	RPCUser                      string   `json:"rpc_user"`
+	RPCPass                      string   `json:"rpc_pass"`
+	RPCTimeout                   int      `json:"rpc_timeout"`
+	RPCURLs                      []string `json:"rpc_urls,omitempty"`
+	RPCBackendProbeInterval      int      `json:"rpc_backend_probe_interval,omitempty"`
+	RPCBatchSize                 int      `json:"rpc_batch_size,omitempty"`
+	Parse                        bool     `json:"parse"`
+	MessageQueueBinding          string   `json:"message_queue_binding"`
+	MessageQueueBindings         []string `json:"message_queue_bindings,omitempty"`
+	Subversion                   string   `json:"subversion"`
+	BlockAddressesToKeep         int      `json:"block_addresses_to_keep"`
+	MempoolWorkers               int      `json:"mempool_workers"`
+	MempoolSubWorkers            int      `json:"mempool_sub_workers"`
+	AddressFormat                string   `json:"address_format"`
+	SupportsEstimateFee          bool     `json:"supports_estimate_fee"`
+	SupportsEstimateSmartFee     bool     `json:"supports_estimate_smart_fee"`
+	XPubMagic                    uint32   `json:"xpub_magic,omitempty"`
+	XPubMagicSegwitP2sh          uint32   `json:"xpub_magic_segwit_p2sh,omitempty"`
+	XPubMagicSegwitNative        uint32   `json:"xpub_magic_segwit_native,omitempty"`
+	Slip44                       uint32   `json:"slip44,omitempty"`
+	AlternativeEstimateFee       string   `json:"alternative_estimate_fee,omitempty"`
+	AlternativeEstimateFeeParams string   `json:"alternative_estimate_fee_params,omitempty"`
+	MinimumCoinbaseConfirmations int      `json:"minimumCoinbaseConfirmations,omitempty"`
+	DustRelayFee                 int64    `json:"dust_relay_fee,omitempty"`
 }
 
 // NewBitcoinRPC returns new BitcoinRPC instance.
@@ -84,6 +98,10 @@ func NewBitcoinRPC(config json.RawMessage, pushHandler func(bchain.NotificationT
 	if c.MempoolSubWorkers < 1 {
 		c.MempoolSubWorkers = 1
 	}
+	// default maximum number of requests in one batch
+	if c.RPCBatchSize < 1 {
+		c.RPCBatchSize = 100
+	}
 	// btc supports both calls, other coins overriding BitcoinRPC can change this
 	c.SupportsEstimateFee = true
 	c.SupportsEstimateSmartFee = true
@@ -105,6 +123,11 @@ func NewBitcoinRPC(config json.RawMessage, pushHandler func(bchain.NotificationT
 		pushHandler:  pushHandler,
 		RPCMarshaler: JSONMarshalerV2{},
 	}
+	if len(c.RPCURLs) > 0 {
+		// rpc_url is the preferred backend, rpc_urls are used when it is not available
+		s.backends = newRPCBackends(append([]string{c.RPCURL}, c.RPCURLs...),
+			append([]string{c.MessageQueueBinding}, c.MessageQueueBindings...), c.RPCBackendProbeInterval)
+	}
 
 	return s, nil
 }
@@ -162,8 +185,13 @@ func (b *BitcoinRPC) InitializeMempool(addrDescForOutpoint bchain.AddrDescForOut
 	b.Mempool.AddrDescForOutpoint = addrDescForOutpoint
 	b.Mempool.OnNewTxAddr = onNewTxAddr
 	b.Mempool.OnNewTx = onNewTx
+	b.mqLock.Lock()
+	defer b.mqLock.Unlock()
 	if b.mq == nil {
-		mq, err := bchain.NewMQ(b.ChainConfig.MessageQueueBinding, b.pushHandler)
+		if b.mqBinding == "" {
+			b.mqBinding = b.ChainConfig.MessageQueueBinding
+		}
+		mq, err := bchain.NewMQ(b.mqBinding, b.pushHandler)
 		if err != nil {
 			glog.Error("mq: ", err)
 			return err
@@ -175,8 +203,16 @@ func (b *BitcoinRPC) InitializeMempool(addrDescForOutpoint bchain.AddrDescForOut
 
 // Shutdown ZeroMQ and other resources
 func (b *BitcoinRPC) Shutdown(ctx context.Context) error {
+	if b.backends != nil {
+		close(b.backends.stop)
+	}
+	b.mqLock.Lock()
+	defer b.mqLock.Unlock()
 	if b.mq != nil {
-		if err := b.mq.Shutdown(ctx); err != nil {
+		mq := b.mq
+		// the subscription is not created again by a later switch of the backend
+		b.mq = nil
+		if err := mq.Shutdown(ctx); err != nil {
 			glog.Error("MQ.Shutdown error: ", err)
 			return err
 		}
@@ -184,6 +220,11 @@ func (b *BitcoinRPC) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// SetMetrics sets the metrics used to observe the batch requests
+func (b *BitcoinRPC) SetMetrics(metrics *common.Metrics) {
+	b.metrics = metrics
+}
+
 // GetCoinName returns the coin name
 func (b *BitcoinRPC) GetCoinName() string {
 	return b.ChainConfig.CoinName
@@ -736,6 +777,80 @@ func (b *BitcoinRPC) GetTransactionSpecific(tx *bchain.Tx) (json.RawMessage, err
 	return b.getRawTransaction(tx.Txid)
 }
 
+// GetTransactions returns the transactions using batched getrawtransaction requests,
+// the transactions not found by the backend are returned as nil
+// If the batch fails, the transactions are requested one by one. The batches are not used any more
+// only if the backend rejected the format of the batch, not after a transport error or a timeout.
+func (b *BitcoinRPC) GetTransactions(txids []string) ([]*bchain.Tx, error) {
+	if atomic.LoadInt32(&b.batchUnsupported) != 0 {
+		return b.getTransactionsOneByOne(txids)
+	}
+	txs, err := b.getTransactionsBatch(txids)
+	if err == nil {
+		return txs, nil
+	}
+	glog.Warning("rpc: getrawtransaction batch of ", len(txids), " txs failed: ", err, ", requesting the txs one by one")
+	rejected := errors.Cause(err) == errBatchRejected
+	txs, err = b.getTransactionsOneByOne(txids)
+	if err != nil {
+		return nil, err
+	}
+	// the backend works but does not accept the batches
+	if rejected && atomic.CompareAndSwapInt32(&b.batchUnsupported, 0, 1) {
+		glog.Warning("rpc: the backend does not support batch requests, requests are sent one by one")
+	}
+	return txs, nil
+}
+
+func (b *BitcoinRPC) getTransactionsOneByOne(txids []string) ([]*bchain.Tx, error) {
+	txs := make([]*bchain.Tx, len(txids))
+	for i, txid := range txids {
+		tx, err := b.GetTransaction(txid)
+		if err != nil {
+			if err == bchain.ErrTxNotFound {
+				continue
+			}
+			return nil, err
+		}
+		txs[i] = tx
+	}
+	return txs, nil
+}
+
+func (b *BitcoinRPC) getTransactionsBatch(txids []string) ([]*bchain.Tx, error) {
+	glog.V(1).Info("rpc: getrawtransaction batch of ", len(txids), " txs")
+
+	req := make([]interface{}, len(txids))
+	res := make([]interface{}, len(txids))
+	r := make([]ResGetRawTransaction, len(txids))
+	for i, txid := range txids {
+		c := &CmdGetRawTransaction{Method: "getrawtransaction"}
+		c.Params.Txid = txid
+		c.Params.Verbose = true
+		req[i] = c
+		res[i] = &r[i]
+	}
+	if err := b.CallBatch(req, res); err != nil {
+		return nil, err
+	}
+	txs := make([]*bchain.Tx, len(txids))
+	for i := range r {
+		if r[i].Error != nil {
+			if IsMissingTx(r[i].Error) {
+				continue
+			}
+			return nil, errors.Annotatef(r[i].Error, "txid %v", txids[i])
+		}
+		tx, err := b.Parser.ParseTxFromJson(r[i].Result)
+		if err != nil {
+			return nil, errors.Annotatef(err, "txid %v", txids[i])
+		}
+		tx.CoinSpecificData = r[i].Result
+		txs[i] = tx
+	}
+	return txs, nil
+}
+
 // getRawTransaction returns json as returned by backend, with all coin specific data
 func (b *BitcoinRPC) getRawTransaction(txid string) (json.RawMessage, error) {
 	glog.V(1).Info("rpc: getrawtransaction ", txid)
@@ -826,6 +941,9 @@ func (b *BitcoinRPC) SendRawTransaction(tx string) (string, error) {
 	res := ResSendRawTransaction{}
 	req := CmdSendRawTransaction{Method: "sendrawtransaction"}
 	req.Params = []string{tx}
+	if b.backends != nil {
+		return b.sendRawTransactionToAll(&req)
+	}
 	err := b.Call(&req, &res)
 
 	if err != nil {
@@ -890,10 +1008,149 @@ func (b *BitcoinRPC) Call(req interface{}, res interface{}) error {
 	if err != nil {
 		return err
 	}
-	httpReq, err := http.NewRequest("POST", b.rpcURL, bytes.NewBuffer(httpData))
-	if err != nil {
+	return b.send(httpData, res)
+}
+
+// send sends the marshalled request, if the active backend is not reachable, the request is retried on another backend
+func (b *BitcoinRPC) send(httpData []byte, res interface{}) error {
+	if b.backends == nil {
+		_, err := b.call(b.rpcURL, httpData, res)
 		return err
 	}
+	// the probing is started by the first call, when the coin specific constructor finished the configuration
+	b.backends.probeOnce.Do(func() { go b.probeBackends() })
+	url := b.backends.activeURL()
+	unreachable, err := b.call(url, httpData, res)
+	for i := 1; unreachable && i < len(b.backends.backends); i++ {
+		if url = b.failover(url, err); url == "" {
+			break
+		}
+		unreachable, err = b.call(url, httpData, res)
+	}
+	return err
+}
+
+// errBatchRejected is the cause of the error of CallBatch if the backend does not accept the batch requests
+var errBatchRejected = errors.New("Batch rejected by the backend")
+
+// CallBatch calls Backend RPC interface with a batch of requests, the responses are unmarshalled to the items of res
+// with the same index. The errors of the individual requests are returned in the responses, in the same way as by Call.
+// The requests are sent in batches of at most rpc_batch_size requests.
+func (b *BitcoinRPC) CallBatch(req []interface{}, res []interface{}) error {
+	if len(req) != len(res) {
+		return errors.New("Number of requests and responses differ")
+	}
+	for from := 0; from < len(req); from += b.ChainConfig.RPCBatchSize {
+		to := from + b.ChainConfig.RPCBatchSize
+		if to > len(req) {
+			to = len(req)
+		}
+		if err := b.callBatch(req[from:to], res[from:to]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *BitcoinRPC) callBatch(req []interface{}, res []interface{}) (err error) {
+	var method struct {
+		Method string `json:"method"`
+	}
+	if b.metrics != nil {
+		defer func(s time.Time) {
+			var e string
+			if err != nil {
+				e = "failure"
+			}
+			b.metrics.RPCBatchLatency.With(common.Labels{"method": method.Method, "size": batchSizeLabel(len(req)), "error": e}).Observe(float64(time.Since(s)) / 1e6) // in milliseconds
+		}(time.Now())
+	}
+	// the id of each request is its index in the batch
+	httpData := []byte{'['}
+	for i := range req {
+		d, err := b.RPCMarshaler.Marshal(req[i])
+		if err != nil {
+			return err
+		}
+		if len(d) < 2 || d[0] != '{' {
+			return ErrInvalidValue
+		}
+		if i == 0 {
+			json.Unmarshal(d, &method)
+		} else {
+			httpData = append(httpData, ',')
+		}
+		httpData = append(httpData, `{"id":`+strconv.Itoa(i)+`,`...)
+		httpData = append(httpData, d[1:]...)
+	}
+	httpData = append(httpData, ']')
+	var body json.RawMessage
+	if err = b.send(httpData, &body); err != nil {
+		if e, ok := err.(*httpStatusError); ok && e.statusCode >= 400 && e.statusCode < 500 {
+			return errors.Annotatef(errBatchRejected, "batch %v: %v", method.Method, err)
+		}
+		return errors.Annotatef(err, "batch %v", method.Method)
+	}
+	// the backend that does not understand the batches responds by a single error object instead of an array
+	var raw []json.RawMessage
+	if err = json.Unmarshal(body, &raw); err != nil {
+		return errors.Annotatef(errBatchRejected, "batch %v: %v", method.Method, err)
+	}
+	received := make([]bool, len(res))
+	for i := range raw {
+		var id struct {
+			ID *int `json:"id"`
+		}
+		if err = json.Unmarshal(raw[i], &id); err != nil {
+			return err
+		}
+		if id.ID == nil || *id.ID < 0 || *id.ID >= len(res) {
+			return errors.Errorf("Invalid id in the response of batch %v", method.Method)
+		}
+		if err = json.Unmarshal(raw[i], res[*id.ID]); err != nil {
+			return err
+		}
+		received[*id.ID] = true
+	}
+	for i := range received {
+		if !received[i] {
+			return errors.Errorf("Missing response %d of batch %v", i, method.Method)
+		}
+	}
+	return nil
+}
+
+// batchSizeLabel returns the label of the batch size in the metrics, the sizes are grouped to limit the number of series
+func batchSizeLabel(size int) string {
+	switch {
+	case size <= 1:
+		return "1"
+	case size <= 10:
+		return "2-10"
+	case size <= 100:
+		return "11-100"
+	default:
+		return "101+"
+	}
+}
+
+// httpStatusError is returned by call if the backend responds by an HTTP error code without a json body
+type httpStatusError struct {
+	statusCode int
+	status     string
+	err        error
+}
+
+func (e *httpStatusError) Error() string {
+	return e.status + " " + e.err.Error()
+}
+
+// call sends the request to the backend at url, returns true if the backend could not be reached
+func (b *BitcoinRPC) call(url string, httpData []byte, res interface{}) (bool, error) {
+	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(httpData))
+	if err != nil {
+		return false, err
+	}
 	httpReq.SetBasicAuth(b.user, b.password)
 	httpRes, err := b.client.Do(httpReq)
 	// in some cases the httpRes can contain data even if it returns error
@@ -902,16 +1159,16 @@ func (b *BitcoinRPC) Call(req interface{}, res interface{}) error {
 		defer httpRes.Body.Close()
 	}
 	if err != nil {
-		return err
+		return true, err
 	}
 	// if server returns HTTP error code it might not return json with response
 	// handle both cases
 	if httpRes.StatusCode != 200 {
 		err = safeDecodeResponse(httpRes.Body, &res)
 		if err != nil {
-			return errors.Errorf("%v %v", httpRes.Status, err)
+			return true, &httpStatusError{statusCode: httpRes.StatusCode, status: httpRes.Status, err: err}
 		}
-		return nil
+		return false, nil
 	}
-	return safeDecodeResponse(httpRes.Body, &res)
+	return false, safeDecodeResponse(httpRes.Body, &res)
 }
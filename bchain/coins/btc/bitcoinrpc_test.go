@@ -0,0 +1,347 @@
+// +build unittest
+
+package btc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+type testBackend struct {
+	*httptest.Server
+	height  uint32
+	hash    string
+	hashes  map[uint32]string
+	sendErr bool
+	sent    int32
+}
+
+func newTestBackend(height uint32, hash string, sendErr bool) *testBackend {
+	b := &testBackend{height: height, hash: hash, sendErr: sendErr}
+	b.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Params struct {
+				Height uint32 `json:"height"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		res := map[string]interface{}{}
+		switch req.Method {
+		case "getblockcount":
+			res["result"] = b.height
+		case "getblockhash":
+			if req.Params.Height == b.height {
+				res["result"] = b.hash
+			} else if h, found := b.hashes[req.Params.Height]; found {
+				res["result"] = h
+			} else {
+				res["error"] = map[string]interface{}{"code": -8, "message": "Block height out of range"}
+			}
+		case "sendrawtransaction":
+			atomic.AddInt32(&b.sent, 1)
+			if b.sendErr {
+				res["error"] = map[string]interface{}{"code": -26, "message": "rejected"}
+			} else {
+				res["result"] = "txid"
+			}
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	return b
+}
+
+func newTestBackendsRPC(t *testing.T, urls ...string) *BitcoinRPC {
+	config := fmt.Sprintf(`{"rpc_url":%q,"rpc_urls":%s,"rpc_timeout":5}`, urls[0], mustMarshal(urls[1:]))
+	chain, err := NewBitcoinRPC(json.RawMessage(config), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chain.(*BitcoinRPC)
+}
+
+func mustMarshal(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// testIndex is the index with the blocks given by heights and hashes
+type testIndex map[uint32]string
+
+func (ti testIndex) GetBestBlock() (uint32, string, error) {
+	var best uint32
+	for height := range ti {
+		if height > best {
+			best = height
+		}
+	}
+	return best, ti[best], nil
+}
+
+func (ti testIndex) GetBlockHash(height uint32) (string, error) {
+	return ti[height], nil
+}
+
+func TestBitcoinRPC_Failover(t *testing.T) {
+	down := newTestBackend(100, "a", false)
+	down.Close()
+	up := newTestBackend(100, "b", false)
+	defer up.Close()
+
+	b := newTestBackendsRPC(t, down.URL, up.URL)
+	defer b.Shutdown(context.Background())
+	// the index conflicts with the backend at the same height
+	b.SetIndexedBlocks(testIndex{99: "z", 100: "a"})
+	if _, err := b.GetBestBlockHeight(); err == nil {
+		t.Fatal("switched to a backend conflicting with the index")
+	}
+	if got := b.backends.activeURL(); got != down.URL {
+		t.Errorf("active backend %v, want %v", got, down.URL)
+	}
+
+	// the index is ahead of the backend and conflicts with it at the best height of the backend
+	b.SetIndexedBlocks(testIndex{100: "a", 101: "c"})
+	if _, err := b.GetBestBlockHeight(); err == nil {
+		t.Fatal("switched to a backend conflicting with the index")
+	}
+
+	b.SetIndexedBlocks(testIndex{99: "z", 100: "b", 101: "c"})
+	height, err := b.GetBestBlockHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != 100 {
+		t.Errorf("GetBestBlockHeight() = %v, want 100", height)
+	}
+	if got := b.backends.activeURL(); got != up.URL {
+		t.Errorf("active backend %v, want %v", got, up.URL)
+	}
+}
+
+func TestBitcoinRPC_FailoverAhead(t *testing.T) {
+	down := newTestBackend(100, "a", false)
+	down.Close()
+	// the backend is ahead of the index on another fork
+	fork := newTestBackend(103, "f103", false)
+	fork.hashes = map[uint32]string{100: "f100", 101: "f101", 102: "f102"}
+	defer fork.Close()
+	// the backend is ahead of the index on the indexed chain
+	ahead := newTestBackend(103, "a103", false)
+	ahead.hashes = map[uint32]string{100: "a100", 101: "a101", 102: "a102"}
+	defer ahead.Close()
+
+	b := newTestBackendsRPC(t, down.URL, fork.URL, ahead.URL)
+	defer b.Shutdown(context.Background())
+	b.SetIndexedBlocks(testIndex{100: "a100", 101: "a101"})
+	height, err := b.GetBestBlockHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != 103 {
+		t.Errorf("GetBestBlockHeight() = %v, want 103", height)
+	}
+	if got := b.backends.activeURL(); got != ahead.URL {
+		t.Errorf("active backend %v, want %v", got, ahead.URL)
+	}
+}
+
+func TestBitcoinRPC_SendRawTransactionToAll(t *testing.T) {
+	rejecting := newTestBackend(100, "a", true)
+	defer rejecting.Close()
+	accepting := newTestBackend(100, "a", false)
+	defer accepting.Close()
+
+	b := newTestBackendsRPC(t, rejecting.URL, accepting.URL)
+	defer b.Shutdown(context.Background())
+	txid, err := b.SendRawTransaction("00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txid != "txid" {
+		t.Errorf("SendRawTransaction() = %v, want txid", txid)
+	}
+	if r, a := atomic.LoadInt32(&rejecting.sent), atomic.LoadInt32(&accepting.sent); r != 1 || a != 1 {
+		t.Errorf("sent to backends %d and %d times, want 1 and 1", r, a)
+	}
+
+	rejecting2 := newTestBackend(100, "a", true)
+	defer rejecting2.Close()
+	b2 := newTestBackendsRPC(t, rejecting.URL, rejecting2.URL)
+	defer b2.Shutdown(context.Background())
+	if _, err = b2.SendRawTransaction("00"); err == nil || err.Error() != "-26: rejected" {
+		t.Errorf("SendRawTransaction() error = %v, want -26: rejected", err)
+	}
+}
+
+func TestBitcoinRPC_CallBatch(t *testing.T) {
+	var batches []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req []struct {
+			ID     int           `json:"id"`
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		batches = append(batches, len(req))
+		// the responses are returned in the reverse order, the height 3 is not found
+		res := make([]map[string]interface{}, 0, len(req))
+		for i := len(req) - 1; i >= 0; i-- {
+			item := map[string]interface{}{"id": req[i].ID}
+			if req[i].Params[0].(float64) == 3 {
+				item["error"] = map[string]interface{}{"code": -8, "message": "Block height out of range"}
+			} else {
+				item["result"] = "hash" + strconv.Itoa(int(req[i].Params[0].(float64)))
+			}
+			res = append(res, item)
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	config := fmt.Sprintf(`{"rpc_url":%q,"rpc_timeout":5,"rpc_batch_size":2}`, server.URL)
+	chain, err := NewBitcoinRPC(json.RawMessage(config), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := chain.(*BitcoinRPC)
+	b.RPCMarshaler = JSONMarshalerV1{}
+	req := make([]interface{}, 3)
+	res := make([]interface{}, 3)
+	r := make([]ResGetBlockHash, 3)
+	for i := range req {
+		c := &CmdGetBlockHash{Method: "getblockhash"}
+		c.Params.Height = uint32(i + 1)
+		req[i] = c
+		res[i] = &r[i]
+	}
+	if err = b.CallBatch(req, res); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(batches, []int{2, 1}) {
+		t.Errorf("batches %v, want [2 1]", batches)
+	}
+	if r[0].Result != "hash1" || r[1].Result != "hash2" || r[0].Error != nil || r[1].Error != nil {
+		t.Errorf("responses %+v %+v, want hash1 and hash2", r[0], r[1])
+	}
+	if r[2].Error == nil || !IsErrBlockNotFound(r[2].Error) {
+		t.Errorf("response %+v, want error Block height out of range", r[2])
+	}
+}
+
+func TestBitcoinRPC_GetTransactionsFallback(t *testing.T) {
+	var batches, single int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var raw json.RawMessage
+		json.NewDecoder(r.Body).Decode(&raw)
+		// the backend does not understand the batches
+		if len(raw) > 0 && raw[0] == '[' {
+			atomic.AddInt32(&batches, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]interface{}{"code": -32700, "message": "Parse error"}})
+			return
+		}
+		atomic.AddInt32(&single, 1)
+		var req struct {
+			Params struct {
+				Txid string `json:"txid"`
+			} `json:"params"`
+		}
+		json.Unmarshal(raw, &req)
+		res := map[string]interface{}{}
+		if req.Params.Txid == "missing" {
+			res["error"] = map[string]interface{}{"code": -5, "message": "No such mempool or blockchain transaction"}
+		} else {
+			res["result"] = map[string]interface{}{"txid": req.Params.Txid, "version": 1, "vin": []interface{}{}, "vout": []interface{}{}}
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	b := newTestBackendsRPC(t, server.URL)
+	b.Parser = NewBitcoinParser(GetChainParams("test"), &Configuration{})
+	txs, err := b.GetTransactions([]string{"a", "missing", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 3 || txs[0] == nil || txs[0].Txid != "a" || txs[1] != nil || txs[2] == nil || txs[2].Txid != "b" {
+		t.Fatalf("GetTransactions = %+v, want a, nil, b", txs)
+	}
+	if batches != 1 || single != 3 {
+		t.Errorf("%d batches and %d single requests, want 1 and 3", batches, single)
+	}
+	// the batches are not tried again
+	if _, err = b.GetTransactions([]string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if batches != 1 || single != 5 {
+		t.Errorf("%d batches and %d single requests, want 1 and 5", batches, single)
+	}
+}
+
+func TestBitcoinRPC_GetTransactionsBatchFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		rejected bool
+	}{
+		{name: "overloaded", status: http.StatusServiceUnavailable, rejected: false},
+		{name: "bad request", status: http.StatusBadRequest, rejected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var batches int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var raw json.RawMessage
+				json.NewDecoder(r.Body).Decode(&raw)
+				if len(raw) > 0 && raw[0] == '[' {
+					atomic.AddInt32(&batches, 1)
+					http.Error(w, "failed", tt.status)
+					return
+				}
+				var req struct {
+					Params struct {
+						Txid string `json:"txid"`
+					} `json:"params"`
+				}
+				json.Unmarshal(raw, &req)
+				res := map[string]interface{}{
+					"result": map[string]interface{}{"txid": req.Params.Txid, "version": 1, "vin": []interface{}{}, "vout": []interface{}{}},
+				}
+				json.NewEncoder(w).Encode(res)
+			}))
+			defer server.Close()
+
+			b := newTestBackendsRPC(t, server.URL)
+			b.Parser = NewBitcoinParser(GetChainParams("test"), &Configuration{})
+			for i := 0; i < 2; i++ {
+				txs, err := b.GetTransactions([]string{"a", "b"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(txs) != 2 || txs[0] == nil || txs[0].Txid != "a" || txs[1] == nil || txs[1].Txid != "b" {
+					t.Fatalf("GetTransactions = %+v, want a, b", txs)
+				}
+			}
+			// the batches are retried after a transient failure
+			want := int32(2)
+			if tt.rejected {
+				want = 1
+			}
+			if got := atomic.LoadInt32(&batches); got != want {
+				t.Errorf("%d batches, want %d", got, want)
+			}
+			if got := atomic.LoadInt32(&b.batchUnsupported) != 0; got != tt.rejected {
+				t.Errorf("batchUnsupported = %v, want %v", got, tt.rejected)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package btc
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/juju/errors"
+	"github.com/martinboehm/btcd/chaincfg/chainhash"
+	"github.com/martinboehm/btcd/wire"
+	"github.com/martinboehm/btcutil"
+	"github.com/martinboehm/btcutil/hdkeychain"
+	"github.com/martinboehm/btcutil/txscript"
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+)
+
+// BIP174 key types used in created PSBTs
+const (
+	psbtGlobalUnsignedTx   = 0x00
+	psbtInNonWitnessUtxo   = 0x00
+	psbtInWitnessUtxo      = 0x01
+	psbtInRedeemScript     = 0x04
+	psbtInBip32Derivation  = 0x06
+	psbtOutRedeemScript    = 0x00
+	psbtOutBip32Derivation = 0x02
+	psbtSeparator          = 0x00
+)
+
+const (
+	psbtTxVersion           = 2
+	psbtCompressedPubKeyLen = 33
+	psbtFingerprintLen      = 4
+	psbtP2shP2wpkhRedeemLen = 22
+	psbtMaxBip32PathLen     = 255
+)
+
+var psbtMagic = []byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+func psbtWriteKeyValue(w *bytes.Buffer, keyType byte, keyData []byte, value []byte) {
+	wire.WriteVarInt(w, 0, uint64(1+len(keyData)))
+	w.WriteByte(keyType)
+	w.Write(keyData)
+	wire.WriteVarInt(w, 0, uint64(len(value)))
+	w.Write(value)
+}
+
+func psbtBip32Derivation(origin *bchain.XpubKeyOrigin, change, index uint32) []byte {
+	b := make([]byte, psbtFingerprintLen+4*(len(origin.Path)+2))
+	binary.BigEndian.PutUint32(b, origin.Fingerprint)
+	o := psbtFingerprintLen
+	for _, p := range origin.Path {
+		binary.LittleEndian.PutUint32(b[o:], p)
+		o += 4
+	}
+	binary.LittleEndian.PutUint32(b[o:], change)
+	binary.LittleEndian.PutUint32(b[o+4:], index)
+	return b
+}
+
+// psbtKey derives the key of the xpub at change/index and returns its compressed public key and P2SH-P2WPKH redeem script
+func (p *BitcoinParser) psbtKey(extKey *hdkeychain.ExtendedKey, addrDesc bchain.AddressDescriptor, change, index uint32) ([]byte, []byte, error) {
+	changeExtKey, err := extKey.Child(change)
+	if err != nil {
+		return nil, nil, err
+	}
+	indexExtKey, err := changeExtKey.Child(index)
+	if err != nil {
+		return nil, nil, err
+	}
+	ad, err := p.addrDescFromExtKey(indexExtKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(ad, addrDesc) {
+		return nil, nil, errors.Errorf("Address at path %d/%d does not belong to xpub", change, index)
+	}
+	pubKey := indexExtKey.PubKeyBytes()
+	var redeemScript []byte
+	if extKey.Version() == p.XPubMagicSegwitP2sh {
+		redeemScript = make([]byte, psbtP2shP2wpkhRedeemLen)
+		redeemScript[0] = 0
+		redeemScript[1] = psbtP2shP2wpkhRedeemLen - 2
+		copy(redeemScript[2:], btcutil.Hash160(pubKey))
+	}
+	return pubKey, redeemScript, nil
+}
+
+// CreatePsbt creates unsigned transaction from given inputs and outputs and returns it serialized in BIP174 format
+// Inputs contain previous transactions and for segwit inputs also spent outputs, inputs and change outputs contain BIP32 derivation of the keys
+// If origin is nil, the xpub itself is considered to be the master key
+func (p *BitcoinParser) CreatePsbt(xpub string, origin *bchain.XpubKeyOrigin, inputs []bchain.PsbtInput, outputs []bchain.PsbtOutput, lockTime uint32) ([]byte, error) {
+	extKey, err := hdkeychain.NewKeyFromString(xpub, p.Params.Base58CksumHasher)
+	if err != nil {
+		return nil, err
+	}
+	if origin == nil {
+		origin = &bchain.XpubKeyOrigin{
+			Fingerprint: binary.BigEndian.Uint32(btcutil.Hash160(extKey.PubKeyBytes())[:psbtFingerprintLen]),
+		}
+	}
+	if len(origin.Path)+2 > psbtMaxBip32PathLen {
+		return nil, errors.New("Derivation path too long")
+	}
+	tx := wire.NewMsgTx(psbtTxVersion)
+	tx.LockTime = lockTime
+	for i := range inputs {
+		in := &inputs[i]
+		hash, err := chainhash.NewHashFromStr(in.Txid)
+		if err != nil {
+			return nil, err
+		}
+		txIn := wire.NewTxIn(wire.NewOutPoint(hash, in.Vout), nil, nil)
+		txIn.Sequence = in.Sequence
+		tx.AddTxIn(txIn)
+	}
+	for i := range outputs {
+		out := &outputs[i]
+		script, err := p.GetScriptFromAddrDesc(out.AddrDesc)
+		if err != nil {
+			return nil, err
+		}
+		tx.AddTxOut(wire.NewTxOut(out.ValueSat.Int64(), script))
+	}
+	var buf, txBuf bytes.Buffer
+	if err = tx.SerializeNoWitness(&txBuf); err != nil {
+		return nil, err
+	}
+	buf.Write(psbtMagic)
+	psbtWriteKeyValue(&buf, psbtGlobalUnsignedTx, nil, txBuf.Bytes())
+	buf.WriteByte(psbtSeparator)
+	for i := range inputs {
+		in := &inputs[i]
+		pubKey, redeemScript, err := p.psbtKey(extKey, in.AddrDesc, in.Change, in.Index)
+		if err != nil {
+			return nil, errors.Annotatef(err, "Input %d", i)
+		}
+		if len(in.PrevTx) > 0 {
+			psbtWriteKeyValue(&buf, psbtInNonWitnessUtxo, nil, in.PrevTx)
+		}
+		sc := txscript.GetScriptClass(in.AddrDesc)
+		if sc == txscript.WitnessV0PubKeyHashTy || (sc == txscript.ScriptHashTy && redeemScript != nil) {
+			var u bytes.Buffer
+			var v [8]byte
+			binary.LittleEndian.PutUint64(v[:], uint64(in.ValueSat.Int64()))
+			u.Write(v[:])
+			wire.WriteVarBytes(&u, 0, in.AddrDesc)
+			psbtWriteKeyValue(&buf, psbtInWitnessUtxo, nil, u.Bytes())
+		} else if len(in.PrevTx) == 0 {
+			return nil, errors.Errorf("Input %d: missing previous transaction", i)
+		}
+		if redeemScript != nil {
+			psbtWriteKeyValue(&buf, psbtInRedeemScript, nil, redeemScript)
+		}
+		if len(pubKey) == psbtCompressedPubKeyLen {
+			psbtWriteKeyValue(&buf, psbtInBip32Derivation, pubKey, psbtBip32Derivation(origin, in.Change, in.Index))
+		}
+		buf.WriteByte(psbtSeparator)
+	}
+	for i := range outputs {
+		out := &outputs[i]
+		if out.IsChange {
+			pubKey, redeemScript, err := p.psbtKey(extKey, out.AddrDesc, out.Change, out.Index)
+			if err != nil {
+				return nil, errors.Annotatef(err, "Output %d", i)
+			}
+			if redeemScript != nil {
+				psbtWriteKeyValue(&buf, psbtOutRedeemScript, nil, redeemScript)
+			}
+			if len(pubKey) == psbtCompressedPubKeyLen {
+				psbtWriteKeyValue(&buf, psbtOutBip32Derivation, pubKey, psbtBip32Derivation(origin, out.Change, out.Index))
+			}
+		}
+		buf.WriteByte(psbtSeparator)
+	}
+	return buf.Bytes(), nil
+}
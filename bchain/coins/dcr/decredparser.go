@@ -354,3 +354,8 @@ func (p *DecredParser) decodeXpub(xpub string) (childNum uint32, depth uint16, e
 	childNum = binary.BigEndian.Uint32(payload[9:13])
 	return
 }
+
+// CreatePsbt is unsupported, Decred transactions cannot be serialized in BIP174 format
+func (p *DecredParser) CreatePsbt(xpub string, origin *bchain.XpubKeyOrigin, inputs []bchain.PsbtInput, outputs []bchain.PsbtOutput, lockTime uint32) ([]byte, error) {
+	return nil, errors.New("Not supported")
+}
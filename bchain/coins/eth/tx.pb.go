@@ -1,11 +1,11 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
-// source: tx.proto
+// source: bchain/coins/eth/tx.proto
 
 /*
 Package eth is a generated protocol buffer package.
 
 It is generated from these files:
-	tx.proto
+	bchain/coins/eth/tx.proto
 
 It has these top-level messages:
 	ProtoCompleteTransaction
@@ -228,33 +228,34 @@ func init() {
 	proto.RegisterType((*ProtoCompleteTransaction_ReceiptType_LogType)(nil), "eth.ProtoCompleteTransaction.ReceiptType.LogType")
 }
 
-func init() { proto.RegisterFile("tx.proto", fileDescriptor0) }
+func init() { proto.RegisterFile("bchain/coins/eth/tx.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 393 bytes of a gzipped FileDescriptorProto
+	// 405 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0xdf, 0x8a, 0xd4, 0x30,
-	0x14, 0xc6, 0xe9, 0x9f, 0xf9, 0xb3, 0xa7, 0x55, 0x24, 0x88, 0x84, 0xe2, 0x45, 0x59, 0xbc, 0xa8,
-	0x5e, 0x14, 0x5c, 0x7d, 0x81, 0x75, 0xc4, 0x55, 0x18, 0xd6, 0x21, 0x46, 0xef, 0xb3, 0x69, 0xd8,
-	0x29, 0xb6, 0x4d, 0x69, 0x52, 0xe8, 0xbe, 0x91, 0x2f, 0xe4, 0xbb, 0x78, 0x29, 0x39, 0x4d, 0xd7,
-	0x11, 0x51, 0xbc, 0x3b, 0xbf, 0x6f, 0xce, 0x37, 0xf9, 0xbe, 0xa4, 0xb0, 0xb5, 0x53, 0xd9, 0x0f,
-	0xda, 0x6a, 0x12, 0x29, 0x7b, 0x3c, 0xff, 0xb6, 0x02, 0x7a, 0x70, 0xb8, 0xd3, 0x6d, 0xdf, 0x28,
-	0xab, 0xf8, 0x20, 0x3a, 0x23, 0xa4, 0xad, 0x75, 0x47, 0x72, 0x48, 0xde, 0x34, 0x5a, 0x7e, 0xbd,
-	0x1e, 0xdb, 0x1b, 0x35, 0xd0, 0x20, 0x0f, 0x8a, 0x07, 0xec, 0x54, 0x22, 0x4f, 0xe1, 0x0c, 0x91,
-	0xd7, 0xad, 0xa2, 0x61, 0x1e, 0x14, 0x31, 0xfb, 0x25, 0x90, 0xd7, 0x10, 0xf2, 0x89, 0x46, 0x79,
-	0x50, 0x24, 0x17, 0xcf, 0x4a, 0x65, 0x8f, 0xe5, 0xdf, 0x8e, 0x2a, 0xf9, 0xc4, 0xef, 0x7a, 0xc5,
-	0x42, 0x3e, 0x91, 0x1d, 0x6c, 0x98, 0x92, 0xaa, 0xee, 0x2d, 0x8d, 0xd1, 0xfa, 0xfc, 0xdf, 0x56,
-	0xbf, 0x8c, 0xfe, 0xc5, 0x99, 0xfd, 0x08, 0x60, 0x3d, 0xff, 0x27, 0x39, 0x87, 0xf4, 0x52, 0x4a,
-	0x3d, 0x76, 0xf6, 0x5a, 0x77, 0x52, 0x61, 0x8d, 0x98, 0xfd, 0xa6, 0x91, 0x0c, 0xb6, 0x57, 0xc2,
-	0x1c, 0x86, 0x5a, 0xce, 0x35, 0x52, 0x76, 0xcf, 0xfe, 0xb7, 0x7d, 0xdd, 0xd6, 0x16, 0xbb, 0xc4,
-	0xec, 0x9e, 0xc9, 0x63, 0x58, 0x7d, 0x11, 0xcd, 0xa8, 0x30, 0x69, 0xca, 0x66, 0x20, 0x14, 0x36,
-	0x07, 0x71, 0xd7, 0x68, 0x51, 0xd1, 0x15, 0xea, 0x0b, 0x12, 0x02, 0xf1, 0x7b, 0x61, 0x8e, 0x74,
-	0x8d, 0x32, 0xce, 0xe4, 0x21, 0x84, 0x5c, 0xd3, 0x0d, 0x2a, 0x21, 0xd7, 0x6e, 0xe7, 0xdd, 0xa0,
-	0x5b, 0xba, 0x9d, 0x77, 0xdc, 0x4c, 0x5e, 0xc0, 0xa3, 0x93, 0xca, 0x1f, 0xba, 0x4a, 0x4d, 0xf4,
-	0x0c, 0x9f, 0xe3, 0x0f, 0x3d, 0xfb, 0x1e, 0x40, 0x72, 0x72, 0x27, 0x2e, 0xcd, 0x95, 0x30, 0x9f,
-	0x8d, 0xaa, 0xb0, 0x7a, 0xca, 0x16, 0x24, 0x4f, 0x60, 0xfd, 0xc9, 0x0a, 0x3b, 0x1a, 0xdf, 0xd9,
-	0x13, 0xd9, 0x41, 0xb4, 0xd7, 0xb7, 0x34, 0xca, 0xa3, 0x22, 0xb9, 0x78, 0xf9, 0xdf, 0xb7, 0x5f,
-	0xee, 0xf5, 0x2d, 0xbe, 0x82, 0x73, 0x67, 0x1f, 0x61, 0xe3, 0xd9, 0x25, 0xb8, 0xac, 0xaa, 0x41,
-	0x19, 0xb3, 0x24, 0xf0, 0xe8, 0xba, 0xbe, 0x15, 0x56, 0xf8, 0xf3, 0x71, 0x76, 0xa9, 0xb8, 0xee,
-	0x6b, 0x69, 0x30, 0x40, 0xca, 0x3c, 0xdd, 0xac, 0xf1, 0xb3, 0x7d, 0xf5, 0x33, 0x00, 0x00, 0xff,
-	0xff, 0xde, 0xd5, 0x28, 0xa3, 0xc2, 0x02, 0x00, 0x00,
+	0x18, 0xc5, 0xe9, 0x9f, 0x99, 0xd9, 0xfd, 0xa6, 0x8a, 0x04, 0x91, 0x58, 0xbc, 0x28, 0x8b, 0x17,
+	0xd5, 0x8b, 0x0e, 0xae, 0xbe, 0xc0, 0x3a, 0xe2, 0x2a, 0x0c, 0xeb, 0x10, 0xa3, 0xf7, 0x99, 0x34,
+	0x6c, 0x83, 0x6d, 0x53, 0x9a, 0x14, 0xba, 0x6f, 0xe4, 0x0b, 0xf9, 0x2e, 0x5e, 0x4a, 0xd2, 0x74,
+	0x1c, 0x11, 0x65, 0xaf, 0xe6, 0xfb, 0x9d, 0xf9, 0x4e, 0x73, 0x4e, 0x5a, 0x78, 0x7a, 0xe0, 0x15,
+	0x93, 0xed, 0x86, 0x2b, 0xd9, 0xea, 0x8d, 0x30, 0xd5, 0xc6, 0x8c, 0x45, 0xd7, 0x2b, 0xa3, 0x50,
+	0x24, 0x4c, 0x75, 0xf1, 0x7d, 0x01, 0x78, 0x6f, 0x71, 0xab, 0x9a, 0xae, 0x16, 0x46, 0xd0, 0x9e,
+	0xb5, 0x9a, 0x71, 0x23, 0x55, 0x8b, 0x32, 0x58, 0xbf, 0xad, 0x15, 0xff, 0x76, 0x33, 0x34, 0x07,
+	0xd1, 0xe3, 0x20, 0x0b, 0xf2, 0x07, 0xe4, 0x54, 0x42, 0xcf, 0xe0, 0xdc, 0x21, 0x95, 0x8d, 0xc0,
+	0x61, 0x16, 0xe4, 0x31, 0xf9, 0x2d, 0xa0, 0x37, 0x10, 0xd2, 0x11, 0x47, 0x59, 0x90, 0xaf, 0x2f,
+	0x9f, 0x17, 0xc2, 0x54, 0xc5, 0xbf, 0x8e, 0x2a, 0xe8, 0x48, 0xef, 0x3a, 0x41, 0x42, 0x3a, 0xa2,
+	0x2d, 0xac, 0x88, 0xe0, 0x42, 0x76, 0x06, 0xc7, 0xce, 0xfa, 0xe2, 0xff, 0x56, 0xbf, 0xec, 0xfc,
+	0xb3, 0x33, 0xfd, 0x19, 0xc0, 0x72, 0x7a, 0x26, 0xba, 0x80, 0xe4, 0x8a, 0x73, 0x35, 0xb4, 0xe6,
+	0x46, 0xb5, 0x5c, 0xb8, 0x1a, 0x31, 0xf9, 0x43, 0x43, 0x29, 0x9c, 0x5d, 0x33, 0xbd, 0xef, 0x25,
+	0x9f, 0x6a, 0x24, 0xe4, 0xc8, 0xfe, 0xbf, 0x9d, 0x6c, 0xa4, 0x71, 0x5d, 0x62, 0x72, 0x64, 0xf4,
+	0x18, 0x16, 0x5f, 0x59, 0x3d, 0x08, 0x97, 0x34, 0x21, 0x13, 0x20, 0x0c, 0xab, 0x3d, 0xbb, 0xab,
+	0x15, 0x2b, 0xf1, 0xc2, 0xe9, 0x33, 0x22, 0x04, 0xf1, 0x07, 0xa6, 0x2b, 0xbc, 0x74, 0xb2, 0x9b,
+	0xd1, 0x43, 0x08, 0xa9, 0xc2, 0x2b, 0xa7, 0x84, 0x54, 0xd9, 0x9d, 0xf7, 0xbd, 0x6a, 0xf0, 0xd9,
+	0xb4, 0x63, 0x67, 0xf4, 0x12, 0x1e, 0x9d, 0x54, 0xfe, 0xd8, 0x96, 0x62, 0xc4, 0xe7, 0xee, 0x75,
+	0xfc, 0xa5, 0xa7, 0x3f, 0x02, 0x58, 0x9f, 0xdc, 0x89, 0x4d, 0x73, 0xcd, 0xf4, 0x17, 0x2d, 0x4a,
+	0x57, 0x3d, 0x21, 0x33, 0xa2, 0x27, 0xb0, 0xfc, 0x6c, 0x98, 0x19, 0xb4, 0xef, 0xec, 0x09, 0x6d,
+	0x21, 0xda, 0xa9, 0x5b, 0x1c, 0x65, 0x51, 0xbe, 0xbe, 0x7c, 0x75, 0xef, 0xdb, 0x2f, 0x76, 0xea,
+	0xd6, 0xfe, 0x12, 0xeb, 0x4e, 0x3f, 0xc1, 0xca, 0xb3, 0x4d, 0x70, 0x55, 0x96, 0xbd, 0xd0, 0x7a,
+	0x4e, 0xe0, 0xd1, 0x76, 0x7d, 0xc7, 0x0c, 0xf3, 0xe7, 0xbb, 0xd9, 0xa6, 0xa2, 0xaa, 0x93, 0x5c,
+	0xbb, 0x00, 0x09, 0xf1, 0x74, 0x58, 0xba, 0xcf, 0xf6, 0xf5, 0xaf, 0x01, 0x00, 0x42, 0x63, 0x7b,
+	0x50, 0xd3, 0x02, 0x00, 0x00,
 }
@@ -0,0 +1,78 @@
+package localchain
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+)
+
+// controlResponse is the response of the control server, either the result or the error is set
+type controlResponse struct {
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// newControlServer creates the http server which mines the blocks and creates the transactions on demand:
+//
+//	POST /generate?blocks=<n>[&address=<address>] - mines the blocks, returns their hashes
+//	POST /send?address=<address>&value=<satoshis>  - sends a synthetic transaction to mempool, returns its txid
+//	POST /invalidate?height=<height>               - disconnects the blocks above the height, returns the best block hash
+//	GET  /info                                     - returns the chain info
+func (c *LocalChain) newControlServer(binding string) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/generate", c.controlHandler(http.MethodPost, func(r *http.Request) (interface{}, error) {
+		blocks := 1
+		if s := r.FormValue("blocks"); s != "" {
+			var err error
+			if blocks, err = strconv.Atoi(s); err != nil || blocks < 1 {
+				return nil, errors.New("Invalid parameter blocks")
+			}
+		}
+		return c.Generate(blocks, r.FormValue("address"))
+	}))
+	mux.HandleFunc("/send", c.controlHandler(http.MethodPost, func(r *http.Request) (interface{}, error) {
+		value, err := strconv.ParseInt(r.FormValue("value"), 10, 64)
+		if err != nil {
+			return nil, errors.New("Invalid parameter value")
+		}
+		return c.Send(r.FormValue("address"), value)
+	}))
+	mux.HandleFunc("/invalidate", c.controlHandler(http.MethodPost, func(r *http.Request) (interface{}, error) {
+		height, err := strconv.ParseUint(r.FormValue("height"), 10, 32)
+		if err != nil {
+			return nil, errors.New("Invalid parameter height")
+		}
+		if err = c.Invalidate(uint32(height)); err != nil {
+			return nil, err
+		}
+		return c.GetBestBlockHash()
+	}))
+	mux.HandleFunc("/info", c.controlHandler(http.MethodGet, func(r *http.Request) (interface{}, error) {
+		return c.GetChainInfo()
+	}))
+	return &http.Server{Addr: binding, Handler: mux}
+}
+
+func (c *LocalChain) controlHandler(method string, handler func(r *http.Request) (interface{}, error)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var rv controlResponse
+		status := http.StatusOK
+		if r.Method != method {
+			status = http.StatusMethodNotAllowed
+			rv.Error = "Method not allowed"
+		} else if result, err := handler(r); err != nil {
+			status = http.StatusBadRequest
+			rv.Error = err.Error()
+		} else {
+			rv.Result = result
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(&rv); err != nil {
+			glog.Error("localchain: control server ", err)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package localchain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/martinboehm/btcd/wire"
+)
+
+// The fixture directory contains
+//
+//	blocks/<height>.hex - raw blocks in hex, consecutive heights starting by the genesis block at height 0
+//	mempool/*.hex       - raw transactions in hex, added to mempool after the blocks are loaded
+//	fixture.json        - steps generating synthetic transactions and blocks, run if there are no blocks except the genesis
+const (
+	blocksDir   = "blocks"
+	mempoolDir  = "mempool"
+	fixtureFile = "fixture.json"
+	// time between the blocks generated by fixture.json, the generated chain does not depend on the current time
+	fixtureBlockSpacing = 10 * time.Minute
+)
+
+// fixtureStep sends the synthetic transactions to mempool and then mines the blocks
+type fixtureStep struct {
+	Send []struct {
+		Address string `json:"address"`
+		Value   int64  `json:"value"`
+	} `json:"send,omitempty"`
+	Blocks int `json:"blocks"`
+	// receiver of the coinbases of the blocks, default the miner address
+	Address string `json:"address,omitempty"`
+}
+
+// readHexFiles returns the names without extension and the decoded content of the *.hex files in the directory,
+// the directory does not have to exist
+func readHexFiles(dir string) ([]string, [][]byte, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	var names []string
+	var data [][]byte
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".hex" {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, nil, err
+		}
+		d, err := hex.DecodeString(strings.TrimSpace(string(b)))
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "file %v", f.Name())
+		}
+		names = append(names, strings.TrimSuffix(f.Name(), ".hex"))
+		data = append(data, d)
+	}
+	return names, data, nil
+}
+
+// loadFixture loads the blocks and mempool transactions of the fixture directory,
+// without the fixture directory the chain contains only the genesis block
+func (c *LocalChain) loadFixture() error {
+	dir := c.ChainConfig.FixtureDir
+	if dir != "" {
+		names, data, err := readHexFiles(filepath.Join(dir, blocksDir))
+		if err != nil {
+			return err
+		}
+		blocks := make([][]byte, len(names))
+		for i, name := range names {
+			height, err := strconv.Atoi(name)
+			if err != nil || height < 0 || height >= len(names) {
+				return errors.Errorf("unexpected block file %v.hex, the blocks must be stored in files <height>.hex with consecutive heights from 0", name)
+			}
+			blocks[height] = data[i]
+		}
+		for height, raw := range blocks {
+			if raw == nil {
+				return errors.Errorf("missing block file %v.hex", height)
+			}
+			var msg wire.MsgBlock
+			if err = msg.Deserialize(bytes.NewReader(raw)); err != nil {
+				return errors.Annotatef(err, "block %v", height)
+			}
+			if _, err = c.connectBlock(&msg, raw); err != nil {
+				return err
+			}
+		}
+	}
+	if len(c.blocks) == 0 {
+		var buf bytes.Buffer
+		if err := c.Params.GenesisBlock.Serialize(&buf); err != nil {
+			return err
+		}
+		b, err := c.connectBlock(c.Params.GenesisBlock, buf.Bytes())
+		if err != nil {
+			return err
+		}
+		if err = c.persistBlock(b); err != nil {
+			return err
+		}
+	}
+	if dir == "" {
+		return nil
+	}
+	if len(c.blocks) == 1 {
+		if err := c.runFixtureSteps(filepath.Join(dir, fixtureFile)); err != nil {
+			return errors.Annotatef(err, "%v", fixtureFile)
+		}
+	}
+	names, data, err := readHexFiles(filepath.Join(dir, mempoolDir))
+	if err != nil {
+		return err
+	}
+	now := time.Now().Unix()
+	for i := range names {
+		msg := wire.NewMsgTx(wire.TxVersion)
+		if err = msg.Deserialize(bytes.NewReader(data[i])); err != nil {
+			return errors.Annotatef(err, "mempool transaction %v", names[i])
+		}
+		if _, err = c.addToMempool(msg, now); err != nil {
+			return errors.Annotatef(err, "mempool transaction %v", names[i])
+		}
+	}
+	return nil
+}
+
+// runFixtureSteps generates the transactions and blocks described by the file, the file does not have to exist
+func (c *LocalChain) runFixtureSteps(file string) error {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var steps []fixtureStep
+	if err = json.Unmarshal(b, &steps); err != nil {
+		return err
+	}
+	for i, s := range steps {
+		for _, send := range s.Send {
+			script, err := c.addressScript(send.Address)
+			if err != nil {
+				return errors.Annotatef(err, "step %v, address %v", i, send.Address)
+			}
+			if _, err = c.sendSynthetic(script, send.Value, c.tip().msg.Header.Timestamp.Unix()); err != nil {
+				return errors.Annotatef(err, "step %v", i)
+			}
+		}
+		script := c.minerScript
+		if s.Address != "" {
+			if script, err = c.addressScript(s.Address); err != nil {
+				return errors.Annotatef(err, "step %v, address %v", i, s.Address)
+			}
+		}
+		for j := 0; j < s.Blocks; j++ {
+			if _, err = c.mineBlock(script, c.tip().msg.Header.Timestamp.Add(fixtureBlockSpacing)); err != nil {
+				return errors.Annotatef(err, "step %v", i)
+			}
+		}
+	}
+	glog.Info("localchain: generated ", len(c.blocks)-1, " blocks by ", file)
+	return nil
+}
+
+// persistBlock stores the block to the fixture directory if the persistence is enabled
+func (c *LocalChain) persistBlock(b *localBlock) error {
+	if !c.ChainConfig.Persist || c.ChainConfig.FixtureDir == "" {
+		return nil
+	}
+	dir := filepath.Join(c.ChainConfig.FixtureDir, blocksDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, strconv.Itoa(int(b.height))+".hex"), []byte(hex.EncodeToString(b.raw)+"\n"), 0644)
+}
+
+// removePersistedBlock removes the disconnected block from the fixture directory if the persistence is enabled
+func (c *LocalChain) removePersistedBlock(height uint32) error {
+	if !c.ChainConfig.Persist || c.ChainConfig.FixtureDir == "" {
+		return nil
+	}
+	err := os.Remove(filepath.Join(c.ChainConfig.FixtureDir, blocksDir, strconv.Itoa(int(height))+".hex"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,569 @@
+package localchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/martinboehm/btcd/wire"
+	"github.com/martinboehm/btcutil"
+	"github.com/martinboehm/btcutil/chaincfg"
+	"github.com/martinboehm/btcutil/txscript"
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+	"github.com/scryptachain/blockbook-scrypta/bchain/coins/btc"
+	"github.com/scryptachain/blockbook-scrypta/common"
+)
+
+const (
+	defaultBlockReward = 50 * 100000000
+	defaultFeePerKB    = 1000
+	// fee of the synthetic transactions
+	syntheticTxFee = 1000
+)
+
+// Configuration represents json config file
+type Configuration struct {
+	CoinName                     string `json:"coin_name"`
+	CoinShortcut                 string `json:"coin_shortcut"`
+	FixtureDir                   string `json:"fixture_dir"`
+	Persist                      bool   `json:"persist"`
+	MinerAddress                 string `json:"miner_address,omitempty"`
+	BlockReward                  int64  `json:"block_reward,omitempty"`
+	FeePerKB                     int64  `json:"fee_per_kb,omitempty"`
+	MineInterval                 int    `json:"mine_interval,omitempty"`
+	ControlBinding               string `json:"control_binding,omitempty"`
+	Subversion                   string `json:"subversion"`
+	BlockAddressesToKeep         int    `json:"block_addresses_to_keep"`
+	MempoolWorkers               int    `json:"mempool_workers"`
+	MempoolSubWorkers            int    `json:"mempool_sub_workers"`
+	XPubMagic                    uint32 `json:"xpub_magic,omitempty"`
+	XPubMagicSegwitP2sh          uint32 `json:"xpub_magic_segwit_p2sh,omitempty"`
+	XPubMagicSegwitNative        uint32 `json:"xpub_magic_segwit_native,omitempty"`
+	Slip44                       uint32 `json:"slip44,omitempty"`
+	MinimumCoinbaseConfirmations int    `json:"minimumCoinbaseConfirmations,omitempty"`
+}
+
+type localBlock struct {
+	msg    *wire.MsgBlock
+	raw    []byte
+	hash   string
+	height uint32
+}
+
+type localTx struct {
+	msg  *wire.MsgTx
+	raw  []byte
+	txid string
+	// block is nil for the transactions in mempool
+	block *localBlock
+	// time when the transaction entered the mempool
+	time int64
+	fee  int64
+}
+
+type utxo struct {
+	out *wire.TxOut
+	// order of creation of the outputs, keeps the synthetic transactions deterministic
+	seq uint64
+}
+
+// LocalChain is a block chain without a backend daemon, it serves the blocks of a fixture directory
+// and mines synthetic blocks and transactions on demand
+type LocalChain struct {
+	*bchain.BaseChain
+	Mempool     *bchain.MempoolBitcoinType
+	ChainConfig *Configuration
+	Params      *chaincfg.Params
+	pushHandler func(bchain.NotificationType)
+	minerScript []byte
+	lock        sync.Mutex
+	blocks      []*localBlock
+	byHash      map[string]*localBlock
+	txs         map[string]*localTx
+	mempool     []*localTx
+	utxos       map[wire.OutPoint]*utxo
+	utxoSeq     uint64
+	control     *http.Server
+	stopMining  chan struct{}
+}
+
+// NewLocalChain returns new LocalChain instance
+func NewLocalChain(config json.RawMessage, pushHandler func(bchain.NotificationType)) (bchain.BlockChain, error) {
+	var c Configuration
+	err := json.Unmarshal(config, &c)
+	if err != nil {
+		return nil, errors.Annotatef(err, "Invalid configuration file")
+	}
+	// keep at least 100 mappings block->addresses to allow rollback
+	if c.BlockAddressesToKeep < 100 {
+		c.BlockAddressesToKeep = 100
+	}
+	if c.MempoolWorkers < 1 {
+		c.MempoolWorkers = 1
+	}
+	if c.MempoolSubWorkers < 1 {
+		c.MempoolSubWorkers = 1
+	}
+	if c.BlockReward == 0 {
+		c.BlockReward = defaultBlockReward
+	}
+	if c.FeePerKB == 0 {
+		c.FeePerKB = defaultFeePerKB
+	}
+	if c.Subversion == "" {
+		c.Subversion = "/LocalChain:1.0.0/"
+	}
+	params := btc.GetChainParams("regtest")
+	parser := btc.NewBitcoinParser(params, &btc.Configuration{
+		BlockAddressesToKeep:         c.BlockAddressesToKeep,
+		XPubMagic:                    c.XPubMagic,
+		XPubMagicSegwitP2sh:          c.XPubMagicSegwitP2sh,
+		XPubMagicSegwitNative:        c.XPubMagicSegwitNative,
+		Slip44:                       c.Slip44,
+		MinimumCoinbaseConfirmations: c.MinimumCoinbaseConfirmations,
+	})
+	s := &LocalChain{
+		BaseChain: &bchain.BaseChain{
+			Parser:  parser,
+			Testnet: true,
+			Network: "regtest",
+		},
+		ChainConfig: &c,
+		Params:      params,
+		pushHandler: pushHandler,
+	}
+	if c.MinerAddress != "" {
+		if s.minerScript, err = s.addressScript(c.MinerAddress); err != nil {
+			return nil, errors.Annotatef(err, "miner_address")
+		}
+	} else {
+		// the coinbases are paid to a fixed address if the miner address is not configured
+		a, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160([]byte("localchain miner")), params)
+		if err != nil {
+			return nil, err
+		}
+		if s.minerScript, err = txscript.PayToAddrScript(a); err != nil {
+			return nil, err
+		}
+	}
+	s.reset()
+	return s, nil
+}
+
+// Initialize loads the fixture directory and starts the control server and the automatic mining
+func (c *LocalChain) Initialize() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.loadFixture(); err != nil {
+		return errors.Annotatef(err, "fixture_dir %v", c.ChainConfig.FixtureDir)
+	}
+	glog.Info("localchain: loaded ", len(c.blocks), " blocks and ", len(c.mempool), " mempool transactions, best block ", c.tip().hash)
+	if c.ChainConfig.ControlBinding != "" {
+		c.control = c.newControlServer(c.ChainConfig.ControlBinding)
+		go func() {
+			glog.Info("localchain: control server starting on ", c.ChainConfig.ControlBinding)
+			if err := c.control.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				glog.Error("localchain: control server ", err)
+			}
+		}()
+	}
+	if c.ChainConfig.MineInterval > 0 {
+		c.stopMining = make(chan struct{})
+		go c.mineLoop(time.Duration(c.ChainConfig.MineInterval)*time.Second, c.stopMining)
+	}
+	return nil
+}
+
+// CreateMempool creates mempool if not already created, however does not initialize it
+func (c *LocalChain) CreateMempool(chain bchain.BlockChain) (bchain.Mempool, error) {
+	if c.Mempool == nil {
+		c.Mempool = bchain.NewMempoolBitcoinType(chain, c.ChainConfig.MempoolWorkers, c.ChainConfig.MempoolSubWorkers)
+	}
+	return c.Mempool, nil
+}
+
+// InitializeMempool sets the callbacks of the mempool
+func (c *LocalChain) InitializeMempool(addrDescForOutpoint bchain.AddrDescForOutpointFunc, onNewTxAddr bchain.OnNewTxAddrFunc, onNewTx bchain.OnNewTxFunc) error {
+	if c.Mempool == nil {
+		return errors.New("Mempool not created")
+	}
+	c.Mempool.AddrDescForOutpoint = addrDescForOutpoint
+	c.Mempool.OnNewTxAddr = onNewTxAddr
+	c.Mempool.OnNewTx = onNewTx
+	return nil
+}
+
+// Shutdown stops the automatic mining and the control server
+func (c *LocalChain) Shutdown(ctx context.Context) error {
+	if c.stopMining != nil {
+		close(c.stopMining)
+		c.stopMining = nil
+	}
+	if c.control != nil {
+		return c.control.Shutdown(ctx)
+	}
+	return nil
+}
+
+// GetCoinName returns the coin name
+func (c *LocalChain) GetCoinName() string {
+	return c.ChainConfig.CoinName
+}
+
+// GetSubversion returns the subversion
+func (c *LocalChain) GetSubversion() string {
+	return c.ChainConfig.Subversion
+}
+
+// GetChainInfo returns information about the local chain
+func (c *LocalChain) GetChainInfo() (*bchain.ChainInfo, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return &bchain.ChainInfo{
+		Chain:         c.Network,
+		Blocks:        len(c.blocks) - 1,
+		Headers:       len(c.blocks) - 1,
+		Bestblockhash: c.tip().hash,
+		Difficulty:    "1",
+		Version:       "localchain",
+		Subversion:    c.ChainConfig.Subversion,
+	}, nil
+}
+
+// GetBestBlockHash returns hash of the tip of the chain
+func (c *LocalChain) GetBestBlockHash() (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.tip().hash, nil
+}
+
+// GetBestBlockHeight returns height of the tip of the chain
+func (c *LocalChain) GetBestBlockHeight() (uint32, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.tip().height, nil
+}
+
+// GetBlockHash returns hash of block in best-block-chain at given height
+func (c *LocalChain) GetBlockHash(height uint32) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if int(height) >= len(c.blocks) {
+		return "", bchain.ErrBlockNotFound
+	}
+	return c.blocks[height].hash, nil
+}
+
+func (c *LocalChain) findBlock(hash string, height uint32) *localBlock {
+	if hash != "" {
+		return c.byHash[hash]
+	}
+	if int(height) < len(c.blocks) {
+		return c.blocks[height]
+	}
+	return nil
+}
+
+func (c *LocalChain) blockHeader(b *localBlock) bchain.BlockHeader {
+	h := bchain.BlockHeader{
+		Hash:          b.hash,
+		Height:        b.height,
+		Confirmations: int(c.tip().height-b.height) + 1,
+		Size:          len(b.raw),
+		Time:          b.msg.Header.Timestamp.Unix(),
+	}
+	if b.height > 0 {
+		h.Prev = b.msg.Header.PrevBlock.String()
+	}
+	if int(b.height)+1 < len(c.blocks) {
+		h.Next = c.blocks[b.height+1].hash
+	}
+	return h
+}
+
+// GetBlockHeader returns header of block with given hash
+func (c *LocalChain) GetBlockHeader(hash string) (*bchain.BlockHeader, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	b := c.byHash[hash]
+	if b == nil {
+		return nil, bchain.ErrBlockNotFound
+	}
+	h := c.blockHeader(b)
+	return &h, nil
+}
+
+// GetBlock returns block with given hash or height, hash has precedence if both passed
+func (c *LocalChain) GetBlock(hash string, height uint32) (*bchain.Block, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	b := c.findBlock(hash, height)
+	if b == nil {
+		return nil, bchain.ErrBlockNotFound
+	}
+	block, err := c.Parser.ParseBlock(b.raw)
+	if err != nil {
+		return nil, errors.Annotatef(err, "hash %v", b.hash)
+	}
+	block.BlockHeader = c.blockHeader(b)
+	return block, nil
+}
+
+// GetBlockInfo returns extended header (more info than in bchain.BlockHeader) with a list of txids
+func (c *LocalChain) GetBlockInfo(hash string) (*bchain.BlockInfo, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	b := c.byHash[hash]
+	if b == nil {
+		return nil, bchain.ErrBlockNotFound
+	}
+	bi := &bchain.BlockInfo{
+		BlockHeader: c.blockHeader(b),
+		Version:     common.JSONNumber(strconv.FormatInt(int64(b.msg.Header.Version), 10)),
+		MerkleRoot:  b.msg.Header.MerkleRoot.String(),
+		Nonce:       common.JSONNumber(strconv.FormatUint(uint64(b.msg.Header.Nonce), 10)),
+		Bits:        fmt.Sprintf("%08x", b.msg.Header.Bits),
+		Difficulty:  "1",
+		Txids:       make([]string, len(b.msg.Transactions)),
+	}
+	for i, tx := range b.msg.Transactions {
+		bi.Txids[i] = tx.TxHash().String()
+	}
+	return bi, nil
+}
+
+// GetMempoolTransactions returns transactions in mempool
+func (c *LocalChain) GetMempoolTransactions() ([]string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	txids := make([]string, len(c.mempool))
+	for i, t := range c.mempool {
+		txids[i] = t.txid
+	}
+	return txids, nil
+}
+
+// GetTransaction returns a transaction by the transaction ID
+func (c *LocalChain) GetTransaction(txid string) (*bchain.Tx, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	t := c.txs[txid]
+	if t == nil {
+		return nil, bchain.ErrTxNotFound
+	}
+	tx := c.Parser.(*btc.BitcoinParser).TxFromMsgTx(t.msg, true)
+	tx.Hex = hex.EncodeToString(t.raw)
+	if t.block != nil {
+		tx.Confirmations = c.tip().height - t.block.height + 1
+		tx.Blocktime = t.block.msg.Header.Timestamp.Unix()
+		tx.Time = tx.Blocktime
+	} else {
+		tx.Time = t.time
+	}
+	return &tx, nil
+}
+
+// GetTransactionForMempool returns a transaction by the transaction ID
+func (c *LocalChain) GetTransactionForMempool(txid string) (*bchain.Tx, error) {
+	return c.GetTransaction(txid)
+}
+
+// GetTransactionSpecific returns the transaction as json, the local chain has no coin specific data
+func (c *LocalChain) GetTransactionSpecific(tx *bchain.Tx) (json.RawMessage, error) {
+	if tx.Hex == "" {
+		var err error
+		if tx, err = c.GetTransaction(tx.Txid); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(tx)
+}
+
+// EstimateSmartFee returns the configured fee per kB
+func (c *LocalChain) EstimateSmartFee(blocks int, conservative bool) (big.Int, error) {
+	var v big.Int
+	v.SetInt64(c.ChainConfig.FeePerKB)
+	return v, nil
+}
+
+// EstimateFee returns the configured fee per kB
+func (c *LocalChain) EstimateFee(blocks int) (big.Int, error) {
+	return c.EstimateSmartFee(blocks, true)
+}
+
+// SendRawTransaction adds the transaction to mempool, the inputs must be unspent outputs of the chain or mempool,
+// the scripts are not verified
+func (c *LocalChain) SendRawTransaction(txHex string) (string, error) {
+	raw, err := hex.DecodeString(txHex)
+	if err != nil {
+		return "", errors.New("TX decode failed")
+	}
+	msg := wire.NewMsgTx(wire.TxVersion)
+	if err = msg.Deserialize(bytes.NewReader(raw)); err != nil {
+		return "", errors.New("TX decode failed")
+	}
+	c.lock.Lock()
+	t, err := c.addToMempool(msg, time.Now().Unix())
+	c.lock.Unlock()
+	if err != nil {
+		return "", err
+	}
+	c.notify(bchain.NotificationNewTx)
+	return t.txid, nil
+}
+
+func (c *LocalChain) notify(n bchain.NotificationType) {
+	if c.pushHandler != nil {
+		c.pushHandler(n)
+	}
+}
+
+func (c *LocalChain) addressScript(address string) ([]byte, error) {
+	a, err := btcutil.DecodeAddress(address, c.Params)
+	if err != nil {
+		return nil, err
+	}
+	return txscript.PayToAddrScript(a)
+}
+
+func (c *LocalChain) tip() *localBlock {
+	return c.blocks[len(c.blocks)-1]
+}
+
+// reset clears the chain, the chain is then empty until the genesis block is connected
+func (c *LocalChain) reset() {
+	c.blocks = nil
+	c.byHash = make(map[string]*localBlock)
+	c.txs = make(map[string]*localTx)
+	c.mempool = nil
+	c.utxos = make(map[wire.OutPoint]*utxo)
+}
+
+// applyTx spends the inputs and adds the outputs of the transaction to the unspent outputs
+// and returns the fee of the transaction, the unspent outputs are not changed if the transaction is rejected
+func (c *LocalChain) applyTx(msg *wire.MsgTx, coinbase bool) (int64, error) {
+	hash := msg.TxHash()
+	if _, found := c.txs[hash.String()]; found {
+		return 0, errors.Errorf("txn-already-known %v", hash)
+	}
+	var out int64
+	for _, to := range msg.TxOut {
+		if to.Value < 0 || to.Value > btcutil.MaxSatoshi {
+			return 0, errors.Errorf("bad-txns-vout-outofrange %v", hash)
+		}
+		if out += to.Value; out > btcutil.MaxSatoshi {
+			return 0, errors.Errorf("bad-txns-txouttotal-toolarge %v", hash)
+		}
+	}
+	var in int64
+	if !coinbase {
+		spent := make(map[wire.OutPoint]struct{}, len(msg.TxIn))
+		for _, ti := range msg.TxIn {
+			if _, found := spent[ti.PreviousOutPoint]; found {
+				return 0, errors.Errorf("bad-txns-inputs-duplicate %v", ti.PreviousOutPoint)
+			}
+			spent[ti.PreviousOutPoint] = struct{}{}
+			u := c.utxos[ti.PreviousOutPoint]
+			if u == nil {
+				return 0, errors.Errorf("bad-txns-inputs-missingorspent %v", ti.PreviousOutPoint)
+			}
+			in += u.out.Value
+		}
+		if out > in {
+			return 0, errors.Errorf("bad-txns-in-belowout %v", hash)
+		}
+		for _, ti := range msg.TxIn {
+			delete(c.utxos, ti.PreviousOutPoint)
+		}
+	}
+	for i, to := range msg.TxOut {
+		c.utxoSeq++
+		c.utxos[*wire.NewOutPoint(&hash, uint32(i))] = &utxo{out: to, seq: c.utxoSeq}
+	}
+	if coinbase {
+		return 0, nil
+	}
+	return in - out, nil
+}
+
+func serializeTx(msg *wire.MsgTx) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := msg.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *LocalChain) addToMempool(msg *wire.MsgTx, txTime int64) (*localTx, error) {
+	if len(msg.TxIn) == 0 || len(msg.TxOut) == 0 {
+		return nil, errors.New("bad-txns-vin-or-vout-empty")
+	}
+	raw, err := serializeTx(msg)
+	if err != nil {
+		return nil, err
+	}
+	fee, err := c.applyTx(msg, false)
+	if err != nil {
+		return nil, err
+	}
+	t := &localTx{msg: msg, raw: raw, txid: msg.TxHash().String(), time: txTime, fee: fee}
+	c.txs[t.txid] = t
+	c.mempool = append(c.mempool, t)
+	return t, nil
+}
+
+// connectBlock appends the block to the chain, the transactions of the block which are not in mempool are applied
+func (c *LocalChain) connectBlock(msg *wire.MsgBlock, raw []byte) (*localBlock, error) {
+	b := &localBlock{
+		msg:    msg,
+		raw:    raw,
+		hash:   msg.BlockHash().String(),
+		height: uint32(len(c.blocks)),
+	}
+	if b.height > 0 && msg.Header.PrevBlock.String() != c.tip().hash {
+		return nil, errors.Errorf("block %v at height %v does not connect to the previous block %v", b.hash, b.height, c.tip().hash)
+	}
+	if len(msg.Transactions) == 0 {
+		return nil, errors.Errorf("block %v has no transactions", b.hash)
+	}
+	inBlock := make(map[string]struct{}, len(msg.Transactions))
+	for i, tx := range msg.Transactions {
+		txid := tx.TxHash().String()
+		inBlock[txid] = struct{}{}
+		if t := c.txs[txid]; t != nil && t.block == nil {
+			t.block = b
+			continue
+		}
+		// the outputs of the genesis coinbase are not spendable
+		if b.height > 0 {
+			if _, err := c.applyTx(tx, i == 0); err != nil {
+				return nil, errors.Annotatef(err, "block %v", b.hash)
+			}
+		}
+		t := &localTx{msg: tx, txid: txid, block: b}
+		var err error
+		if t.raw, err = serializeTx(tx); err != nil {
+			return nil, err
+		}
+		c.txs[txid] = t
+	}
+	mempool := c.mempool[:0]
+	for _, t := range c.mempool {
+		if _, found := inBlock[t.txid]; !found {
+			mempool = append(mempool, t)
+		}
+	}
+	c.mempool = mempool
+	c.blocks = append(c.blocks, b)
+	c.byHash[b.hash] = b
+	return b, nil
+}
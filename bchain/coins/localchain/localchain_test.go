@@ -0,0 +1,254 @@
+// +build unittest
+
+package localchain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/martinboehm/btcd/wire"
+	"github.com/martinboehm/btcutil"
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+)
+
+type testNotifications struct {
+	sync.Mutex
+	n []bchain.NotificationType
+}
+
+func (t *testNotifications) handler(n bchain.NotificationType) {
+	t.Lock()
+	t.n = append(t.n, n)
+	t.Unlock()
+}
+
+func (t *testNotifications) take() []bchain.NotificationType {
+	t.Lock()
+	defer t.Unlock()
+	n := t.n
+	t.n = nil
+	return n
+}
+
+func newTestChain(t *testing.T, config map[string]interface{}, notifications *testNotifications) *LocalChain {
+	t.Helper()
+	config["coin_name"] = "Localchain"
+	b, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var handler func(bchain.NotificationType)
+	if notifications != nil {
+		handler = notifications.handler
+	}
+	bc, err := NewLocalChain(b, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := bc.(*LocalChain)
+	if err = c.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func testAddress(t *testing.T, c *LocalChain, seed string) string {
+	a, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160([]byte(seed)), c.Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a.EncodeAddress()
+}
+
+func bestBlock(t *testing.T, c *LocalChain) (uint32, string) {
+	t.Helper()
+	height, err := c.GetBestBlockHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := c.GetBestBlockHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return height, hash
+}
+
+func TestLocalChain_Genesis(t *testing.T) {
+	c := newTestChain(t, map[string]interface{}{}, nil)
+	height, hash := bestBlock(t, c)
+	if height != 0 || hash != c.Params.GenesisHash.String() {
+		t.Fatalf("best block %v %v, want genesis %v", height, hash, c.Params.GenesisHash)
+	}
+	b, err := c.GetBlock("", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Hash != hash || len(b.Txs) != 1 || b.Confirmations != 1 {
+		t.Errorf("GetBlock = %+v", b.BlockHeader)
+	}
+	if _, err = c.GetBlock("", 1); err != bchain.ErrBlockNotFound {
+		t.Errorf("GetBlock above tip: %v, want ErrBlockNotFound", err)
+	}
+	if _, err = c.Send(testAddress(t, c, "a"), 1000); err == nil {
+		t.Error("Send without mined outputs succeeded")
+	}
+}
+
+func TestLocalChain_Fixture(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localchain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	c := newTestChain(t, map[string]interface{}{}, nil)
+	address := testAddress(t, c, "a")
+	fixture := `[{"blocks": 3}, {"send": [{"address": "` + address + `", "value": 100000}], "blocks": 1}]`
+	if err = ioutil.WriteFile(filepath.Join(dir, fixtureFile), []byte(fixture), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	notifications := &testNotifications{}
+	c = newTestChain(t, map[string]interface{}{"fixture_dir": dir, "persist": true}, notifications)
+	height, hash := bestBlock(t, c)
+	if height != 4 {
+		t.Fatalf("best height %v, want 4", height)
+	}
+	b, err := c.GetBlock(hash, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Txs) != 2 || b.Prev != c.blocks[3].hash || b.Height != 4 {
+		t.Fatalf("GetBlock = %+v with %d txs", b.BlockHeader, len(b.Txs))
+	}
+	tx, err := c.GetTransaction(b.Txs[1].Txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Confirmations != 1 || tx.Blocktime != b.Time || tx.Vout[0].ValueSat.Int64() != 100000 || !reflect.DeepEqual(tx.Vout[0].ScriptPubKey.Addresses, []string{address}) {
+		t.Errorf("GetTransaction = %+v", tx)
+	}
+	// the generated chain does not depend on the time of the generation
+	if c2 := newTestChain(t, map[string]interface{}{"fixture_dir": dir, "persist": false}, nil); c2.tip().hash != hash {
+		t.Errorf("regenerated best block %v, want %v", c2.tip().hash, hash)
+	}
+
+	// synthetic transaction mined on demand
+	txid, err := c.Send(address, 5000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txids, _ := c.GetMempoolTransactions(); !reflect.DeepEqual(txids, []string{txid}) {
+		t.Errorf("GetMempoolTransactions = %v, want %v", txids, txid)
+	}
+	if _, err = c.SendRawTransaction(c.txs[txid].msg.TxIn[0].PreviousOutPoint.Hash.String()); err == nil {
+		t.Error("SendRawTransaction of invalid data succeeded")
+	}
+	tx, _ = c.GetTransaction(txid)
+	if _, err = c.SendRawTransaction(tx.Hex); err == nil {
+		t.Error("SendRawTransaction of a known transaction succeeded")
+	}
+	hashes, err := c.Generate(1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txids, _ := c.GetMempoolTransactions(); len(txids) != 0 {
+		t.Errorf("GetMempoolTransactions after mining = %v", txids)
+	}
+	if tx, _ = c.GetTransaction(txid); tx.Confirmations != 1 {
+		t.Errorf("confirmations %v, want 1", tx.Confirmations)
+	}
+	if n := notifications.take(); !reflect.DeepEqual(n, []bchain.NotificationType{bchain.NotificationNewTx, bchain.NotificationNewBlock}) {
+		t.Errorf("notifications %v", n)
+	}
+
+	// the persisted blocks are loaded after restart, the fixture steps are not run again
+	c2 := newTestChain(t, map[string]interface{}{"fixture_dir": dir, "persist": true}, nil)
+	if height, hash = bestBlock(t, c2); height != 5 || hash != hashes[0] {
+		t.Errorf("best block after restart %v %v, want 5 %v", height, hash, hashes[0])
+	}
+
+	// the transactions of the disconnected blocks return to mempool
+	if err = c2.Invalidate(4); err != nil {
+		t.Fatal(err)
+	}
+	if height, _ = bestBlock(t, c2); height != 4 {
+		t.Errorf("best height after invalidate %v, want 4", height)
+	}
+	if txids, _ := c2.GetMempoolTransactions(); !reflect.DeepEqual(txids, []string{txid}) {
+		t.Errorf("GetMempoolTransactions after invalidate = %v, want %v", txids, txid)
+	}
+	if _, err = os.Stat(filepath.Join(dir, blocksDir, "5.hex")); !os.IsNotExist(err) {
+		t.Errorf("disconnected block file not removed: %v", err)
+	}
+	if tx, _ = c2.GetTransaction(txid); tx.Confirmations != 0 {
+		t.Errorf("confirmations after invalidate %v, want 0", tx.Confirmations)
+	}
+}
+
+func TestLocalChain_RejectedSpend(t *testing.T) {
+	c := newTestChain(t, map[string]interface{}{}, nil)
+	if _, err := c.Generate(1, ""); err != nil {
+		t.Fatal(err)
+	}
+	coinbase := c.blocks[1].msg.Transactions[0]
+	hash := coinbase.TxHash()
+	reward := coinbase.TxOut[0].Value
+	script, err := c.addressScript(testAddress(t, c, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spend := func(value int64, outpoints ...*wire.OutPoint) error {
+		msg := wire.NewMsgTx(wire.TxVersion)
+		for _, op := range outpoints {
+			msg.AddTxIn(wire.NewTxIn(op, nil, nil))
+		}
+		msg.AddTxOut(wire.NewTxOut(value, script))
+		var buf bytes.Buffer
+		if err := msg.Serialize(&buf); err != nil {
+			t.Fatal(err)
+		}
+		_, err := c.SendRawTransaction(hex.EncodeToString(buf.Bytes()))
+		return err
+	}
+	outpoint := wire.NewOutPoint(&hash, 0)
+	rejected := []struct {
+		name      string
+		value     int64
+		outpoints []*wire.OutPoint
+	}{
+		{"output above input", reward + 1, []*wire.OutPoint{outpoint}},
+		{"duplicate input", reward + 1, []*wire.OutPoint{outpoint, outpoint}},
+		{"negative output", -1, []*wire.OutPoint{outpoint}},
+		{"missing input", 1000, []*wire.OutPoint{wire.NewOutPoint(&hash, 1)}},
+	}
+	for _, r := range rejected {
+		if err := spend(r.value, r.outpoints...); err == nil {
+			t.Errorf("%v: transaction accepted", r.name)
+		}
+		// the rejected transaction does not spend the output
+		if u := c.utxos[*outpoint]; u == nil || u.out.Value != reward || len(c.utxos) != 1 {
+			t.Fatalf("%v: unspent outputs changed by the rejected transaction: %v", r.name, c.utxos)
+		}
+	}
+	if txids, _ := c.GetMempoolTransactions(); len(txids) != 0 {
+		t.Errorf("GetMempoolTransactions = %v, want empty", txids)
+	}
+
+	// the output is spendable after the rejections, but only once
+	if err = spend(reward-1000, outpoint); err != nil {
+		t.Fatal(err)
+	}
+	if err = spend(reward-2000, outpoint); err == nil {
+		t.Error("double spend accepted")
+	}
+	if txids, _ := c.GetMempoolTransactions(); len(txids) != 1 {
+		t.Errorf("GetMempoolTransactions = %v, want one transaction", txids)
+	}
+}
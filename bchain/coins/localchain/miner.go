@@ -0,0 +1,200 @@
+package localchain
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/martinboehm/btcd/blockchain"
+	"github.com/martinboehm/btcd/chaincfg/chainhash"
+	"github.com/martinboehm/btcd/wire"
+	"github.com/martinboehm/btcutil"
+	"github.com/martinboehm/btcutil/txscript"
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+)
+
+// Generate mines the blocks containing all transactions of mempool and returns their hashes,
+// the coinbases are paid to the address or to the miner address if the address is empty
+func (c *LocalChain) Generate(blocks int, address string) ([]string, error) {
+	script := c.minerScript
+	if address != "" {
+		var err error
+		if script, err = c.addressScript(address); err != nil {
+			return nil, err
+		}
+	}
+	hashes := make([]string, 0, blocks)
+	c.lock.Lock()
+	for i := 0; i < blocks; i++ {
+		t := time.Unix(time.Now().Unix(), 0)
+		if prev := c.tip().msg.Header.Timestamp; !t.After(prev) {
+			t = prev.Add(time.Second)
+		}
+		b, err := c.mineBlock(script, t)
+		if err != nil {
+			c.lock.Unlock()
+			return nil, err
+		}
+		hashes = append(hashes, b.hash)
+	}
+	c.lock.Unlock()
+	if len(hashes) > 0 {
+		glog.Info("localchain: generated ", len(hashes), " blocks, best block ", hashes[len(hashes)-1])
+		c.notify(bchain.NotificationNewBlock)
+	}
+	return hashes, nil
+}
+
+// Send adds to mempool a synthetic transaction paying the value in satoshis to the address from the outputs of the miner
+func (c *LocalChain) Send(address string, value int64) (string, error) {
+	script, err := c.addressScript(address)
+	if err != nil {
+		return "", err
+	}
+	c.lock.Lock()
+	t, err := c.sendSynthetic(script, value, time.Now().Unix())
+	c.lock.Unlock()
+	if err != nil {
+		return "", err
+	}
+	c.notify(bchain.NotificationNewTx)
+	return t.txid, nil
+}
+
+// Invalidate disconnects the blocks above the height, their transactions are returned to mempool
+func (c *LocalChain) Invalidate(height uint32) error {
+	c.lock.Lock()
+	best := c.tip().height
+	if height >= best {
+		c.lock.Unlock()
+		return nil
+	}
+	blocks := c.blocks[:height+1]
+	var txs []*localTx
+	for _, b := range c.blocks[height+1:] {
+		for _, tx := range b.msg.Transactions[1:] {
+			txs = append(txs, c.txs[tx.TxHash().String()])
+		}
+	}
+	txs = append(txs, c.mempool...)
+	c.reset()
+	for _, b := range blocks {
+		if _, err := c.connectBlock(b.msg, b.raw); err != nil {
+			c.lock.Unlock()
+			return err
+		}
+	}
+	now := time.Now().Unix()
+	for _, t := range txs {
+		if t.time == 0 {
+			t.time = now
+		}
+		if _, err := c.addToMempool(t.msg, t.time); err != nil {
+			glog.Warning("localchain: transaction ", t.txid, " removed from mempool: ", err)
+		}
+	}
+	var err error
+	for h := best; h > height && err == nil; h-- {
+		err = c.removePersistedBlock(h)
+	}
+	c.lock.Unlock()
+	glog.Info("localchain: disconnected blocks ", height+1, "-", best)
+	c.notify(bchain.NotificationNewBlock)
+	return err
+}
+
+func (c *LocalChain) mineLoop(period time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := c.Generate(1, ""); err != nil {
+				glog.Error("localchain: mining ", err)
+			}
+		}
+	}
+}
+
+// mineBlock connects a new block with the transactions of mempool
+func (c *LocalChain) mineBlock(script []byte, t time.Time) (*localBlock, error) {
+	height := c.tip().height + 1
+	var fees int64
+	for _, tx := range c.mempool {
+		fees += tx.fee
+	}
+	// the height in the coinbase makes the coinbases of the blocks unique
+	coinbaseScript, err := txscript.NewScriptBuilder().AddInt64(int64(height)).AddData([]byte("localchain")).Script()
+	if err != nil {
+		return nil, err
+	}
+	coinbase := wire.NewMsgTx(wire.TxVersion)
+	coinbase.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{}, wire.MaxPrevOutIndex), coinbaseScript, nil))
+	coinbase.AddTxOut(wire.NewTxOut(c.ChainConfig.BlockReward+fees, script))
+	txs := []*btcutil.Tx{btcutil.NewTx(coinbase)}
+	for _, tx := range c.mempool {
+		txs = append(txs, btcutil.NewTx(tx.msg))
+	}
+	merkles := blockchain.BuildMerkleTreeStore(txs, false)
+	msg := wire.NewMsgBlock(&wire.BlockHeader{
+		Version:    0x20000000,
+		PrevBlock:  c.tip().msg.BlockHash(),
+		MerkleRoot: *merkles[len(merkles)-1],
+		Timestamp:  t,
+		Bits:       c.Params.PowLimitBits,
+	})
+	for _, tx := range txs {
+		if err = msg.AddTransaction(tx.MsgTx()); err != nil {
+			return nil, err
+		}
+	}
+	var buf bytes.Buffer
+	if err = msg.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	b, err := c.connectBlock(msg, buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if err = c.persistBlock(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// sendSynthetic adds to mempool a transaction spending the outputs of the miner, in the order of their creation
+func (c *LocalChain) sendSynthetic(script []byte, value int64, txTime int64) (*localTx, error) {
+	if value <= 0 {
+		return nil, errors.New("value must be positive")
+	}
+	var outpoints []wire.OutPoint
+	for op, u := range c.utxos {
+		if bytes.Equal(u.out.PkScript, c.minerScript) {
+			outpoints = append(outpoints, op)
+		}
+	}
+	sort.Slice(outpoints, func(i, j int) bool {
+		return c.utxos[outpoints[i]].seq < c.utxos[outpoints[j]].seq
+	})
+	msg := wire.NewMsgTx(wire.TxVersion)
+	var sum int64
+	for i := range outpoints {
+		if sum >= value+syntheticTxFee {
+			break
+		}
+		msg.AddTxIn(wire.NewTxIn(&outpoints[i], nil, nil))
+		sum += c.utxos[outpoints[i]].out.Value
+	}
+	if sum < value+syntheticTxFee {
+		return nil, errors.Errorf("insufficient funds of the miner %v, generate blocks first", sum)
+	}
+	msg.AddTxOut(wire.NewTxOut(value, script))
+	if change := sum - value - syntheticTxFee; change > 0 {
+		msg.AddTxOut(wire.NewTxOut(change, c.minerScript))
+	}
+	return c.addToMempool(msg, txTime)
+}
@@ -0,0 +1,116 @@
+// +build unittest
+
+package localchain
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/scryptachain/blockbook-scrypta/common"
+	"github.com/scryptachain/blockbook-scrypta/db"
+)
+
+// TestLocalChain_SyncFork syncs the index from the local chain and checks that the index follows a fork of the chain
+func TestLocalChain_SyncFork(t *testing.T) {
+	c := newTestChain(t, map[string]interface{}{}, nil)
+	alice := testAddress(t, c, "alice")
+	bob := testAddress(t, c, "bob")
+	if _, err := c.Generate(4, ""); err != nil {
+		t.Fatal(err)
+	}
+	txid, err := c.Send(alice, 100000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Generate(1, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "localchain_sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	metrics, err := common.GetMetrics("Localchain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := db.NewRocksDB(dir, 1<<17, 1<<14, c.GetChainParser(), metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	is, err := d.LoadInternalState("Localchain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SetInternalState(is)
+	sw, err := db.NewSyncWorker(d, c, 1, 0, 0, false, make(chan os.Signal), metrics, is)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBestBlock := func(want uint32) {
+		t.Helper()
+		height, hash, err := d.GetBestBlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if height != want || hash != c.tip().hash {
+			t.Fatalf("indexed best block %v %v, want %v %v", height, hash, want, c.tip().hash)
+		}
+	}
+	txHeight := func(txid string) uint32 {
+		t.Helper()
+		ta, err := d.GetTxAddresses(txid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ta == nil {
+			return 0
+		}
+		return ta.Height
+	}
+
+	if err = sw.ResyncIndex(nil, true); err != nil {
+		t.Fatal(err)
+	}
+	checkBestBlock(5)
+	if h := txHeight(txid); h != 5 {
+		t.Errorf("transaction %v indexed at height %v, want 5", txid, h)
+	}
+
+	// blocks 4 and 5 are replaced by three blocks paying to bob, the payment to alice returns to mempool and is mined again
+	var orphaned []string
+	for _, b := range c.blocks[4:] {
+		orphaned = append(orphaned, b.msg.Transactions[0].TxHash().String())
+	}
+	if err = c.Invalidate(3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Generate(3, bob); err != nil {
+		t.Fatal(err)
+	}
+	if err = sw.ResyncIndex(nil, false); err != nil {
+		t.Fatal(err)
+	}
+	checkBestBlock(6)
+	for _, o := range orphaned {
+		if h := txHeight(o); h != 0 {
+			t.Errorf("orphaned coinbase %v indexed at height %v", o, h)
+		}
+	}
+	if h := txHeight(txid); h != 4 {
+		t.Errorf("transaction %v indexed at height %v, want 4", txid, h)
+	}
+	var bobTxs int
+	if err = d.GetTransactions(bob, 0, ^uint32(0), func(txid string, height uint32, indexes []int32) error {
+		bobTxs++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if bobTxs != 3 {
+		t.Errorf("bob has %d transactions, want 3", bobTxs)
+	}
+}
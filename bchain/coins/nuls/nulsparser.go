@@ -202,3 +202,8 @@ func (p *NulsParser) xor(body []byte) byte {
 	}
 	return xor
 }
+
+// CreatePsbt is unsupported
+func (p *NulsParser) CreatePsbt(xpub string, origin *bchain.XpubKeyOrigin, inputs []bchain.PsbtInput, outputs []bchain.PsbtOutput, lockTime uint32) ([]byte, error) {
+	return nil, errors.New("Not supported")
+}
@@ -77,15 +77,16 @@ func (b *ScryptaRPC) GetBlock(hash string, height uint32) (*bchain.Block, error)
 	if res.Error != nil {
 		return nil, errors.Annotatef(res.Error, "hash %v", hash)
 	}
-	txs := make([]bchain.Tx, 0, len(res.Result.Txids))
-	for _, txid := range res.Result.Txids {
-		tx, err := b.GetTransaction(txid)
-		if err != nil {
-			if err == bchain.ErrTxNotFound {
-				glog.Errorf("rpc: getblock: skipping transanction in block %s due error: %s", hash, err)
-				continue
-			}
-			return nil, err
+	// the transactions are requested in batches
+	btxs, err := b.GetTransactions(res.Result.Txids)
+	if err != nil {
+		return nil, errors.Annotatef(err, "hash %v", hash)
+	}
+	txs := make([]bchain.Tx, 0, len(btxs))
+	for i, tx := range btxs {
+		if tx == nil {
+			glog.Errorf("rpc: getblock: skipping transanction %s in block %s due error: %s", res.Result.Txids[i], hash, bchain.ErrTxNotFound)
+			continue
 		}
 		txs = append(txs, *tx)
 	}
@@ -100,6 +101,11 @@ func (b *ScryptaRPC) GetTransactionForMempool(txid string) (*bchain.Tx, error) {
 	return b.GetTransaction(txid)
 }
 
+// GetTransactionsForMempool returns the mempool transactions using batched requests
+func (b *ScryptaRPC) GetTransactionsForMempool(txids []string) ([]*bchain.Tx, error) {
+	return b.GetTransactions(txids)
+}
+
 // MasternodeList returns masternodes list
 func (b *ScryptaRPC) MasternodeList() ([]interface{}, error) {
 	glog.V(1).Info("rpc: masternodelist")
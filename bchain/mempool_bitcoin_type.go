@@ -2,26 +2,41 @@ package bchain
 
 import (
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/golang/glog"
 )
 
+// number of transactions requested from the backend in one batch if the chain supports it
+const mempoolBatchSize = 100
+
 type chanInputPayload struct {
 	tx    *MempoolTx
 	index int
 }
 
+// inputResult is the result of getInputAddress, pending is set if the input transaction must be requested from the backend
+type inputResult struct {
+	ai      *addrIndex
+	index   int
+	pending bool
+}
+
 // MempoolBitcoinType is mempool handle.
 type MempoolBitcoinType struct {
 	BaseMempool
-	chanTxid            chan string
+	chanTxids           chan []string
 	chanAddrIndex       chan txidio
+	batcher             MempoolTransactionsBatcher
 	AddrDescForOutpoint AddrDescForOutpointFunc
+	workersMux          sync.Mutex
+	stopWorkers         chan struct{}
 }
 
 // NewMempoolBitcoinType creates new mempool handler.
-// For now there is no cleanup of sync routines, the expectation is that the mempool is created only once per process
+// The sync routines are stopped only when the number of workers is changed by SetWorkers,
+// the expectation is that the mempool is created only once per process
 func NewMempoolBitcoinType(chain BlockChain, workers int, subworkers int) *MempoolBitcoinType {
 	m := &MempoolBitcoinType{
 		BaseMempool: BaseMempool{
@@ -29,35 +44,98 @@ func NewMempoolBitcoinType(chain BlockChain, workers int, subworkers int) *Mempo
 			txEntries:    make(map[string]txEntry),
 			addrDescToTx: make(map[string][]Outpoint),
 		},
-		chanTxid:      make(chan string, 1),
+		chanTxids:     make(chan []string, 1),
 		chanAddrIndex: make(chan txidio, 1),
 	}
+	// the transactions are requested in batches if the chain supports it
+	m.batcher, _ = chain.(MempoolTransactionsBatcher)
+	m.startWorkers(workers, subworkers)
+	glog.Info("mempool: starting with ", workers, "*", subworkers, " sync workers")
+	return m
+}
+
+// startWorkers starts the sync workers, they run until the channel stopWorkers is closed
+func (m *MempoolBitcoinType) startWorkers(workers int, subworkers int) {
+	stop := make(chan struct{})
+	m.workersMux.Lock()
+	if m.stopWorkers != nil {
+		close(m.stopWorkers)
+	}
+	m.stopWorkers = stop
+	m.workersMux.Unlock()
 	for i := 0; i < workers; i++ {
 		go func(i int) {
 			chanInput := make(chan chanInputPayload, 1)
-			chanResult := make(chan *addrIndex, 1)
+			chanResult := make(chan inputResult, 1)
+			defer close(chanInput)
 			for j := 0; j < subworkers; j++ {
 				go func(j int) {
 					for payload := range chanInput {
-						ai := m.getInputAddress(&payload)
-						chanResult <- ai
+						chanResult <- m.getInputAddress(&payload)
 					}
 				}(j)
 			}
-			for txid := range m.chanTxid {
-				io, ok := m.getTxAddrs(txid, chanInput, chanResult)
-				if !ok {
-					io = []addrIndex{}
+			for {
+				var txids []string
+				select {
+				case <-stop:
+					return
+				case txids = <-m.chanTxids:
+				}
+				txs := m.getTransactions(txids)
+				for k, txid := range txids {
+					io, ok := m.getTxAddrs(txid, txs[k], chanInput, chanResult)
+					if !ok {
+						io = []addrIndex{}
+					}
+					m.chanAddrIndex <- txidio{txid, io}
 				}
-				m.chanAddrIndex <- txidio{txid, io}
 			}
 		}(i)
 	}
-	glog.Info("mempool: starting with ", workers, "*", subworkers, " sync workers")
-	return m
 }
 
-func (m *MempoolBitcoinType) getInputAddress(payload *chanInputPayload) *addrIndex {
+// SetWorkers replaces the sync workers by the given number of workers and subworkers,
+// the replaced workers finish the transactions they are processing
+func (m *MempoolBitcoinType) SetWorkers(workers int, subworkers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	if subworkers < 1 {
+		subworkers = 1
+	}
+	m.startWorkers(workers, subworkers)
+	glog.Info("mempool: using ", workers, "*", subworkers, " sync workers")
+}
+
+// getTransactions returns the transactions from the backend, in one batch if the chain supports it,
+// the transactions which cannot be returned are nil
+func (m *MempoolBitcoinType) getTransactions(txids []string) []*Tx {
+	if m.batcher != nil && len(txids) > 1 {
+		txs, err := m.batcher.GetTransactionsForMempool(txids)
+		if err == nil {
+			for i := range txs {
+				if txs[i] == nil {
+					glog.Error("cannot get transaction ", txids[i], ": ", ErrTxNotFound)
+				}
+			}
+			return txs
+		}
+		glog.Error("cannot get batch of ", len(txids), " transactions: ", err, ", requesting them one by one")
+	}
+	txs := make([]*Tx, len(txids))
+	for i, txid := range txids {
+		tx, err := m.chain.GetTransactionForMempool(txid)
+		if err != nil {
+			glog.Error("cannot get transaction ", txid, ": ", err)
+			continue
+		}
+		txs[i] = tx
+	}
+	return txs
+}
+
+func (m *MempoolBitcoinType) getInputAddress(payload *chanInputPayload) inputResult {
 	var addrDesc AddressDescriptor
 	var value *big.Int
 	vin := &payload.tx.Vin[payload.index]
@@ -65,32 +143,66 @@ func (m *MempoolBitcoinType) getInputAddress(payload *chanInputPayload) *addrInd
 		addrDesc, value = m.AddrDescForOutpoint(Outpoint{vin.Txid, int32(vin.Vout)})
 	}
 	if addrDesc == nil {
+		// with batching, the input transactions are requested together after all inputs of the transaction are processed
+		if m.batcher != nil {
+			return inputResult{index: payload.index, pending: true}
+		}
 		itx, err := m.chain.GetTransactionForMempool(vin.Txid)
 		if err != nil {
 			glog.Error("cannot get transaction ", vin.Txid, ": ", err)
-			return nil
-		}
-		if int(vin.Vout) >= len(itx.Vout) {
-			glog.Error("Vout len in transaction ", vin.Txid, " ", len(itx.Vout), " input.Vout=", vin.Vout)
-			return nil
-		}
-		addrDesc, err = m.chain.GetChainParser().GetAddrDescFromVout(&itx.Vout[vin.Vout])
-		if err != nil {
-			glog.Error("error in addrDesc in ", vin.Txid, " ", vin.Vout, ": ", err)
-			return nil
+			return inputResult{index: payload.index}
 		}
-		value = &itx.Vout[vin.Vout].ValueSat
+		return inputResult{ai: m.inputAddressFromTx(vin, itx), index: payload.index}
 	}
 	vin.AddrDesc = addrDesc
 	vin.ValueSat = *value
+	return inputResult{ai: &addrIndex{string(addrDesc), ^int32(vin.Vout)}, index: payload.index}
+}
+
+// inputAddressFromTx sets the address and value of the input from the output of the input transaction
+func (m *MempoolBitcoinType) inputAddressFromTx(vin *MempoolVin, itx *Tx) *addrIndex {
+	if int(vin.Vout) >= len(itx.Vout) {
+		glog.Error("Vout len in transaction ", vin.Txid, " ", len(itx.Vout), " input.Vout=", vin.Vout)
+		return nil
+	}
+	addrDesc, err := m.chain.GetChainParser().GetAddrDescFromVout(&itx.Vout[vin.Vout])
+	if err != nil {
+		glog.Error("error in addrDesc in ", vin.Txid, " ", vin.Vout, ": ", err)
+		return nil
+	}
+	vin.AddrDesc = addrDesc
+	vin.ValueSat = itx.Vout[vin.Vout].ValueSat
 	return &addrIndex{string(addrDesc), ^int32(vin.Vout)}
+}
 
+// getPendingInputAddresses requests the transactions of the pending inputs in one batch and returns their addresses
+func (m *MempoolBitcoinType) getPendingInputAddresses(mtx *MempoolTx, pending []int) []addrIndex {
+	txids := make([]string, 0, len(pending))
+	positions := make(map[string]int, len(pending))
+	for _, i := range pending {
+		txid := mtx.Vin[i].Txid
+		if _, found := positions[txid]; !found {
+			positions[txid] = len(txids)
+			txids = append(txids, txid)
+		}
+	}
+	itxs := m.getTransactions(txids)
+	io := make([]addrIndex, 0, len(pending))
+	for _, i := range pending {
+		vin := &mtx.Vin[i]
+		itx := itxs[positions[vin.Txid]]
+		if itx == nil {
+			continue
+		}
+		if ai := m.inputAddressFromTx(vin, itx); ai != nil {
+			io = append(io, *ai)
+		}
+	}
+	return io
 }
 
-func (m *MempoolBitcoinType) getTxAddrs(txid string, chanInput chan chanInputPayload, chanResult chan *addrIndex) ([]addrIndex, bool) {
-	tx, err := m.chain.GetTransactionForMempool(txid)
-	if err != nil {
-		glog.Error("cannot get transaction ", txid, ": ", err)
+func (m *MempoolBitcoinType) getTxAddrs(txid string, tx *Tx, chanInput chan chanInputPayload, chanResult chan inputResult) ([]addrIndex, bool) {
+	if tx == nil {
 		return nil, false
 	}
 	glog.V(2).Info("mempool: gettxaddrs ", txid, ", ", len(tx.Vin), " inputs")
@@ -110,6 +222,14 @@ func (m *MempoolBitcoinType) getTxAddrs(txid string, chanInput chan chanInputPay
 		}
 	}
 	dispatched := 0
+	var pending []int
+	onResult := func(r inputResult) {
+		if r.pending {
+			pending = append(pending, r.index)
+		} else if r.ai != nil {
+			io = append(io, *r.ai)
+		}
+	}
 	for i := range tx.Vin {
 		input := &tx.Vin[i]
 		if input.Coinbase != "" {
@@ -120,10 +240,8 @@ func (m *MempoolBitcoinType) getTxAddrs(txid string, chanInput chan chanInputPay
 		for {
 			select {
 			// store as many processed results as possible
-			case ai := <-chanResult:
-				if ai != nil {
-					io = append(io, *ai)
-				}
+			case r := <-chanResult:
+				onResult(r)
 				dispatched--
 			// send input to be processed
 			case chanInput <- payload:
@@ -133,10 +251,10 @@ func (m *MempoolBitcoinType) getTxAddrs(txid string, chanInput chan chanInputPay
 		}
 	}
 	for i := 0; i < dispatched; i++ {
-		ai := <-chanResult
-		if ai != nil {
-			io = append(io, *ai)
-		}
+		onResult(<-chanResult)
+	}
+	if len(pending) > 0 {
+		io = append(io, m.getPendingInputAddresses(mtx, pending)...)
 	}
 	if m.OnNewTx != nil {
 		m.OnNewTx(mtx)
@@ -168,26 +286,40 @@ func (m *MempoolBitcoinType) Resync() (int, error) {
 	txsMap := make(map[string]struct{}, len(txs))
 	dispatched := 0
 	txTime := uint32(time.Now().Unix())
+	batchSize := 1
+	if m.batcher != nil {
+		batchSize = mempoolBatchSize
+	}
+	batch := make([]string, 0, batchSize)
 	// get transaction in parallel using goroutines created in NewUTXOMempool
+	dispatch := func() {
+		for {
+			select {
+			// store as many processed transactions as possible
+			case tio := <-m.chanAddrIndex:
+				onNewEntry(tio.txid, txEntry{tio.io, txTime})
+				dispatched--
+			// send the batch of transactions to be processed
+			case m.chanTxids <- batch:
+				dispatched += len(batch)
+				batch = make([]string, 0, batchSize)
+				return
+			}
+		}
+	}
 	for _, txid := range txs {
 		txsMap[txid] = struct{}{}
 		_, exists := m.txEntries[txid]
 		if !exists {
-		loop:
-			for {
-				select {
-				// store as many processed transactions as possible
-				case tio := <-m.chanAddrIndex:
-					onNewEntry(tio.txid, txEntry{tio.io, txTime})
-					dispatched--
-				// send transaction to be processed
-				case m.chanTxid <- txid:
-					dispatched++
-					break loop
-				}
+			batch = append(batch, txid)
+			if len(batch) == batchSize {
+				dispatch()
 			}
 		}
 	}
+	if len(batch) > 0 {
+		dispatch()
+	}
 	for i := 0; i < dispatched; i++ {
 		tio := <-m.chanAddrIndex
 		onNewEntry(tio.txid, txEntry{tio.io, txTime})
@@ -0,0 +1,59 @@
+// +build unittest
+
+package bchain
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type testMempoolChain struct {
+	BlockChain
+	txids []string
+}
+
+func (c *testMempoolChain) GetMempoolTransactions() ([]string, error) {
+	return c.txids, nil
+}
+
+func (c *testMempoolChain) GetTransactionForMempool(txid string) (*Tx, error) {
+	return &Tx{Txid: txid}, nil
+}
+
+func TestMempoolBitcoinType_SetWorkers(t *testing.T) {
+	chain := &testMempoolChain{}
+	m := NewMempoolBitcoinType(chain, 2, 1)
+	var lock sync.Mutex
+	processed := make(map[string]int)
+	m.OnNewTx = func(tx *MempoolTx) {
+		lock.Lock()
+		processed[tx.Txid]++
+		lock.Unlock()
+	}
+	resync := func(from, to int) {
+		chain.txids = nil
+		for i := from; i < to; i++ {
+			chain.txids = append(chain.txids, "tx"+strconv.Itoa(i))
+		}
+		if _, err := m.Resync(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resync(0, 20)
+	m.SetWorkers(4, 3)
+	resync(20, 50)
+	m.SetWorkers(0, 0)
+	resync(50, 60)
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(processed) != 60 {
+		t.Errorf("processed %d transactions, want 60", len(processed))
+	}
+	for txid, n := range processed {
+		if n != 1 {
+			t.Errorf("%v processed %d times, want once", txid, n)
+		}
+	}
+}
@@ -131,6 +131,17 @@ func (m *MempoolEthereumType) Resync() (int, error) {
 	return entries, nil
 }
 
+// ExportEntries returns nil, the state of EthereumType mempool is not persisted because it cannot be imported
+func (m *MempoolEthereumType) ExportEntries() []MempoolPersistedEntry {
+	return nil
+}
+
+// ImportEntries does not import anything for EthereumType mempool,
+// the backend is not queried for the list of transactions on each resync and stale entries would not be removed
+func (m *MempoolEthereumType) ImportEntries(entries []MempoolPersistedEntry) int {
+	return 0
+}
+
 // AddTransactionToMempool adds transactions to mempool
 func (m *MempoolEthereumType) AddTransactionToMempool(txid string) {
 	m.mux.Lock()
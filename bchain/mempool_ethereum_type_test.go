@@ -0,0 +1,26 @@
+// +build unittest
+
+package bchain
+
+import "testing"
+
+func TestMempoolEthereumType_PersistedEntries(t *testing.T) {
+	m := NewMempoolEthereumType(&testMempoolChain{}, 1, false)
+	entries := []MempoolPersistedEntry{{
+		Txid:        "txid",
+		Time:        1,
+		AddrIndexes: []MempoolAddrIndex{{AddrDesc: AddressDescriptor{1, 2, 3}, N: 0}},
+	}}
+	// the entries would not be removed by resync, the state is neither imported nor exported
+	if n := m.ImportEntries(entries); n != 0 {
+		t.Errorf("ImportEntries() = %d, want 0", n)
+	}
+	m.txEntries["txid"] = txEntry{addrIndexes: []addrIndex{{string([]byte{1, 2, 3}), 0}}, time: 1}
+	if got := m.ExportEntries(); got != nil {
+		t.Errorf("ExportEntries() = %+v, want nil", got)
+	}
+	// the base mempool exports its entries
+	if got := m.BaseMempool.ExportEntries(); len(got) != 1 || got[0].Txid != "txid" {
+		t.Errorf("BaseMempool.ExportEntries() = %+v, want the entry txid", got)
+	}
+}
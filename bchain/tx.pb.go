@@ -1,11 +1,11 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
-// source: tx.proto
+// source: bchain/tx.proto
 
 /*
 Package bchain is a generated protocol buffer package.
 
 It is generated from these files:
-	tx.proto
+	bchain/tx.proto
 
 It has these top-level messages:
 	ProtoTransaction
@@ -201,30 +201,30 @@ func init() {
 	proto.RegisterType((*ProtoTransaction_VoutType)(nil), "bchain.ProtoTransaction.VoutType")
 }
 
-func init() { proto.RegisterFile("tx.proto", fileDescriptor0) }
+func init() { proto.RegisterFile("bchain/tx.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
 	// 346 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x52, 0x41, 0x6e, 0xea, 0x30,
-	0x14, 0x94, 0x89, 0x09, 0xe1, 0x7d, 0xd0, 0x47, 0x5e, 0x54, 0x16, 0xea, 0x22, 0x65, 0x95, 0x55,
-	0x16, 0x54, 0x3d, 0x40, 0xdb, 0x0d, 0x52, 0x2b, 0x84, 0x1c, 0x94, 0x7d, 0x12, 0x2c, 0xb0, 0x4a,
-	0x6d, 0x9a, 0x38, 0x12, 0x48, 0xbd, 0x51, 0x8f, 0xd0, 0xcb, 0x55, 0x7e, 0x84, 0x50, 0x90, 0xba,
-	0xf3, 0x8c, 0xdf, 0x64, 0xe6, 0x4d, 0x0c, 0x81, 0xdd, 0xc7, 0xbb, 0xd2, 0x58, 0xc3, 0xfc, 0xbc,
-	0xd8, 0x64, 0x4a, 0x4f, 0xbe, 0x29, 0x8c, 0x16, 0x8e, 0x59, 0x96, 0x99, 0xae, 0xb2, 0xc2, 0x2a,
-	0xa3, 0x19, 0x03, 0xba, 0xdc, 0xab, 0x15, 0x27, 0x21, 0x89, 0x06, 0x02, 0xcf, 0x6c, 0x04, 0xde,
-	0x4c, 0xee, 0x79, 0x07, 0x29, 0x77, 0x64, 0xb7, 0xd0, 0x7f, 0xda, 0x9a, 0xe2, 0xcd, 0xaa, 0x77,
-	0xc9, 0xbd, 0x90, 0x44, 0x54, 0x9c, 0x09, 0x36, 0x86, 0xe0, 0xf5, 0x74, 0x49, 0x43, 0x12, 0x0d,
-	0x45, 0x8b, 0xd9, 0x0d, 0xf8, 0x33, 0xa9, 0xd6, 0x1b, 0xcb, 0xbb, 0x78, 0xd3, 0x20, 0x36, 0x05,
-	0x2f, 0x55, 0x9a, 0xfb, 0xa1, 0x17, 0xfd, 0x9b, 0x86, 0xf1, 0x31, 0x62, 0x7c, 0x1d, 0x2f, 0x4e,
-	0x95, 0x5e, 0x1e, 0x76, 0x52, 0xb8, 0x61, 0xf6, 0x00, 0x34, 0x35, 0xb5, 0xe5, 0x3d, 0x14, 0xdd,
-	0xfd, 0x2d, 0x32, 0xb5, 0x45, 0x15, 0x8e, 0x33, 0x0e, 0xbd, 0x54, 0x96, 0x95, 0x32, 0x9a, 0x07,
-	0x21, 0x89, 0xba, 0xe2, 0x04, 0xc7, 0x5f, 0x04, 0x7a, 0x8d, 0x83, 0x5b, 0xe2, 0xd9, 0x28, 0x9d,
-	0x67, 0x95, 0xc4, 0x32, 0xfa, 0xa2, 0xc5, 0x6d, 0x49, 0x9d, 0x5f, 0x25, 0xb1, 0x26, 0x8c, 0x87,
-	0x6b, 0x1d, 0x9d, 0x26, 0x30, 0x48, 0x8a, 0x52, 0xed, 0x6c, 0xa2, 0xd6, 0xae, 0x41, 0x8a, 0xf3,
-	0x17, 0x9c, 0xf3, 0x49, 0xe4, 0x47, 0x2d, 0x75, 0x21, 0x9b, 0x4a, 0x5a, 0xec, 0x6a, 0x7e, 0x5c,
-	0xad, 0x4a, 0x59, 0x55, 0xb2, 0xc2, 0x6a, 0xfa, 0xe2, 0x4c, 0x8c, 0x3f, 0x21, 0x38, 0x6d, 0xe6,
-	0xbe, 0x92, 0x66, 0xdb, 0x5a, 0x26, 0x99, 0x6d, 0x7e, 0x5d, 0x8b, 0xd9, 0x00, 0xc8, 0x1c, 0xa3,
-	0x0e, 0x05, 0x99, 0xb3, 0x08, 0xfe, 0x1f, 0xfd, 0x17, 0x75, 0xfe, 0x22, 0x0f, 0x2e, 0x96, 0x87,
-	0x82, 0x6b, 0xfa, 0xd2, 0x9d, 0x5e, 0xb9, 0xe7, 0x3e, 0x3e, 0xa6, 0xfb, 0x9f, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0xa1, 0x51, 0x2e, 0xba, 0x58, 0x02, 0x00, 0x00,
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x52, 0xc1, 0x6e, 0xe2, 0x30,
+	0x14, 0x94, 0x89, 0x09, 0xf0, 0x16, 0x04, 0xf2, 0x61, 0x65, 0xa1, 0x3d, 0x64, 0x39, 0xe5, 0x94,
+	0x95, 0x58, 0xf5, 0x03, 0xda, 0x5e, 0x90, 0x5a, 0x21, 0xe4, 0xa0, 0xdc, 0x93, 0x60, 0x81, 0x55,
+	0x6a, 0xd3, 0xc4, 0x91, 0x40, 0xea, 0x1f, 0xf5, 0x13, 0xfa, 0x73, 0x95, 0x5f, 0x42, 0x28, 0x48,
+	0xbd, 0x79, 0xc6, 0x6f, 0x32, 0xf3, 0x26, 0x86, 0x71, 0x96, 0xef, 0x52, 0xa5, 0xff, 0xd9, 0x63,
+	0x74, 0x28, 0x8c, 0x35, 0xcc, 0xaf, 0x89, 0xd9, 0x27, 0x85, 0xc9, 0xca, 0x31, 0xeb, 0x22, 0xd5,
+	0x65, 0x9a, 0x5b, 0x65, 0x34, 0x63, 0x40, 0xd7, 0x47, 0xb5, 0xe1, 0x24, 0x20, 0xe1, 0x50, 0xe0,
+	0x99, 0x4d, 0xc0, 0x5b, 0xc8, 0x23, 0xef, 0x20, 0xe5, 0x8e, 0xec, 0x0f, 0x0c, 0x1e, 0xf6, 0x26,
+	0x7f, 0xb1, 0xea, 0x55, 0x72, 0x2f, 0x20, 0x21, 0x15, 0x17, 0x82, 0x4d, 0xa1, 0xff, 0x7c, 0xbe,
+	0xa4, 0x01, 0x09, 0x47, 0xa2, 0xc5, 0xec, 0x37, 0xf8, 0x0b, 0xa9, 0xb6, 0x3b, 0xcb, 0xbb, 0x78,
+	0xd3, 0x20, 0x36, 0x07, 0x2f, 0x51, 0x9a, 0xfb, 0x81, 0x17, 0xfe, 0x9a, 0x07, 0x51, 0x1d, 0x31,
+	0xba, 0x8d, 0x17, 0x25, 0x4a, 0xaf, 0x4f, 0x07, 0x29, 0xdc, 0x30, 0xbb, 0x03, 0x9a, 0x98, 0xca,
+	0xf2, 0x1e, 0x8a, 0xfe, 0xfe, 0x2c, 0x32, 0x95, 0x45, 0x15, 0x8e, 0x33, 0x0e, 0xbd, 0x44, 0x16,
+	0xa5, 0x32, 0x9a, 0xf7, 0x03, 0x12, 0x76, 0xc5, 0x19, 0x4e, 0x3f, 0x08, 0xf4, 0x1a, 0x07, 0xb7,
+	0xc4, 0xa3, 0x51, 0x3a, 0x4b, 0x4b, 0x89, 0x65, 0x0c, 0x44, 0x8b, 0xdb, 0x92, 0x3a, 0xdf, 0x4a,
+	0x62, 0x4d, 0x18, 0x0f, 0xd7, 0xaa, 0x9d, 0x66, 0x30, 0x8c, 0xf3, 0x42, 0x1d, 0x6c, 0xac, 0xb6,
+	0xae, 0x41, 0x8a, 0xf3, 0x57, 0x9c, 0xf3, 0x89, 0xe5, 0x5b, 0x25, 0x75, 0x2e, 0x9b, 0x4a, 0x5a,
+	0xec, 0x6a, 0xbe, 0xdf, 0x6c, 0x0a, 0x59, 0x96, 0xb2, 0xc4, 0x6a, 0x06, 0xe2, 0x42, 0x4c, 0xdf,
+	0xa1, 0x7f, 0xde, 0xcc, 0x7d, 0x25, 0x49, 0xf7, 0x95, 0x8c, 0x53, 0xdb, 0xfc, 0xba, 0x16, 0xb3,
+	0x21, 0x90, 0x25, 0x46, 0x1d, 0x09, 0xb2, 0x64, 0x21, 0x8c, 0x6b, 0xff, 0x55, 0x95, 0x3d, 0xc9,
+	0x93, 0x8b, 0xe5, 0xa1, 0xe0, 0x96, 0xbe, 0x76, 0xa7, 0x37, 0xee, 0x99, 0x8f, 0x8f, 0xe9, 0xff,
+	0xd7, 0x00, 0xc6, 0x91, 0x64, 0xf9, 0x5f, 0x02, 0x00, 0x00,
 }
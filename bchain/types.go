@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/scryptachain/blockbook-scrypta/common"
 )
@@ -226,6 +227,46 @@ type MempoolTxidEntry struct {
 // MempoolTxidEntries is array of MempoolTxidEntry
 type MempoolTxidEntries []MempoolTxidEntry
 
+// MempoolAddrIndex is address descriptor of an input (n<0, stored as binary complement of vout) or output of mempool transaction
+type MempoolAddrIndex struct {
+	AddrDesc AddressDescriptor
+	N        int32
+}
+
+// MempoolPersistedEntry contains mempool transaction data, which are persisted across restarts
+type MempoolPersistedEntry struct {
+	Txid        string
+	Time        uint32
+	AddrIndexes []MempoolAddrIndex
+}
+
+// XpubKeyOrigin contains fingerprint of the master key and derivation path of the xpub
+type XpubKeyOrigin struct {
+	Fingerprint uint32
+	Path        []uint32
+}
+
+// PsbtInput is an input of an unsigned transaction, spending an output of an xpub address at Change/Index
+type PsbtInput struct {
+	Txid     string
+	Vout     uint32
+	Sequence uint32
+	ValueSat big.Int
+	AddrDesc AddressDescriptor
+	PrevTx   []byte
+	Change   uint32
+	Index    uint32
+}
+
+// PsbtOutput is an output of an unsigned transaction, IsChange marks output to an xpub address at Change/Index
+type PsbtOutput struct {
+	ValueSat big.Int
+	AddrDesc AddressDescriptor
+	IsChange bool
+	Change   uint32
+	Index    uint32
+}
+
 // OnNewBlockFunc is used to send notification about a new block
 type OnNewBlockFunc func(hash string, height uint32)
 
@@ -235,6 +276,9 @@ type OnNewTxAddrFunc func(tx *Tx, desc AddressDescriptor)
 // OnNewTxFunc is used to send notification about a new transaction/address
 type OnNewTxFunc func(tx *MempoolTx)
 
+// TxConfirmationsFunc returns the number of confirmations of the transaction in the index, 0 if the transaction is not indexed
+type TxConfirmationsFunc func(txid string) (uint32, error)
+
 // AddrDescForOutpointFunc returns address descriptor and value for given outpoint or nil if outpoint not found
 type AddrDescForOutpointFunc func(outpoint Outpoint) (AddressDescriptor, *big.Int)
 
@@ -292,6 +336,9 @@ type BlockChainParser interface {
 	AmountDecimals() int
 	// MinimumCoinbaseConfirmations returns minimum number of confirmations a coinbase transaction must have before it can be spent
 	MinimumCoinbaseConfirmations() int
+	// DustLimit returns the value of the output with the address descriptor below which the backend does not relay the transaction,
+	// nil if the coin does not have a dust limit
+	DustLimit(addrDesc AddressDescriptor) *big.Int
 	// AmountToDecimalString converts amount in big.Int to string with decimal point in the correct place
 	AmountToDecimalString(a *big.Int) string
 	// AmountToBigInt converts amount in common.JSONNumber (string) to big.Int
@@ -303,6 +350,7 @@ type BlockChainParser interface {
 	GetAddressesFromAddrDesc(addrDesc AddressDescriptor) ([]string, bool, error)
 	GetScriptFromAddrDesc(addrDesc AddressDescriptor) ([]byte, error)
 	IsAddrDescIndexable(addrDesc AddressDescriptor) bool
+	GetScriptTypeFromVout(output *Vout) string
 	// transactions
 	PackedTxidLen() int
 	PackTxid(txid string) ([]byte, error)
@@ -320,10 +368,17 @@ type BlockChainParser interface {
 	DerivationBasePath(xpub string) (string, error)
 	DeriveAddressDescriptors(xpub string, change uint32, indexes []uint32) ([]AddressDescriptor, error)
 	DeriveAddressDescriptorsFromTo(xpub string, change uint32, fromIndex uint32, toIndex uint32) ([]AddressDescriptor, error)
+	CreatePsbt(xpub string, origin *XpubKeyOrigin, inputs []PsbtInput, outputs []PsbtOutput, lockTime uint32) ([]byte, error)
 	// EthereumType specific
 	EthereumTypeGetErc20FromTx(tx *Tx) ([]Erc20Transfer, error)
 }
 
+// BackendErrors reports the errors of the RPC calls to the backend, implemented by the blockchain with metrics
+type BackendErrors interface {
+	// GetBackendErrors returns the number of consecutive failed RPC calls, the last error and its time
+	GetBackendErrors() (int, string, time.Time)
+}
+
 // Mempool defines common interface to mempool
 type Mempool interface {
 	Resync() (int, error)
@@ -331,4 +386,33 @@ type Mempool interface {
 	GetAddrDescTransactions(addrDesc AddressDescriptor) ([]Outpoint, error)
 	GetAllEntries() MempoolTxidEntries
 	GetTransactionTime(txid string) uint32
+	// ExportEntries returns the entries to persist on shutdown, nil if the mempool does not support the import
+	ExportEntries() []MempoolPersistedEntry
+	ImportEntries(entries []MempoolPersistedEntry) int
+}
+
+// IndexedBlocks gives access to the blocks stored in the index
+type IndexedBlocks interface {
+	// GetBestBlock returns the height and hash of the best indexed block, empty hash if the index is empty
+	GetBestBlock() (uint32, string, error)
+	// GetBlockHash returns the hash of the indexed block at the height, empty string if the block is not indexed
+	GetBlockHash(height uint32) (string, error)
+}
+
+// MultipleBackends is implemented by the blockchains which can connect to multiple backends
+type MultipleBackends interface {
+	// SetIndexedBlocks sets the index used to refuse backends with a chain conflicting with the index
+	SetIndexedBlocks(index IndexedBlocks)
+}
+
+// MempoolWorkersSetter is implemented by the mempools whose number of sync workers can be changed at runtime
+type MempoolWorkersSetter interface {
+	SetWorkers(workers int, subworkers int)
+}
+
+// MempoolTransactionsBatcher is implemented by the blockchains which can get multiple mempool transactions in one backend request
+type MempoolTransactionsBatcher interface {
+	// GetTransactionsForMempool returns the transactions in the same way as GetTransactionForMempool,
+	// the transactions not found by the backend are returned as nil
+	GetTransactionsForMempool(txids []string) ([]*Tx, error)
 }
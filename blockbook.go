@@ -4,15 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"math/rand"
 	"net/http"
 	_ "net/http/pprof"
+	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"reflect"
 	"runtime/debug"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
@@ -26,6 +33,7 @@ import (
 	"github.com/scryptachain/blockbook-scrypta/db"
 	"github.com/scryptachain/blockbook-scrypta/fiat"
 	"github.com/scryptachain/blockbook-scrypta/server"
+	"github.com/scryptachain/blockbook-scrypta/tracing"
 )
 
 // debounce too close requests for resync
@@ -48,6 +56,11 @@ var (
 	dbCache        = flag.Int("dbcache", 1<<29, "size of the rocksdb cache")
 	dbMaxOpenFiles = flag.Int("dbmaxopenfiles", 1<<14, "max open files by rocksdb")
 
+	// read only secondary instance of the db synchronized by another blockbook process
+	readOnlySecondary        = flag.Bool("readonly-secondary", false, "open the db in datadir as a read only secondary instance of the db synchronized by another blockbook process and serve the API without synchronization of the index, requires build with tag rocksdb_secondary")
+	secondaryPath            = flag.String("secondarypath", "", "directory of the own files of the read only secondary instance, must differ for each instance (default blockbook-secondary-<pid> in the temp directory)")
+	secondaryCatchUpPeriodMs = flag.Int("secondarycatchupperiod", 1000, "period of the catch up of the read only secondary instance with the synchronizing instance in milliseconds")
+
 	blockFrom      = flag.Int("blockheight", -1, "height of the starting block")
 	blockUntil     = flag.Int("blockuntil", -1, "height of the final block")
 	rollbackHeight = flag.Int("rollback", -1, "rollback to the given height and quit")
@@ -65,8 +78,12 @@ var (
 
 	internalBinding = flag.String("internal", "", "internal http server binding [address]:port, (default no internal server)")
 
+	adminToken = flag.String("admintoken", "", "token authenticating the admin jobs of the internal server, passed in the header Authorization: Bearer <token> (default admin jobs disabled)")
+
 	publicBinding = flag.String("public", "", "public http server binding [address]:port[/path] (default no public server)")
 
+	grpcBinding = flag.String("grpc", "", "gRPC server binding [address]:port, requires certfile (default no gRPC server)")
+
 	certFiles = flag.String("certfile", "", "to enable SSL specify path to certificate files without extension, expecting <certfile>.crt and <certfile>.key (default no SSL)")
 
 	explorerURL = flag.String("explorer", "", "address of blockchain explorer")
@@ -82,6 +99,43 @@ var (
 
 	// resync mempool at least each resyncMempoolPeriodMs (could be more often if invoked by message from ZeroMQ)
 	resyncMempoolPeriodMs = flag.Int("resyncmempoolperiod", 40017, "resync mempool period in milliseconds")
+
+	// rebroadcast transactions sent by blockbook which disappeared from the mempool
+	rebroadcastWindowMinutes = flag.Int("rebroadcastwindow", 1440, "period in minutes after sending during which unconfirmed transactions missing in the mempool are rebroadcast, 0 disables the tracking of sent transactions")
+	checkBroadcastsPeriodMs  = flag.Int("checkbroadcastsperiod", 300017, "period of checking of sent transactions in milliseconds")
+
+	fiatBackfill        = flag.String("fiatbackfill", "", "download fiat rates missing in the range of dates YYYYMMDD[-YYYYMMDD] (default to yesterday) and exit")
+	fiatBackfillPauseMs = flag.Int("fiatbackfillpause", 1500, "pause between requests to the fiat rates provider during the backfill in milliseconds")
+
+	// log of websocket notifications, which allows the clients to resume the subscriptions after reconnect
+	wsNotificationLog   = flag.Int("wsnotificationlog", 10000, "number of websocket notifications kept in memory for the resume of subscriptions, 0 disables the resume")
+	wsNotificationSpill = flag.Int("wsnotificationspill", 0, "number of websocket notifications evicted from memory kept in the database, 0 disables the spill")
+
+	// access control of the public interface, API keys are managed by the internal server
+	rateLimit              = flag.Float64("ratelimit", 0, "number of request units per second per client IP of the public API and websocket interface, 0 disables the rate limiting of clients without API key")
+	rateLimitBurst         = flag.Float64("ratelimitburst", 0, "maximum number of request units available to a client at once (default ratelimit)")
+	rateLimitSubscriptions = flag.Int("ratelimitsubscriptions", 0, "maximum number of subscribed addresses or accounts per websocket connection, 0 means no limit")
+	rateLimitIPHeader      = flag.String("ratelimitipheader", "", "http header with the client IP set by a reverse proxy, e.g. X-Real-Ip (default the remote address)")
+
+	apiCacheSizeMB        = flag.Int("apicachesize", 0, "size of the cache of the public API responses in MB, 0 disables the cache")
+	apiCacheTTLSeconds    = flag.Int("apicachettl", 10, "lifetime of the cached address summaries and unconfirmed transactions in seconds")
+	apiCacheConfirmations = flag.Int("apicacheconfirmations", 6, "number of confirmations after which the cached transactions and blocks are considered immutable")
+
+	// thresholds of the readiness checks of the endpoint health/ready
+	healthMaxBlocksBehind   = flag.Int("healthmaxblocksbehind", 2, "maximum number of blocks the index can be behind the backend to be reported ready by health/ready, 0 disables the check")
+	healthMaxSyncAge        = flag.Int("healthmaxsyncage", 0, "maximum time in seconds since the last block was connected to the index to be reported ready, 0 disables the check")
+	healthMaxMempoolSyncAge = flag.Int("healthmaxmempoolsyncage", 300, "maximum time in seconds since the last mempool sync to be reported ready, 0 disables the check")
+	healthMaxBackendErrors  = flag.Int("healthmaxbackenderrors", 10, "maximum number of consecutive failed backend RPC calls to be reported ready, 0 disables the check")
+
+	// tracing of the requests of the public interface, exported to an OpenTelemetry collector
+	otlpEndpoint     = flag.String("otlpendpoint", "", "url of the OTLP/HTTP traces receiver of the OpenTelemetry collector, e.g. http://localhost:4318/v1/traces (default tracing disabled)")
+	traceSampleRatio = flag.Float64("tracesampleratio", 1, "ratio of the traced requests, between 0 and 1")
+
+	// structured logs written as JSON lines, rotated by size
+	accessLogPath = flag.String("accesslog", "", "path to the access log of the public API and websocket requests (default no access log)")
+	auditLogPath  = flag.String("auditlog", "", "path to the audit log of the sent transactions and admin actions (default no audit log)")
+	logMaxSizeMB  = flag.Int("logmaxsize", 100, "size in MB after which the access and audit logs are rotated, 0 disables the rotation")
+	logMaxBackups = flag.Int("logmaxbackups", 5, "number of rotated access and audit log files kept")
 )
 
 var (
@@ -91,19 +145,36 @@ var (
 	chanSyncIndexDone             = make(chan struct{})
 	chanSyncMempoolDone           = make(chan struct{})
 	chanStoreInternalStateDone    = make(chan struct{})
+	chanCheckBroadcasts           = make(chan struct{})
+	chanCheckBroadcastsDone       = make(chan struct{})
 	chain                         bchain.BlockChain
 	mempool                       bchain.Mempool
+	broadcastTracker              *bchain.BroadcastTracker
+	fiatRates                     *fiat.RatesDownloader
+	accessLog                     *common.JSONLog
+	auditLog                      *common.JSONLog
 	index                         *db.RocksDB
 	txCache                       *db.TxCache
 	metrics                       *common.Metrics
 	syncWorker                    *db.SyncWorker
+	secondaryWorker               *db.SecondaryWorker
 	internalState                 *common.InternalState
 	callbacksOnNewBlock           []bchain.OnNewBlockFunc
 	callbacksOnNewTxAddr          []bchain.OnNewTxAddrFunc
 	callbacksOnNewTx              []bchain.OnNewTxFunc
 	callbacksOnNewFiatRatesTicker []fiat.OnNewFiatRatesTicker
 	chanOsSignal                  chan os.Signal
+	chanReloadConfig              chan os.Signal
 	inShutdown                    int32
+
+	// runtimeConfig is the part of the configuration which can be changed by SIGHUP without restart
+	runtimeConfig *reloadableConfig
+
+	// syncIndexLock is held during the resync of the index, syncIndexPaused stops the resyncs while admin jobs modify the index
+	syncIndexLock   sync.Mutex
+	syncIndexPaused int32
+	// timestamp of the last fiat rates ticker notified by the read only secondary instance
+	lastFiatRatesTicker time.Time
 )
 
 func init() {
@@ -131,7 +202,10 @@ func mainWithExitCode() int {
 	rand.Seed(time.Now().UTC().UnixNano())
 
 	chanOsSignal = make(chan os.Signal, 1)
-	signal.Notify(chanOsSignal, syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
+	signal.Notify(chanOsSignal, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
+	// SIGHUP reloads the configuration, it is handled by reloadConfigLoop
+	chanReloadConfig = make(chan os.Signal, 1)
+	signal.Notify(chanReloadConfig, syscall.SIGHUP)
 
 	glog.Infof("Blockbook: %+v, debug mode %v", common.GetVersionInfo(), *debugMode)
 
@@ -159,6 +233,10 @@ func mainWithExitCode() int {
 		glog.Error("config: ", err)
 		return exitCodeFatal
 	}
+	if runtimeConfig, err = loadReloadableConfig(*blockchain); err != nil {
+		glog.Error("config: ", err)
+		return exitCodeFatal
+	}
 
 	// gspt.SetProcTitle("blockbook-" + normalizeName(coin))
 
@@ -167,19 +245,72 @@ func mainWithExitCode() int {
 		glog.Error("metrics: ", err)
 		return exitCodeFatal
 	}
+	api.SetXpubCacheMetrics(metrics)
+
+	if *otlpEndpoint != "" {
+		if err = tracing.Init(tracing.Config{
+			Endpoint:    *otlpEndpoint,
+			ServiceName: "blockbook-" + normalizeName(coin),
+			Attributes:  map[string]string{"service.version": common.GetVersionInfo().Version},
+			SampleRatio: *traceSampleRatio,
+		}); err != nil {
+			glog.Error("tracing: ", err)
+			return exitCodeFatal
+		}
+	}
+
+	if *accessLogPath != "" {
+		if accessLog, err = common.NewJSONLog(*accessLogPath, int64(*logMaxSizeMB)<<20, *logMaxBackups); err != nil {
+			glog.Error("accessLog: ", err)
+			return exitCodeFatal
+		}
+		defer accessLog.Close()
+	}
+	if *auditLogPath != "" {
+		if auditLog, err = common.NewJSONLog(*auditLogPath, int64(*logMaxSizeMB)<<20, *logMaxBackups); err != nil {
+			glog.Error("auditLog: ", err)
+			return exitCodeFatal
+		}
+		defer auditLog.Close()
+	}
 
 	if chain, mempool, err = getBlockChainWithRetry(coin, *blockchain, pushSynchronizationHandler, metrics, 120); err != nil {
 		glog.Error("rpc: ", err)
 		return exitCodeFatal
 	}
 
-	index, err = db.NewRocksDB(*dbPath, *dbCache, *dbMaxOpenFiles, chain.GetChainParser(), metrics)
+	if *readOnlySecondary {
+		// the options writing to the db are reserved to the primary instance
+		if *synchronize || *fixUtxo || *rollbackHeight >= 0 || *computeColumnStats || *computeFeeStatsFlag || *fiatBackfill != "" || *blockFrom >= 0 {
+			glog.Error("readonly-secondary: cannot be used with sync, fixutxo, rollback, computedbstats, computefeestats, fiatbackfill or blockheight")
+			return exitCodeFatal
+		}
+		path := *secondaryPath
+		if path == "" {
+			path = filepath.Join(os.TempDir(), "blockbook-secondary-"+strconv.Itoa(os.Getpid()))
+			defer os.RemoveAll(path)
+		}
+		index, err = db.NewRocksDBSecondary(*dbPath, path, *dbCache, chain.GetChainParser(), metrics)
+	} else {
+		index, err = db.NewRocksDB(*dbPath, *dbCache, *dbMaxOpenFiles, chain.GetChainParser(), metrics)
+	}
 	if err != nil {
 		glog.Error("rocksDB: ", err)
 		return exitCodeFatal
 	}
 	defer index.Close()
 
+	// the blockchain does not switch to a backend with a chain conflicting with the index
+	if mb, ok := chain.(bchain.MultipleBackends); ok {
+		mb.SetIndexedBlocks(index)
+	}
+
+	if *rebroadcastWindowMinutes > 0 {
+		// all transactions sent using chain are tracked from now on
+		broadcastTracker = bchain.NewBroadcastTracker(chain, mempool, indexedTxConfirmations, time.Duration(*rebroadcastWindowMinutes)*time.Minute)
+		chain = broadcastTracker
+	}
+
 	internalState, err = newInternalState(coin, coinShortcut, coinLabel, index)
 	if err != nil {
 		glog.Error("internalState: ", err)
@@ -187,8 +318,8 @@ func mainWithExitCode() int {
 	}
 
 	// fix possible inconsistencies in the UTXO index
-	if *fixUtxo || !internalState.UtxoChecked {
-		err = index.FixUtxos(chanOsSignal)
+	if !*readOnlySecondary && (*fixUtxo || !internalState.UtxoChecked) {
+		err = index.FixUtxos(chanOsSignal, nil)
 		if err != nil {
 			glog.Error("fixUtxos: ", err)
 			return exitCodeFatal
@@ -210,7 +341,10 @@ func mainWithExitCode() int {
 			glog.Error("internalState: database is in inconsistent state and cannot be used")
 			return exitCodeFatal
 		}
-		glog.Warning("internalState: database was left in open state, possibly previous ungraceful shutdown")
+		// the db of a read only secondary instance is kept open by the primary instance
+		if !*readOnlySecondary {
+			glog.Warning("internalState: database was left in open state, possibly previous ungraceful shutdown")
+		}
 	}
 
 	if *computeFeeStatsFlag {
@@ -225,7 +359,7 @@ func mainWithExitCode() int {
 
 	if *computeColumnStats {
 		internalState.DbState = common.DbStateOpen
-		err = index.ComputeInternalStateColumnStats(chanOsSignal)
+		err = index.ComputeInternalStateColumnStats(chanOsSignal, nil)
 		if err != nil {
 			glog.Error("internalState: ", err)
 			return exitCodeFatal
@@ -234,18 +368,34 @@ func mainWithExitCode() int {
 		return exitCodeOK
 	}
 
+	if *fiatBackfill != "" {
+		err = backfillFiatRates(*fiatBackfill)
+		if err != nil && err != db.ErrOperationInterrupted {
+			glog.Error("backfillFiatRates: ", err)
+			return exitCodeFatal
+		}
+		return exitCodeOK
+	}
+
 	syncWorker, err = db.NewSyncWorker(index, chain, *syncWorkers, *syncChunk, *blockFrom, *dryRun, chanOsSignal, metrics, internalState)
 	if err != nil {
 		glog.Errorf("NewSyncWorker %v", err)
 		return exitCodeFatal
 	}
 
-	// set the DbState to open at this moment, after all important workers are initialized
-	internalState.DbState = common.DbStateOpen
-	err = index.StoreInternalState(internalState)
-	if err != nil {
-		glog.Error("internalState: ", err)
-		return exitCodeFatal
+	if *readOnlySecondary {
+		if secondaryWorker, err = db.NewSecondaryWorker(index, internalState, onNewBlockHash); err != nil {
+			glog.Error("NewSecondaryWorker ", err)
+			return exitCodeFatal
+		}
+	} else {
+		// set the DbState to open at this moment, after all important workers are initialized
+		internalState.DbState = common.DbStateOpen
+		err = index.StoreInternalState(internalState)
+		if err != nil {
+			glog.Error("internalState: ", err)
+			return exitCodeFatal
+		}
 	}
 
 	if *rollbackHeight >= 0 {
@@ -256,7 +406,8 @@ func mainWithExitCode() int {
 		return exitCodeOK
 	}
 
-	if txCache, err = db.NewTxCache(index, chain, metrics, internalState, !*noTxCache); err != nil {
+	// the read only secondary instance cannot store the transactions to the cache
+	if txCache, err = db.NewTxCache(index, chain, metrics, internalState, !*noTxCache && !*readOnlySecondary); err != nil {
 		glog.Error("txCache ", err)
 		return exitCodeFatal
 	}
@@ -266,6 +417,14 @@ func mainWithExitCode() int {
 		glog.Error("blockbookAppInfoMetric ", err)
 	}
 
+	// the fiat rates of the read only secondary instance are downloaded by the primary instance
+	if !*readOnlySecondary {
+		fiatRates = newFiatRatesDownloader(index, runtimeConfig)
+		if fiatRates != nil {
+			fiatRates.SetMetrics(metrics)
+		}
+	}
+
 	var internalServer *server.InternalServer
 	if *internalBinding != "" {
 		internalServer, err = startInternalServer()
@@ -284,6 +443,15 @@ func mainWithExitCode() int {
 		}
 	}
 
+	var grpcServer *server.GRPCServer
+	if *grpcBinding != "" {
+		grpcServer, err = startGRPCServer()
+		if err != nil {
+			glog.Error("grpc server: ", err)
+			return exitCodeFatal
+		}
+	}
+
 	if *synchronize {
 		internalState.SyncMode = true
 		internalState.InitialSync = true
@@ -295,26 +463,23 @@ func mainWithExitCode() int {
 			return exitCodeOK
 		}
 		// initialize mempool after the initial sync is complete
-		var addrDescForOutpoint bchain.AddrDescForOutpointFunc
-		if chain.GetChainParser().GetChainType() == bchain.ChainBitcoinType {
-			addrDescForOutpoint = index.AddrDescForOutpoint
-		}
-		err = chain.InitializeMempool(addrDescForOutpoint, onNewTxAddr, onNewTx)
-		if err != nil {
-			glog.Error("initializeMempool ", err)
+		if err = initializeMempool(); err != nil {
 			return exitCodeFatal
 		}
-		var mempoolCount int
-		if mempoolCount, err = mempool.Resync(); err != nil {
-			glog.Error("resyncMempool ", err)
-			return exitCodeFatal
-		}
-		internalState.FinishedMempoolSync(mempoolCount)
 		go syncIndexLoop()
 		go syncMempoolLoop()
 		internalState.InitialSync = false
+	} else if *readOnlySecondary {
+		if err = initializeMempool(); err != nil {
+			return exitCodeFatal
+		}
+		go syncSecondaryLoop()
+		go syncMempoolLoop()
 	}
 	go storeInternalStateLoop()
+	if broadcastTracker != nil {
+		go checkBroadcastsLoop()
+	}
 
 	if publicServer != nil {
 		// start full public interface
@@ -325,6 +490,11 @@ func mainWithExitCode() int {
 		publicServer.ConnectFullPublicInterface()
 	}
 
+	if grpcServer != nil {
+		callbacksOnNewBlock = append(callbacksOnNewBlock, grpcServer.OnNewBlock)
+		callbacksOnNewTx = append(callbacksOnNewTx, grpcServer.OnNewTx)
+	}
+
 	if *blockFrom >= 0 {
 		if *blockUntil < 0 {
 			*blockUntil = *blockFrom
@@ -343,19 +513,32 @@ func mainWithExitCode() int {
 		}
 	}
 
-	if internalServer != nil || publicServer != nil || chain != nil {
+	if internalServer != nil || publicServer != nil || grpcServer != nil || chain != nil {
 		// start fiat rates downloader only if not shutting down immediately
-		initFiatRatesDownloader(index, *blockchain)
-		waitForSignalAndShutdown(internalServer, publicServer, chain, 10*time.Second)
+		if fiatRates != nil {
+			glog.Info("Starting FiatRates downloader...")
+			go fiatRates.Run()
+		}
+		go reloadConfigLoop(publicServer, grpcServer)
+		waitForSignalAndShutdown(internalServer, publicServer, grpcServer, chain, 10*time.Second)
 	}
 
-	if *synchronize {
+	if broadcastTracker != nil {
+		close(chanCheckBroadcasts)
+		<-chanCheckBroadcastsDone
+	}
+
+	if *synchronize || *readOnlySecondary {
 		close(chanSyncIndex)
 		close(chanSyncMempool)
 		close(chanStoreInternalState)
 		<-chanSyncIndexDone
 		<-chanSyncMempoolDone
 		<-chanStoreInternalStateDone
+		// the mempool state is stored by the primary instance
+		if *synchronize {
+			storeMempoolState()
+		}
 	}
 	return exitCodeOK
 }
@@ -385,10 +568,19 @@ func getBlockChainWithRetry(coin string, configfile string, pushHandler func(bch
 }
 
 func startInternalServer() (*server.InternalServer, error) {
-	internalServer, err := server.NewInternalServer(*internalBinding, *certFiles, index, chain, mempool, txCache, internalState)
+	internalServer, err := server.NewInternalServer(*internalBinding, *certFiles, index, chain, mempool, txCache, internalState, broadcastTracker, fiatRates)
 	if err != nil {
 		return nil, err
 	}
+	internalServer.SetAuditLog(auditLog)
+	if *adminToken != "" {
+		if *readOnlySecondary {
+			glog.Warning("internal server: admin jobs are not available in the read only secondary instance")
+		} else {
+			internalServer.SetAdmin(*adminToken, pauseSyncIndex)
+			registerAdminJobs(internalServer)
+		}
+	}
 	go func() {
 		err = internalServer.Run()
 		if err != nil {
@@ -403,12 +595,60 @@ func startInternalServer() (*server.InternalServer, error) {
 	return internalServer, nil
 }
 
+func startGRPCServer() (*server.GRPCServer, error) {
+	grpcServer, err := server.NewGRPCServer(*grpcBinding, *certFiles, index, chain, mempool, txCache, metrics, internalState)
+	if err != nil {
+		return nil, err
+	}
+	grpcServer.SetRateLimit(server.RateLimitConfig{
+		Rate:             runtimeConfig.RateLimit,
+		Burst:            runtimeConfig.RateLimitBurst,
+		MaxSubscriptions: runtimeConfig.RateLimitSubscriptions,
+		IPHeader:         *rateLimitIPHeader,
+	})
+	go func() {
+		err = grpcServer.Run()
+		if err != nil {
+			glog.Error("grpc server: ", err)
+			return
+		}
+		glog.Info("grpc server: closed")
+	}()
+	return grpcServer, nil
+}
+
 func startPublicServer() (*server.PublicServer, error) {
 	// start public server in limited functionality, extend it after sync is finished by calling ConnectFullPublicInterface
-	publicServer, err := server.NewPublicServer(*publicBinding, *certFiles, index, chain, mempool, txCache, *explorerURL, metrics, internalState, *debugMode)
+	publicServer, err := server.NewPublicServer(*publicBinding, *certFiles, index, chain, mempool, txCache, runtimeConfig.ExplorerURL, metrics, internalState, *debugMode)
 	if err != nil {
 		return nil, err
 	}
+	spill := *wsNotificationSpill
+	if *readOnlySecondary && spill > 0 {
+		glog.Warning("public server: the spill of websocket notifications to the db is not available in the read only secondary instance")
+		spill = 0
+	}
+	if err = publicServer.SetNotificationLog(*wsNotificationLog, spill); err != nil {
+		return nil, err
+	}
+	publicServer.SetResponseCache(server.ResponseCacheConfig{
+		Size:          *apiCacheSizeMB << 20,
+		TTL:           time.Duration(*apiCacheTTLSeconds) * time.Second,
+		Confirmations: *apiCacheConfirmations,
+	})
+	publicServer.SetHealthConfig(server.HealthConfig{
+		MaxBlocksBehind:   *healthMaxBlocksBehind,
+		MaxSyncAge:        time.Duration(*healthMaxSyncAge) * time.Second,
+		MaxMempoolSyncAge: time.Duration(*healthMaxMempoolSyncAge) * time.Second,
+		MaxBackendErrors:  *healthMaxBackendErrors,
+	})
+	publicServer.SetRequestLogs(accessLog, auditLog)
+	publicServer.SetRateLimit(server.RateLimitConfig{
+		Rate:             runtimeConfig.RateLimit,
+		Burst:            runtimeConfig.RateLimitBurst,
+		MaxSubscriptions: runtimeConfig.RateLimitSubscriptions,
+		IPHeader:         *rateLimitIPHeader,
+	})
 	go func() {
 		err = publicServer.Run()
 		if err != nil {
@@ -424,30 +664,125 @@ func startPublicServer() (*server.PublicServer, error) {
 }
 
 func performRollback() error {
+	return rollbackIndex(uint32(*rollbackHeight), nil, nil)
+}
+
+// number of blocks disconnected at once by the rollback admin job, between the chunks the job can be canceled
+const rollbackChunk = 100
+
+// rollbackIndex disconnects the blocks above the height, if progress is set, the blocks are disconnected in chunks
+func rollbackIndex(rollbackHeight uint32, progress db.ProgressFunc, stop chan os.Signal) error {
 	bestHeight, bestHash, err := index.GetBestBlock()
 	if err != nil {
 		glog.Error("rollbackHeight: ", err)
 		return err
 	}
-	if uint32(*rollbackHeight) > bestHeight {
-		glog.Infof("nothing to rollback, rollbackHeight %d, bestHeight: %d", *rollbackHeight, bestHeight)
-	} else {
-		hashes := []string{bestHash}
-		for height := bestHeight - 1; height >= uint32(*rollbackHeight); height-- {
-			hash, err := index.GetBlockHash(height)
-			if err != nil {
-				glog.Error("rollbackHeight: ", err)
-				return err
-			}
-			hashes = append(hashes, hash)
+	if rollbackHeight > bestHeight {
+		glog.Infof("nothing to rollback, rollbackHeight %d, bestHeight: %d", rollbackHeight, bestHeight)
+		return nil
+	}
+	hashes := []string{bestHash}
+	for height := bestHeight - 1; height >= rollbackHeight; height-- {
+		hash, err := index.GetBlockHash(height)
+		if err != nil {
+			glog.Error("rollbackHeight: ", err)
+			return err
+		}
+		hashes = append(hashes, hash)
+	}
+	if progress == nil {
+		err = syncWorker.DisconnectBlocks(rollbackHeight, bestHeight, hashes)
+		if err != nil {
+			glog.Error("rollbackHeight: ", err)
+		}
+		return err
+	}
+	total := int64(len(hashes))
+	for higher := bestHeight; ; {
+		lower := rollbackHeight
+		if higher-lower >= rollbackChunk {
+			lower = higher - rollbackChunk + 1
 		}
-		err = syncWorker.DisconnectBlocks(uint32(*rollbackHeight), bestHeight, hashes)
+		select {
+		case <-stop:
+			return db.ErrOperationInterrupted
+		default:
+		}
+		// hashes are ordered from the best block down
+		err = syncWorker.DisconnectBlocks(lower, higher, hashes[bestHeight-higher:bestHeight-lower+1])
 		if err != nil {
 			glog.Error("rollbackHeight: ", err)
 			return err
 		}
+		progress(int64(bestHeight-lower+1), total)
+		if lower == rollbackHeight {
+			return nil
+		}
+		higher = lower - 1
 	}
-	return nil
+}
+
+// onRollback notifies the servers about the new best block if the rollback disconnected any blocks,
+// so that the cached responses and the subscribers do not see the disconnected blocks, and resyncs the mempool
+func onRollback(previousHeight uint32) {
+	bestHeight, bestHash, err := index.GetBestBlock()
+	if err != nil {
+		glog.Error("onRollback: ", err)
+		return
+	}
+	if bestHeight == previousHeight {
+		return
+	}
+	onNewBlockHash(bestHash, bestHeight)
+	requestSync(chanSyncMempool)
+}
+
+// registerAdminJobs registers the operations otherwise available only by the command line flags as admin jobs of the internal server
+func registerAdminJobs(s *server.InternalServer) {
+	s.RegisterAdminJob("rollback", true, func(params url.Values, progress db.ProgressFunc, stop chan os.Signal) error {
+		height, err := strconv.Atoi(params.Get("height"))
+		if err != nil || height <= 0 {
+			return errors.New("Invalid height")
+		}
+		bestHeight, _, err := index.GetBestBlock()
+		if err != nil {
+			return err
+		}
+		err = rollbackIndex(uint32(height), progress, stop)
+		onRollback(bestHeight)
+		return err
+	})
+	s.RegisterAdminJob("fixutxo", true, func(params url.Values, progress db.ProgressFunc, stop chan os.Signal) error {
+		if err := index.FixUtxos(stop, progress); err != nil {
+			return err
+		}
+		internalState.UtxoChecked = true
+		return index.StoreInternalState(internalState)
+	})
+	s.RegisterAdminJob("computedbstats", false, func(params url.Values, progress db.ProgressFunc, stop chan os.Signal) error {
+		return index.ComputeInternalStateColumnStats(stop, progress)
+	})
+	s.RegisterAdminJob("computefeestats", false, func(params url.Values, progress db.ProgressFunc, stop chan os.Signal) error {
+		from, err := strconv.Atoi(params.Get("from"))
+		if err != nil || from < 0 {
+			return errors.New("Invalid from")
+		}
+		to, err := strconv.Atoi(params.Get("to"))
+		if err != nil || to < from {
+			return errors.New("Invalid to")
+		}
+		w, err := api.NewWorker(index, chain, mempool, txCache, internalState)
+		if err != nil {
+			return err
+		}
+		for block := from; block <= to; block++ {
+			if err = w.ComputeFeeStats(block, block, stop); err != nil {
+				return err
+			}
+			progress(int64(block-from+1), int64(to-from+1))
+		}
+		return nil
+	})
 }
 
 func blockbookAppInfoMetric(db *db.RocksDB, chain bchain.BlockChain, txCache *db.TxCache, is *common.InternalState, metrics *common.Metrics) error {
@@ -470,6 +805,22 @@ func blockbookAppInfoMetric(db *db.RocksDB, chain bchain.BlockChain, txCache *db
 	return nil
 }
 
+// indexedTxConfirmations returns the number of confirmations of the transaction in the index, 0 if it is not indexed
+func indexedTxConfirmations(txid string) (uint32, error) {
+	ta, err := index.GetTxAddresses(txid)
+	if err != nil || ta == nil {
+		return 0, err
+	}
+	bestHeight, _, err := index.GetBestBlock()
+	if err != nil {
+		return 0, err
+	}
+	if bestHeight < ta.Height {
+		return 0, nil
+	}
+	return bestHeight - ta.Height + 1, nil
+}
+
 func newInternalState(coin, coinShortcut, coinLabel string, d *db.RocksDB) (*common.InternalState, error) {
 	is, err := d.LoadInternalState(coin)
 	if err != nil {
@@ -532,6 +883,11 @@ func syncIndexLoop() {
 	glog.Info("syncIndexLoop starting")
 	// resync index about every 15 minutes if there are no chanSyncIndex requests, with debounce 1 second
 	tickAndDebounce(time.Duration(*resyncIndexPeriodMs)*time.Millisecond, debounceResyncIndexMs*time.Millisecond, chanSyncIndex, func() {
+		syncIndexLock.Lock()
+		defer syncIndexLock.Unlock()
+		if atomic.LoadInt32(&syncIndexPaused) != 0 {
+			return
+		}
 		if err := syncWorker.ResyncIndex(onNewBlockHash, false); err != nil {
 			glog.Error("syncIndexLoop ", errors.ErrorStack(err), ", will retry...")
 			// retry once in case of random network error, after a slight delay
@@ -544,6 +900,61 @@ func syncIndexLoop() {
 	glog.Info("syncIndexLoop stopped")
 }
 
+// pauseSyncIndex stops the synchronization of the index, waits for the running resync to finish
+// and returns a function resuming the synchronization
+func pauseSyncIndex() func() {
+	atomic.StoreInt32(&syncIndexPaused, 1)
+	syncIndexLock.Lock()
+	syncIndexLock.Unlock()
+	glog.Info("syncIndexLoop paused")
+	return func() {
+		atomic.StoreInt32(&syncIndexPaused, 0)
+		glog.Info("syncIndexLoop resumed")
+		// the index may be behind the backend after the job, do not wait for the next notification or tick
+		requestSync(chanSyncIndex)
+	}
+}
+
+// requestSync asks the sync loop to run, the request is dropped if the loop is busy or in shutdown
+func requestSync(ch chan struct{}) {
+	if atomic.LoadInt32(&inShutdown) != 0 {
+		return
+	}
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// syncSecondaryLoop follows the changes of the index done by the primary instance
+func syncSecondaryLoop() {
+	defer close(chanSyncIndexDone)
+	glog.Info("syncSecondaryLoop starting")
+	tickAndDebounce(time.Duration(*secondaryCatchUpPeriodMs)*time.Millisecond, debounceResyncIndexMs*time.Millisecond, chanSyncIndex, func() {
+		if err := secondaryWorker.CatchUp(); err != nil {
+			glog.Error("syncSecondaryLoop ", errors.ErrorStack(err))
+		}
+		notifyNewFiatRatesTicker()
+	})
+	glog.Info("syncSecondaryLoop stopped")
+}
+
+// notifyNewFiatRatesTicker notifies about the last fiat rates ticker stored by the primary instance if it changed
+func notifyNewFiatRatesTicker() {
+	ticker, err := index.FiatRatesFindLastTicker()
+	if err != nil {
+		glog.Error("notifyNewFiatRatesTicker ", err)
+		return
+	}
+	if ticker == nil || ticker.Timestamp == nil || !ticker.Timestamp.After(lastFiatRatesTicker) {
+		return
+	}
+	if !lastFiatRatesTicker.IsZero() {
+		onNewFiatRatesTicker(ticker)
+	}
+	lastFiatRatesTicker = *ticker.Timestamp
+}
+
 func onNewBlockHash(hash string, height uint32) {
 	for _, c := range callbacksOnNewBlock {
 		c(hash, height)
@@ -572,13 +983,73 @@ func syncMempoolLoop() {
 	glog.Info("syncMempoolLoop stopped")
 }
 
+// loadMempoolState imports the mempool entries persisted at the last shutdown,
+// the following resync removes the entries which are no longer in the backend mempool
+func loadMempoolState() {
+	entries, err := index.LoadMempoolEntries()
+	if err != nil {
+		glog.Error("loadMempoolState ", err)
+		return
+	}
+	if len(entries) > 0 {
+		glog.Info("loadMempoolState: imported ", mempool.ImportEntries(entries), " of ", len(entries), " stored mempool transactions")
+		// the state is imported, do not load it again after an unclean shutdown
+		if err = index.DeleteMempoolEntries(); err != nil {
+			glog.Error("loadMempoolState ", err)
+		}
+	}
+}
+
+// initializeMempool initializes the mempool, imports the stored mempool state and does the first resync
+func initializeMempool() error {
+	var addrDescForOutpoint bchain.AddrDescForOutpointFunc
+	if chain.GetChainParser().GetChainType() == bchain.ChainBitcoinType {
+		addrDescForOutpoint = index.AddrDescForOutpoint
+	}
+	err := chain.InitializeMempool(addrDescForOutpoint, onNewTxAddr, onNewTx)
+	if err != nil {
+		glog.Error("initializeMempool ", err)
+		return err
+	}
+	loadMempoolState()
+	mempoolCount, err := mempool.Resync()
+	if err != nil {
+		glog.Error("resyncMempool ", err)
+		return err
+	}
+	internalState.FinishedMempoolSync(mempoolCount)
+	return nil
+}
+
+func storeMempoolState() {
+	entries := mempool.ExportEntries()
+	if entries == nil {
+		// the mempool of the chain type cannot import the state
+		return
+	}
+	if err := index.StoreMempoolEntries(entries); err != nil {
+		glog.Error("storeMempoolState ", err)
+		return
+	}
+	glog.Info("storeMempoolState: stored ", len(entries), " mempool transactions")
+}
+
+func checkBroadcastsLoop() {
+	defer close(chanCheckBroadcastsDone)
+	glog.Info("checkBroadcastsLoop starting")
+	tickAndDebounce(time.Duration(*checkBroadcastsPeriodMs)*time.Millisecond, time.Duration(*checkBroadcastsPeriodMs)*time.Millisecond, chanCheckBroadcasts, func() {
+		broadcastTracker.CheckBroadcasts()
+	})
+	glog.Info("checkBroadcastsLoop stopped")
+}
+
 func storeInternalStateLoop() {
 	stopCompute := make(chan os.Signal)
 	defer func() {
 		close(stopCompute)
 		close(chanStoreInternalStateDone)
 	}()
-	signal.Notify(stopCompute, syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
+	signal.Notify(stopCompute, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
 	var computeRunning bool
 	lastCompute := time.Now()
 	lastAppInfo := time.Now()
@@ -591,10 +1062,11 @@ func storeInternalStateLoop() {
 		glog.Info("storeInternalStateLoop starting with db stats compute disabled")
 	}
 	tickAndDebounce(storeInternalStatePeriodMs*time.Millisecond, (storeInternalStatePeriodMs-1)*time.Millisecond, chanStoreInternalState, func() {
-		if (*dbStatsPeriodHours) > 0 && !computeRunning && lastCompute.Add(computePeriod).Before(time.Now()) {
+		// the internal state of the read only secondary instance is stored by the primary instance
+		if !*readOnlySecondary && (*dbStatsPeriodHours) > 0 && !computeRunning && lastCompute.Add(computePeriod).Before(time.Now()) {
 			computeRunning = true
 			go func() {
-				err := index.ComputeInternalStateColumnStats(stopCompute)
+				err := index.ComputeInternalStateColumnStats(stopCompute, nil)
 				if err != nil {
 					glog.Error("computeInternalStateColumnStats error: ", err)
 				}
@@ -602,9 +1074,12 @@ func storeInternalStateLoop() {
 				computeRunning = false
 			}()
 		}
-		if err := index.StoreInternalState(internalState); err != nil {
-			glog.Error("storeInternalStateLoop ", errors.ErrorStack(err))
+		if !*readOnlySecondary {
+			if err := index.StoreInternalState(internalState); err != nil {
+				glog.Error("storeInternalStateLoop ", errors.ErrorStack(err))
+			}
 		}
+		index.UpdateCacheMetrics()
 		if lastAppInfo.Add(logAppInfoPeriod).Before(time.Now()) {
 			glog.Info(index.GetMemoryStats())
 			if err := blockbookAppInfoMetric(index, chain, txCache, internalState, metrics); err != nil {
@@ -642,7 +1117,7 @@ func pushSynchronizationHandler(nt bchain.NotificationType) {
 	}
 }
 
-func waitForSignalAndShutdown(internal *server.InternalServer, public *server.PublicServer, chain bchain.BlockChain, timeout time.Duration) {
+func waitForSignalAndShutdown(internal *server.InternalServer, public *server.PublicServer, grpc *server.GRPCServer, chain bchain.BlockChain, timeout time.Duration) {
 	sig := <-chanOsSignal
 	atomic.StoreInt32(&inShutdown, 1)
 	glog.Infof("shutdown: %v", sig)
@@ -662,11 +1137,21 @@ func waitForSignalAndShutdown(internal *server.InternalServer, public *server.Pu
 		}
 	}
 
+	if grpc != nil {
+		if err := grpc.Shutdown(ctx); err != nil {
+			glog.Error("grpc server: shutdown error: ", err)
+		}
+	}
+
 	if chain != nil {
 		if err := chain.Shutdown(ctx); err != nil {
 			glog.Error("rpc: shutdown error: ", err)
 		}
 	}
+
+	if err := tracing.Shutdown(ctx); err != nil {
+		glog.Error("tracing: shutdown error: ", err)
+	}
 }
 
 func printResult(txid string, vout int32, isOutput bool) error {
@@ -693,33 +1178,237 @@ func computeFeeStats(stopCompute chan os.Signal, blockFrom, blockTo int, db *db.
 	return err
 }
 
-func initFiatRatesDownloader(db *db.RocksDB, configfile string) {
+// newFiatRatesDownloader creates the fiat rates downloader, returns nil if the fiat rates are not configured
+func newFiatRatesDownloader(db *db.RocksDB, config *reloadableConfig) *fiat.RatesDownloader {
+	if !config.fiatRatesEnabled() {
+		glog.Infof("FiatRates config (%v) is empty, so the functionality is disabled.", *blockchain)
+		return nil
+	}
+	rd, err := fiat.NewFiatRatesDownloader(db, config.FiatRates, config.FiatRatesParams, nil, onNewFiatRatesTicker)
+	if err != nil {
+		glog.Errorf("NewFiatRatesDownloader Init error: %v", err)
+		return nil
+	}
+	glog.Infof("Using %v FiatRates downloader", config.FiatRates)
+	return rd
+}
+
+// backfillFiatRates downloads the fiat rates missing in the range given as YYYYMMDD[-YYYYMMDD]
+func backfillFiatRates(dates string) error {
+	rd := newFiatRatesDownloader(index, runtimeConfig)
+	if rd == nil {
+		return errors.New("fiat rates are not configured")
+	}
+	var from, to *time.Time
+	var err error
+	d := strings.SplitN(dates, "-", 2)
+	if from, err = db.FiatRatesConvertDate(d[0]); err != nil {
+		return err
+	}
+	if len(d) > 1 {
+		if to, err = db.FiatRatesConvertDate(d[1]); err != nil {
+			return err
+		}
+	} else {
+		yesterday := time.Now().UTC().Add(-24 * time.Hour)
+		to = &yesterday
+	}
+	status, err := rd.Backfill(*from, *to, time.Duration(*fiatBackfillPauseMs)*time.Millisecond, chanOsSignal)
+	if status != nil {
+		glog.Infof("backfillFiatRates: %d missing days, %d downloaded, %d failed", status.Missing, status.Downloaded, len(status.FailedDays))
+		if status.Coverage != nil {
+			currencies := make([]string, 0, len(status.Coverage.Currencies))
+			for c := range status.Coverage.Currencies {
+				currencies = append(currencies, c)
+			}
+			sort.Strings(currencies)
+			for _, c := range currencies {
+				cc := status.Coverage.Currencies[c]
+				glog.Infof("backfillFiatRates: %v %d of %d days (%.2f%%), %v - %v", c, cc.Days, status.Coverage.Days, cc.Coverage*100, cc.First, cc.Last)
+			}
+		}
+	}
+	return err
+}
+
+// reloadableConfig is the part of the blockchaincfg configuration which can be changed without restart by SIGHUP
+// The explorer url and the rate limits default to the values of the command line flags
+type reloadableConfig struct {
+	FiatRates              string  `json:"fiat_rates"`
+	FiatRatesParams        string  `json:"fiat_rates_params"`
+	ExplorerURL            string  `json:"explorer_url"`
+	RateLimit              float64 `json:"rate_limit"`
+	RateLimitBurst         float64 `json:"rate_limit_burst"`
+	RateLimitSubscriptions int     `json:"rate_limit_subscriptions"`
+	MempoolWorkers         int     `json:"mempool_workers"`
+	MempoolSubWorkers      int     `json:"mempool_sub_workers"`
+	// other values of the configuration, their changes require restart
+	other map[string]json.RawMessage
+}
+
+func loadReloadableConfig(configfile string) (*reloadableConfig, error) {
 	data, err := ioutil.ReadFile(configfile)
 	if err != nil {
-		glog.Errorf("Error reading file %v, %v", configfile, err)
-		return
+		return nil, errors.Annotatef(err, "read %v", configfile)
+	}
+	c := &reloadableConfig{
+		ExplorerURL:            *explorerURL,
+		RateLimit:              *rateLimit,
+		RateLimitBurst:         *rateLimitBurst,
+		RateLimitSubscriptions: *rateLimitSubscriptions,
+	}
+	if err = json.Unmarshal(data, c); err != nil {
+		return nil, errors.Annotatef(err, "parse %v", configfile)
 	}
+	if err = json.Unmarshal(data, &c.other); err != nil {
+		return nil, errors.Annotatef(err, "parse %v", configfile)
+	}
+	t := reflect.TypeOf(*c)
+	for i := 0; i < t.NumField(); i++ {
+		if tag := t.Field(i).Tag.Get("json"); tag != "" {
+			delete(c.other, tag)
+		}
+	}
+	// the same defaults as used by the mempool
+	if c.MempoolWorkers < 1 {
+		c.MempoolWorkers = 1
+	}
+	if c.MempoolSubWorkers < 1 {
+		c.MempoolSubWorkers = 1
+	}
+	return c, nil
+}
+
+func (c *reloadableConfig) fiatRatesEnabled() bool {
+	return c.FiatRates != "" && c.FiatRatesParams != ""
+}
 
-	var config struct {
-		FiatRates       string `json:"fiat_rates"`
-		FiatRatesParams string `json:"fiat_rates_params"`
+func (c *reloadableConfig) validate() error {
+	if c.ExplorerURL != "" {
+		if u, err := url.Parse(c.ExplorerURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Errorf("explorer_url %q is not a valid http(s) url", c.ExplorerURL)
+		}
 	}
+	if c.RateLimit < 0 || c.RateLimitBurst < 0 || c.RateLimitSubscriptions < 0 {
+		return errors.New("rate_limit, rate_limit_burst and rate_limit_subscriptions must not be negative")
+	}
+	return nil
+}
 
-	err = json.Unmarshal(data, &config)
+// diff returns the descriptions of the changes of the reloadable values from the config old
+func (c *reloadableConfig) diff(old *reloadableConfig) []string {
+	var changes []string
+	vo, vn := reflect.ValueOf(*old), reflect.ValueOf(*c)
+	for i := 0; i < vn.NumField(); i++ {
+		key := vn.Type().Field(i).Tag.Get("json")
+		if key == "" {
+			continue
+		}
+		if from, to := vo.Field(i).Interface(), vn.Field(i).Interface(); from != to {
+			// the fiat rates params may contain API keys, they are not logged
+			if key == "fiat_rates_params" {
+				changes = append(changes, key+" changed")
+			} else {
+				changes = append(changes, fmt.Sprintf("%v changed from %q to %q", key, fmt.Sprint(from), fmt.Sprint(to)))
+			}
+		}
+	}
+	return changes
+}
+
+// restartRequired returns the sorted keys of the other values of the configuration changed from the config old
+func (c *reloadableConfig) restartRequired(old *reloadableConfig) []string {
+	var keys []string
+	for k, v := range c.other {
+		if string(v) != string(old.other[k]) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range old.other {
+		if _, found := c.other[k]; !found {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// reloadConfig applies the changed configuration, the configuration is applied only if it is valid
+func reloadConfig(public *server.PublicServer, grpc *server.GRPCServer) error {
+	c, err := loadReloadableConfig(*blockchain)
 	if err != nil {
-		glog.Errorf("Error parsing config file %v, %v", configfile, err)
-		return
+		return err
+	}
+	if err = c.validate(); err != nil {
+		return err
+	}
+	old := runtimeConfig
+	// the other values stay as loaded at the start, their changes are reported until restart
+	for _, k := range c.restartRequired(old) {
+		glog.Warning("reloadConfig: ", k, " changed, the change requires restart and is not applied")
+	}
+	c.other = old.other
+	changes := c.diff(old)
+	if len(changes) == 0 {
+		glog.Info("reloadConfig: no changes to apply")
+		return nil
+	}
+	if !*readOnlySecondary && c.fiatRatesEnabled() != (fiatRates != nil) {
+		return errors.New("enabling or disabling of fiat rates requires restart")
+	}
+	if public != nil && (c.ExplorerURL == "") != (old.ExplorerURL == "") {
+		return errors.New("switch between internal and external explorer requires restart")
+	}
+	// all values are checked before any of them is applied, a failure leaves the running configuration unchanged
+	var fiatConfig *fiat.RatesConfig
+	if fiatRates != nil && (c.FiatRates != old.FiatRates || c.FiatRatesParams != old.FiatRatesParams) {
+		if fiatConfig, err = fiatRates.NewRatesConfig(c.FiatRates, c.FiatRatesParams); err != nil {
+			return errors.Annotate(err, "fiat_rates_params")
+		}
+	}
+	// the explorer url is applied first, it is the only value whose application can fail
+	if public != nil && c.ExplorerURL != old.ExplorerURL {
+		if err = public.SetExplorerURL(c.ExplorerURL); err != nil {
+			return err
+		}
+	}
+	if fiatConfig != nil {
+		fiatRates.Reconfigure(fiatConfig)
+	}
+	if c.RateLimit != old.RateLimit || c.RateLimitBurst != old.RateLimitBurst || c.RateLimitSubscriptions != old.RateLimitSubscriptions {
+		rl := server.RateLimitConfig{
+			Rate:             c.RateLimit,
+			Burst:            c.RateLimitBurst,
+			MaxSubscriptions: c.RateLimitSubscriptions,
+		}
+		if public != nil {
+			public.UpdateRateLimit(rl)
+		}
+		if grpc != nil {
+			grpc.UpdateRateLimit(rl)
+		}
 	}
+	if c.MempoolWorkers != old.MempoolWorkers || c.MempoolSubWorkers != old.MempoolSubWorkers {
+		if m, ok := mempool.(bchain.MempoolWorkersSetter); ok {
+			m.SetWorkers(c.MempoolWorkers, c.MempoolSubWorkers)
+		}
+	}
+	runtimeConfig = c
+	for _, change := range changes {
+		glog.Info("reloadConfig: ", change)
+	}
+	return nil
+}
 
-	if config.FiatRates == "" || config.FiatRatesParams == "" {
-		glog.Infof("FiatRates config (%v) is empty, so the functionality is disabled.", configfile)
-	} else {
-		fiatRates, err := fiat.NewFiatRatesDownloader(db, config.FiatRates, config.FiatRatesParams, nil, onNewFiatRatesTicker)
-		if err != nil {
-			glog.Errorf("NewFiatRatesDownloader Init error: %v", err)
+// reloadConfigLoop reloads the configuration on SIGHUP until the shutdown
+func reloadConfigLoop(public *server.PublicServer, grpc *server.GRPCServer) {
+	for range chanReloadConfig {
+		if atomic.LoadInt32(&inShutdown) != 0 {
 			return
 		}
-		glog.Infof("Starting %v FiatRates downloader...", config.FiatRates)
-		go fiatRates.Run()
+		glog.Info("reloadConfig: reloading ", *blockchain)
+		if err := reloadConfig(public, grpc); err != nil {
+			glog.Error("reloadConfig: configuration not applied, ", err)
+		}
 	}
 }
@@ -0,0 +1,126 @@
+package common
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/golang/glog"
+)
+
+// JSONLog writes entries as JSON lines to a file, the file is rotated when it exceeds the maximum size
+// The rotated files have suffixes .1 (the newest) to .<maxBackups> (the oldest)
+// The methods can be called on nil JSONLog, which means that the log is disabled
+type JSONLog struct {
+	lock       sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewJSONLog opens the log file for appending, maxSize is in bytes (0 means no rotation),
+// maxBackups is the number of the kept rotated files
+func NewJSONLog(path string, maxSize int64, maxBackups int) (*JSONLog, error) {
+	l := &JSONLog{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *JSONLog) open() error {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.file = f
+	l.size = fi.Size()
+	return nil
+}
+
+// rotate renames the current file to .1, shifts the older files and opens a new file, must be called with the lock held
+func (l *JSONLog) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	l.file = nil
+	if l.maxBackups <= 0 {
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	} else {
+		os.Remove(l.path + "." + strconv.Itoa(l.maxBackups))
+		for i := l.maxBackups - 1; i > 0; i-- {
+			if err := os.Rename(l.path+"."+strconv.Itoa(i), l.path+"."+strconv.Itoa(i+1)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err := os.Rename(l.path, l.path+".1"); err != nil {
+			return err
+		}
+	}
+	return l.open()
+}
+
+// Log writes the entry as one line of JSON
+// The errors are only reported by glog, logging must not break the processing of the requests
+func (l *JSONLog) Log(entry interface{}) {
+	if l == nil {
+		return
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		glog.Error("JSONLog ", l.path, ": ", err)
+		return
+	}
+	b = append(b, '\n')
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file == nil {
+		// the previous rotation failed, try to open the file again
+		if err = l.open(); err != nil {
+			glog.Error("JSONLog ", l.path, ": ", err)
+			return
+		}
+	}
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(b)) > l.maxSize {
+		if err = l.rotate(); err != nil {
+			glog.Error("JSONLog ", l.path, ": rotate: ", err)
+			if l.file == nil {
+				return
+			}
+		}
+	}
+	n, err := l.file.Write(b)
+	l.size += int64(n)
+	if err != nil {
+		glog.Error("JSONLog ", l.path, ": ", err)
+	}
+}
+
+// Close closes the log file
+func (l *JSONLog) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,72 @@
+// +build unittest
+
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jsonlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "access.log")
+
+	type entry struct {
+		N int `json:"n"`
+	}
+	// each entry has 8 bytes, the file is rotated after 2 entries
+	l, err := NewJSONLog(path, 20, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 7; i++ {
+		l.Log(entry{i})
+	}
+	if err = l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	read := func(p string) []string {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Split(strings.TrimSpace(string(b)), "\n")
+	}
+	if got, want := read(path), []string{`{"n":7}`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("log = %v, want %v", got, want)
+	}
+	if got, want := read(path+".1"), []string{`{"n":5}`, `{"n":6}`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("log.1 = %v, want %v", got, want)
+	}
+	if got, want := read(path+".2"), []string{`{"n":3}`, `{"n":4}`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("log.2 = %v, want %v", got, want)
+	}
+	if _, err = os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("log.3 exists, want only 2 backups")
+	}
+
+	// the log is appended after reopen
+	if l, err = NewJSONLog(path, 20, 2); err != nil {
+		t.Fatal(err)
+	}
+	l.Log(entry{8})
+	l.Close()
+	if got, want := read(path), []string{`{"n":7}`, `{"n":8}`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("log = %v, want %v", got, want)
+	}
+
+	// nil log is disabled
+	var disabled *JSONLog
+	disabled.Log(entry{9})
+	if err = disabled.Close(); err != nil {
+		t.Error(err)
+	}
+}
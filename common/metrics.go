@@ -16,16 +16,30 @@ type Metrics struct {
 	WebsocketSubscribes   *prometheus.CounterVec
 	WebsocketClients      prometheus.Gauge
 	WebsocketReqDuration  *prometheus.HistogramVec
+	APIReqDuration        *prometheus.HistogramVec
 	IndexResyncDuration   prometheus.Histogram
 	MempoolResyncDuration prometheus.Histogram
 	TxCacheEfficiency     *prometheus.CounterVec
+	APICacheEfficiency    *prometheus.CounterVec
 	RPCLatency            *prometheus.HistogramVec
+	RPCBatchLatency       *prometheus.HistogramVec
 	IndexResyncErrors     *prometheus.CounterVec
 	IndexDBSize           prometheus.Gauge
+	BackendLag            prometheus.Gauge
+	BulkConnectBlocks     prometheus.Counter
+	BulkConnectTxs        prometheus.Counter
 	ExplorerViews         *prometheus.CounterVec
+	RateLimitRejections   *prometheus.CounterVec
+	GRPCRequests          *prometheus.CounterVec
+	GRPCStreams           prometheus.Gauge
 	MempoolSize           prometheus.Gauge
 	DbColumnRows          *prometheus.GaugeVec
 	DbColumnSize          *prometheus.GaugeVec
+	DbCacheEfficiency     *prometheus.GaugeVec
+	DbCacheUsage          prometheus.Gauge
+	XpubCacheSize         prometheus.Gauge
+	XpubCacheEvictions    prometheus.Counter
+	FiatRatesErrors       *prometheus.CounterVec
 	BlockbookAppInfo      *prometheus.GaugeVec
 }
 
@@ -100,6 +114,15 @@ func GetMetrics(coin string) (*Metrics, error) {
 		},
 		[]string{"method"},
 	)
+	metrics.APIReqDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "blockbook_api_req_duration",
+			Help:        "REST API request duration by handler and http status (in milliseconds)",
+			Buckets:     []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000},
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"handler", "status"},
+	)
 	metrics.IndexResyncDuration = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Name:        "blockbook_index_resync_duration",
@@ -124,6 +147,14 @@ func GetMetrics(coin string) (*Metrics, error) {
 		},
 		[]string{"status"},
 	)
+	metrics.APICacheEfficiency = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "blockbook_apicache_efficiency",
+			Help:        "Efficiency of the cache of the public API responses",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"status"},
+	)
 	metrics.RPCLatency = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:        "blockbook_rpc_latency",
@@ -133,6 +164,15 @@ func GetMetrics(coin string) (*Metrics, error) {
 		},
 		[]string{"method", "error"},
 	)
+	metrics.RPCBatchLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "blockbook_rpc_batch_latency",
+			Help:        "Latency of blockchain RPC batch requests by method and batch size (in milliseconds)",
+			Buckets:     []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500},
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"method", "size", "error"},
+	)
 	metrics.IndexResyncErrors = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name:        "blockbook_index_resync_errors",
@@ -148,6 +188,27 @@ func GetMetrics(coin string) (*Metrics, error) {
 			ConstLabels: Labels{"coin": coin},
 		},
 	)
+	metrics.BackendLag = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name:        "blockbook_backend_lag",
+			Help:        "Number of blocks the index is behind the backend",
+			ConstLabels: Labels{"coin": coin},
+		},
+	)
+	metrics.BulkConnectBlocks = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name:        "blockbook_bulkconnect_blocks",
+			Help:        "Number of blocks connected in bulk mode during the initial sync",
+			ConstLabels: Labels{"coin": coin},
+		},
+	)
+	metrics.BulkConnectTxs = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name:        "blockbook_bulkconnect_txs",
+			Help:        "Number of transactions connected in bulk mode during the initial sync",
+			ConstLabels: Labels{"coin": coin},
+		},
+	)
 	metrics.ExplorerViews = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name:        "blockbook_explorer_views",
@@ -156,6 +217,29 @@ func GetMetrics(coin string) (*Metrics, error) {
 		},
 		[]string{"action"},
 	)
+	metrics.RateLimitRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "blockbook_ratelimit_rejections",
+			Help:        "Number of requests rejected by the access control by interface and reason",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"interface", "reason"},
+	)
+	metrics.GRPCRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "blockbook_grpc_requests",
+			Help:        "Total number of gRPC requests by method and status",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"method", "status"},
+	)
+	metrics.GRPCStreams = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name:        "blockbook_grpc_streams",
+			Help:        "Number of currently open gRPC subscription streams",
+			ConstLabels: Labels{"coin": coin},
+		},
+	)
 	metrics.MempoolSize = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Name:        "blockbook_mempool_size",
@@ -179,6 +263,43 @@ func GetMetrics(coin string) (*Metrics, error) {
 		},
 		[]string{"column"},
 	)
+	metrics.DbCacheEfficiency = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "blockbook_dbcache_efficiency",
+			Help:        "Number of hits and misses of the db block cache by type of block since the start",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"cache", "status"},
+	)
+	metrics.DbCacheUsage = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name:        "blockbook_dbcache_usage",
+			Help:        "Memory used by the db block cache (in bytes)",
+			ConstLabels: Labels{"coin": coin},
+		},
+	)
+	metrics.XpubCacheSize = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name:        "blockbook_xpubcache_size",
+			Help:        "Number of xpubs in the xpub cache",
+			ConstLabels: Labels{"coin": coin},
+		},
+	)
+	metrics.XpubCacheEvictions = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name:        "blockbook_xpubcache_evictions",
+			Help:        "Number of xpubs evicted from the xpub cache",
+			ConstLabels: Labels{"coin": coin},
+		},
+	)
+	metrics.FiatRatesErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "blockbook_fiatrates_errors",
+			Help:        "Number of errors of the fiat rates downloader by operation",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"operation"},
+	)
 	metrics.BlockbookAppInfo = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name:        "blockbook_app_info",
@@ -0,0 +1,250 @@
+//usr/bin/go run $0 $@ ; exit
+// +build ignore
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scryptachain/blockbook-scrypta/common"
+)
+
+const (
+	outputFile = "contrib/grafana/blockbook-dashboard.json"
+	rateWindow = "5m"
+	// quantile of the histograms shown in the dashboard
+	quantile = "0.95"
+)
+
+// MetricInfo describes a metric of the common.Metrics struct
+type MetricInfo struct {
+	Field  string
+	Kind   string
+	Name   string
+	Help   string
+	Labels []string
+}
+
+var descRegexp = regexp.MustCompile(`^Desc\{fqName: ("(?:[^"\\]|\\.)*"), help: ("(?:[^"\\]|\\.)*"), constLabels: \{.*\}, variableLabels: \[(.*)\]\}$`)
+
+// the units of the metrics are given in the help as "(in <unit>)"
+var units = map[string]string{
+	"(in milliseconds)": "ms",
+	"(in microseconds)": "µs",
+	"(in seconds)":      "s",
+	"(in bytes)":        "bytes",
+}
+
+// loadMetrics reads the definitions of the metrics from the descriptors of the collectors of common.Metrics
+func loadMetrics() ([]MetricInfo, error) {
+	metrics, err := common.GetMetrics("dashboard")
+	if err != nil {
+		return nil, err
+	}
+	v := reflect.ValueOf(metrics).Elem()
+	t := v.Type()
+	items := make([]MetricInfo, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		kind := t.Field(i).Type.Name()
+		if kind == "" {
+			kind = t.Field(i).Type.Elem().Name()
+		}
+		ch := make(chan *prometheus.Desc, 1)
+		v.Field(i).Interface().(prometheus.Collector).Describe(ch)
+		desc := <-ch
+		m := descRegexp.FindStringSubmatch(desc.String())
+		if m == nil {
+			return nil, fmt.Errorf("%s: cannot parse descriptor %s", t.Field(i).Name, desc)
+		}
+		mi := MetricInfo{Field: t.Field(i).Name, Kind: kind}
+		if mi.Name, err = strconv.Unquote(m[1]); err != nil {
+			return nil, err
+		}
+		if mi.Help, err = strconv.Unquote(m[2]); err != nil {
+			return nil, err
+		}
+		mi.Labels = strings.Fields(m[3])
+		items = append(items, mi)
+	}
+	return items, nil
+}
+
+func (mi *MetricInfo) unit() string {
+	for s, u := range units {
+		if strings.Contains(mi.Help, s) {
+			return u
+		}
+	}
+	return "short"
+}
+
+// query returns the PromQL expression and the legend of the panel of the metric
+func (mi *MetricInfo) query() (string, string) {
+	selector := `{coin="$coin"}`
+	by := strings.Join(mi.Labels, ", ")
+	legend := make([]string, len(mi.Labels))
+	for i, l := range mi.Labels {
+		legend[i] = "{{" + l + "}}"
+	}
+	switch mi.Kind {
+	case "Counter", "CounterVec":
+		expr := "rate(" + mi.Name + selector + "[" + rateWindow + "])"
+		if by != "" {
+			return "sum by (" + by + ") (" + expr + ")", strings.Join(legend, " ")
+		}
+		return "sum(" + expr + ")", mi.Name
+	case "Histogram", "HistogramVec":
+		by = strings.Join(append([]string{"le"}, mi.Labels...), ", ")
+		expr := "sum by (" + by + ") (rate(" + mi.Name + "_bucket" + selector + "[" + rateWindow + "]))"
+		if len(legend) == 0 {
+			legend = []string{mi.Name}
+		}
+		return "histogram_quantile(" + quantile + ", " + expr + ")", strings.Join(legend, " ")
+	default:
+		if by != "" {
+			return mi.Name + selector, strings.Join(legend, " ")
+		}
+		return mi.Name + selector, mi.Name
+	}
+}
+
+func (mi *MetricInfo) title() string {
+	title := strings.Replace(mi.Help, "Total number", "Number", 1)
+	for s := range units {
+		title = strings.TrimSpace(strings.Replace(title, s, "", 1))
+	}
+	switch mi.Kind {
+	case "Counter", "CounterVec":
+		title += " per second"
+	case "Histogram", "HistogramVec":
+		title += " (" + quantile + " quantile)"
+	}
+	return title
+}
+
+func panel(id int, mi *MetricInfo) map[string]interface{} {
+	expr, legend := mi.query()
+	return map[string]interface{}{
+		"id":          id,
+		"type":        "timeseries",
+		"title":       mi.title(),
+		"description": mi.Name + ": " + mi.Help,
+		"datasource":  map[string]string{"type": "prometheus", "uid": "${datasource}"},
+		"gridPos":     map[string]int{"h": 8, "w": 12, "x": 12 * ((id - 1) % 2), "y": 8 * ((id - 1) / 2)},
+		"fieldConfig": map[string]interface{}{
+			"defaults":  map[string]interface{}{"unit": mi.unit()},
+			"overrides": []interface{}{},
+		},
+		"options": map[string]interface{}{
+			"legend":  map[string]interface{}{"displayMode": "list", "placement": "bottom", "showLegend": true},
+			"tooltip": map[string]interface{}{"mode": "multi", "sort": "none"},
+		},
+		"targets": []interface{}{
+			map[string]interface{}{
+				"datasource":   map[string]string{"type": "prometheus", "uid": "${datasource}"},
+				"expr":         expr,
+				"legendFormat": legend,
+				"refId":        "A",
+			},
+		},
+	}
+}
+
+func dashboard(items []MetricInfo) map[string]interface{} {
+	panels := make([]interface{}, 0, len(items))
+	for i := range items {
+		// the info metrics carry only labels, they are not plotted
+		if strings.HasSuffix(items[i].Name, "_info") {
+			continue
+		}
+		panels = append(panels, panel(len(panels)+1, &items[i]))
+	}
+	return map[string]interface{}{
+		"title":         "Blockbook",
+		"uid":           "blockbook",
+		"tags":          []string{"blockbook"},
+		"editable":      true,
+		"schemaVersion": 36,
+		"refresh":       "1m",
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"annotations":   map[string]interface{}{"list": []interface{}{}},
+		"templating": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{
+					"name":  "datasource",
+					"label": "Data source",
+					"type":  "datasource",
+					"query": "prometheus",
+				},
+				map[string]interface{}{
+					"name":       "coin",
+					"label":      "Coin",
+					"type":       "query",
+					"datasource": map[string]string{"type": "prometheus", "uid": "${datasource}"},
+					"definition": "label_values(blockbook_app_info, coin)",
+					"query":      map[string]string{"query": "label_values(blockbook_app_info, coin)", "refId": "coin"},
+					"refresh":    1,
+					"sort":       1,
+				},
+			},
+		},
+		"panels": panels,
+	}
+}
+
+func writeDashboard(output string, items []MetricInfo) error {
+	var (
+		buf io.WriteCloser
+		err error
+	)
+	if output == "stdout" {
+		buf = os.Stdout
+	} else {
+		if err = os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+			return err
+		}
+		buf, err = os.OpenFile(output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		defer buf.Close()
+	}
+	b, err := json.MarshalIndent(dashboard(items), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = buf.Write(append(b, '\n'))
+	return err
+}
+
+func main() {
+	output := "stdout"
+	if len(os.Args) > 1 {
+		if len(os.Args) == 2 && os.Args[1] == "-w" {
+			output = outputFile
+		} else {
+			fmt.Fprintf(os.Stderr, "Usage: %s [-w]\n", filepath.Base(os.Args[0]))
+			fmt.Fprintf(os.Stderr, "    -w    write output to %s instead of stdout\n", outputFile)
+			os.Exit(1)
+		}
+	}
+
+	items, err := loadMetrics()
+	if err != nil {
+		panic(err)
+	}
+
+	err = writeDashboard(output, items)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/golang/glog"
+)
+
+// API keys are stored in the default column under the prefix followed by the id of the key,
+// the keys themselves are not stored, only their SHA-256 hashes
+const apiKeyPrefix = "apiKey:"
+
+// APIKey is a key of a client of the public interface with its limits
+// Key is set only when the key is created, ID is the hex encoded SHA-256 hash of the key
+// Rate is the number of request units per second, Burst the maximum number of units available at once
+// MaxSubscriptions limits the number of subscribed addresses and accounts per websocket connection
+// Zero limits mean the default limits of the server
+type APIKey struct {
+	Key              string  `json:"key,omitempty"`
+	ID               string  `json:"id"`
+	Name             string  `json:"name"`
+	Rate             float64 `json:"rate"`
+	Burst            float64 `json:"burst"`
+	MaxSubscriptions int     `json:"maxSubscriptions"`
+	Created          int64   `json:"created"`
+}
+
+// APIKeyID returns the id of the API key, under which the key is stored
+func APIKeyID(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
+
+// StoreAPIKey stores the API key under its id, replacing the existing key with the same value, the key itself is not stored
+func (d *RocksDB) StoreAPIKey(k *APIKey) error {
+	s := *k
+	s.ID = APIKeyID(k.Key)
+	s.Key = ""
+	buf, err := json.Marshal(&s)
+	if err != nil {
+		return err
+	}
+	if err = d.db.PutCF(d.wo, d.cfh[cfDefault], []byte(apiKeyPrefix+s.ID), buf); err != nil {
+		return err
+	}
+	k.ID = s.ID
+	return nil
+}
+
+// GetAPIKey returns the API key or nil if the key does not exist
+func (d *RocksDB) GetAPIKey(key string) (*APIKey, error) {
+	return d.GetAPIKeyByID(APIKeyID(key))
+}
+
+// GetAPIKeyByID returns the API key with the id or nil if the key does not exist
+func (d *RocksDB) GetAPIKeyByID(id string) (*APIKey, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfDefault], []byte(apiKeyPrefix+id))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	data := val.Data()
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var k APIKey
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// GetAPIKeys returns all stored API keys, without the keys themselves
+func (d *RocksDB) GetAPIKeys() ([]APIKey, error) {
+	keys := []APIKey{}
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfDefault])
+	defer it.Close()
+	prefix := []byte(apiKeyPrefix)
+	for it.Seek(prefix); it.Valid(); it.Next() {
+		if !bytes.HasPrefix(it.Key().Data(), prefix) {
+			break
+		}
+		var k APIKey
+		if err := json.Unmarshal(it.Value().Data(), &k); err != nil {
+			glog.Error("GetAPIKeys: cannot unpack key ", string(it.Key().Data()), ": ", err)
+			continue
+		}
+		keys = append(keys, k)
+	}
+	return keys, it.Err()
+}
+
+// DeleteAPIKey deletes the API key with the id
+func (d *RocksDB) DeleteAPIKey(id string) error {
+	return d.db.DeleteCF(d.wo, d.cfh[cfDefault], []byte(apiKeyPrefix+id))
+}
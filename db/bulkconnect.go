@@ -332,13 +332,20 @@ func (b *BulkConnect) connectBlockEthereumType(block *bchain.Block, storeBlockTx
 // ConnectBlock connects block in bulk mode
 func (b *BulkConnect) ConnectBlock(block *bchain.Block, storeBlockTxs bool) error {
 	b.height = block.Height
+	var err error
 	if b.chainType == bchain.ChainBitcoinType {
-		return b.connectBlockBitcoinType(block, storeBlockTxs)
+		err = b.connectBlockBitcoinType(block, storeBlockTxs)
 	} else if b.chainType == bchain.ChainEthereumType {
-		return b.connectBlockEthereumType(block, storeBlockTxs)
+		err = b.connectBlockEthereumType(block, storeBlockTxs)
+	} else {
+		// for default is to connect blocks in non bulk mode
+		return b.d.ConnectBlock(block)
+	}
+	if err == nil && b.d.metrics != nil {
+		b.d.metrics.BulkConnectBlocks.Inc()
+		b.d.metrics.BulkConnectTxs.Add(float64(len(block.Txs)))
 	}
-	// for default is to connect blocks in non bulk mode
-	return b.d.ConnectBlock(block)
+	return err
 }
 
 // Close flushes the cached data and switches DB from inconsistent state open
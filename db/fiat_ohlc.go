@@ -0,0 +1,239 @@
+package db
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/tecbot/gorocksdb"
+)
+
+// OHLCInterval is the length of the bucket of the aggregated fiat rates
+type OHLCInterval byte
+
+const (
+	// OHLCHour - hourly candles
+	OHLCHour OHLCInterval = 'h'
+	// OHLCDay - daily candles
+	OHLCDay OHLCInterval = 'd'
+	// OHLCWeek - weekly candles, the week starts on Monday
+	OHLCWeek OHLCInterval = 'w'
+)
+
+// OHLCIntervals lists all intervals in which the fiat rates are aggregated
+var OHLCIntervals = []OHLCInterval{OHLCHour, OHLCDay, OHLCWeek}
+
+// the first Monday after the unix epoch
+const ohlcWeekOffset = 4 * 24 * 3600
+
+// ParseOHLCInterval converts interval name (hour, day or week) to OHLCInterval
+func ParseOHLCInterval(s string) (OHLCInterval, error) {
+	switch s {
+	case "hour":
+		return OHLCHour, nil
+	case "day":
+		return OHLCDay, nil
+	case "week":
+		return OHLCWeek, nil
+	}
+	return 0, errors.New("Invalid interval " + s + ", possible values are hour, day and week")
+}
+
+// String returns the name of the interval
+func (i OHLCInterval) String() string {
+	switch i {
+	case OHLCHour:
+		return "hour"
+	case OHLCDay:
+		return "day"
+	case OHLCWeek:
+		return "week"
+	}
+	return "unknown"
+}
+
+// Seconds returns the length of the interval in seconds
+func (i OHLCInterval) Seconds() int64 {
+	switch i {
+	case OHLCDay:
+		return 24 * 3600
+	case OHLCWeek:
+		return 7 * 24 * 3600
+	}
+	return 3600
+}
+
+// BucketStart returns the start of the bucket containing the unix time t
+func (i OHLCInterval) BucketStart(t int64) int64 {
+	if i == OHLCWeek {
+		return t - (t-ohlcWeekOffset)%i.Seconds()
+	}
+	return t - t%i.Seconds()
+}
+
+// OHLC contains open, high, low and close rate of a currency in a time interval
+type OHLC struct {
+	Open  float64 `json:"o"`
+	High  float64 `json:"h"`
+	Low   float64 `json:"l"`
+	Close float64 `json:"c"`
+}
+
+// FiatRatesCandle is OHLC of a currency in the bucket starting at Time
+type FiatRatesCandle struct {
+	Time int64 `json:"t"`
+	OHLC
+}
+
+// ResultFiatRatesOHLC contains candles of a currency in the interval
+type ResultFiatRatesOHLC struct {
+	Currency string            `json:"currency"`
+	Interval string            `json:"interval"`
+	Candles  []FiatRatesCandle `json:"candles"`
+}
+
+// fiatRatesOHLCBucket is the stored form of the candles in a bucket
+// First and Last are times of the first and the last aggregated ticker, they make the updates independent on the order of tickers
+type fiatRatesOHLCBucket struct {
+	First   int64            `json:"f"`
+	Last    int64            `json:"l"`
+	Candles map[string]*OHLC `json:"c"`
+}
+
+func packOHLCKey(interval OHLCInterval, start int64) []byte {
+	key := make([]byte, 5)
+	key[0] = byte(interval)
+	binary.BigEndian.PutUint32(key[1:], uint32(start))
+	return key
+}
+
+func unpackOHLCKey(key []byte) (OHLCInterval, int64, error) {
+	if len(key) != 5 {
+		return 0, 0, errors.New("Invalid OHLC key")
+	}
+	return OHLCInterval(key[0]), int64(binary.BigEndian.Uint32(key[1:])), nil
+}
+
+func (d *RocksDB) getOHLCBucket(key []byte) (*fiatRatesOHLCBucket, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfFiatRatesOHLC], key)
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	buf := val.Data()
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	var b fiatRatesOHLCBucket
+	if err := json.Unmarshal(buf, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// addTicker merges the ticker at unix time t to the bucket, adding the same ticker again does not change the bucket
+func (b *fiatRatesOHLCBucket) addTicker(t int64, rates map[string]float64) {
+	empty := len(b.Candles) == 0
+	if b.Candles == nil {
+		b.Candles = make(map[string]*OHLC, len(rates))
+	}
+	for currency, rate := range rates {
+		c, found := b.Candles[currency]
+		if !found {
+			b.Candles[currency] = &OHLC{Open: rate, High: rate, Low: rate, Close: rate}
+			continue
+		}
+		if rate > c.High {
+			c.High = rate
+		}
+		if rate < c.Low {
+			c.Low = rate
+		}
+		if t <= b.First {
+			c.Open = rate
+		}
+		if t >= b.Last {
+			c.Close = rate
+		}
+	}
+	if empty || t < b.First {
+		b.First = t
+	}
+	if empty || t > b.Last {
+		b.Last = t
+	}
+}
+
+// FiatRatesUpdateOHLC merges the ticker to the hour, day and week candles containing the time of the ticker
+func (d *RocksDB) FiatRatesUpdateOHLC(ticker *CurrencyRatesTicker) error {
+	if ticker.Timestamp == nil || len(ticker.Rates) == 0 {
+		return nil
+	}
+	t := ticker.Timestamp.Unix()
+	wb := gorocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	for _, interval := range OHLCIntervals {
+		key := packOHLCKey(interval, interval.BucketStart(t))
+		b, err := d.getOHLCBucket(key)
+		if err != nil {
+			return err
+		}
+		if b == nil {
+			b = &fiatRatesOHLCBucket{}
+		}
+		b.addTicker(t, ticker.Rates)
+		buf, err := json.Marshal(b)
+		if err != nil {
+			return err
+		}
+		wb.PutCF(d.cfh[cfFiatRatesOHLC], key, buf)
+	}
+	return d.db.Write(d.wo, wb)
+}
+
+// FiatRatesOHLCLastTime returns the time of the last ticker aggregated to the candles, nil if there are no candles
+func (d *RocksDB) FiatRatesOHLCLastTime() (*time.Time, error) {
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfFiatRatesOHLC])
+	defer it.Close()
+	it.SeekForPrev(packOHLCKey(OHLCHour, int64(^uint32(0))))
+	if !it.Valid() {
+		return nil, it.Err()
+	}
+	interval, _, err := unpackOHLCKey(it.Key().Data())
+	if err != nil || interval != OHLCHour {
+		return nil, err
+	}
+	var b fiatRatesOHLCBucket
+	if err := json.Unmarshal(it.Value().Data(), &b); err != nil {
+		return nil, err
+	}
+	t := time.Unix(b.Last, 0).UTC()
+	return &t, nil
+}
+
+// FiatRatesGetOHLC returns candles of the currency in the interval for buckets starting in the range [from, to]
+func (d *RocksDB) FiatRatesGetOHLC(currency string, interval OHLCInterval, from, to int64) ([]FiatRatesCandle, error) {
+	candles := []FiatRatesCandle{}
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfFiatRatesOHLC])
+	defer it.Close()
+	for it.Seek(packOHLCKey(interval, interval.BucketStart(from))); it.Valid(); it.Next() {
+		i, start, err := unpackOHLCKey(it.Key().Data())
+		if err != nil {
+			return nil, err
+		}
+		if i != interval || start > to {
+			break
+		}
+		var b fiatRatesOHLCBucket
+		if err := json.Unmarshal(it.Value().Data(), &b); err != nil {
+			glog.Error("FiatRatesGetOHLC error unpacking bucket: ", err)
+			return nil, err
+		}
+		if c, found := b.Candles[currency]; found {
+			candles = append(candles, FiatRatesCandle{Time: start, OHLC: *c})
+		}
+	}
+	return candles, it.Err()
+}
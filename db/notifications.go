@@ -0,0 +1,79 @@
+package db
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/tecbot/gorocksdb"
+)
+
+// notifications spilled from the websocket notification log are stored in the default column
+// under the prefix followed by the big endian sequence number
+const notificationKeyPrefix = "notification:"
+
+func packNotificationKey(seq uint64) []byte {
+	key := make([]byte, len(notificationKeyPrefix)+8)
+	copy(key, notificationKeyPrefix)
+	binary.BigEndian.PutUint64(key[len(notificationKeyPrefix):], seq)
+	return key
+}
+
+// Notification is a serialized notification with its sequence number
+type Notification struct {
+	Seq  uint64
+	Data []byte
+}
+
+// StoreNotifications stores the serialized notifications and in the same write batch deletes
+// the stored notifications with sequence numbers in the range [deleteFrom, deleteTo)
+func (d *RocksDB) StoreNotifications(notifications []Notification, deleteFrom, deleteTo uint64) error {
+	wb := gorocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	for seq := deleteFrom; seq < deleteTo; seq++ {
+		wb.DeleteCF(d.cfh[cfDefault], packNotificationKey(seq))
+	}
+	for i := range notifications {
+		wb.PutCF(d.cfh[cfDefault], packNotificationKey(notifications[i].Seq), notifications[i].Data)
+	}
+	return d.db.Write(d.wo, wb)
+}
+
+// GetNotifications returns the stored notifications with sequence numbers in the range [from, to]
+func (d *RocksDB) GetNotifications(from, to uint64) ([]Notification, error) {
+	var notifications []Notification
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfDefault])
+	defer it.Close()
+	prefix := []byte(notificationKeyPrefix)
+	for it.Seek(packNotificationKey(from)); it.Valid(); it.Next() {
+		key := it.Key().Data()
+		if !bytes.HasPrefix(key, prefix) || len(key) != len(prefix)+8 {
+			break
+		}
+		seq := binary.BigEndian.Uint64(key[len(prefix):])
+		if seq > to {
+			break
+		}
+		notifications = append(notifications, Notification{Seq: seq, Data: append([]byte(nil), it.Value().Data()...)})
+	}
+	return notifications, it.Err()
+}
+
+// DeleteNotifications deletes the stored notifications with sequence numbers lower than before
+func (d *RocksDB) DeleteNotifications(before uint64) error {
+	wb := gorocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfDefault])
+	defer it.Close()
+	prefix := []byte(notificationKeyPrefix)
+	for it.Seek(prefix); it.Valid(); it.Next() {
+		key := it.Key().Data()
+		if !bytes.HasPrefix(key, prefix) || len(key) != len(prefix)+8 || binary.BigEndian.Uint64(key[len(prefix):]) >= before {
+			break
+		}
+		wb.DeleteCF(d.cfh[cfDefault], append([]byte(nil), key...))
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	return d.db.Write(d.wo, wb)
+}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 	"unsafe"
 
@@ -36,14 +37,25 @@ const FiatRatesTimeFormat = "20060102150405" // YYYYMMDDhhmmss
 
 // CurrencyRatesTicker contains coin ticker data fetched from API
 type CurrencyRatesTicker struct {
-	Timestamp *time.Time // return as unix timestamp in API
-	Rates     map[string]float64
+	Timestamp  *time.Time // return as unix timestamp in API
+	Rates      map[string]float64
+	Provenance map[string][]string // providers of the rates, set by the multi provider in both aggregation modes (in fallback mode the single provider used)
+	Derived    map[string]string   // currencies with rates derived from other rates and the method of the derivation
+}
+
+// currencyRatesTickerExtended is the stored form of the ticker with provenance or derived rates,
+// other tickers are stored as plain map of rates
+type currencyRatesTickerExtended struct {
+	Rates      map[string]float64  `json:"rates"`
+	Provenance map[string][]string `json:"provenance,omitempty"`
+	Derived    map[string]string   `json:"derived,omitempty"`
 }
 
 // ResultTickerAsString contains formatted CurrencyRatesTicker data
 type ResultTickerAsString struct {
 	Timestamp int64              `json:"ts,omitempty"`
 	Rates     map[string]float64 `json:"rates"`
+	Derived   map[string]string  `json:"derived,omitempty"`
 	Error     string             `json:"error,omitempty"`
 }
 
@@ -98,6 +110,8 @@ type RocksDB struct {
 	cache        *gorocksdb.Cache
 	maxOpenFiles int
 	cbs          connectBlockStats
+	// secondary is set if the db is opened as a read only secondary instance, see NewRocksDBSecondary
+	secondary bool
 }
 
 const (
@@ -107,6 +121,7 @@ const (
 	cfBlockTxs
 	cfTransactions
 	cfFiatRates
+	cfFiatRatesOHLC
 	// BitcoinType
 	cfAddressBalance
 	cfTxAddresses
@@ -116,7 +131,7 @@ const (
 
 // common columns
 var cfNames []string
-var cfBaseNames = []string{"default", "height", "addresses", "blockTxs", "transactions", "fiatRates"}
+var cfBaseNames = []string{"default", "height", "addresses", "blockTxs", "transactions", "fiatRates", "fiatRatesOHLC"}
 
 // type specific columns
 var cfNamesBitcoinType = []string{"addressBalance", "txAddresses"}
@@ -128,8 +143,10 @@ func openDB(path string, c *gorocksdb.Cache, openFiles int) (*gorocksdb.DB, []*g
 	// opts for addresses without bloom filter
 	// from documentation: if most of your queries are executed using iterators, you shouldn't set bloom filter
 	optsAddresses := createAndSetDBOptions(0, c, openFiles)
-	// default, height, addresses, blockTxids, transactions
-	cfOptions := []*gorocksdb.Options{opts, opts, optsAddresses, opts, opts, opts}
+	// the statistics are used for the metrics of the block cache
+	opts.EnableStatistics()
+	// default, height, addresses, blockTxids, transactions, fiatRates, fiatRatesOHLC
+	cfOptions := []*gorocksdb.Options{opts, opts, optsAddresses, opts, opts, opts, opts}
 	// append type specific options
 	count := len(cfNames) - len(cfOptions)
 	for i := 0; i < count; i++ {
@@ -147,14 +164,8 @@ func openDB(path string, c *gorocksdb.Cache, openFiles int) (*gorocksdb.DB, []*g
 func NewRocksDB(path string, cacheSize, maxOpenFiles int, parser bchain.BlockChainParser, metrics *common.Metrics) (d *RocksDB, err error) {
 	glog.Infof("rocksdb: opening %s, required data version %v, cache size %v, max open files %v", path, dbVersion, cacheSize, maxOpenFiles)
 
-	cfNames = append([]string{}, cfBaseNames...)
-	chainType := parser.GetChainType()
-	if chainType == bchain.ChainBitcoinType {
-		cfNames = append(cfNames, cfNamesBitcoinType...)
-	} else if chainType == bchain.ChainEthereumType {
-		cfNames = append(cfNames, cfNamesEthereumType...)
-	} else {
-		return nil, errors.New("Unknown chain type")
+	if err = setColumnNames(parser); err != nil {
+		return nil, err
 	}
 
 	c := gorocksdb.NewLRUCache(cacheSize)
@@ -164,7 +175,21 @@ func NewRocksDB(path string, cacheSize, maxOpenFiles int, parser bchain.BlockCha
 	}
 	wo := gorocksdb.NewDefaultWriteOptions()
 	ro := gorocksdb.NewDefaultReadOptions()
-	return &RocksDB{path, db, wo, ro, cfh, parser, nil, metrics, c, maxOpenFiles, connectBlockStats{}}, nil
+	return &RocksDB{path, db, wo, ro, cfh, parser, nil, metrics, c, maxOpenFiles, connectBlockStats{}, false}, nil
+}
+
+// setColumnNames sets the names of the columns used by the chain type of the parser
+func setColumnNames(parser bchain.BlockChainParser) error {
+	cfNames = append([]string{}, cfBaseNames...)
+	chainType := parser.GetChainType()
+	if chainType == bchain.ChainBitcoinType {
+		cfNames = append(cfNames, cfNamesBitcoinType...)
+	} else if chainType == bchain.ChainEthereumType {
+		cfNames = append(cfNames, cfNamesEthereumType...)
+	} else {
+		return errors.New("Unknown chain type")
+	}
+	return nil
 }
 
 func (d *RocksDB) closeDB() error {
@@ -197,7 +222,13 @@ func (d *RocksDB) FiatRatesStoreTicker(ticker *CurrencyRatesTicker) error {
 	} else if ticker.Timestamp == nil {
 		return errors.New("Error storing ticker: empty timestamp")
 	}
-	ratesMarshalled, err := json.Marshal(ticker.Rates)
+	var ratesMarshalled []byte
+	var err error
+	if len(ticker.Provenance) > 0 || len(ticker.Derived) > 0 {
+		ratesMarshalled, err = json.Marshal(currencyRatesTickerExtended{Rates: ticker.Rates, Provenance: ticker.Provenance, Derived: ticker.Derived})
+	} else {
+		ratesMarshalled, err = json.Marshal(ticker.Rates)
+	}
 	if err != nil {
 		glog.Error("Error marshalling ticker rates: ", err)
 		return err
@@ -211,6 +242,17 @@ func (d *RocksDB) FiatRatesStoreTicker(ticker *CurrencyRatesTicker) error {
 	return nil
 }
 
+func unpackCurrencyRatesTicker(ticker *CurrencyRatesTicker, buf []byte) error {
+	var te currencyRatesTickerExtended
+	if err := json.Unmarshal(buf, &te); err == nil && te.Rates != nil {
+		ticker.Rates = te.Rates
+		ticker.Provenance = te.Provenance
+		ticker.Derived = te.Derived
+		return nil
+	}
+	return json.Unmarshal(buf, &ticker.Rates)
+}
+
 // FiatRatesFindTicker gets FiatRates data closest to the specified timestamp
 func (d *RocksDB) FiatRatesFindTicker(tickerTime *time.Time) (*CurrencyRatesTicker, error) {
 	ticker := &CurrencyRatesTicker{}
@@ -226,7 +268,7 @@ func (d *RocksDB) FiatRatesFindTicker(tickerTime *time.Time) (*CurrencyRatesTick
 		}
 		timeObj = timeObj.UTC()
 		ticker.Timestamp = &timeObj
-		err = json.Unmarshal(it.Value().Data(), &ticker.Rates)
+		err = unpackCurrencyRatesTicker(ticker, it.Value().Data())
 		if err != nil {
 			glog.Error("FiatRatesFindTicker error unpacking rates: ", err)
 			return nil, err
@@ -257,7 +299,7 @@ func (d *RocksDB) FiatRatesFindLastTicker() (*CurrencyRatesTicker, error) {
 		}
 		timeObj = timeObj.UTC()
 		ticker.Timestamp = &timeObj
-		err = json.Unmarshal(it.Value().Data(), &ticker.Rates)
+		err = unpackCurrencyRatesTicker(ticker, it.Value().Data())
 		if err != nil {
 			glog.Error("FiatRatesFindTicker error unpacking rates: ", err)
 			return nil, err
@@ -274,11 +316,39 @@ func (d *RocksDB) FiatRatesFindLastTicker() (*CurrencyRatesTicker, error) {
 	return ticker, nil
 }
 
+// FiatRatesGetTickers calls fn for all tickers in the time range [from, to), the iteration stops if fn returns an error
+func (d *RocksDB) FiatRatesGetTickers(from, to *time.Time, fn func(ticker *CurrencyRatesTicker) error) error {
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfFiatRates])
+	defer it.Close()
+	toFormatted := []byte(to.UTC().Format(FiatRatesTimeFormat))
+	for it.Seek([]byte(from.UTC().Format(FiatRatesTimeFormat))); it.Valid(); it.Next() {
+		key := it.Key().Data()
+		if bytes.Compare(key, toFormatted) >= 0 {
+			break
+		}
+		timeObj, err := time.Parse(FiatRatesTimeFormat, string(key))
+		if err != nil {
+			glog.Error("FiatRatesGetTickers time parse error: ", err)
+			return err
+		}
+		timeObj = timeObj.UTC()
+		ticker := &CurrencyRatesTicker{Timestamp: &timeObj}
+		if err = unpackCurrencyRatesTicker(ticker, it.Value().Data()); err != nil {
+			glog.Error("FiatRatesGetTickers error unpacking rates: ", err)
+			return err
+		}
+		if err = fn(ticker); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
 // Close releases the RocksDB environment opened in NewRocksDB.
 func (d *RocksDB) Close() error {
 	if d.db != nil {
-		// store the internal state of the app
-		if d.is != nil && d.is.DbState == common.DbStateOpen {
+		// store the internal state of the app, the secondary instance cannot write
+		if d.is != nil && d.is.DbState == common.DbStateOpen && !d.secondary {
 			d.is.DbState = common.DbStateClosed
 			if err := d.StoreInternalState(d.is); err != nil {
 				glog.Info("internalState: ", err)
@@ -345,6 +415,48 @@ func (d *RocksDB) GetMemoryStats() string {
 	return fmt.Sprintf("Total %d, indexAndFilter %d, memtable %d, %+v", total, indexAndFilter, memtable, m)
 }
 
+// cache statistics reported by UpdateCacheMetrics, the tickers of RocksDB statistics by cache and status
+var dbCacheTickers = []struct {
+	ticker, cache, status string
+}{
+	{"rocksdb.block.cache.hit", "all", "hit"},
+	{"rocksdb.block.cache.miss", "all", "miss"},
+	{"rocksdb.block.cache.index.hit", "index", "hit"},
+	{"rocksdb.block.cache.index.miss", "index", "miss"},
+	{"rocksdb.block.cache.filter.hit", "filter", "hit"},
+	{"rocksdb.block.cache.filter.miss", "filter", "miss"},
+	{"rocksdb.block.cache.data.hit", "data", "hit"},
+	{"rocksdb.block.cache.data.miss", "data", "miss"},
+}
+
+// UpdateCacheMetrics sets the metrics of the usage and of the hits and misses of the block cache
+func (d *RocksDB) UpdateCacheMetrics() {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.DbCacheUsage.Set(float64(d.cache.GetUsage()))
+	counts := parseStatisticsCounts(d.db.GetProperty("rocksdb.options-statistics"))
+	for _, t := range dbCacheTickers {
+		if v, found := counts[t.ticker]; found {
+			d.metrics.DbCacheEfficiency.With(common.Labels{"cache": t.cache, "status": t.status}).Set(float64(v))
+		}
+	}
+}
+
+// parseStatisticsCounts parses the tickers from the RocksDB statistics, lines in the form "rocksdb.block.cache.hit COUNT : 1234"
+func parseStatisticsCounts(stats string) map[string]int64 {
+	counts := make(map[string]int64)
+	for _, line := range strings.Split(stats, "\n") {
+		f := strings.Fields(line)
+		if len(f) == 4 && f[1] == "COUNT" && f[2] == ":" {
+			if v, err := strconv.ParseInt(f[3], 10, 64); err == nil {
+				counts[f[0]] = v
+			}
+		}
+	}
+	return counts
+}
+
 // StopIteration is returned by callback function to signal stop of iteration
 type StopIteration struct{}
 
@@ -1794,6 +1906,127 @@ func (d *RocksDB) storeState(is *common.InternalState) error {
 	return d.db.PutCF(d.wo, d.cfh[cfDefault], []byte(internalStateKey), buf)
 }
 
+// mempool state
+const mempoolStateKey = "mempoolState"
+
+func (d *RocksDB) packMempoolEntries(entries []bchain.MempoolPersistedEntry) ([]byte, error) {
+	buf := make([]byte, 0, 64*len(entries)+vlq.MaxLen64)
+	varBuf := make([]byte, vlq.MaxLen64)
+	l := packVaruint(uint(len(entries)), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	for i := range entries {
+		e := &entries[i]
+		btxID, err := d.chainParser.PackTxid(e.Txid)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, btxID...)
+		buf = append(buf, packUint(e.Time)...)
+		l = packVaruint(uint(len(e.AddrIndexes)), varBuf)
+		buf = append(buf, varBuf[:l]...)
+		for j := range e.AddrIndexes {
+			ai := &e.AddrIndexes[j]
+			l = packVaruint(uint(len(ai.AddrDesc)), varBuf)
+			buf = append(buf, varBuf[:l]...)
+			buf = append(buf, ai.AddrDesc...)
+			l = packVarint32(ai.N, varBuf)
+			buf = append(buf, varBuf[:l]...)
+		}
+	}
+	return buf, nil
+}
+
+// unpackMempoolEntries unpacks the entries packed by packMempoolEntries, the data is checked so that a truncated
+// or corrupted value returns an error
+func (d *RocksDB) unpackMempoolEntries(buf []byte) ([]bchain.MempoolPersistedEntry, error) {
+	errInconsistent := errors.New("Inconsistent data in unpackMempoolEntries")
+	txidUnpackedLen := d.chainParser.PackedTxidLen()
+	p := 0
+	// varuint returns the next varuint, which must not be greater than max
+	varuint := func(max int) (int, error) {
+		v, l := unpackVaruint(buf[p:])
+		if l <= 0 || v > uint(max) {
+			return 0, errInconsistent
+		}
+		p += l
+		return int(v), nil
+	}
+	// each entry takes at least the txid, time and the number of addresses
+	n, err := varuint(len(buf) / (txidUnpackedLen + 5))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]bchain.MempoolPersistedEntry, n)
+	for i := 0; i < n; i++ {
+		if p+txidUnpackedLen+4 > len(buf) {
+			return nil, errInconsistent
+		}
+		txid, err := d.chainParser.UnpackTxid(buf[p : p+txidUnpackedLen])
+		if err != nil {
+			return nil, err
+		}
+		p += txidUnpackedLen
+		e := &entries[i]
+		e.Txid = txid
+		e.Time = unpackUint(buf[p : p+4])
+		p += 4
+		// each address index takes at least the length of the descriptor and the index
+		na, err := varuint((len(buf) - p) / 2)
+		if err != nil {
+			return nil, err
+		}
+		e.AddrIndexes = make([]bchain.MempoolAddrIndex, na)
+		for j := 0; j < na; j++ {
+			al, err := varuint(len(buf))
+			if err != nil {
+				return nil, err
+			}
+			if p+al > len(buf) {
+				return nil, errInconsistent
+			}
+			e.AddrIndexes[j].AddrDesc = append(bchain.AddressDescriptor(nil), buf[p:p+al]...)
+			p += al
+			var l int
+			e.AddrIndexes[j].N, l = unpackVarint32(buf[p:])
+			if l <= 0 {
+				return nil, errInconsistent
+			}
+			p += l
+		}
+	}
+	return entries, nil
+}
+
+// StoreMempoolEntries stores the mempool entries to db so that they survive restart
+func (d *RocksDB) StoreMempoolEntries(entries []bchain.MempoolPersistedEntry) error {
+	buf, err := d.packMempoolEntries(entries)
+	if err != nil {
+		return err
+	}
+	return d.db.PutCF(d.wo, d.cfh[cfDefault], []byte(mempoolStateKey), buf)
+}
+
+// LoadMempoolEntries loads the mempool entries stored by StoreMempoolEntries
+// The caller should remove the entries by DeleteMempoolEntries after they are imported,
+// so that a stale state is not loaded after an unclean shutdown
+func (d *RocksDB) LoadMempoolEntries() ([]bchain.MempoolPersistedEntry, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfDefault], []byte(mempoolStateKey))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	data := val.Data()
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return d.unpackMempoolEntries(data)
+}
+
+// DeleteMempoolEntries removes the mempool entries stored by StoreMempoolEntries
+func (d *RocksDB) DeleteMempoolEntries() error {
+	return d.db.DeleteCF(d.wo, d.cfh[cfDefault], []byte(mempoolStateKey))
+}
+
 func (d *RocksDB) computeColumnSize(col int, stopCompute chan os.Signal) (int64, int64, int64, error) {
 	var rows, keysSum, valuesSum int64
 	var seekKey []byte
@@ -1834,10 +2067,13 @@ func (d *RocksDB) computeColumnSize(col int, stopCompute chan os.Signal) (int64,
 
 // ComputeInternalStateColumnStats computes stats of all db columns and sets them to internal state
 // can be very slow operation
-func (d *RocksDB) ComputeInternalStateColumnStats(stopCompute chan os.Signal) error {
+func (d *RocksDB) ComputeInternalStateColumnStats(stopCompute chan os.Signal, progress ProgressFunc) error {
 	start := time.Now()
 	glog.Info("db: ComputeInternalStateColumnStats start")
 	for c := 0; c < len(cfNames); c++ {
+		if progress != nil {
+			progress(int64(c), int64(len(cfNames)))
+		}
 		rows, keysSum, valuesSum, err := d.computeColumnSize(c, stopCompute)
 		if err != nil {
 			return err
@@ -1970,13 +2206,17 @@ func (d *RocksDB) fixUtxo(addrDesc bchain.AddressDescriptor, ba *AddrBalance) (b
 }
 
 // FixUtxos checks and fixes possible
-func (d *RocksDB) FixUtxos(stop chan os.Signal) error {
+func (d *RocksDB) FixUtxos(stop chan os.Signal, progress ProgressFunc) error {
 	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
 		glog.Info("FixUtxos: applicable only for bitcoin type coins")
 		return nil
 	}
 	glog.Info("FixUtxos: starting")
-	var row, errorsCount, fixedCount int64
+	var row, errorsCount, fixedCount, total int64
+	if d.is != nil {
+		// the number of rows is only an estimate from the last computation of the column stats
+		total, _, _ = d.is.GetDBColumnStatValues(cfAddressBalance)
+	}
 	var seekKey []byte
 	// do not use cache
 	ro := gorocksdb.NewDefaultReadOptions()
@@ -1988,6 +2228,9 @@ func (d *RocksDB) FixUtxos(stop chan os.Signal) error {
 			it.SeekToFirst()
 		} else {
 			glog.Info("FixUtxos: row ", row, ", errors ", errorsCount)
+			if progress != nil {
+				progress(row, total)
+			}
 			it.Seek(seekKey)
 			it.Next()
 		}
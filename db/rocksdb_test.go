@@ -1551,6 +1551,33 @@ func TestRocksTickers(t *testing.T) {
 		t.Errorf("Incorrect ticker found. Expected: %v, found: %+v", ticker1.Timestamp, ticker.Timestamp)
 	}
 
+	// ticker with provenance
+	ts3, _ := time.Parse(FiatRatesTimeFormat, "20190629120000")
+	ticker3 := &CurrencyRatesTicker{
+		Timestamp: &ts3,
+		Rates: map[string]float64{
+			"usd": 31000,
+			"eur": 28000,
+			"czk": 700000,
+		},
+		Provenance: map[string][]string{
+			"usd": {"coingecko", "coinpaprika"},
+			"eur": {"coingecko"},
+		},
+		Derived: map[string]string{
+			"czk": "fx",
+		},
+	}
+	if err = d.FiatRatesStoreTicker(ticker3); err != nil {
+		t.Errorf("Error storing ticker! %v", err)
+	}
+	ticker, err = d.FiatRatesFindLastTicker()
+	if err != nil {
+		t.Errorf("TestRocksTickers err: %+v", err)
+	} else if ticker == nil || !reflect.DeepEqual(ticker.Rates, ticker3.Rates) || !reflect.DeepEqual(ticker.Provenance, ticker3.Provenance) || !reflect.DeepEqual(ticker.Derived, ticker3.Derived) {
+		t.Errorf("Incorrect ticker found. Expected: %+v, found: %+v", ticker3, ticker)
+	}
+
 	ticker, err = d.FiatRatesFindTicker(&futureKey) // should not find anything
 	if err != nil {
 		t.Errorf("TestRocksTickers err: %+v", err)
@@ -1558,3 +1585,176 @@ func TestRocksTickers(t *testing.T) {
 		t.Errorf("Ticker found, but the timestamp is older than the last ticker entry.")
 	}
 }
+
+func TestRocksDB_MempoolEntries(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	entries := []bchain.MempoolPersistedEntry{
+		{
+			Txid: dbtestdata.TxidB2T1,
+			Time: 1554000000,
+			AddrIndexes: []bchain.MempoolAddrIndex{
+				{AddrDesc: addressToAddrDesc(dbtestdata.AddrA, d.chainParser), N: 0},
+				{AddrDesc: addressToAddrDesc(dbtestdata.Addr3, d.chainParser), N: ^int32(1)},
+			},
+		},
+		{
+			Txid: dbtestdata.TxidB2T2,
+			Time: 1554000123,
+			AddrIndexes: []bchain.MempoolAddrIndex{
+				{AddrDesc: addressToAddrDesc(dbtestdata.Addr8, d.chainParser), N: 1},
+			},
+		},
+	}
+	if err := d.StoreMempoolEntries(entries); err != nil {
+		t.Fatal(err)
+	}
+	got, err := d.LoadMempoolEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("LoadMempoolEntries() = %+v, want %+v", got, entries)
+	}
+	// the entries stay stored until they are deleted after the import
+	got, err = d.LoadMempoolEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("LoadMempoolEntries() second load = %+v, want %+v", got, entries)
+	}
+	if err = d.DeleteMempoolEntries(); err != nil {
+		t.Fatal(err)
+	}
+	got, err = d.LoadMempoolEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("LoadMempoolEntries() after delete = %+v, want empty", got)
+	}
+}
+
+func TestRocksDB_UnpackMempoolEntriesCorrupted(t *testing.T) {
+	d := &RocksDB{chainParser: bitcoinTestnetParser()}
+	entries := []bchain.MempoolPersistedEntry{
+		{
+			Txid: dbtestdata.TxidB2T1,
+			Time: 1554000000,
+			AddrIndexes: []bchain.MempoolAddrIndex{
+				{AddrDesc: addressToAddrDesc(dbtestdata.AddrA, d.chainParser), N: 0},
+				{AddrDesc: addressToAddrDesc(dbtestdata.Addr3, d.chainParser), N: ^int32(1)},
+			},
+		},
+	}
+	buf, err := d.packMempoolEntries(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := d.unpackMempoolEntries(buf)
+	if err != nil || !reflect.DeepEqual(got, entries) {
+		t.Fatalf("unpackMempoolEntries() = %+v, %v, want %+v", got, err, entries)
+	}
+	// every truncation of the value is detected
+	for i := 0; i < len(buf); i++ {
+		if _, err := d.unpackMempoolEntries(buf[:i]); err == nil {
+			t.Errorf("unpackMempoolEntries() of the value truncated to %d bytes did not fail", i)
+		}
+	}
+	// the counts and lengths over the size of the value are rejected
+	corrupted := [][]byte{
+		{0xff, 0xff, 0xff, 0xff, 0x7f},
+		append([]byte{0x01}, append(buf[1:1+d.chainParser.PackedTxidLen()+4], 0xff, 0xff, 0xff, 0x7f)...),
+		append([]byte{0x01}, append(buf[1:1+d.chainParser.PackedTxidLen()+4], 0x01, 0xff, 0xff, 0x7f)...),
+	}
+	for i, c := range corrupted {
+		if _, err := d.unpackMempoolEntries(c); err == nil {
+			t.Errorf("unpackMempoolEntries() of the corrupted value %d did not fail", i)
+		}
+	}
+}
+
+func TestRocksTickersOHLC(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	if last, err := d.FiatRatesOHLCLastTime(); err != nil || last != nil {
+		t.Fatalf("FiatRatesOHLCLastTime() = %v, %v, want nil", last, err)
+	}
+	tickers := []struct {
+		time  string
+		rates map[string]float64
+	}{
+		{"20190628101500", map[string]float64{"usd": 100, "eur": 90}},
+		{"20190628104500", map[string]float64{"usd": 120, "eur": 100}},
+		// out of order ticker changes only high and low
+		{"20190628103000", map[string]float64{"usd": 80, "eur": 85}},
+		{"20190628110500", map[string]float64{"usd": 110}},
+		// the same ticker again does not change the candles
+		{"20190628101500", map[string]float64{"usd": 100, "eur": 90}},
+	}
+	for _, tt := range tickers {
+		ts, _ := time.Parse(FiatRatesTimeFormat, tt.time)
+		if err := d.FiatRatesUpdateOHLC(&CurrencyRatesTicker{Timestamp: &ts, Rates: tt.rates}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	last, err := d.FiatRatesOHLCLastTime()
+	if err != nil || last == nil || last.Unix() != 1561719900 {
+		t.Errorf("FiatRatesOHLCLastTime() = %v, %v, want 1561719900", last, err)
+	}
+	tests := []struct {
+		currency string
+		interval OHLCInterval
+		from, to int64
+		want     []FiatRatesCandle
+	}{
+		{"usd", OHLCHour, 1561717800, 1561719600, []FiatRatesCandle{
+			{Time: 1561716000, OHLC: OHLC{Open: 100, High: 120, Low: 80, Close: 120}},
+			{Time: 1561719600, OHLC: OHLC{Open: 110, High: 110, Low: 110, Close: 110}},
+		}},
+		{"eur", OHLCHour, 1561716000, 1561723200, []FiatRatesCandle{
+			{Time: 1561716000, OHLC: OHLC{Open: 90, High: 100, Low: 85, Close: 100}},
+		}},
+		{"usd", OHLCDay, 1561680000, 1561680000, []FiatRatesCandle{
+			{Time: 1561680000, OHLC: OHLC{Open: 100, High: 120, Low: 80, Close: 110}},
+		}},
+		{"usd", OHLCWeek, 1561680000, 1561766400, []FiatRatesCandle{
+			{Time: 1561334400, OHLC: OHLC{Open: 100, High: 120, Low: 80, Close: 110}},
+		}},
+		{"usd", OHLCDay, 1561766400, 1562371200, []FiatRatesCandle{}},
+		{"czk", OHLCDay, 1561680000, 1561680000, []FiatRatesCandle{}},
+	}
+	for _, tt := range tests {
+		got, err := d.FiatRatesGetOHLC(tt.currency, tt.interval, tt.from, tt.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FiatRatesGetOHLC(%v, %v, %v, %v) = %+v, want %+v", tt.currency, tt.interval, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func Test_parseStatisticsCounts(t *testing.T) {
+	stats := `rocksdb.block.cache.miss COUNT : 1523
+rocksdb.block.cache.hit COUNT : 98234
+rocksdb.block.cache.index.hit COUNT : 0
+rocksdb.db.get.micros P50 : 2.500000 P95 : 10.000000 P99 : 31.000000 P100 : 412.000000 COUNT : 120 SUM : 600
+rocksdb.invalid COUNT : x
+`
+	want := map[string]int64{
+		"rocksdb.block.cache.miss":      1523,
+		"rocksdb.block.cache.hit":       98234,
+		"rocksdb.block.cache.index.hit": 0,
+	}
+	if got := parseStatisticsCounts(stats); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseStatisticsCounts() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,208 @@
+package db
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+	"github.com/scryptachain/blockbook-scrypta/common"
+	"github.com/tecbot/gorocksdb"
+)
+
+// number of the last blocks remembered by SecondaryWorker to find the fork point of a reorg done by the primary instance
+const secondaryKnownBlocks = 100
+
+// NewRocksDBSecondary opens the db at path as a read only secondary instance of the db written by another (primary) process.
+// The secondaryPath is the directory of the own files of the secondary instance, it must be unique for each secondary instance.
+// The data written by the primary instance are visible after CatchUpWithPrimary.
+func NewRocksDBSecondary(path, secondaryPath string, cacheSize int, parser bchain.BlockChainParser, metrics *common.Metrics) (*RocksDB, error) {
+	glog.Infof("rocksdb: opening %s as secondary instance in %s, required data version %v, cache size %v", path, secondaryPath, dbVersion, cacheSize)
+	if err := setColumnNames(parser); err != nil {
+		return nil, err
+	}
+	c := gorocksdb.NewLRUCache(cacheSize)
+	db, cfh, err := openSecondaryDB(path, secondaryPath, c)
+	if err != nil {
+		return nil, err
+	}
+	wo := gorocksdb.NewDefaultWriteOptions()
+	ro := gorocksdb.NewDefaultReadOptions()
+	return &RocksDB{path: path, db: db, wo: wo, ro: ro, cfh: cfh, chainParser: parser, metrics: metrics, cache: c, secondary: true}, nil
+}
+
+// IsSecondary returns true if the db is opened as a read only secondary instance
+func (d *RocksDB) IsSecondary() bool {
+	return d.secondary
+}
+
+// CatchUpWithPrimary makes the data written by the primary instance visible to the secondary instance
+func (d *RocksDB) CatchUpWithPrimary() error {
+	if !d.secondary {
+		return errors.New("db is not a secondary instance")
+	}
+	return tryCatchUpWithPrimary(d.db)
+}
+
+// ReloadInternalState updates the column stats of the internal state from the state stored by the primary instance
+func (d *RocksDB) ReloadInternalState() error {
+	if d.is == nil {
+		return errors.New("Internal state not created")
+	}
+	val, err := d.db.GetCF(d.ro, d.cfh[cfDefault], []byte(internalStateKey))
+	if err != nil {
+		return err
+	}
+	defer val.Free()
+	if len(val.Data()) == 0 {
+		return nil
+	}
+	stored, err := common.UnpackInternalState(val.Data())
+	if err != nil {
+		return err
+	}
+	for c := range cfNames {
+		for _, sc := range stored.DbColumns {
+			if sc.Name == cfNames[c] {
+				d.is.SetDBColumnStats(c, sc.Rows, sc.KeyBytes, sc.ValueBytes)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// secondaryBlockSource is the part of RocksDB used by SecondaryWorker
+type secondaryBlockSource interface {
+	GetBestBlock() (uint32, string, error)
+	GetBlockHash(height uint32) (string, error)
+	GetBlockInfo(height uint32) (*BlockInfo, error)
+}
+
+// SecondaryWorker follows the blocks connected and disconnected by the primary instance of the db,
+// updates the internal state and notifies about the new blocks
+type SecondaryWorker struct {
+	db         *RocksDB
+	blocks     secondaryBlockSource
+	catchUp    func() error
+	is         *common.InternalState
+	onNewBlock bchain.OnNewBlockFunc
+	bestHeight uint32
+	// hashes of the last blocks by height
+	known map[uint32]string
+}
+
+// NewSecondaryWorker creates SecondaryWorker starting from the current best block of the db
+func NewSecondaryWorker(db *RocksDB, is *common.InternalState, onNewBlock bchain.OnNewBlockFunc) (*SecondaryWorker, error) {
+	if !db.secondary {
+		return nil, errors.New("db is not a secondary instance")
+	}
+	return newSecondaryWorker(db, db, db.CatchUpWithPrimary, is, onNewBlock)
+}
+
+func newSecondaryWorker(db *RocksDB, blocks secondaryBlockSource, catchUp func() error, is *common.InternalState, onNewBlock bchain.OnNewBlockFunc) (*SecondaryWorker, error) {
+	w := &SecondaryWorker{
+		db:         db,
+		blocks:     blocks,
+		catchUp:    catchUp,
+		is:         is,
+		onNewBlock: onNewBlock,
+		known:      make(map[uint32]string),
+	}
+	height, hash, err := blocks.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	if hash != "" {
+		w.remember(height, hash)
+	}
+	is.FinishedSync(height)
+	return w, nil
+}
+
+func (w *SecondaryWorker) remember(height uint32, hash string) {
+	w.known[height] = hash
+	delete(w.known, height-secondaryKnownBlocks)
+	w.bestHeight = height
+}
+
+// CatchUp makes the changes of the primary instance visible, updates the internal state and notifies about the new blocks
+func (w *SecondaryWorker) CatchUp() error {
+	start := time.Now()
+	w.is.StartedSync()
+	if err := w.catchUp(); err != nil {
+		return errors.Annotate(err, "CatchUpWithPrimary")
+	}
+	if w.db != nil {
+		if err := w.db.ReloadInternalState(); err != nil {
+			glog.Error("SecondaryWorker: ReloadInternalState ", err)
+		}
+	}
+	height, hash, err := w.blocks.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	if hash == "" {
+		// the primary instance has not connected any block yet
+		w.is.FinishedSyncNoChange()
+		return nil
+	}
+	if height == w.bestHeight && hash == w.known[height] {
+		w.is.FinishedSyncNoChange()
+		return nil
+	}
+	// the first height to connect, the db was empty if there are no known blocks
+	next := uint32(0)
+	if len(w.known) > 0 {
+		// find the last block of the known chain which is still in the db
+		fork := w.bestHeight
+		if height < fork {
+			fork = height
+		}
+		for {
+			knownHash, found := w.known[fork]
+			if !found {
+				return errors.Errorf("reorg deeper than %d blocks, restart required", secondaryKnownBlocks)
+			}
+			h, err := w.blocks.GetBlockHash(fork)
+			if err != nil {
+				return err
+			}
+			if h == knownHash {
+				break
+			}
+			if fork == 0 {
+				return errors.New("reorg of the genesis block")
+			}
+			fork--
+		}
+		if fork < w.bestHeight {
+			glog.Info("SecondaryWorker: the primary instance disconnected blocks ", fork+1, "-", w.bestHeight)
+			w.is.RemoveLastBlockTimes(int(w.bestHeight - fork))
+			for h := fork + 1; h <= w.bestHeight; h++ {
+				delete(w.known, h)
+			}
+			w.bestHeight = fork
+		}
+		next = fork + 1
+	}
+	for h := next; h <= height; h++ {
+		bi, err := w.blocks.GetBlockInfo(h)
+		if err != nil {
+			return err
+		}
+		if bi == nil {
+			// the block is being connected by the primary instance, continue at the next catch up
+			break
+		}
+		w.is.AppendBlockTime(uint32(bi.Time))
+		w.remember(h, bi.Hash)
+		w.is.UpdateBestHeight(h)
+		if w.onNewBlock != nil {
+			w.onNewBlock(bi.Hash, h)
+		}
+	}
+	w.is.FinishedSync(w.bestHeight)
+	glog.Info("SecondaryWorker: caught up with the primary instance at height ", w.bestHeight, " in ", time.Since(start))
+	return nil
+}
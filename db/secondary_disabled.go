@@ -0,0 +1,18 @@
+// +build !rocksdb_secondary
+
+package db
+
+import (
+	"github.com/juju/errors"
+	"github.com/tecbot/gorocksdb"
+)
+
+var errSecondaryNotSupported = errors.New("secondary instance requires build with tag rocksdb_secondary and RocksDB 6.5 or newer")
+
+func openSecondaryDB(path, secondaryPath string, c *gorocksdb.Cache) (*gorocksdb.DB, []*gorocksdb.ColumnFamilyHandle, error) {
+	return nil, nil, errSecondaryNotSupported
+}
+
+func tryCatchUpWithPrimary(db *gorocksdb.DB) error {
+	return errSecondaryNotSupported
+}
@@ -0,0 +1,91 @@
+// +build rocksdb_secondary
+
+package db
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+import "C"
+
+import (
+	"errors"
+	"reflect"
+	"unsafe"
+
+	"github.com/tecbot/gorocksdb"
+)
+
+// the secondary instance is not supported by gorocksdb, it is opened using the RocksDB C API
+// and the handles are stored to the unexported fields of gorocksdb types, in the same way as in createAndSetDBOptions
+
+// unexportedField returns the pointer to the unexported field of the struct pointed by v
+func unexportedField(v interface{}, name string) unsafe.Pointer {
+	return unsafe.Pointer(reflect.Indirect(reflect.ValueOf(v)).FieldByName(name).UnsafeAddr())
+}
+
+func openSecondaryDB(path, secondaryPath string, c *gorocksdb.Cache) (*gorocksdb.DB, []*gorocksdb.ColumnFamilyHandle, error) {
+	opts := createAndSetDBOptions(10, c, -1)
+	optsAddresses := createAndSetDBOptions(0, c, -1)
+	opts.SetCreateIfMissing(false)
+	opts.SetCreateIfMissingColumnFamilies(false)
+	opts.EnableStatistics()
+	cfOptions := []*gorocksdb.Options{opts, opts, optsAddresses, opts, opts, opts, opts}
+	for len(cfOptions) < len(cfNames) {
+		cfOptions = append(cfOptions, opts)
+	}
+
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	cSecondaryPath := C.CString(secondaryPath)
+	defer C.free(unsafe.Pointer(cSecondaryPath))
+	cNames := make([]*C.char, len(cfNames))
+	for i, n := range cfNames {
+		cNames[i] = C.CString(n)
+	}
+	defer func() {
+		for _, n := range cNames {
+			C.free(unsafe.Pointer(n))
+		}
+	}()
+	cOpts := make([]*C.rocksdb_options_t, len(cfNames))
+	for i, o := range cfOptions {
+		cOpts[i] = *(**C.rocksdb_options_t)(unexportedField(o, "c"))
+	}
+	cHandles := make([]*C.rocksdb_column_family_handle_t, len(cfNames))
+
+	var cErr *C.char
+	cDB := C.rocksdb_open_as_secondary_column_families(
+		*(**C.rocksdb_options_t)(unexportedField(opts, "c")),
+		cPath,
+		cSecondaryPath,
+		C.int(len(cfNames)),
+		&cNames[0],
+		&cOpts[0],
+		&cHandles[0],
+		&cErr,
+	)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return nil, nil, errors.New(C.GoString(cErr))
+	}
+
+	db := &gorocksdb.DB{}
+	*(**C.rocksdb_t)(unexportedField(db, "c")) = cDB
+	*(*string)(unexportedField(db, "name")) = path
+	*(**gorocksdb.Options)(unexportedField(db, "opts")) = opts
+	cfh := make([]*gorocksdb.ColumnFamilyHandle, len(cHandles))
+	for i, h := range cHandles {
+		cfh[i] = &gorocksdb.ColumnFamilyHandle{}
+		*(**C.rocksdb_column_family_handle_t)(unexportedField(cfh[i], "c")) = h
+	}
+	return db, cfh, nil
+}
+
+func tryCatchUpWithPrimary(db *gorocksdb.DB) error {
+	var cErr *C.char
+	C.rocksdb_try_catch_up_with_primary((*C.rocksdb_t)(db.UnsafeGetDB()), &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
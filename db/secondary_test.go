@@ -0,0 +1,109 @@
+// +build unittest
+
+package db
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/scryptachain/blockbook-scrypta/common"
+)
+
+// testBlockSource is the chain of the blocks of the primary instance
+type testBlockSource struct {
+	hashes []string
+}
+
+func (s *testBlockSource) GetBestBlock() (uint32, string, error) {
+	if len(s.hashes) == 0 {
+		return 0, "", nil
+	}
+	return uint32(len(s.hashes) - 1), s.hashes[len(s.hashes)-1], nil
+}
+
+func (s *testBlockSource) GetBlockHash(height uint32) (string, error) {
+	if int(height) >= len(s.hashes) {
+		return "", nil
+	}
+	return s.hashes[height], nil
+}
+
+func (s *testBlockSource) GetBlockInfo(height uint32) (*BlockInfo, error) {
+	if int(height) >= len(s.hashes) {
+		return nil, nil
+	}
+	return &BlockInfo{Hash: s.hashes[height], Time: 1000 + int64(height), Height: height}, nil
+}
+
+func (s *testBlockSource) connect(prefix string, count int) {
+	for i := 0; i < count; i++ {
+		s.hashes = append(s.hashes, prefix+strconv.Itoa(len(s.hashes)))
+	}
+}
+
+func TestSecondaryWorker(t *testing.T) {
+	source := &testBlockSource{}
+	catchUps := 0
+	is := &common.InternalState{}
+	var notified []string
+	w, err := newSecondaryWorker(nil, source, func() error { catchUps++; return nil }, is, func(hash string, height uint32) {
+		notified = append(notified, hash)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(name string, wantNotified []string) {
+		t.Helper()
+		if err := w.CatchUp(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(notified, wantNotified) {
+			t.Errorf("%s: notified %v, want %v", name, notified, wantNotified)
+		}
+		notified = nil
+		synchronized, height, _ := is.GetSyncState()
+		if !synchronized || int(height) != len(source.hashes)-1 {
+			t.Errorf("%s: sync state %v %v, want synchronized at %v", name, synchronized, height, len(source.hashes)-1)
+		}
+		if len(is.BlockTimes) != len(source.hashes) || is.GetBlockTime(height) != 1000+uint32(height) {
+			t.Errorf("%s: %d block times, want %d", name, len(is.BlockTimes), len(source.hashes))
+		}
+	}
+
+	// the primary instance connects the first blocks to the empty db
+	source.connect("a", 3)
+	check("initial", []string{"a0", "a1", "a2"})
+	check("no change", nil)
+	source.connect("a", 2)
+	check("new blocks", []string{"a3", "a4"})
+	// reorg replaces the blocks 3 and 4 by 3 new blocks
+	source.hashes = source.hashes[:3]
+	source.connect("b", 3)
+	check("reorg", []string{"b3", "b4", "b5"})
+	// disconnect only
+	source.hashes = source.hashes[:5]
+	check("disconnect", nil)
+	if catchUps != 5 {
+		t.Errorf("%d catch ups with primary, want 5", catchUps)
+	}
+
+	// the worker started with a non empty db does not notify the existing blocks
+	w, err = newSecondaryWorker(nil, source, func() error { return nil }, is, func(hash string, height uint32) {
+		notified = append(notified, hash)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	source.connect("c", 1)
+	check("restart", []string{"c5"})
+
+	// too deep reorg
+	source.hashes = source.hashes[:1]
+	source.connect("d", secondaryKnownBlocks+10)
+	w.known = map[uint32]string{5: "c5"}
+	w.bestHeight = 5
+	if err := w.CatchUp(); err == nil {
+		t.Error("too deep reorg not reported")
+	}
+}
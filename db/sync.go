@@ -48,11 +48,15 @@ var errSynced = errors.New("synced")
 // ErrOperationInterrupted is returned when operation is interrupted by OS signal
 var ErrOperationInterrupted = errors.New("ErrOperationInterrupted")
 
+// ProgressFunc reports the progress of a long running operation, total is 0 if it is not known
+type ProgressFunc func(done, total int64)
+
 // ResyncIndex synchronizes index to the top of the blockchain
 // onNewBlock is called when new block is connected, but not in initial parallel sync
 func (w *SyncWorker) ResyncIndex(onNewBlock bchain.OnNewBlockFunc, initialSync bool) error {
 	start := time.Now()
 	w.is.StartedSync()
+	defer w.updateBackendLag()
 
 	err := w.resyncIndex(onNewBlock, initialSync)
 
@@ -83,6 +87,19 @@ func (w *SyncWorker) ResyncIndex(onNewBlock bchain.OnNewBlockFunc, initialSync b
 	return err
 }
 
+// updateBackendLag sets the metric of the number of blocks the index is behind the backend
+func (w *SyncWorker) updateBackendLag() {
+	remoteBestHeight, err := w.chain.GetBestBlockHeight()
+	if err != nil {
+		return
+	}
+	localBestHeight, _, err := w.db.GetBestBlock()
+	if err != nil {
+		return
+	}
+	w.metrics.BackendLag.Set(float64(remoteBestHeight) - float64(localBestHeight))
+}
+
 func (w *SyncWorker) resyncIndex(onNewBlock bchain.OnNewBlockFunc, initialSync bool) error {
 	remoteBestHash, err := w.chain.GetBestBlockHash()
 	if err != nil {
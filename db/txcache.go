@@ -33,6 +33,13 @@ func NewTxCache(db *RocksDB, chain bchain.BlockChain, metrics *common.Metrics, i
 	}, nil
 }
 
+// WithChain returns a copy of the cache getting the missing transactions from the chain
+func (c *TxCache) WithChain(chain bchain.BlockChain) *TxCache {
+	cc := *c
+	cc.chain = chain
+	return &cc
+}
+
 // GetTransaction returns transaction either from RocksDB or if not present from blockchain
 // it the transaction is confirmed, it is stored in the RocksDB
 func (c *TxCache) GetTransaction(txid string) (*bchain.Tx, int, error) {
@@ -0,0 +1,250 @@
+package fiat
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/scryptachain/blockbook-scrypta/db"
+)
+
+const (
+	backfillDay             = 24 * time.Hour
+	backfillMaxRetries      = 5
+	backfillMaxPause        = 5 * time.Minute
+	backfillMinRetryPause   = time.Second
+	backfillMaxReportedDays = 1000
+	backfillDateFormat      = "2006-01-02"
+)
+
+// ErrBackfillRunning is returned if a backfill is started while another one is running
+var ErrBackfillRunning = errors.New("Fiat rates backfill is already running")
+
+// CurrencyCoverage contains number of days in the range which have a ticker with the currency
+type CurrencyCoverage struct {
+	Days     int     `json:"days"`
+	Coverage float64 `json:"coverage"`
+	First    string  `json:"first"`
+	Last     string  `json:"last"`
+}
+
+// FiatRatesCoverage describes coverage of the days in the range [From, To] by stored tickers
+type FiatRatesCoverage struct {
+	From        string                      `json:"from"`
+	To          string                      `json:"to"`
+	Days        int                         `json:"days"`
+	CoveredDays int                         `json:"coveredDays"`
+	MissingDays []string                    `json:"missingDays"`
+	Currencies  map[string]CurrencyCoverage `json:"currencies"`
+	missing     []time.Time
+}
+
+// BackfillStatus contains state and result of a backfill of fiat rates
+type BackfillStatus struct {
+	Running    bool               `json:"running"`
+	Started    time.Time          `json:"started"`
+	Finished   *time.Time         `json:"finished,omitempty"`
+	From       string             `json:"from"`
+	To         string             `json:"to"`
+	Missing    int                `json:"missing"`
+	Processed  int                `json:"processed"`
+	Downloaded int                `json:"downloaded"`
+	FailedDays []string           `json:"failedDays,omitempty"`
+	Error      string             `json:"error,omitempty"`
+	Coverage   *FiatRatesCoverage `json:"coverage,omitempty"`
+}
+
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ComputeFiatRatesCoverage scans stored tickers and finds days in the range [from, to] without any ticker
+// and for each currency the number of days in which the currency has a rate
+func ComputeFiatRatesCoverage(d *db.RocksDB, from, to time.Time) (*FiatRatesCoverage, error) {
+	from = truncateToDay(from)
+	to = truncateToDay(to)
+	if to.Before(from) {
+		return nil, errors.New("Invalid range")
+	}
+	end := to.Add(backfillDay)
+	days := int(end.Sub(from) / backfillDay)
+	covered := make([]bool, days)
+	currencyDays := make(map[string]map[int]struct{})
+	err := d.FiatRatesGetTickers(&from, &end, func(ticker *db.CurrencyRatesTicker) error {
+		day := int(ticker.Timestamp.Sub(from) / backfillDay)
+		covered[day] = true
+		for c := range ticker.Rates {
+			cd, found := currencyDays[c]
+			if !found {
+				cd = make(map[int]struct{})
+				currencyDays[c] = cd
+			}
+			cd[day] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c := &FiatRatesCoverage{
+		From:        from.Format(backfillDateFormat),
+		To:          to.Format(backfillDateFormat),
+		Days:        days,
+		MissingDays: []string{},
+		Currencies:  make(map[string]CurrencyCoverage, len(currencyDays)),
+	}
+	for i := range covered {
+		if covered[i] {
+			c.CoveredDays++
+		} else {
+			t := from.Add(time.Duration(i) * backfillDay)
+			c.missing = append(c.missing, t)
+			if len(c.MissingDays) < backfillMaxReportedDays {
+				c.MissingDays = append(c.MissingDays, t.Format(backfillDateFormat))
+			}
+		}
+	}
+	for currency, cd := range currencyDays {
+		indexes := make([]int, 0, len(cd))
+		for i := range cd {
+			indexes = append(indexes, i)
+		}
+		sort.Ints(indexes)
+		c.Currencies[currency] = CurrencyCoverage{
+			Days:     len(indexes),
+			Coverage: float64(len(indexes)) / float64(days),
+			First:    from.Add(time.Duration(indexes[0]) * backfillDay).Format(backfillDateFormat),
+			Last:     from.Add(time.Duration(indexes[len(indexes)-1]) * backfillDay).Format(backfillDateFormat),
+		}
+	}
+	return c, nil
+}
+
+// BackfillStatus returns the status of the running or the last finished backfill, nil if there was none
+func (rd *RatesDownloader) BackfillStatus() *BackfillStatus {
+	rd.backfillMux.Lock()
+	defer rd.backfillMux.Unlock()
+	if rd.backfill == nil {
+		return nil
+	}
+	s := *rd.backfill
+	s.FailedDays = append([]string(nil), s.FailedDays...)
+	return &s
+}
+
+func (rd *RatesDownloader) updateBackfill(update func(s *BackfillStatus)) {
+	rd.backfillMux.Lock()
+	update(rd.backfill)
+	rd.backfillMux.Unlock()
+}
+
+// downloadDay downloads and stores the historical ticker for the day, on error it retries with exponentially increasing pause,
+// starting from backfillMinRetryPause if the pause is zero
+// Returns false if the ticker could not be downloaded or stored, error only if the backfill was interrupted
+func (rd *RatesDownloader) downloadDay(day time.Time, pause time.Duration, interrupt chan os.Signal) (bool, error) {
+	if pause <= 0 {
+		// the pause is doubled before the first retry
+		pause = backfillMinRetryPause / 2
+	}
+	for retry := 0; ; retry++ {
+		downloader, _ := rd.config()
+		ticker, err := downloader.getTicker(&day)
+		if err == nil {
+			if err = rd.storeTicker(ticker); err != nil {
+				glog.Errorf("Fiat rates backfill: error storing ticker for %v: %v", day, err)
+				rd.countError("store")
+				return false, nil
+			}
+			return true, nil
+		}
+		rd.countError("download")
+		if retry >= backfillMaxRetries {
+			glog.Errorf("Fiat rates backfill: giving up on %v: %v", day, err)
+			return false, nil
+		}
+		pause *= 2
+		if pause > backfillMaxPause {
+			pause = backfillMaxPause
+		}
+		glog.Warningf("Fiat rates backfill: error downloading %v: %v, retrying in %v", day, err, pause)
+		select {
+		case <-interrupt:
+			return false, db.ErrOperationInterrupted
+		case <-time.After(pause):
+		}
+	}
+}
+
+// Backfill finds days in the range [from, to] without stored ticker and downloads the historical tickers for them
+// The requests are paced by the pause, which is increased if the provider returns errors (e.g. because of a rate limit)
+// The backfill can be stopped by a signal in the interrupt channel, only one backfill can run at a time
+func (rd *RatesDownloader) Backfill(from, to time.Time, pause time.Duration, interrupt chan os.Signal) (*BackfillStatus, error) {
+	rd.backfillMux.Lock()
+	if rd.backfill != nil && rd.backfill.Running {
+		rd.backfillMux.Unlock()
+		return nil, ErrBackfillRunning
+	}
+	rd.backfill = &BackfillStatus{
+		Running: true,
+		Started: time.Now().UTC(),
+		From:    truncateToDay(from).Format(backfillDateFormat),
+		To:      truncateToDay(to).Format(backfillDateFormat),
+	}
+	rd.backfillMux.Unlock()
+	err := rd.runBackfill(from, to, pause, interrupt)
+	rd.updateBackfill(func(s *BackfillStatus) {
+		now := time.Now().UTC()
+		s.Running = false
+		s.Finished = &now
+		if err != nil {
+			s.Error = err.Error()
+		}
+	})
+	return rd.BackfillStatus(), err
+}
+
+func (rd *RatesDownloader) runBackfill(from, to time.Time, pause time.Duration, interrupt chan os.Signal) error {
+	coverage, err := ComputeFiatRatesCoverage(rd.db, from, to)
+	if err != nil {
+		return err
+	}
+	glog.Infof("Fiat rates backfill: %v-%v, %d of %d days missing", coverage.From, coverage.To, len(coverage.missing), coverage.Days)
+	rd.updateBackfill(func(s *BackfillStatus) {
+		s.Missing = len(coverage.missing)
+	})
+	for i, day := range coverage.missing {
+		if i > 0 {
+			select {
+			case <-interrupt:
+				return db.ErrOperationInterrupted
+			case <-time.After(pause):
+			}
+		}
+		downloaded, err := rd.downloadDay(day, pause, interrupt)
+		if err != nil {
+			return err
+		}
+		rd.updateBackfill(func(s *BackfillStatus) {
+			s.Processed++
+			if downloaded {
+				s.Downloaded++
+			} else {
+				s.FailedDays = append(s.FailedDays, day.Format(backfillDateFormat))
+			}
+		})
+		if (i+1)%100 == 0 {
+			glog.Infof("Fiat rates backfill: processed %d of %d days", i+1, len(coverage.missing))
+		}
+	}
+	if coverage, err = ComputeFiatRatesCoverage(rd.db, from, to); err != nil {
+		return err
+	}
+	rd.updateBackfill(func(s *BackfillStatus) {
+		s.Coverage = coverage
+	})
+	glog.Infof("Fiat rates backfill: finished, %d of %d days covered", coverage.CoveredDays, coverage.Days)
+	return nil
+}
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang/glog"
@@ -18,6 +21,9 @@ type Coingecko struct {
 	coin               string
 	httpTimeoutSeconds time.Duration
 	timeFormat         string
+	// currencies supported by the simple price API, downloaded once
+	currencies     []string
+	currenciesLock sync.Mutex
 }
 
 // NewCoinGeckoDownloader creates a coingecko structure that implements the RatesDownloaderInterface
@@ -32,12 +38,27 @@ func NewCoinGeckoDownloader(url string, coin string, timeFormat string) RatesDow
 
 // makeRequest retrieves the response from Coingecko API at the specified date.
 // If timestamp is nil, it fetches the latest market data available.
-func (cg *Coingecko) makeRequest(timestamp *time.Time) ([]byte, error) {
-	requestURL := cg.url + "/coins/" + cg.coin
+func (cg *Coingecko) makeRequest(coin string, timestamp *time.Time) ([]byte, error) {
+	requestURL := cg.url + "/coins/" + coin
 	if timestamp != nil {
 		requestURL += "/history"
 	}
+	q := url.Values{}
+	if timestamp == nil {
+		q.Add("market_data", "true")
+		q.Add("localization", "false")
+		q.Add("tickers", "false")
+		q.Add("community_data", "false")
+		q.Add("developer_data", "false")
+	} else {
+		timestampFormatted := timestamp.Format(cg.timeFormat)
+		q.Add("date", timestampFormatted)
+	}
+	return cg.get(requestURL, q)
+}
 
+// get retrieves the response from Coingecko API at the url with the query parameters
+func (cg *Coingecko) get(requestURL string, q url.Values) ([]byte, error) {
 	req, err := http.NewRequest("GET", requestURL, nil)
 	if err != nil {
 		glog.Errorf("Error creating a new request for %v: %v", requestURL, err)
@@ -46,23 +67,9 @@ func (cg *Coingecko) makeRequest(timestamp *time.Time) ([]byte, error) {
 	req.Close = true
 	req.Header.Set("Content-Type", "application/json")
 
-	// Add query parameters
-	q := req.URL.Query()
-
 	// Add a unix timestamp to query parameters to get uncached responses
 	currentTimestamp := strconv.FormatInt(time.Now().UTC().UnixNano(), 10)
 	q.Add("current_timestamp", currentTimestamp)
-
-	if timestamp == nil {
-		q.Add("market_data", "true")
-		q.Add("localization", "false")
-		q.Add("tickers", "false")
-		q.Add("community_data", "false")
-		q.Add("developer_data", "false")
-	} else {
-		timestampFormatted := timestamp.Format(cg.timeFormat)
-		q.Add("date", timestampFormatted)
-	}
 	req.URL.RawQuery = q.Encode()
 
 	client := &http.Client{
@@ -86,6 +93,10 @@ func (cg *Coingecko) makeRequest(timestamp *time.Time) ([]byte, error) {
 // GetData gets fiat rates from API at the specified date and returns a CurrencyRatesTicker
 // If timestamp is nil, it will download the current fiat rates.
 func (cg *Coingecko) getTicker(timestamp *time.Time) (*db.CurrencyRatesTicker, error) {
+	return cg.getCoinTicker(cg.coin, timestamp)
+}
+
+func (cg *Coingecko) getCoinTicker(coin string, timestamp *time.Time) (*db.CurrencyRatesTicker, error) {
 	dataTimestamp := timestamp
 	if timestamp == nil {
 		timeNow := time.Now()
@@ -93,7 +104,7 @@ func (cg *Coingecko) getTicker(timestamp *time.Time) (*db.CurrencyRatesTicker, e
 	}
 	dataTimestampUTC := dataTimestamp.UTC()
 	ticker := &db.CurrencyRatesTicker{Timestamp: &dataTimestampUTC}
-	bodyBytes, err := cg.makeRequest(timestamp)
+	bodyBytes, err := cg.makeRequest(coin, timestamp)
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +127,7 @@ func (cg *Coingecko) getTicker(timestamp *time.Time) (*db.CurrencyRatesTicker, e
 
 // MarketDataExists checks if there's data available for the specific timestamp.
 func (cg *Coingecko) marketDataExists(timestamp *time.Time) (bool, error) {
-	resp, err := cg.makeRequest(timestamp)
+	resp, err := cg.makeRequest(cg.coin, timestamp)
 	if err != nil {
 		glog.Error("Error getting market data: ", err)
 		return false, err
@@ -134,3 +145,70 @@ func (cg *Coingecko) marketDataExists(timestamp *time.Time) (bool, error) {
 	}
 	return len(data.MarketData.Prices) != 0, nil
 }
+
+// supportedCurrencies returns the currencies supported by the simple price API, the list is downloaded only once
+func (cg *Coingecko) supportedCurrencies() ([]string, error) {
+	cg.currenciesLock.Lock()
+	defer cg.currenciesLock.Unlock()
+	if cg.currencies != nil {
+		return cg.currencies, nil
+	}
+	bodyBytes, err := cg.get(cg.url+"/simple/supported_vs_currencies", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	var currencies []string
+	if err = json.Unmarshal(bodyBytes, &currencies); err != nil {
+		glog.Errorf("Error parsing Coingecko supported currencies: %v", err)
+		return nil, err
+	}
+	if len(currencies) == 0 {
+		return nil, errors.New("No supported currencies")
+	}
+	cg.currencies = currencies
+	return currencies, nil
+}
+
+// getTickers returns the tickers of the coin and of the other coin, the current rates of both coins are downloaded
+// in one request of the simple price API, the historical rates need a request for each coin
+// The error is returned only if the rates of the coin are not available, the ticker of the other coin can be nil
+func (cg *Coingecko) getTickers(timestamp *time.Time, other string) (*db.CurrencyRatesTicker, *db.CurrencyRatesTicker, error) {
+	if timestamp != nil {
+		ticker, err := cg.getCoinTicker(cg.coin, timestamp)
+		if err != nil {
+			return nil, nil, err
+		}
+		otherTicker, err := cg.getCoinTicker(other, timestamp)
+		if err != nil {
+			glog.Warningf("Coingecko: rates of %v error: %v", other, err)
+			return ticker, nil, nil
+		}
+		return ticker, otherTicker, nil
+	}
+	currencies, err := cg.supportedCurrencies()
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now().UTC()
+	q := url.Values{}
+	q.Add("ids", cg.coin+","+other)
+	q.Add("vs_currencies", strings.Join(currencies, ","))
+	bodyBytes, err := cg.get(cg.url+"/simple/price", q)
+	if err != nil {
+		return nil, nil, err
+	}
+	var data map[string]map[string]float64
+	if err = json.Unmarshal(bodyBytes, &data); err != nil {
+		glog.Errorf("Error parsing Coingecko simple price response: %v", err)
+		return nil, nil, err
+	}
+	if len(data[cg.coin]) == 0 {
+		return nil, nil, errors.New("No rates of " + cg.coin)
+	}
+	ticker := &db.CurrencyRatesTicker{Timestamp: &now, Rates: data[cg.coin]}
+	var otherTicker *db.CurrencyRatesTicker
+	if len(data[other]) > 0 {
+		otherTicker = &db.CurrencyRatesTicker{Timestamp: &now, Rates: data[other]}
+	}
+	return ticker, otherTicker, nil
+}
@@ -0,0 +1,108 @@
+package fiat
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/scryptachain/blockbook-scrypta/db"
+)
+
+// CoinPaprika is a structure that implements RatesDownloaderInterface
+type CoinPaprika struct {
+	url        string
+	coin       string
+	currencies []string
+}
+
+// currencies supported by CoinPaprika in historical data
+var coinPaprikaHistoricalCurrencies = map[string]struct{}{"usd": {}, "btc": {}}
+
+// NewCoinPaprikaDownloader creates a CoinPaprika structure that implements the RatesDownloaderInterface
+// CoinPaprika provides historical rates only in usd and btc, the latest rates in the specified currencies
+func NewCoinPaprikaDownloader(url string, coin string, currencies []string) RatesDownloaderInterface {
+	if len(currencies) == 0 {
+		currencies = []string{"usd", "btc", "eur"}
+	}
+	return &CoinPaprika{
+		url:        url,
+		coin:       coin,
+		currencies: currencies,
+	}
+}
+
+func (cp *CoinPaprika) getLatest() (map[string]float64, error) {
+	var data struct {
+		Quotes map[string]struct {
+			Price float64 `json:"price"`
+		} `json:"quotes"`
+	}
+	q := url.Values{}
+	q.Add("quotes", strings.ToUpper(strings.Join(cp.currencies, ",")))
+	if err := httpGetJSON(cp.url+"/tickers/"+cp.coin+"?"+q.Encode(), nil, &data); err != nil {
+		return nil, err
+	}
+	rates := make(map[string]float64, len(data.Quotes))
+	for c, v := range data.Quotes {
+		rates[strings.ToLower(c)] = v.Price
+	}
+	return rates, nil
+}
+
+func (cp *CoinPaprika) getHistorical(timestamp *time.Time, currency string) (float64, bool, error) {
+	var data []struct {
+		Timestamp string  `json:"timestamp"`
+		Price     float64 `json:"price"`
+	}
+	q := url.Values{}
+	q.Add("start", timestamp.UTC().Format("2006-01-02"))
+	q.Add("interval", "1d")
+	q.Add("limit", "1")
+	q.Add("quote", currency)
+	if err := httpGetJSON(cp.url+"/tickers/"+cp.coin+"/historical?"+q.Encode(), nil, &data); err != nil {
+		return 0, false, err
+	}
+	if len(data) == 0 {
+		return 0, false, nil
+	}
+	return data[0].Price, true, nil
+}
+
+// getTicker gets fiat rates from API at the specified date and returns a CurrencyRatesTicker
+// If timestamp is nil, it will download the current fiat rates.
+func (cp *CoinPaprika) getTicker(timestamp *time.Time) (*db.CurrencyRatesTicker, error) {
+	ticker := &db.CurrencyRatesTicker{Timestamp: tickerTimestamp(timestamp)}
+	if timestamp == nil {
+		rates, err := cp.getLatest()
+		if err != nil {
+			return nil, err
+		}
+		ticker.Rates = rates
+		return ticker, nil
+	}
+	ticker.Rates = make(map[string]float64)
+	for _, c := range cp.currencies {
+		c = strings.ToLower(c)
+		if _, found := coinPaprikaHistoricalCurrencies[c]; !found {
+			continue
+		}
+		rate, found, err := cp.getHistorical(timestamp, c)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			ticker.Rates[c] = rate
+		}
+	}
+	if len(ticker.Rates) == 0 {
+		return nil, errors.New("No historical data")
+	}
+	return ticker, nil
+}
+
+// marketDataExists checks if there's data available for the specific timestamp.
+func (cp *CoinPaprika) marketDataExists(timestamp *time.Time) (bool, error) {
+	_, found, err := cp.getHistorical(timestamp, "usd")
+	return found, err
+}
@@ -0,0 +1,130 @@
+package fiat
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scryptachain/blockbook-scrypta/db"
+)
+
+// CryptoCompare limits the length of the list of currencies in the historical request
+const cryptoCompareMaxHistoricalSymbolsLen = 30
+
+// CryptoCompare is a structure that implements RatesDownloaderInterface
+type CryptoCompare struct {
+	url        string
+	coin       string
+	currencies []string
+	apiKey     string
+}
+
+// NewCryptoCompareDownloader creates a CryptoCompare structure that implements the RatesDownloaderInterface
+func NewCryptoCompareDownloader(url string, coin string, currencies []string, apiKey string) RatesDownloaderInterface {
+	if len(currencies) == 0 {
+		currencies = []string{"usd", "eur", "btc"}
+	}
+	cc := make([]string, len(currencies))
+	for i := range currencies {
+		cc[i] = strings.ToUpper(currencies[i])
+	}
+	return &CryptoCompare{
+		url:        url,
+		coin:       strings.ToUpper(coin),
+		currencies: cc,
+		apiKey:     apiKey,
+	}
+}
+
+// symbolsChunks splits the currencies to comma separated lists not longer than maxLen
+func (cc *CryptoCompare) symbolsChunks(maxLen int) []string {
+	var chunks []string
+	var chunk string
+	for _, c := range cc.currencies {
+		if chunk != "" && len(chunk)+1+len(c) > maxLen {
+			chunks = append(chunks, chunk)
+			chunk = ""
+		}
+		if chunk != "" {
+			chunk += ","
+		}
+		chunk += c
+	}
+	if chunk != "" {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func (cc *CryptoCompare) request(path string, q url.Values) (json.RawMessage, error) {
+	var headers map[string]string
+	if cc.apiKey != "" {
+		headers = map[string]string{"authorization": "Apikey " + cc.apiKey}
+	}
+	var data json.RawMessage
+	if err := httpGetJSON(cc.url+path+"?"+q.Encode(), headers, &data); err != nil {
+		return nil, err
+	}
+	var e struct {
+		Response string `json:"Response"`
+		Message  string `json:"Message"`
+	}
+	if json.Unmarshal(data, &e) == nil && e.Response == "Error" {
+		return nil, errors.New(e.Message)
+	}
+	return data, nil
+}
+
+// getTicker gets fiat rates from API at the specified date and returns a CurrencyRatesTicker
+// If timestamp is nil, it will download the current fiat rates.
+func (cc *CryptoCompare) getTicker(timestamp *time.Time) (*db.CurrencyRatesTicker, error) {
+	ticker := &db.CurrencyRatesTicker{Timestamp: tickerTimestamp(timestamp)}
+	rates := make(map[string]float64)
+	if timestamp == nil {
+		q := url.Values{}
+		q.Add("fsym", cc.coin)
+		q.Add("tsyms", strings.Join(cc.currencies, ","))
+		data, err := cc.request("/data/price", q)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(data, &rates); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, symbols := range cc.symbolsChunks(cryptoCompareMaxHistoricalSymbolsLen) {
+			q := url.Values{}
+			q.Add("fsym", cc.coin)
+			q.Add("tsyms", symbols)
+			q.Add("ts", strconv.FormatInt(timestamp.Unix(), 10))
+			data, err := cc.request("/data/pricehistorical", q)
+			if err != nil {
+				return nil, err
+			}
+			var r map[string]map[string]float64
+			if err = json.Unmarshal(data, &r); err != nil {
+				return nil, err
+			}
+			for c, v := range r[cc.coin] {
+				// zero is returned for dates without data
+				if v > 0 {
+					rates[c] = v
+				}
+			}
+		}
+	}
+	ticker.Rates = lowerCaseRates(rates)
+	return ticker, nil
+}
+
+// marketDataExists checks if there's data available for the specific timestamp.
+func (cc *CryptoCompare) marketDataExists(timestamp *time.Time) (bool, error) {
+	ticker, err := cc.getTicker(timestamp)
+	if err != nil {
+		return false, err
+	}
+	return len(ticker.Rates) > 0, nil
+}
@@ -0,0 +1,156 @@
+package fiat
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/scryptachain/blockbook-scrypta/db"
+)
+
+// methods of derivation of the rates, stored in CurrencyRatesTicker.Derived
+const (
+	// DerivedViaIntermediate - the rate was computed using the rate of the coin in an intermediate asset (e.g. coin->btc->fiat)
+	DerivedViaIntermediate = "intermediate"
+	// DerivedViaFX - the rate was computed using the rate of the coin in the base fiat currency and a FX cross-rate
+	DerivedViaFX = "fx"
+)
+
+// multiCoinDownloader is implemented by the downloaders which can download the rates of another coin
+// together with the rates of their coin
+type multiCoinDownloader interface {
+	getTickers(timestamp *time.Time, other string) (*db.CurrencyRatesTicker, *db.CurrencyRatesTicker, error)
+}
+
+// DerivedParams contains configuration of the derived rates
+// The rates of the intermediate asset are downloaded either by the IntermediateProvider or, if IntermediateCoin
+// is set, by the downloader of the coin together with the rates of the coin
+type DerivedParams struct {
+	Intermediate         string          `json:"intermediate"`
+	IntermediateProvider *ProviderParams `json:"intermediateProvider"`
+	IntermediateCoin     string          `json:"intermediateCoin"`
+	FXBase               string          `json:"fxBase"`
+	FXProvider           *ProviderParams `json:"fxProvider"`
+	Currencies           []string        `json:"currencies"`
+}
+
+// DerivedRates implements RatesDownloaderInterface, it adds rates derived via an intermediate asset
+// and via FX cross-rates to the rates downloaded by the wrapped downloader
+// Rates returned directly by the wrapped downloader are never overwritten
+type DerivedRates struct {
+	downloader   RatesDownloaderInterface
+	intermediate string
+	via          RatesDownloaderInterface
+	// viaCoin is the coin of the intermediate asset downloaded by the downloader together with the coin
+	viaCoin    string
+	fxBase     string
+	fx         RatesDownloaderInterface
+	currencies map[string]struct{}
+}
+
+// NewDerivedRates creates DerivedRates wrapping the downloader
+func NewDerivedRates(downloader RatesDownloaderInterface, params *DerivedParams, timeFormat string) (*DerivedRates, error) {
+	dr := &DerivedRates{
+		downloader:   downloader,
+		intermediate: strings.ToLower(params.Intermediate),
+		fxBase:       strings.ToLower(params.FXBase),
+	}
+	var err error
+	if params.IntermediateCoin != "" && dr.intermediate != "" {
+		if params.IntermediateProvider != nil {
+			return nil, errors.New("Both intermediateProvider and intermediateCoin are set")
+		}
+		if _, ok := downloader.(multiCoinDownloader); !ok {
+			return nil, errors.New("The provider cannot download the rates of intermediateCoin")
+		}
+		dr.viaCoin = params.IntermediateCoin
+	} else if params.IntermediateProvider != nil && dr.intermediate != "" {
+		if dr.via, err = newRatesProvider(params.IntermediateProvider, timeFormat); err != nil {
+			return nil, err
+		}
+	}
+	if params.FXProvider != nil && dr.fxBase != "" {
+		if dr.fx, err = newRatesProvider(params.FXProvider, timeFormat); err != nil {
+			return nil, err
+		}
+	}
+	if len(params.Currencies) > 0 {
+		dr.currencies = make(map[string]struct{}, len(params.Currencies))
+		for _, c := range params.Currencies {
+			dr.currencies[strings.ToLower(c)] = struct{}{}
+		}
+	}
+	return dr, nil
+}
+
+// deriveRates adds to the ticker missing rates computed as rate of the coin in the base times the rates of the base
+func (dr *DerivedRates) deriveRates(ticker *db.CurrencyRatesTicker, base string, baseRates map[string]float64, method string) int {
+	baseRate := ticker.Rates[base]
+	if baseRate <= 0 {
+		return 0
+	}
+	count := 0
+	for c, r := range baseRates {
+		if r <= 0 || c == base {
+			continue
+		}
+		if _, found := ticker.Rates[c]; found {
+			continue
+		}
+		if dr.currencies != nil {
+			if _, found := dr.currencies[c]; !found {
+				continue
+			}
+		}
+		ticker.Rates[c] = baseRate * r
+		if ticker.Derived == nil {
+			ticker.Derived = make(map[string]string)
+		}
+		ticker.Derived[c] = method
+		count++
+	}
+	return count
+}
+
+// getTicker returns the ticker of the wrapped downloader with added derived rates,
+// errors of the providers of the intermediate or FX rates are only logged
+func (dr *DerivedRates) getTicker(timestamp *time.Time) (*db.CurrencyRatesTicker, error) {
+	var ticker, viaTicker *db.CurrencyRatesTicker
+	var err error
+	if dr.viaCoin != "" {
+		ticker, viaTicker, err = dr.downloader.(multiCoinDownloader).getTickers(timestamp, dr.viaCoin)
+	} else {
+		ticker, err = dr.downloader.getTicker(timestamp)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ticker.Rates == nil {
+		ticker.Rates = make(map[string]float64)
+	}
+	if viaTicker != nil {
+		dr.deriveRates(ticker, dr.intermediate, viaTicker.Rates, DerivedViaIntermediate)
+	} else if dr.via != nil {
+		t, err := dr.via.getTicker(timestamp)
+		if err != nil {
+			glog.Warningf("Derived rates: intermediate %v rates error: %v", dr.intermediate, err)
+		} else {
+			dr.deriveRates(ticker, dr.intermediate, t.Rates, DerivedViaIntermediate)
+		}
+	}
+	if dr.fx != nil {
+		t, err := dr.fx.getTicker(timestamp)
+		if err != nil {
+			glog.Warningf("Derived rates: FX rates of %v error: %v", dr.fxBase, err)
+		} else {
+			dr.deriveRates(ticker, dr.fxBase, t.Rates, DerivedViaFX)
+		}
+	}
+	return ticker, nil
+}
+
+// marketDataExists checks if there's data of the coin available for the specific timestamp.
+func (dr *DerivedRates) marketDataExists(timestamp *time.Time) (bool, error) {
+	return dr.downloader.marketDataExists(timestamp)
+}
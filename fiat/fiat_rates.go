@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/scryptachain/blockbook-scrypta/common"
 	"github.com/scryptachain/blockbook-scrypta/db"
 )
 
@@ -28,27 +30,24 @@ type RatesDownloader struct {
 	timeFormat          string
 	callbackOnNewTicker OnNewFiatRatesTicker
 	downloader          RatesDownloaderInterface
+	configMux           sync.Mutex
+	backfillMux         sync.Mutex
+	backfill            *BackfillStatus
+	metrics             *common.Metrics
 }
 
 // NewFiatRatesDownloader initiallizes the downloader for FiatRates API.
 // If the startTime is nil, the downloader will start from the beginning.
+// The apiType "multi" combines the providers listed in the params, other types are single providers (coingecko, coinpaprika, cryptocompare, json or file).
 func NewFiatRatesDownloader(db *db.RocksDB, apiType string, params string, startTime *time.Time, callback OnNewFiatRatesTicker) (*RatesDownloader, error) {
 	var rd = &RatesDownloader{}
-	type fiatRatesParams struct {
-		URL           string `json:"url"`
-		Coin          string `json:"coin"`
-		PeriodSeconds int    `json:"periodSeconds"`
-	}
-	rdParams := &fiatRatesParams{}
-	err := json.Unmarshal([]byte(params), &rdParams)
+	rd.timeFormat = "02-01-2006" // Layout string for FiatRates date formatting (DD-MM-YYYY)
+	downloader, period, err := newDownloader(apiType, params, rd.timeFormat)
 	if err != nil {
 		return nil, err
 	}
-	if rdParams.URL == "" || rdParams.PeriodSeconds == 0 {
-		return nil, errors.New("Missing parameters")
-	}
-	rd.timeFormat = "02-01-2006"                                           // Layout string for FiatRates date formatting (DD-MM-YYYY)
-	rd.periodSeconds = time.Duration(rdParams.PeriodSeconds) * time.Second // Time period for syncing the latest market data
+	rd.downloader = downloader
+	rd.periodSeconds = period // Time period for syncing the latest market data
 	rd.db = db
 	rd.callbackOnNewTicker = callback
 	if startTime == nil {
@@ -57,12 +56,83 @@ func NewFiatRatesDownloader(db *db.RocksDB, apiType string, params string, start
 	} else {
 		rd.startTime = startTime // If startTime is nil, time.Now() will be used
 	}
-	if apiType == "coingecko" {
-		rd.downloader = NewCoinGeckoDownloader(rdParams.URL, rdParams.Coin, rd.timeFormat)
+	return rd, nil
+}
+
+// newDownloader creates the downloader of the apiType from the params and returns it with the period of the sync of the latest rates
+func newDownloader(apiType string, params string, timeFormat string) (RatesDownloaderInterface, time.Duration, error) {
+	type fiatRatesParams struct {
+		ProviderParams
+		PeriodSeconds int              `json:"periodSeconds"`
+		Aggregation   string           `json:"aggregation"`
+		Providers     []ProviderParams `json:"providers"`
+		Derived       *DerivedParams   `json:"derived"`
+	}
+	rdParams := &fiatRatesParams{}
+	err := json.Unmarshal([]byte(params), &rdParams)
+	if err != nil {
+		return nil, 0, err
+	}
+	if rdParams.PeriodSeconds == 0 {
+		return nil, 0, errors.New("Missing parameters")
+	}
+	var downloader RatesDownloaderInterface
+	if apiType == "multi" {
+		downloader, err = NewRatesProviders(rdParams.Providers, rdParams.Aggregation, timeFormat)
 	} else {
-		return nil, fmt.Errorf("NewFiatRatesDownloader: incorrect API type %q", apiType)
+		rdParams.ProviderParams.Type = apiType
+		downloader, err = newRatesProvider(&rdParams.ProviderParams, timeFormat)
+	}
+	if err == nil && rdParams.Derived != nil {
+		downloader, err = NewDerivedRates(downloader, rdParams.Derived, timeFormat)
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("NewFiatRatesDownloader: %v", err)
+	}
+	return downloader, time.Duration(rdParams.PeriodSeconds) * time.Second, nil
+}
+
+// RatesConfig is the downloader and the sync period prepared by NewRatesConfig
+type RatesConfig struct {
+	downloader RatesDownloaderInterface
+	period     time.Duration
+}
+
+// NewRatesConfig checks apiType and params and returns the configuration, which is applied by Reconfigure
+func (rd *RatesDownloader) NewRatesConfig(apiType string, params string) (*RatesConfig, error) {
+	downloader, period, err := newDownloader(apiType, params, rd.timeFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &RatesConfig{downloader: downloader, period: period}, nil
+}
+
+// Reconfigure replaces the downloader and the sync period by the ones of the configuration,
+// the running downloader switches to them at the next download
+func (rd *RatesDownloader) Reconfigure(c *RatesConfig) {
+	rd.configMux.Lock()
+	rd.downloader = c.downloader
+	rd.periodSeconds = c.period
+	rd.configMux.Unlock()
+}
+
+// config returns the current downloader and sync period
+func (rd *RatesDownloader) config() (RatesDownloaderInterface, time.Duration) {
+	rd.configMux.Lock()
+	defer rd.configMux.Unlock()
+	return rd.downloader, rd.periodSeconds
+}
+
+// SetMetrics sets the metrics counting the errors of the downloader
+func (rd *RatesDownloader) SetMetrics(metrics *common.Metrics) {
+	rd.metrics = metrics
+}
+
+// countError increments the metric of the errors of the operation (download, store or ohlc)
+func (rd *RatesDownloader) countError(operation string) {
+	if rd.metrics != nil {
+		rd.metrics.FiatRatesErrors.With(common.Labels{"operation": operation}).Inc()
 	}
-	return rd, nil
 }
 
 // Run starts the FiatRates downloader. If there are tickers available, it continues from the last record.
@@ -71,6 +141,13 @@ func NewFiatRatesDownloader(db *db.RocksDB, apiType string, params string, start
 func (rd *RatesDownloader) Run() error {
 	var timestamp *time.Time
 
+	if count, err := UpdateOHLC(rd.db); err != nil {
+		glog.Errorf("RatesDownloader UpdateOHLC error: %v", err)
+		rd.countError("ohlc")
+	} else {
+		glog.Infof("RatesDownloader: %d tickers aggregated to OHLC candles", count)
+	}
+
 	// Check if there are any tickers stored in database
 	glog.Infof("Finding last available ticker...")
 	ticker, err := rd.db.FiatRatesFindLastTicker()
@@ -117,10 +194,12 @@ func (rd *RatesDownloader) findEarliestMarketData() (*time.Time, error) {
 	for {
 		var dataExists bool = false
 		for {
-			dataExists, err = rd.downloader.marketDataExists(&currentDate)
+			downloader, period := rd.config()
+			dataExists, err = downloader.marketDataExists(&currentDate)
 			if err != nil {
-				glog.Errorf("Error checking if market data exists for date %v. Error: %v. Retrying in %v seconds.", currentDate, err, rd.periodSeconds)
-				timer := time.NewTimer(rd.periodSeconds)
+				glog.Errorf("Error checking if market data exists for date %v. Error: %v. Retrying in %v seconds.", currentDate, err, period)
+				rd.countError("download")
+				timer := time.NewTimer(period)
 				<-timer.C
 			}
 			break
@@ -142,16 +221,20 @@ func (rd *RatesDownloader) findEarliestMarketData() (*time.Time, error) {
 
 // syncLatest downloads the latest FiatRates data every rd.PeriodSeconds
 func (rd *RatesDownloader) syncLatest() error {
-	timer := time.NewTimer(rd.periodSeconds)
+	_, period := rd.config()
+	timer := time.NewTimer(period)
 	var lastTickerRates map[string]float64
 	sameTickerCounter := 0
 	for {
-		ticker, err := rd.downloader.getTicker(nil)
+		var downloader RatesDownloaderInterface
+		downloader, period = rd.config()
+		ticker, err := downloader.getTicker(nil)
 		if err != nil {
 			// Do not exit on GET error, log it, wait and try again
 			glog.Errorf("syncLatest GetData error: %v", err)
+			rd.countError("download")
 			<-timer.C
-			timer.Reset(rd.periodSeconds)
+			timer.Reset(period)
 			continue
 		}
 
@@ -159,7 +242,7 @@ func (rd *RatesDownloader) syncLatest() error {
 			// If rates are the same as previous, do not store them
 			glog.Infof("syncLatest: ticker rates for %v are the same as previous, skipping...", ticker.Timestamp)
 			<-timer.C
-			timer.Reset(rd.periodSeconds)
+			timer.Reset(period)
 			sameTickerCounter++
 			continue
 		}
@@ -167,15 +250,16 @@ func (rd *RatesDownloader) syncLatest() error {
 		sameTickerCounter = 0
 
 		glog.Infof("syncLatest: storing ticker for %v", ticker.Timestamp)
-		err = rd.db.FiatRatesStoreTicker(ticker)
+		err = rd.storeTicker(ticker)
 		if err != nil {
 			// If there's an error storing ticker (like missing rates), log it, wait and try again
 			glog.Errorf("syncLatest StoreTicker error: %v", err)
+			rd.countError("store")
 		} else if rd.callbackOnNewTicker != nil {
 			rd.callbackOnNewTicker(ticker)
 		}
 		<-timer.C
-		timer.Reset(rd.periodSeconds)
+		timer.Reset(period)
 	}
 }
 
@@ -189,20 +273,23 @@ func (rd *RatesDownloader) syncHistorical(timestamp *time.Time) error {
 			break
 		}
 
-		ticker, err := rd.downloader.getTicker(timestamp)
+		downloader, retryPeriod := rd.config()
+		ticker, err := downloader.getTicker(timestamp)
 		if err != nil {
 			// Do not exit on GET error, log it, wait and try again
 			glog.Errorf("syncHistorical GetData error: %v", err)
+			rd.countError("download")
 			<-timer.C
-			timer.Reset(rd.periodSeconds)
+			timer.Reset(retryPeriod)
 			continue
 		}
 
 		glog.Infof("syncHistorical: storing ticker for %v", ticker.Timestamp)
-		err = rd.db.FiatRatesStoreTicker(ticker)
+		err = rd.storeTicker(ticker)
 		if err != nil {
 			// If there's an error storing ticker (like missing rates), log it and continue to the next day
 			glog.Errorf("syncHistorical error storing ticker for %v: %v", timestamp, err)
+			rd.countError("store")
 		}
 
 		*timestamp = timestamp.Add(time.Hour * 24) // go to the next day
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -174,3 +175,270 @@ func TestFiatRates(t *testing.T) {
 		}
 	}
 }
+
+func writeRatesFile(t *testing.T, dir, name, content string) string {
+	path := dir + "/" + name
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReconfigure(t *testing.T) {
+	rd, err := NewFiatRatesDownloader(nil, "file", `{"periodSeconds": 60, "path": "/tmp/rates1.json"}`, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = rd.NewRatesConfig("coingecko", `{"periodSeconds": 60}`); err == nil {
+		t.Fatal("NewRatesConfig accepted params without url")
+	}
+	downloader, period := rd.config()
+	if d, ok := downloader.(*FileSource); !ok || d.path != "/tmp/rates1.json" || period != time.Minute {
+		t.Fatalf("invalid params applied: %+v, %v", downloader, period)
+	}
+	c, err := rd.NewRatesConfig("file", `{"periodSeconds": 30, "path": "/tmp/rates2.json"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if downloader, _ = rd.config(); downloader.(*FileSource).path != "/tmp/rates1.json" {
+		t.Fatal("NewRatesConfig applied the config")
+	}
+	rd.Reconfigure(c)
+	downloader, period = rd.config()
+	if d, ok := downloader.(*FileSource); !ok || d.path != "/tmp/rates2.json" || period != 30*time.Second {
+		t.Errorf("config() = %+v, %v, want file downloader of /tmp/rates2.json and 30s", downloader, period)
+	}
+}
+
+func TestRatesProviders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fiatrates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/latest" {
+			fmt.Fprintln(w, `{"data":{"rates":{"USD":"7100.5","EUR":6400}}}`)
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+	file1 := writeRatesFile(t, dir, "1.json", `{"20191121":{"usd":7000,"eur":6300,"czk":160000},"20191122":{"usd":7200,"eur":6500}}`)
+	file2 := writeRatesFile(t, dir, "2.json", `{"20191122":{"usd":7300}}`)
+	providers := []ProviderParams{
+		{Type: "file", Name: "f1", Path: file1},
+		{Type: "file", Name: "f2", Path: file2},
+		{Type: "json", URL: mockServer.URL + "/latest", HistoryURL: mockServer.URL + "/history/{date}", RatesPath: "data.rates"},
+	}
+
+	rp, err := NewRatesProviders(providers, AggregationMedian, "02-01-2006")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticker, err := rp.getTicker(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRates := map[string]float64{"usd": 7200, "eur": 6450}
+	wantProvenance := map[string][]string{"usd": {"f1", "f2", "json"}, "eur": {"f1", "json"}}
+	if !reflect.DeepEqual(ticker.Rates, wantRates) || !reflect.DeepEqual(ticker.Provenance, wantProvenance) {
+		t.Errorf("getTicker(nil) = %v %v, want %v %v", ticker.Rates, ticker.Provenance, wantRates, wantProvenance)
+	}
+
+	// the json provider does not have historical data, f2 has no data for the date
+	ts := time.Date(2019, 11, 21, 12, 0, 0, 0, time.UTC)
+	ticker, err = rp.getTicker(&ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRates = map[string]float64{"usd": 7000, "eur": 6300, "czk": 160000}
+	if !reflect.DeepEqual(ticker.Rates, wantRates) || !ticker.Timestamp.Equal(ts) {
+		t.Errorf("getTicker(%v) = %v %v, want %v", ts, ticker.Timestamp, ticker.Rates, wantRates)
+	}
+
+	// fallback uses the first provider in the order, which has the data
+	rp, err = NewRatesProviders([]ProviderParams{providers[1], providers[0]}, AggregationFallback, "02-01-2006")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticker, err = rp.getTicker(&ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantProvenance = map[string][]string{"usd": {"f1"}, "eur": {"f1"}, "czk": {"f1"}}
+	if !reflect.DeepEqual(ticker.Rates, wantRates) || !reflect.DeepEqual(ticker.Provenance, wantProvenance) {
+		t.Errorf("getTicker(%v) = %v %v, want %v %v", ts, ticker.Rates, ticker.Provenance, wantRates, wantProvenance)
+	}
+	ts = time.Date(2019, 11, 20, 0, 0, 0, 0, time.UTC)
+	if exists, err := rp.marketDataExists(&ts); err != nil || exists {
+		t.Errorf("marketDataExists(%v) = %v %v, want false", ts, exists, err)
+	}
+
+	if _, err = NewRatesProviders([]ProviderParams{providers[0], providers[0]}, AggregationMedian, "02-01-2006"); err == nil {
+		t.Error("NewRatesProviders expected error for duplicate provider names")
+	}
+}
+
+func TestDerivedRates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fiatrates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	coin := writeRatesFile(t, dir, "coin.json", `{"20191122":{"btc":0.0001,"usd":0.8}}`)
+	btc := writeRatesFile(t, dir, "btc.json", `{"20191122":{"btc":1,"usd":7000,"eur":6300,"czk":160000}}`)
+	fx := writeRatesFile(t, dir, "fx.json", `{"20191122":{"eur":0.9,"chf":0.99,"jpy":108}}`)
+	dr, err := NewDerivedRates(NewFileDownloader(coin), &DerivedParams{
+		Intermediate:         "BTC",
+		IntermediateProvider: &ProviderParams{Type: "file", Path: btc},
+		FXBase:               "usd",
+		FXProvider:           &ProviderParams{Type: "file", Path: fx},
+	}, "02-01-2006")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2019, 11, 22, 10, 0, 0, 0, time.UTC)
+	ticker, err := dr.getTicker(&ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRates := map[string]float64{"btc": 0.0001, "usd": 0.8, "eur": 0.63, "czk": 16, "chf": 0.792, "jpy": 86.4}
+	if len(ticker.Rates) != len(wantRates) {
+		t.Errorf("getTicker() rates = %v, want %v", ticker.Rates, wantRates)
+	}
+	for c, want := range wantRates {
+		if got := ticker.Rates[c]; got < want*0.999999 || got > want*1.000001 {
+			t.Errorf("getTicker() rate %v = %v, want %v", c, got, want)
+		}
+	}
+	wantDerived := map[string]string{"eur": DerivedViaIntermediate, "czk": DerivedViaIntermediate, "chf": DerivedViaFX, "jpy": DerivedViaFX}
+	if !reflect.DeepEqual(ticker.Derived, wantDerived) {
+		t.Errorf("getTicker() derived = %v, want %v", ticker.Derived, wantDerived)
+	}
+
+	// only the configured currencies are derived
+	dr.currencies = map[string]struct{}{"eur": {}}
+	if ticker, err = dr.getTicker(&ts); err != nil {
+		t.Fatal(err)
+	}
+	wantDerived = map[string]string{"eur": DerivedViaIntermediate}
+	if !reflect.DeepEqual(ticker.Derived, wantDerived) || len(ticker.Rates) != 3 {
+		t.Errorf("getTicker() = %v %v, want derived %v", ticker.Rates, ticker.Derived, wantDerived)
+	}
+}
+
+func TestDerivedRates_IntermediateCoin(t *testing.T) {
+	requests := make(map[string]int)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/simple/supported_vs_currencies":
+			fmt.Fprintln(w, `["btc","usd","eur","czk"]`)
+		case "/simple/price":
+			if ids := r.URL.Query().Get("ids"); ids != "scrypta,bitcoin" {
+				t.Errorf("simple price ids %v", ids)
+			}
+			if vs := r.URL.Query().Get("vs_currencies"); vs != "btc,usd,eur,czk" {
+				t.Errorf("simple price vs_currencies %v", vs)
+			}
+			fmt.Fprintln(w, `{"scrypta":{"btc":0.0001,"usd":0.8},"bitcoin":{"btc":1,"usd":7000,"eur":6300,"czk":160000}}`)
+		case "/coins/scrypta/history":
+			fmt.Fprintln(w, `{"market_data":{"current_price":{"btc":0.0002,"usd":1.5}}}`)
+		case "/coins/bitcoin/history":
+			fmt.Fprintln(w, `{"market_data":{"current_price":{"btc":1,"usd":7500,"eur":6800}}}`)
+		default:
+			t.Errorf("Unknown URL path: %v", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+	downloader, _, err := newDownloader("coingecko", `{"url": "`+mockServer.URL+`", "coin": "scrypta", "periodSeconds": 60, "derived": {"intermediate": "btc", "intermediateCoin": "bitcoin"}}`, "02-01-2006")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the current rates of both coins are downloaded in one request, the supported currencies only once
+	for i := 0; i < 2; i++ {
+		ticker, err := downloader.getTicker(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantRates := map[string]float64{"btc": 0.0001, "usd": 0.8, "eur": 0.63, "czk": 16}
+		for c, want := range wantRates {
+			if got := ticker.Rates[c]; got < want*0.999999 || got > want*1.000001 {
+				t.Errorf("getTicker() rate %v = %v, want %v", c, got, want)
+			}
+		}
+		if want := map[string]string{"eur": DerivedViaIntermediate, "czk": DerivedViaIntermediate}; !reflect.DeepEqual(ticker.Derived, want) {
+			t.Errorf("getTicker() derived = %v, want %v", ticker.Derived, want)
+		}
+	}
+	if want := map[string]int{"/simple/supported_vs_currencies": 1, "/simple/price": 2}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests %v, want %v", requests, want)
+	}
+	// the historical rates are downloaded for each coin
+	ts := time.Date(2019, 11, 22, 0, 0, 0, 0, time.UTC)
+	ticker, err := downloader.getTicker(&ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ticker.Rates["eur"], 0.0002*6800; got < want*0.999999 || got > want*1.000001 || ticker.Rates["usd"] != 1.5 {
+		t.Errorf("getTicker(%v) rates = %v, want eur %v", ts, ticker.Rates, want)
+	}
+
+	if _, _, err = newDownloader("json", `{"url": "`+mockServer.URL+`", "periodSeconds": 60, "derived": {"intermediate": "btc", "intermediateCoin": "bitcoin"}}`, "02-01-2006"); err == nil {
+		t.Error("intermediateCoin accepted for a provider which cannot download other coins")
+	}
+}
+
+func TestFiatRatesBackfill(t *testing.T) {
+	d, _, tmp := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d, tmp)
+	dir, err := ioutil.TempDir("", "fiatrates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := writeRatesFile(t, dir, "rates.json", `{"20191120":{"usd":7100,"eur":6400},"20191122":{"usd":7200}}`)
+	rd, err := NewFiatRatesDownloader(d, "file", `{"periodSeconds": 60, "path": "`+path+`"}`, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storedTime := time.Date(2019, 11, 21, 0, 0, 0, 0, time.UTC)
+	stored := &db.CurrencyRatesTicker{Timestamp: &storedTime, Rates: map[string]float64{"usd": 7000}}
+	if err = d.FiatRatesStoreTicker(stored); err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2019, 11, 20, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2019, 11, 23, 0, 0, 0, 0, time.UTC)
+
+	coverage, err := ComputeFiatRatesCoverage(d, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMissing := []string{"2019-11-20", "2019-11-22", "2019-11-23"}
+	if coverage.Days != 4 || coverage.CoveredDays != 1 || !reflect.DeepEqual(coverage.MissingDays, wantMissing) {
+		t.Errorf("ComputeFiatRatesCoverage() = %+v, want missing %v", coverage, wantMissing)
+	}
+
+	// there are no rates for 2019-11-23 in the file, the day fails after the retries
+	status, err := rd.Backfill(from, to, time.Millisecond, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Running || status.Missing != 3 || status.Processed != 3 || status.Downloaded != 2 || !reflect.DeepEqual(status.FailedDays, []string{"2019-11-23"}) {
+		t.Errorf("Backfill() = %+v", status)
+	}
+	wantCurrencies := map[string]CurrencyCoverage{
+		"usd": {Days: 3, Coverage: 0.75, First: "2019-11-20", Last: "2019-11-22"},
+		"eur": {Days: 1, Coverage: 0.25, First: "2019-11-20", Last: "2019-11-20"},
+	}
+	if status.Coverage == nil || status.Coverage.CoveredDays != 3 || !reflect.DeepEqual(status.Coverage.Currencies, wantCurrencies) {
+		t.Errorf("Backfill() coverage = %+v, want %v", status.Coverage, wantCurrencies)
+	}
+	ticker, err := d.FiatRatesFindTicker(&from)
+	if err != nil || ticker == nil || ticker.Rates["eur"] != 6400 {
+		t.Errorf("FiatRatesFindTicker(%v) = %+v %v", from, ticker, err)
+	}
+}
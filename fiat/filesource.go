@@ -0,0 +1,83 @@
+package fiat
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/scryptachain/blockbook-scrypta/db"
+)
+
+// FileSource is a structure that implements RatesDownloaderInterface using rates stored in a local file,
+// it is intended for tests and for importing of rates from other sources
+// The file contains JSON object with timestamps (in the formats accepted by db.FiatRatesConvertDate) as keys and rates as values
+type FileSource struct {
+	path string
+}
+
+type fileSourceTicker struct {
+	timestamp time.Time
+	rates     map[string]float64
+}
+
+// NewFileDownloader creates a FileSource structure that implements the RatesDownloaderInterface
+func NewFileDownloader(path string) RatesDownloaderInterface {
+	return &FileSource{path: path}
+}
+
+// load reads the file on every call so that the file can be changed without restart, the tickers are sorted by time
+func (fs *FileSource) load() ([]fileSourceTicker, error) {
+	b, err := ioutil.ReadFile(fs.path)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]map[string]float64
+	if err = json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	tickers := make([]fileSourceTicker, 0, len(data))
+	for k, rates := range data {
+		t, err := db.FiatRatesConvertDate(k)
+		if err != nil {
+			return nil, err
+		}
+		tickers = append(tickers, fileSourceTicker{timestamp: t.UTC(), rates: lowerCaseRates(rates)})
+	}
+	sort.Slice(tickers, func(i, j int) bool {
+		return tickers[i].timestamp.Before(tickers[j].timestamp)
+	})
+	return tickers, nil
+}
+
+// getTicker returns the last ticker in the file not older than one day before the timestamp
+// If timestamp is nil, the last ticker in the file is returned
+func (fs *FileSource) getTicker(timestamp *time.Time) (*db.CurrencyRatesTicker, error) {
+	tickers, err := fs.load()
+	if err != nil {
+		return nil, err
+	}
+	if len(tickers) == 0 {
+		return nil, errors.New("No rates in file " + fs.path)
+	}
+	if timestamp == nil {
+		return &db.CurrencyRatesTicker{Timestamp: tickerTimestamp(nil), Rates: tickers[len(tickers)-1].rates}, nil
+	}
+	i := sort.Search(len(tickers), func(i int) bool {
+		return tickers[i].timestamp.After(*timestamp)
+	})
+	if i == 0 || timestamp.Sub(tickers[i-1].timestamp) >= 24*time.Hour {
+		return nil, errors.New("No rates for " + timestamp.String())
+	}
+	return &db.CurrencyRatesTicker{Timestamp: tickerTimestamp(timestamp), Rates: tickers[i-1].rates}, nil
+}
+
+// marketDataExists checks if there is a ticker at or before the timestamp
+func (fs *FileSource) marketDataExists(timestamp *time.Time) (bool, error) {
+	tickers, err := fs.load()
+	if err != nil {
+		return false, err
+	}
+	return len(tickers) > 0 && !tickers[0].timestamp.After(*timestamp), nil
+}
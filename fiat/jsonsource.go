@@ -0,0 +1,103 @@
+package fiat
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scryptachain/blockbook-scrypta/db"
+)
+
+// JSONSource is a structure that implements RatesDownloaderInterface for a generic JSON API
+// The rates are read from an object of currency-rate pairs found in the response at the ratesPath
+type JSONSource struct {
+	url        string
+	historyURL string
+	timeFormat string
+	ratesPath  []string
+}
+
+// NewJSONDownloader creates a JSONSource structure that implements the RatesDownloaderInterface
+// The historyURL can contain placeholders {date} (formatted using timeFormat) and {timestamp} (unix time),
+// ratesPath is a dot separated path to the rates in the response, empty path means the whole response
+func NewJSONDownloader(url string, historyURL string, timeFormat string, ratesPath string) RatesDownloaderInterface {
+	if timeFormat == "" {
+		timeFormat = "2006-01-02"
+	}
+	var path []string
+	if ratesPath != "" {
+		path = strings.Split(ratesPath, ".")
+	}
+	return &JSONSource{
+		url:        url,
+		historyURL: historyURL,
+		timeFormat: timeFormat,
+		ratesPath:  path,
+	}
+}
+
+// parseRates finds the rates in the response, the rates can be numbers or strings containing numbers
+func (js *JSONSource) parseRates(data json.RawMessage) (map[string]float64, error) {
+	for _, p := range js.ratesPath {
+		var o map[string]json.RawMessage
+		if err := json.Unmarshal(data, &o); err != nil {
+			return nil, err
+		}
+		var found bool
+		if data, found = o[p]; !found {
+			return nil, errors.New("Rates not found in the response at " + p)
+		}
+	}
+	var r map[string]interface{}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	rates := make(map[string]float64, len(r))
+	for c, v := range r {
+		switch v := v.(type) {
+		case float64:
+			rates[c] = v
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				rates[c] = f
+			}
+		}
+	}
+	return lowerCaseRates(rates), nil
+}
+
+// getTicker gets fiat rates from API at the specified date and returns a CurrencyRatesTicker
+// If timestamp is nil, it will download the current fiat rates.
+func (js *JSONSource) getTicker(timestamp *time.Time) (*db.CurrencyRatesTicker, error) {
+	requestURL := js.url
+	if timestamp != nil {
+		if js.historyURL == "" {
+			return nil, errors.New("Historical rates not supported")
+		}
+		requestURL = strings.Replace(js.historyURL, "{date}", timestamp.UTC().Format(js.timeFormat), -1)
+		requestURL = strings.Replace(requestURL, "{timestamp}", strconv.FormatInt(timestamp.Unix(), 10), -1)
+	}
+	var data json.RawMessage
+	if err := httpGetJSON(requestURL, nil, &data); err != nil {
+		return nil, err
+	}
+	rates, err := js.parseRates(data)
+	if err != nil {
+		return nil, err
+	}
+	return &db.CurrencyRatesTicker{Timestamp: tickerTimestamp(timestamp), Rates: rates}, nil
+}
+
+// marketDataExists checks if there's data available for the specific timestamp.
+func (js *JSONSource) marketDataExists(timestamp *time.Time) (bool, error) {
+	if js.historyURL == "" {
+		return false, nil
+	}
+	ticker, err := js.getTicker(timestamp)
+	if err != nil {
+		return false, err
+	}
+	return len(ticker.Rates) > 0, nil
+}
@@ -0,0 +1,41 @@
+package fiat
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/scryptachain/blockbook-scrypta/db"
+)
+
+// storeTicker stores the ticker and aggregates it to the OHLC candles, failure of the aggregation is only logged
+// as the candles are caught up from the stored tickers on the next start
+func (rd *RatesDownloader) storeTicker(ticker *db.CurrencyRatesTicker) error {
+	if err := rd.db.FiatRatesStoreTicker(ticker); err != nil {
+		return err
+	}
+	if err := rd.db.FiatRatesUpdateOHLC(ticker); err != nil {
+		glog.Errorf("Error updating OHLC candles for %v: %v", ticker.Timestamp, err)
+		rd.countError("ohlc")
+	}
+	return nil
+}
+
+// UpdateOHLC aggregates to the OHLC candles all tickers stored since the last aggregated ticker
+// Aggregation of a ticker is idempotent, therefore the last aggregated ticker is processed again
+func UpdateOHLC(d *db.RocksDB) (int, error) {
+	from, err := d.FiatRatesOHLCLastTime()
+	if err != nil {
+		return 0, err
+	}
+	if from == nil {
+		t := time.Unix(0, 0).UTC()
+		from = &t
+	}
+	to := time.Now().UTC().Add(24 * time.Hour)
+	count := 0
+	err = d.FiatRatesGetTickers(from, &to, func(ticker *db.CurrencyRatesTicker) error {
+		count++
+		return d.FiatRatesUpdateOHLC(ticker)
+	})
+	return count, err
+}
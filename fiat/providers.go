@@ -0,0 +1,268 @@
+package fiat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/scryptachain/blockbook-scrypta/db"
+)
+
+// aggregation modes of multiple rates providers
+const (
+	// AggregationFallback uses the rates of the first provider in the configured order which returns them
+	AggregationFallback = "fallback"
+	// AggregationMedian queries all providers and uses median of the returned rates for each currency
+	AggregationMedian = "median"
+)
+
+const providerHTTPTimeout = 15 * time.Second
+
+// ProviderParams contains configuration of a single rates provider
+type ProviderParams struct {
+	Type       string   `json:"type"`
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	Coin       string   `json:"coin"`
+	APIKey     string   `json:"apiKey"`
+	Currencies []string `json:"currencies"`
+	HistoryURL string   `json:"historyUrl"`
+	TimeFormat string   `json:"timeFormat"`
+	RatesPath  string   `json:"ratesPath"`
+	Path       string   `json:"path"`
+}
+
+type ratesProvider struct {
+	name       string
+	downloader RatesDownloaderInterface
+}
+
+// RatesProviders implements RatesDownloaderInterface over multiple providers
+// with fallback in the configured order or with median aggregation of the rates
+type RatesProviders struct {
+	providers   []ratesProvider
+	aggregation string
+}
+
+// newRatesProvider creates downloader of the given type
+func newRatesProvider(p *ProviderParams, timeFormat string) (RatesDownloaderInterface, error) {
+	if p.Type == "file" {
+		if p.Path == "" {
+			return nil, errors.New("Missing parameters")
+		}
+		return NewFileDownloader(p.Path), nil
+	}
+	if p.URL == "" {
+		return nil, errors.New("Missing parameters")
+	}
+	switch p.Type {
+	case "coingecko":
+		return NewCoinGeckoDownloader(p.URL, p.Coin, timeFormat), nil
+	case "coinpaprika":
+		return NewCoinPaprikaDownloader(p.URL, p.Coin, p.Currencies), nil
+	case "cryptocompare":
+		return NewCryptoCompareDownloader(p.URL, p.Coin, p.Currencies, p.APIKey), nil
+	case "json":
+		return NewJSONDownloader(p.URL, p.HistoryURL, p.TimeFormat, p.RatesPath), nil
+	}
+	return nil, fmt.Errorf("incorrect API type %q", p.Type)
+}
+
+// NewRatesProviders creates RatesProviders from the configuration of the providers
+func NewRatesProviders(params []ProviderParams, aggregation string, timeFormat string) (*RatesProviders, error) {
+	if len(params) == 0 {
+		return nil, errors.New("Missing providers")
+	}
+	if aggregation == "" {
+		aggregation = AggregationFallback
+	} else if aggregation != AggregationFallback && aggregation != AggregationMedian {
+		return nil, fmt.Errorf("incorrect aggregation %q", aggregation)
+	}
+	rp := &RatesProviders{aggregation: aggregation}
+	names := make(map[string]struct{})
+	for i := range params {
+		d, err := newRatesProvider(&params[i], timeFormat)
+		if err != nil {
+			return nil, err
+		}
+		name := params[i].Name
+		if name == "" {
+			name = params[i].Type
+		}
+		if _, found := names[name]; found {
+			return nil, fmt.Errorf("duplicate provider name %q", name)
+		}
+		names[name] = struct{}{}
+		rp.providers = append(rp.providers, ratesProvider{name: name, downloader: d})
+	}
+	return rp, nil
+}
+
+func median(v []float64) float64 {
+	sort.Float64s(v)
+	l := len(v)
+	if l%2 == 1 {
+		return v[l/2]
+	}
+	return (v[l/2-1] + v[l/2]) / 2
+}
+
+// aggregateTickers computes median of the rates of the tickers for each currency and records providers of the rates
+func aggregateTickers(names []string, tickers []*db.CurrencyRatesTicker, timestamp *time.Time) *db.CurrencyRatesTicker {
+	values := make(map[string][]float64)
+	provenance := make(map[string][]string)
+	for i, t := range tickers {
+		if t == nil {
+			continue
+		}
+		for currency, rate := range t.Rates {
+			if rate <= 0 {
+				continue
+			}
+			values[currency] = append(values[currency], rate)
+			provenance[currency] = append(provenance[currency], names[i])
+		}
+	}
+	ticker := &db.CurrencyRatesTicker{
+		Timestamp:  timestamp,
+		Rates:      make(map[string]float64, len(values)),
+		Provenance: provenance,
+	}
+	for currency, v := range values {
+		ticker.Rates[currency] = median(v)
+	}
+	return ticker
+}
+
+func tickerTimestamp(timestamp *time.Time) *time.Time {
+	var t time.Time
+	if timestamp == nil {
+		t = time.Now().UTC()
+	} else {
+		t = timestamp.UTC()
+	}
+	return &t
+}
+
+func (rp *RatesProviders) getTickerFallback(timestamp *time.Time) (*db.CurrencyRatesTicker, error) {
+	var err error
+	for _, p := range rp.providers {
+		var ticker *db.CurrencyRatesTicker
+		ticker, err = p.downloader.getTicker(timestamp)
+		if err == nil && len(ticker.Rates) > 0 {
+			return aggregateTickers([]string{p.name}, []*db.CurrencyRatesTicker{ticker}, tickerTimestamp(timestamp)), nil
+		}
+		if err == nil {
+			err = errors.New("empty rates")
+		}
+		glog.Warningf("Fiat rates provider %v error: %v", p.name, err)
+	}
+	return nil, err
+}
+
+func (rp *RatesProviders) getTickerMedian(timestamp *time.Time) (*db.CurrencyRatesTicker, error) {
+	tickers := make([]*db.CurrencyRatesTicker, len(rp.providers))
+	errs := make([]error, len(rp.providers))
+	names := make([]string, len(rp.providers))
+	var wg sync.WaitGroup
+	for i := range rp.providers {
+		names[i] = rp.providers[i].name
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tickers[i], errs[i] = rp.providers[i].downloader.getTicker(timestamp)
+		}(i)
+	}
+	wg.Wait()
+	var lastErr error
+	for i, err := range errs {
+		if err != nil {
+			glog.Warningf("Fiat rates provider %v error: %v", names[i], err)
+			tickers[i] = nil
+			lastErr = err
+		}
+	}
+	ticker := aggregateTickers(names, tickers, tickerTimestamp(timestamp))
+	if len(ticker.Rates) == 0 {
+		if lastErr == nil {
+			lastErr = errors.New("empty rates")
+		}
+		return nil, lastErr
+	}
+	return ticker, nil
+}
+
+// getTicker returns the aggregated ticker, error is returned only if no provider returned rates
+func (rp *RatesProviders) getTicker(timestamp *time.Time) (*db.CurrencyRatesTicker, error) {
+	if rp.aggregation == AggregationMedian {
+		return rp.getTickerMedian(timestamp)
+	}
+	return rp.getTickerFallback(timestamp)
+}
+
+// marketDataExists returns true if any of the providers has market data for the timestamp
+func (rp *RatesProviders) marketDataExists(timestamp *time.Time) (bool, error) {
+	var lastErr error
+	failed := 0
+	for _, p := range rp.providers {
+		exists, err := p.downloader.marketDataExists(timestamp)
+		if err != nil {
+			glog.Warningf("Fiat rates provider %v error: %v", p.name, err)
+			lastErr = err
+			failed++
+			continue
+		}
+		if exists {
+			return true, nil
+		}
+	}
+	if failed == len(rp.providers) {
+		return false, lastErr
+	}
+	return false, nil
+}
+
+// httpGetJSON downloads the url and unmarshals the JSON response to v
+func httpGetJSON(requestURL string, headers map[string]string, v interface{}) error {
+	req, err := http.NewRequest("GET", requestURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Close = true
+	req.Header.Set("Content-Type", "application/json")
+	for k, h := range headers {
+		req.Header.Set(k, h)
+	}
+	client := &http.Client{
+		Timeout: providerHTTPTimeout,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("Invalid response status: " + string(resp.Status))
+	}
+	bodyBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bodyBytes, v)
+}
+
+// lowerCaseRates converts currency codes to lower case, as they are stored by Blockbook
+func lowerCaseRates(rates map[string]float64) map[string]float64 {
+	r := make(map[string]float64, len(rates))
+	for c, v := range rates {
+		r[strings.ToLower(c)] = v
+	}
+	return r
+}
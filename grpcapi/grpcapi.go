@@ -0,0 +1,31 @@
+// Package grpcapi defines the gRPC interface of Blockbook, which mirrors the v2 REST and websocket API.
+//
+// The service and the messages are defined in blockbook.proto, the Go code is generated by protoc with
+// the plugins protoc-gen-go and protoc-gen-go-grpc:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative grpcapi/blockbook.proto
+//
+// The server requires TLS, clients connect by grpc.Dial with transport credentials and use NewBlockbookClient.
+// The API key is sent in the metadata x-api-key.
+package grpcapi
+
+// ServiceName is the full name of the gRPC service
+const ServiceName = "blockbook.Blockbook"
+
+// Methods of the service
+const (
+	MethodGetAddress          = "GetAddress"
+	MethodGetXpub             = "GetXpub"
+	MethodGetUtxo             = "GetUtxo"
+	MethodGetTransaction      = "GetTransaction"
+	MethodGetBlock            = "GetBlock"
+	MethodEstimateFee         = "EstimateFee"
+	MethodSendTransaction     = "SendTransaction"
+	MethodGetCurrentFiatRates = "GetCurrentFiatRates"
+	MethodGetFiatRatesForTime = "GetFiatRatesForTimestamps"
+	MethodSubscribeNewBlock   = "SubscribeNewBlock"
+	MethodSubscribeAddresses  = "SubscribeAddresses"
+)
+
+// APIKeyMetadata is the key of the metadata with the API key of the client
+const APIKeyMetadata = "x-api-key"
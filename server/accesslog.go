@@ -0,0 +1,209 @@
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/scryptachain/blockbook-scrypta/common"
+)
+
+const (
+	requestIDHeader = "X-Request-Id"
+	// maximum length of the request id passed by the client
+	maxRequestIDLength = 64
+	// the params in the access log are truncated to this length
+	maxLoggedParamsLength = 256
+)
+
+// requestLogs are the access log of the requests and the audit log of the sent transactions and admin actions
+// The methods can be called on nil requestLogs, which means that the logging is disabled
+type requestLogs struct {
+	access *common.JSONLog
+	audit  *common.JSONLog
+}
+
+// accessLogEntry is a line of the access log
+type accessLogEntry struct {
+	Time       string  `json:"time"`
+	RequestID  string  `json:"requestId"`
+	TraceID    string  `json:"traceId,omitempty"`
+	Interface  string  `json:"interface"`
+	Connection uint64  `json:"connection,omitempty"`
+	ClientIP   string  `json:"clientIp"`
+	HTTPMethod string  `json:"httpMethod,omitempty"`
+	Method     string  `json:"method"`
+	Params     string  `json:"params,omitempty"`
+	Status     int     `json:"status,omitempty"`
+	DurationMs float64 `json:"durationMs"`
+	Size       int     `json:"size"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// auditLogEntry is a line of the audit log
+type auditLogEntry struct {
+	Time      string `json:"time"`
+	RequestID string `json:"requestId,omitempty"`
+	Interface string `json:"interface"`
+	ClientIP  string `json:"clientIp,omitempty"`
+	Action    string `json:"action"`
+	Params    string `json:"params,omitempty"`
+	Txid      string `json:"txid,omitempty"`
+	TxSize    int    `json:"txSize,omitempty"`
+	Status    string `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func newRequestLogs(access, audit *common.JSONLog) *requestLogs {
+	if access == nil && audit == nil {
+		return nil
+	}
+	return &requestLogs{access: access, audit: audit}
+}
+
+func (l *requestLogs) accessEnabled() bool {
+	return l != nil && l.access != nil
+}
+
+func (l *requestLogs) logAccess(e *accessLogEntry, start time.Time) {
+	if !l.accessEnabled() {
+		return
+	}
+	e.Time = start.UTC().Format(time.RFC3339Nano)
+	e.DurationMs = float64(time.Since(start)) / float64(time.Millisecond)
+	e.Params = truncateParams(e.Params)
+	l.access.Log(e)
+}
+
+func (l *requestLogs) logAudit(e *auditLogEntry) {
+	if l == nil || l.audit == nil {
+		return
+	}
+	e.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	l.audit.Log(e)
+}
+
+// logSendTx records the broadcast of the transaction given as hex, successful or not
+func (l *requestLogs) logSendTx(iface, requestID, clientIP, txHex, txid string, err error) {
+	e := auditLogEntry{
+		RequestID: requestID,
+		Interface: iface,
+		ClientIP:  clientIP,
+		Action:    "sendtx",
+		Txid:      txid,
+		TxSize:    len(txHex) / 2,
+		Status:    "success",
+	}
+	if err != nil {
+		e.Status = "failure"
+		e.Error = err.Error()
+	}
+	l.logAudit(&e)
+}
+
+func truncateParams(p string) string {
+	if len(p) > maxLoggedParamsLength {
+		return p[:maxLoggedParamsLength] + "..."
+	}
+	return p
+}
+
+// redactedQuery returns the path and query of the request with the values of the secret parameters shortened
+func redactedQuery(u *url.URL) string {
+	if q := redactedParams(u); q != "" {
+		return u.Path + "?" + q
+	}
+	return u.Path
+}
+
+// redactedParams returns the query of the request with the values of the secret parameters shortened
+func redactedParams(u *url.URL) string {
+	q := u.Query()
+	for _, p := range []string{apiKeyParam, "key"} {
+		if v := q.Get(p); v != "" {
+			if len(v) > 8 {
+				v = v[:6] + "..."
+			} else {
+				v = "..."
+			}
+			q.Set(p, v)
+		}
+	}
+	return q.Encode()
+}
+
+var (
+	requestIDPrefix  string
+	requestIDCounter uint64
+)
+
+func init() {
+	b := make([]byte, 4)
+	rand.New(rand.NewSource(time.Now().UnixNano())).Read(b)
+	requestIDPrefix = hex.EncodeToString(b) + "-"
+}
+
+// newRequestID returns a unique id of the request within the run of the process
+func newRequestID() string {
+	return requestIDPrefix + strconv.FormatUint(atomic.AddUint64(&requestIDCounter, 1), 10)
+}
+
+// httpRequestID returns the request id passed by the client in the header X-Request-Id or a new request id
+func httpRequestID(r *http.Request) string {
+	id := r.Header.Get(requestIDHeader)
+	if id == "" || len(id) > maxRequestIDLength {
+		return newRequestID()
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' || id[i] == '"' {
+			return newRequestID()
+		}
+	}
+	return id
+}
+
+type requestIDKey struct{}
+
+// requestIDFromContext returns the request id stored in the context by the jsonHandler, empty string if there is none
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// remoteIP returns the ip part of the remote address of the request
+func remoteIP(remoteAddr string) string {
+	ip, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return ip
+}
+
+// logResponseWriter records the status and the size of the response for the access log and the metrics
+type logResponseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func (w *logResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *logResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
@@ -0,0 +1,269 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/scryptachain/blockbook-scrypta/db"
+)
+
+// AdminJobFunc runs an admin job with the parameters of the request, reports its progress
+// and should return soon after the stop channel is closed
+type AdminJobFunc func(params url.Values, progress db.ProgressFunc, stop chan os.Signal) error
+
+// AdminJobStatus is the status of an admin job
+type AdminJobStatus string
+
+// Statuses of the admin jobs
+const (
+	AdminJobRunning  AdminJobStatus = "running"
+	AdminJobFinished AdminJobStatus = "finished"
+	AdminJobFailed   AdminJobStatus = "failed"
+	AdminJobCanceled AdminJobStatus = "canceled"
+)
+
+// number of finished jobs kept for the status requests
+const maxFinishedAdminJobs = 50
+
+type adminJobKind struct {
+	run       AdminJobFunc
+	pauseSync bool
+}
+
+// AdminJob is an operational job run in background by the internal server
+type AdminJob struct {
+	ID       string            `json:"id"`
+	Kind     string            `json:"kind"`
+	Params   map[string]string `json:"params,omitempty"`
+	Status   AdminJobStatus    `json:"status"`
+	Done     int64             `json:"done"`
+	Total    int64             `json:"total,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Started  time.Time         `json:"started"`
+	Finished *time.Time        `json:"finished,omitempty"`
+	stop     chan os.Signal
+	canceled bool
+	done     chan struct{}
+}
+
+// cancel signals the job to stop, must be called with the lock held
+func (j *AdminJob) cancel() {
+	if !j.canceled {
+		j.canceled = true
+		close(j.stop)
+	}
+}
+
+// adminJobs runs the admin jobs, only one job runs at a time
+type adminJobs struct {
+	lock      sync.Mutex
+	token     string
+	pauseSync func() func()
+	kinds     map[string]adminJobKind
+	jobs      []*AdminJob
+	running   *AdminJob
+	lastID    int
+	logs      *requestLogs
+}
+
+// SetAdmin enables the admin endpoints, the requests must be authenticated by the token in the header Authorization: Bearer <token>
+// pauseSync stops the synchronization of the index and returns a function resuming it
+func (s *InternalServer) SetAdmin(token string, pauseSync func() func()) {
+	s.admin = &adminJobs{
+		token:     token,
+		pauseSync: pauseSync,
+		kinds:     make(map[string]adminJobKind),
+		logs:      s.logs,
+	}
+}
+
+// RegisterAdminJob registers a kind of admin job, if pauseSync is set, the synchronization of the index is paused while the job runs
+func (s *InternalServer) RegisterAdminJob(kind string, pauseSync bool, run AdminJobFunc) {
+	s.admin.kinds[kind] = adminJobKind{run: run, pauseSync: pauseSync}
+}
+
+func (a *adminJobs) authenticated(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(a.token)) == 1
+}
+
+// snapshot returns a copy of the job which can be serialized without the lock, must be called with the lock held
+func (j *AdminJob) snapshot() *AdminJob {
+	return &AdminJob{
+		ID:       j.ID,
+		Kind:     j.Kind,
+		Params:   j.Params,
+		Status:   j.Status,
+		Done:     j.Done,
+		Total:    j.Total,
+		Error:    j.Error,
+		Started:  j.Started,
+		Finished: j.Finished,
+	}
+}
+
+func (a *adminJobs) list() []*AdminJob {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	res := make([]*AdminJob, len(a.jobs))
+	for i, j := range a.jobs {
+		res[i] = j.snapshot()
+	}
+	return res
+}
+
+func (a *adminJobs) find(id string) *AdminJob {
+	for _, j := range a.jobs {
+		if j.ID == id {
+			return j
+		}
+	}
+	return nil
+}
+
+// start starts the job of the kind, returns nil and the http status if the job cannot be started
+func (a *adminJobs) start(kind string, params url.Values) (*AdminJob, int, string) {
+	k, found := a.kinds[kind]
+	if !found {
+		return nil, http.StatusBadRequest, "Unknown job kind " + kind
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.running != nil {
+		return nil, http.StatusConflict, "Job " + a.running.ID + " is running"
+	}
+	a.lastID++
+	j := &AdminJob{
+		ID:      strconv.Itoa(a.lastID),
+		Kind:    kind,
+		Params:  make(map[string]string),
+		Status:  AdminJobRunning,
+		Started: time.Now().UTC(),
+		stop:    make(chan os.Signal),
+		done:    make(chan struct{}),
+	}
+	for p := range params {
+		if p != "kind" {
+			j.Params[p] = params.Get(p)
+		}
+	}
+	a.running = j
+	a.jobs = append(a.jobs, j)
+	if len(a.jobs) > maxFinishedAdminJobs {
+		a.jobs = a.jobs[len(a.jobs)-maxFinishedAdminJobs:]
+	}
+	go a.run(j, k, params)
+	return j.snapshot(), http.StatusAccepted, ""
+}
+
+func (a *adminJobs) run(j *AdminJob, k adminJobKind, params url.Values) {
+	defer close(j.done)
+	glog.Info("admin job ", j.ID, " ", j.Kind, " started")
+	var resume func()
+	if k.pauseSync && a.pauseSync != nil {
+		resume = a.pauseSync()
+	}
+	err := k.run(params, func(done, total int64) {
+		a.lock.Lock()
+		j.Done, j.Total = done, total
+		a.lock.Unlock()
+	}, j.stop)
+	// resume the sync before the job is reported as finished
+	if resume != nil {
+		resume()
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	now := time.Now().UTC()
+	j.Finished = &now
+	select {
+	case <-j.stop:
+		j.Status = AdminJobCanceled
+	default:
+		if err != nil {
+			j.Status = AdminJobFailed
+			j.Error = err.Error()
+		} else {
+			j.Status = AdminJobFinished
+		}
+	}
+	a.running = nil
+	glog.Info("admin job ", j.ID, " ", j.Kind, " ", j.Status, " in ", now.Sub(j.Started), ", error ", err)
+	a.logs.logAudit(&auditLogEntry{
+		Interface: "internal",
+		Action:    "admin job " + j.Kind,
+		Params:    "id=" + j.ID,
+		Status:    string(j.Status),
+		Error:     j.Error,
+	})
+}
+
+// shutdown cancels the running job and waits until it stops
+func (a *adminJobs) shutdown(ctx context.Context) {
+	a.lock.Lock()
+	j := a.running
+	if j != nil {
+		j.cancel()
+	}
+	a.lock.Unlock()
+	if j != nil {
+		select {
+		case <-j.done:
+		case <-ctx.Done():
+			glog.Error("admin job ", j.ID, " ", j.Kind, " did not stop in time")
+		}
+	}
+}
+
+// apiAdminJobs lists the admin jobs, POST request starts the job given by parameter kind with the other parameters passed to the job
+// The requests for a single job (api/v2/admin/jobs/<id>) return its status, DELETE request cancels it
+func (s *InternalServer) apiAdminJobs(w http.ResponseWriter, r *http.Request) {
+	if s.admin == nil {
+		writeInternalJSON(w, http.StatusNotFound, resultInternalError{"Admin interface is not enabled"})
+		return
+	}
+	if !s.admin.authenticated(r) {
+		writeInternalJSON(w, http.StatusUnauthorized, resultInternalError{"Unauthorized"})
+		return
+	}
+	id := r.URL.Path[strings.LastIndexByte(r.URL.Path, '/')+1:]
+	if id == "jobs" {
+		if r.Method == http.MethodPost {
+			j, status, msg := s.admin.start(r.URL.Query().Get("kind"), r.URL.Query())
+			if j == nil {
+				writeInternalJSON(w, status, resultInternalError{msg})
+				return
+			}
+			writeInternalJSON(w, status, j)
+			return
+		}
+		writeInternalJSON(w, http.StatusOK, s.admin.list())
+		return
+	}
+	s.admin.lock.Lock()
+	j := s.admin.find(id)
+	var res *AdminJob
+	if j != nil {
+		if r.Method == http.MethodDelete && j.Status == AdminJobRunning {
+			j.cancel()
+		}
+		res = j.snapshot()
+	}
+	s.admin.lock.Unlock()
+	if res == nil {
+		writeInternalJSON(w, http.StatusNotFound, resultInternalError{"Job not found"})
+		return
+	}
+	writeInternalJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,326 @@
+package server
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scryptachain/blockbook-scrypta/api"
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+	"github.com/scryptachain/blockbook-scrypta/common"
+)
+
+// ResponseCacheConfig configures the cache of the public API responses
+type ResponseCacheConfig struct {
+	// Size is the maximum size of the cached responses in bytes, 0 disables the cache
+	Size int
+	// TTL is the lifetime of the results changed by new transactions, i.e. address summaries and unconfirmed transactions
+	TTL time.Duration
+	// Confirmations is the number of confirmations after which transactions and blocks are considered immutable
+	Confirmations int
+}
+
+// the immutable results are refreshed after this period, which limits the staleness of the spent status of outputs
+// of transactions, which were not seen in the mempool before they were confirmed
+const immutableCacheTTL = time.Hour
+
+// max-age of the immutable results for the clients, only the number of confirmations changes
+const immutableClientMaxAge = 60
+
+type cacheEntry struct {
+	key       string
+	data      interface{}
+	size      int
+	immutable bool
+	expires   time.Time
+	tags      []string
+	elem      *list.Element
+}
+
+// responseCache is LRU cache of the results of the public API
+// The entries are tagged by transactions and addresses which they contain and are invalidated
+// by new blocks (all non immutable entries, all entries on reorg) and by new mempool transactions
+// Each invalidation increases the generation, the results computed in an older generation are not stored
+// if there was a new block or their tags were invalidated in the meantime
+type responseCache struct {
+	lock         sync.Mutex
+	config       ResponseCacheConfig
+	metrics      *common.Metrics
+	entries      map[string]*cacheEntry
+	lru          *list.List
+	tags         map[string]map[*cacheEntry]struct{}
+	size         int
+	lastHeight   uint32
+	pendingSpent map[string]struct{}
+	gen          uint64
+	blockGen     uint64
+	// invalidated contains the generations of the invalidations of the tags since the last block
+	invalidated map[string]uint64
+}
+
+func newResponseCache(config ResponseCacheConfig, metrics *common.Metrics) *responseCache {
+	return &responseCache{
+		config:       config,
+		metrics:      metrics,
+		entries:      make(map[string]*cacheEntry),
+		lru:          list.New(),
+		tags:         make(map[string]map[*cacheEntry]struct{}),
+		pendingSpent: make(map[string]struct{}),
+		invalidated:  make(map[string]uint64),
+	}
+}
+
+func txTag(txid string) string {
+	return "t" + txid
+}
+
+func addrTag(addrDesc bchain.AddressDescriptor) string {
+	return "a" + string(addrDesc)
+}
+
+// getCacheKey returns the key of the request or empty string if the request cannot be cached
+func getCacheKey(r *http.Request) string {
+	if r.Method != http.MethodGet {
+		return ""
+	}
+	q := r.URL.Query()
+	// fiat values depend on the current rate, spending txids change with new transactions
+	if q.Get("currency") != "" || q.Get("spending") != "" {
+		return ""
+	}
+	return r.URL.RequestURI()
+}
+
+// get returns the cached data with the number of confirmations updated to the best height
+func (c *responseCache) get(key string, bestHeight uint32) (interface{}, bool, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e, found := c.entries[key]
+	if found && time.Now().After(e.expires) {
+		c.remove(e)
+		found = false
+	}
+	if !found {
+		c.metrics.APICacheEfficiency.With(common.Labels{"status": "miss"}).Inc()
+		return nil, false, false
+	}
+	c.metrics.APICacheEfficiency.With(common.Labels{"status": "hit"}).Inc()
+	c.lru.MoveToFront(e.elem)
+	return withConfirmations(e.data, bestHeight), e.immutable, true
+}
+
+// generation returns the current generation of the cache, which must be passed to store
+func (c *responseCache) generation() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.gen
+}
+
+// classify returns if the result is immutable or changes with new transactions and the tags of the result,
+// ok is false if the result cannot be cached
+func (c *responseCache) classify(path string, data interface{}, parser bchain.BlockChainParser) (immutable bool, tags []string, ok bool) {
+	switch d := data.(type) {
+	case *api.Tx:
+		return d.Blockheight > 0 && int(d.Confirmations) >= c.config.Confirmations, []string{txTag(d.Txid)}, true
+	case *api.Block:
+		tags = make([]string, len(d.Transactions))
+		for i, tx := range d.Transactions {
+			tags[i] = txTag(tx.Txid)
+		}
+		return d.Confirmations >= c.config.Confirmations, tags, true
+	case *api.Address:
+		// xpub results depend on many addresses and are not cached
+		if strings.Contains(path, "/address/") {
+			if addrDesc, err := parser.GetAddrDescFromAddress(d.AddrStr); err == nil {
+				return false, []string{addrTag(addrDesc)}, true
+			}
+		}
+	case []api.Utxo:
+		if i := strings.LastIndexByte(path, '/'); i > 0 && strings.Contains(path, "/utxo/") {
+			if addrDesc, err := parser.GetAddrDescFromAddress(path[i+1:]); err == nil {
+				return false, []string{addrTag(addrDesc)}, true
+			}
+		}
+	}
+	return false, nil, false
+}
+
+// store caches the result of the request, size is the size of the serialized result, generation is the generation
+// of the cache before the result was computed
+func (c *responseCache) store(key string, data interface{}, size int, immutable bool, tags []string, generation uint64) {
+	if size > c.config.Size/16 {
+		return
+	}
+	ttl := c.config.TTL
+	if immutable {
+		ttl = immutableCacheTTL
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if generation < c.blockGen {
+		return
+	}
+	for _, t := range tags {
+		if c.invalidated[t] > generation {
+			return
+		}
+	}
+	if old, found := c.entries[key]; found {
+		c.remove(old)
+	}
+	e := &cacheEntry{
+		key:       key,
+		data:      data,
+		size:      size,
+		immutable: immutable,
+		expires:   time.Now().Add(ttl),
+		tags:      tags,
+	}
+	e.elem = c.lru.PushFront(e)
+	c.entries[key] = e
+	c.size += size
+	for _, t := range tags {
+		m, found := c.tags[t]
+		if !found {
+			m = make(map[*cacheEntry]struct{})
+			c.tags[t] = m
+		}
+		m[e] = struct{}{}
+	}
+	for c.size > c.config.Size {
+		c.remove(c.lru.Back().Value.(*cacheEntry))
+	}
+}
+
+// remove removes the entry from the cache, must be called with the lock held
+func (c *responseCache) remove(e *cacheEntry) {
+	c.lru.Remove(e.elem)
+	delete(c.entries, e.key)
+	c.size -= e.size
+	for _, t := range e.tags {
+		if m, found := c.tags[t]; found {
+			delete(m, e)
+			if len(m) == 0 {
+				delete(c.tags, t)
+			}
+		}
+	}
+}
+
+// invalidateTag removes all entries with the tag, must be called with the lock held
+func (c *responseCache) invalidateTag(tag string) {
+	for e := range c.tags[tag] {
+		c.remove(e)
+	}
+	c.invalidated[tag] = c.gen
+}
+
+// onNewBlock removes the results changed by the block, on reorg removes all results
+func (c *responseCache) onNewBlock(height uint32) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.gen++
+	c.blockGen = c.gen
+	if height <= c.lastHeight {
+		c.entries = make(map[string]*cacheEntry)
+		c.lru.Init()
+		c.tags = make(map[string]map[*cacheEntry]struct{})
+		c.size = 0
+	} else {
+		for t := range c.pendingSpent {
+			c.invalidateTag(t)
+		}
+		for el := c.lru.Front(); el != nil; {
+			e := el.Value.(*cacheEntry)
+			el = el.Next()
+			if !e.immutable {
+				c.remove(e)
+			}
+		}
+	}
+	c.pendingSpent = make(map[string]struct{})
+	c.invalidated = make(map[string]uint64)
+	c.lastHeight = height
+}
+
+// onNewTx removes the results of the addresses of the mempool transaction and of the transactions spent by it
+func (c *responseCache) onNewTx(tx *bchain.MempoolTx, addrDescs []bchain.AddressDescriptor) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.gen++
+	for _, addrDesc := range addrDescs {
+		c.invalidateTag(addrTag(addrDesc))
+	}
+	for i := range tx.Vin {
+		if tx.Vin[i].Txid != "" {
+			t := txTag(tx.Vin[i].Txid)
+			c.invalidateTag(t)
+			c.pendingSpent[t] = struct{}{}
+		}
+	}
+}
+
+// withConfirmations returns a copy of the cached transaction or block with the number of confirmations for the best height
+func withConfirmations(data interface{}, bestHeight uint32) interface{} {
+	patchTx := func(tx *api.Tx) *api.Tx {
+		t := *tx
+		if t.Blockheight > 0 && bestHeight >= uint32(t.Blockheight) {
+			t.Confirmations = bestHeight - uint32(t.Blockheight) + 1
+		}
+		return &t
+	}
+	switch d := data.(type) {
+	case *api.Tx:
+		return patchTx(d)
+	case *api.Block:
+		b := *d
+		if bestHeight >= b.Height {
+			b.Confirmations = int(bestHeight-b.Height) + 1
+		}
+		b.Transactions = make([]*api.Tx, len(d.Transactions))
+		for i := range d.Transactions {
+			b.Transactions[i] = patchTx(d.Transactions[i])
+		}
+		return &b
+	}
+	return data
+}
+
+// cacheControl returns the value of the Cache-Control header of the result
+func (c *responseCache) cacheControl(cached, immutable bool) string {
+	if !cached {
+		return "no-cache"
+	}
+	maxAge := int(c.config.TTL / time.Second)
+	if immutable {
+		maxAge = immutableClientMaxAge
+	}
+	return "public, max-age=" + strconv.Itoa(maxAge)
+}
+
+// encodeWithETag serializes the result and sets ETag and Cache-Control headers,
+// notModified is true if the client already has the same version of the result
+func encodeWithETag(w http.ResponseWriter, r *http.Request, data interface{}, cacheControl string) (b []byte, notModified bool, err error) {
+	var buf bytes.Buffer
+	if err = json.NewEncoder(&buf).Encode(data); err != nil {
+		return nil, false, err
+	}
+	h := fnv.New64a()
+	h.Write(buf.Bytes())
+	etag := fmt.Sprintf("\"%x\"", h.Sum64())
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControl)
+	for _, t := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		if strings.TrimSpace(t) == etag {
+			return buf.Bytes(), true, nil
+		}
+	}
+	return buf.Bytes(), false, nil
+}
@@ -0,0 +1,556 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/scryptachain/blockbook-scrypta/api"
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+	"github.com/scryptachain/blockbook-scrypta/common"
+	"github.com/scryptachain/blockbook-scrypta/db"
+	"github.com/scryptachain/blockbook-scrypta/grpcapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// number of notifications buffered for a stream, the stream of a client which does not read them is closed
+	grpcStreamBufferSize = 500
+	// maximum number of addresses subscribed by one SubscribeAddresses call, the limit of the client can be lower
+	grpcMaxSubscribedAddresses = 1000
+	// maximum size of a received message
+	grpcMaxRecvMsgSize = 4 << 20
+)
+
+// GRPCServer is handle to the gRPC interface to blockbook, see the grpcapi package for the definition of the service
+type GRPCServer struct {
+	grpcapi.UnimplementedBlockbookServer
+	binding     string
+	server      *grpc.Server
+	db          *db.RocksDB
+	txCache     *db.TxCache
+	chain       bchain.BlockChain
+	chainParser bchain.BlockChainParser
+	mempool     bchain.Mempool
+	metrics     *common.Metrics
+	is          *common.InternalState
+	api         *api.Worker
+	limiter     *rateLimiter
+	closing     chan struct{}
+	closeOnce   sync.Once
+
+	newBlockSubscriptions     map[*grpcStream]struct{}
+	newBlockSubscriptionsLock sync.Mutex
+	addressSubscriptions      map[string]map[*grpcStream]struct{}
+	addressSubscriptionsLock  sync.Mutex
+}
+
+// grpcStream is an open server streaming call
+type grpcStream struct {
+	out       chan interface{}
+	overflow  chan struct{}
+	closeOnce sync.Once
+}
+
+// send queues the message to the stream without blocking, the stream is closed if the client does not keep up
+func (st *grpcStream) send(m interface{}) {
+	select {
+	case st.out <- m:
+	default:
+		st.closeOnce.Do(func() { close(st.overflow) })
+	}
+}
+
+// NewGRPCServer creates new gRPC interface to blockbook and returns its handle
+// The interface is served only over TLS, therefore the certificate files must be specified
+func NewGRPCServer(binding, certFiles string, db *db.RocksDB, chain bchain.BlockChain, mempool bchain.Mempool, txCache *db.TxCache, metrics *common.Metrics, is *common.InternalState) (*GRPCServer, error) {
+	if certFiles == "" {
+		return nil, errors.New("gRPC server requires TLS, specify the certificate files")
+	}
+	creds, err := credentials.NewServerTLSFromFile(fmt.Sprint(certFiles, ".crt"), fmt.Sprint(certFiles, ".key"))
+	if err != nil {
+		return nil, err
+	}
+	api, err := api.NewWorker(db, chain, mempool, txCache, is)
+	if err != nil {
+		return nil, err
+	}
+	s := &GRPCServer{
+		binding:               binding,
+		db:                    db,
+		txCache:               txCache,
+		chain:                 chain,
+		chainParser:           chain.GetChainParser(),
+		mempool:               mempool,
+		metrics:               metrics,
+		is:                    is,
+		api:                   api,
+		closing:               make(chan struct{}),
+		newBlockSubscriptions: make(map[*grpcStream]struct{}),
+		addressSubscriptions:  make(map[string]map[*grpcStream]struct{}),
+	}
+	s.initServer(grpc.Creds(creds))
+	return s, nil
+}
+
+// initServer creates the gRPC server with the interceptors of the access control and the metrics and registers the service
+func (s *GRPCServer) initServer(opts ...grpc.ServerOption) {
+	opts = append(opts,
+		grpc.MaxRecvMsgSize(grpcMaxRecvMsgSize),
+		grpc.UnaryInterceptor(s.unaryInterceptor),
+		grpc.StreamInterceptor(s.streamInterceptor),
+	)
+	s.server = grpc.NewServer(opts...)
+	grpcapi.RegisterBlockbookServer(s.server, s)
+}
+
+// Run starts the server, it returns nil after the server is closed
+func (s *GRPCServer) Run() error {
+	glog.Info("grpc server: starting to listen on ", s.binding)
+	l, err := net.Listen("tcp", s.binding)
+	if err != nil {
+		return err
+	}
+	return s.server.Serve(l)
+}
+
+// Close closes the server
+func (s *GRPCServer) Close() error {
+	glog.Infof("grpc server: closing")
+	s.closeStreams()
+	s.server.Stop()
+	return nil
+}
+
+// Shutdown shuts down the server, the calls in progress are cancelled if they do not finish before the context is done
+func (s *GRPCServer) Shutdown(ctx context.Context) error {
+	glog.Infof("grpc server: shutdown")
+	// the streams would otherwise keep the connections active until the timeout of the shutdown
+	s.closeStreams()
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
+// SetRateLimit enables the access control of the gRPC interface, the clients have buckets separate from the public server
+// It must be called before the server starts to accept connections
+func (s *GRPCServer) SetRateLimit(config RateLimitConfig) {
+	s.limiter = newRateLimiter(s.db, s.metrics, config)
+}
+
+// UpdateRateLimit changes the limits of the clients without API key of the running server, the IPHeader of the config is ignored
+func (s *GRPCServer) UpdateRateLimit(config RateLimitConfig) {
+	s.limiter.setLimits(config)
+}
+
+func (s *GRPCServer) closeStreams() {
+	s.closeOnce.Do(func() { close(s.closing) })
+}
+
+// accessStatus converts the rejection by the access control to gRPC status
+func accessStatus(ae *accessError) error {
+	if ae.status == http.StatusForbidden {
+		return status.Error(codes.PermissionDenied, ae.Error())
+	}
+	return status.Error(codes.ResourceExhausted, ae.Error())
+}
+
+// checkRateLimit returns error if the call is rejected by the access control, req is nil for the streaming calls
+func (s *GRPCServer) checkRateLimit(ctx context.Context, method string, req interface{}) error {
+	if s.limiter == nil {
+		return nil
+	}
+	ip, key := s.limiter.grpcClient(ctx)
+	if ae := s.limiter.allow(ip, key, grpcRequestCost(s.chainParser, method, req), "grpc"); ae != nil {
+		return accessStatus(ae)
+	}
+	return nil
+}
+
+// grpcError converts the error to gRPC status, public api errors are caused by the request
+func grpcError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if e, ok := err.(*api.APIError); ok && e.Public {
+		return status.Error(codes.InvalidArgument, e.Text)
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func grpcMethod(fullMethod string) string {
+	return strings.TrimPrefix(fullMethod, "/"+grpcapi.ServiceName+"/")
+}
+
+func (s *GRPCServer) countRequest(method string, err error) {
+	st := "success"
+	if err != nil {
+		st = "failure"
+	}
+	s.metrics.GRPCRequests.With(common.Labels{"method": method, "status": st}).Inc()
+}
+
+func (s *GRPCServer) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	method := grpcMethod(info.FullMethod)
+	defer func() {
+		if e := recover(); e != nil {
+			glog.Error("grpc ", method, " recovered from panic: ", e)
+			resp, err = nil, status.Error(codes.Internal, "Internal error")
+		}
+		s.countRequest(method, err)
+	}()
+	if err = s.checkRateLimit(ctx, method, req); err != nil {
+		return nil, err
+	}
+	resp, err = handler(ctx, req)
+	if err != nil {
+		err = grpcError(err)
+		if status.Code(err) == codes.Internal {
+			glog.Error("grpc ", method, " error: ", err)
+		}
+	}
+	return resp, err
+}
+
+func (s *GRPCServer) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	method := grpcMethod(info.FullMethod)
+	defer func() {
+		if e := recover(); e != nil {
+			glog.Error("grpc ", method, " recovered from panic: ", e)
+			err = status.Error(codes.Internal, "Internal error")
+		}
+		s.countRequest(method, err)
+	}()
+	if err = s.checkRateLimit(ss.Context(), method, nil); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// GetAddress returns the balances and transactions of the address
+func (s *GRPCServer) GetAddress(ctx context.Context, req *grpcapi.AddressRequest) (*grpcapi.Address, error) {
+	return s.getAddress(req, false)
+}
+
+// GetXpub returns the balances and transactions of the xpub
+func (s *GRPCServer) GetXpub(ctx context.Context, req *grpcapi.AddressRequest) (*grpcapi.Address, error) {
+	return s.getAddress(req, true)
+}
+
+// GetUtxo returns the unspent outputs of the address or xpub
+func (s *GRPCServer) GetUtxo(ctx context.Context, req *grpcapi.UtxoRequest) (*grpcapi.UtxoList, error) {
+	var utxo api.Utxos
+	var err error
+	if isXpub(s.chainParser, req.Account) {
+		utxo, err = s.api.GetXpubUtxo(req.Account, req.Confirmed, int(req.Gap))
+	} else {
+		utxo, err = s.api.GetAddressUtxo(req.Account, req.Confirmed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return grpcUtxos(utxo), nil
+}
+
+// GetTransaction returns the transaction
+func (s *GRPCServer) GetTransaction(ctx context.Context, req *grpcapi.TransactionRequest) (*grpcapi.Tx, error) {
+	tx, err := s.api.GetTransaction(req.Txid, false, false)
+	if err == nil && req.Currency != "" {
+		err = s.api.SetTxFiat(tx, req.Currency)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return grpcTx(tx), nil
+}
+
+// GetBlock returns the block with a page of its transactions
+func (s *GRPCServer) GetBlock(ctx context.Context, req *grpcapi.BlockRequest) (*grpcapi.Block, error) {
+	block, err := s.api.GetBlock(req.Block, int(req.Page), txsInAPI)
+	if err == nil && req.Currency != "" {
+		err = s.api.SetBlockFiat(block, req.Currency)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return grpcBlock(block), nil
+}
+
+// EstimateFee returns the estimated fees for the requested numbers of blocks
+func (s *GRPCServer) EstimateFee(ctx context.Context, req *grpcapi.EstimateFeeRequest) (*grpcapi.EstimateFeeResponse, error) {
+	r := estimateFeeReq{
+		Blocks: make([]int, len(req.Blocks)),
+		Specific: map[string]interface{}{
+			"conservative": !req.Economical,
+			"txsize":       float64(req.TxSize),
+		},
+	}
+	for i, b := range req.Blocks {
+		r.Blocks[i] = int(b)
+	}
+	for k, v := range req.Specific {
+		r.Specific[k] = v
+	}
+	fees, err := estimateFee(s.chain, &r)
+	if err != nil {
+		return nil, err
+	}
+	res := &grpcapi.EstimateFeeResponse{Fees: make([]*grpcapi.Fee, len(fees))}
+	for i := range fees {
+		res.Fees[i] = &grpcapi.Fee{
+			FeePerTx:   fees[i].FeePerTx,
+			FeePerUnit: fees[i].FeePerUnit,
+			FeeLimit:   fees[i].FeeLimit,
+		}
+	}
+	return res, nil
+}
+
+// SendTransaction sends the transaction to the network
+func (s *GRPCServer) SendTransaction(ctx context.Context, req *grpcapi.SendTransactionRequest) (*grpcapi.SendTransactionResponse, error) {
+	txid, err := s.api.SendTransaction(req.Hex)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcapi.SendTransactionResponse{Txid: txid}, nil
+}
+
+// GetCurrentFiatRates returns the last downloaded fiat rates
+func (s *GRPCServer) GetCurrentFiatRates(ctx context.Context, req *grpcapi.FiatRatesRequest) (*grpcapi.FiatRates, error) {
+	t, err := s.api.GetCurrentFiatRates(req.Currencies)
+	if err != nil {
+		return nil, err
+	}
+	return grpcFiatRates(t), nil
+}
+
+// GetFiatRatesForTimestamps returns the fiat rates closest to the requested timestamps
+func (s *GRPCServer) GetFiatRatesForTimestamps(ctx context.Context, req *grpcapi.FiatRatesRequest) (*grpcapi.FiatRatesList, error) {
+	tickers, err := s.api.GetFiatRatesForTimestamps(req.Timestamps, req.Currencies)
+	if err != nil {
+		return nil, err
+	}
+	res := &grpcapi.FiatRatesList{Tickers: make([]*grpcapi.FiatRates, len(tickers.Tickers))}
+	for i := range tickers.Tickers {
+		res.Tickers[i] = grpcFiatRates(&tickers.Tickers[i])
+	}
+	return res, nil
+}
+
+// stream sends the messages of the stream to the client until the client cancels the call or the server is closing
+// The response headers are sent first, after them the client knows that the subscription is registered
+func (s *GRPCServer) stream(ss grpc.ServerStream, st *grpcStream) error {
+	s.metrics.GRPCStreams.Inc()
+	defer s.metrics.GRPCStreams.Dec()
+	if err := ss.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case m := <-st.out:
+			if err := ss.SendMsg(m); err != nil {
+				return err
+			}
+		case <-st.overflow:
+			return status.Error(codes.ResourceExhausted, "Too many pending notifications")
+		case <-s.closing:
+			return status.Error(codes.Unavailable, "Server is shutting down")
+		case <-ss.Context().Done():
+			return nil
+		}
+	}
+}
+
+func newGRPCStream() *grpcStream {
+	return &grpcStream{
+		out:      make(chan interface{}, grpcStreamBufferSize),
+		overflow: make(chan struct{}),
+	}
+}
+
+// SubscribeNewBlock streams the notifications about new blocks
+func (s *GRPCServer) SubscribeNewBlock(req *grpcapi.SubscribeNewBlockRequest, ss grpcapi.Blockbook_SubscribeNewBlockServer) error {
+	st := newGRPCStream()
+	s.newBlockSubscriptionsLock.Lock()
+	s.newBlockSubscriptions[st] = struct{}{}
+	s.newBlockSubscriptionsLock.Unlock()
+	defer func() {
+		s.newBlockSubscriptionsLock.Lock()
+		delete(s.newBlockSubscriptions, st)
+		s.newBlockSubscriptionsLock.Unlock()
+	}()
+	return s.stream(ss, st)
+}
+
+// SubscribeAddresses streams the notifications about new transactions of the addresses
+func (s *GRPCServer) SubscribeAddresses(req *grpcapi.SubscribeAddressesRequest, ss grpcapi.Blockbook_SubscribeAddressesServer) error {
+	if len(req.Addresses) == 0 {
+		return status.Error(codes.InvalidArgument, "Missing addresses")
+	}
+	if len(req.Addresses) > grpcMaxSubscribedAddresses {
+		return status.Errorf(codes.ResourceExhausted, "Too many addresses, the limit is %d", grpcMaxSubscribedAddresses)
+	}
+	if s.limiter != nil {
+		_, key := s.limiter.grpcClient(ss.Context())
+		if ae := s.limiter.checkSubscriptions(key, len(req.Addresses), "grpc"); ae != nil {
+			return accessStatus(ae)
+		}
+	}
+	addrDescs := make([]string, len(req.Addresses))
+	for i, a := range req.Addresses {
+		addrDesc, err := s.chainParser.GetAddrDescFromAddress(a)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "Invalid address "+a)
+		}
+		addrDescs[i] = string(addrDesc)
+	}
+	st := newGRPCStream()
+	s.addressSubscriptionsLock.Lock()
+	for _, ad := range addrDescs {
+		as, found := s.addressSubscriptions[ad]
+		if !found {
+			as = make(map[*grpcStream]struct{})
+			s.addressSubscriptions[ad] = as
+		}
+		as[st] = struct{}{}
+	}
+	s.addressSubscriptionsLock.Unlock()
+	defer func() {
+		s.addressSubscriptionsLock.Lock()
+		for _, ad := range addrDescs {
+			if as, found := s.addressSubscriptions[ad]; found {
+				delete(as, st)
+				if len(as) == 0 {
+					delete(s.addressSubscriptions, ad)
+				}
+			}
+		}
+		s.addressSubscriptionsLock.Unlock()
+	}()
+	return s.stream(ss, st)
+}
+
+func (s *GRPCServer) getAddress(req *grpcapi.AddressRequest, xpub bool) (*grpcapi.Address, error) {
+	opt := api.AccountDetailsTxidHistory
+	switch req.Details {
+	case "basic":
+		opt = api.AccountDetailsBasic
+	case "tokens":
+		opt = api.AccountDetailsTokens
+	case "tokenBalances":
+		opt = api.AccountDetailsTokenBalances
+	case "txslight":
+		opt = api.AccountDetailsTxHistoryLight
+	case "txs":
+		opt = api.AccountDetailsTxHistory
+	}
+	tokensToReturn := api.TokensToReturnNonzeroBalance
+	switch req.Tokens {
+	case "derived":
+		tokensToReturn = api.TokensToReturnDerived
+	case "used":
+		tokensToReturn = api.TokensToReturnUsed
+	}
+	filter := api.AddressFilter{
+		FromHeight:     req.From,
+		ToHeight:       req.To,
+		Contract:       req.Contract,
+		Vout:           api.AddressFilterVoutOff,
+		TokensToReturn: tokensToReturn,
+	}
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 || pageSize > txsInAPI {
+		pageSize = txsInAPI
+	}
+	var a *api.Address
+	var err error
+	if xpub {
+		a, err = s.api.GetXpubAddress(req.Account, int(req.Page), pageSize, opt, &filter, int(req.Gap))
+		if err == nil && req.Currency != "" {
+			err = s.api.SetXpubFiat(a, req.Account, int(req.Gap), req.Currency)
+		}
+	} else {
+		a, err = s.api.GetAddress(req.Account, int(req.Page), pageSize, opt, &filter)
+		if err == nil && req.Currency != "" {
+			err = s.api.SetAddressFiat(a, req.Currency)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return grpcAddress(a), nil
+}
+
+// OnNewBlock is a callback that streams info about new block to the subscribed clients
+func (s *GRPCServer) OnNewBlock(hash string, height uint32) {
+	s.newBlockSubscriptionsLock.Lock()
+	defer s.newBlockSubscriptionsLock.Unlock()
+	data := &grpcapi.NewBlockNotification{
+		Height: height,
+		Hash:   hash,
+	}
+	for st := range s.newBlockSubscriptions {
+		st.send(data)
+	}
+	if len(s.newBlockSubscriptions) > 0 {
+		glog.Info("grpc: streaming new block ", height, " ", hash, " to ", len(s.newBlockSubscriptions), " streams")
+	}
+}
+
+// OnNewTx is a callback that streams info about a tx affecting the subscribed addresses
+func (s *GRPCServer) OnNewTx(tx *bchain.MempoolTx) {
+	addrDescs := getTxAddrDescs(s.chainParser, tx)
+	// release the lock immediately, GetTransactionFromMempoolTx is potentially slow
+	var subscribed []bchain.AddressDescriptor
+	s.addressSubscriptionsLock.Lock()
+	for _, addrDesc := range addrDescs {
+		if len(s.addressSubscriptions[string(addrDesc)]) > 0 {
+			subscribed = append(subscribed, addrDesc)
+		}
+	}
+	s.addressSubscriptionsLock.Unlock()
+	if len(subscribed) == 0 {
+		return
+	}
+	atx, err := s.api.GetTransactionFromMempoolTx(tx)
+	if err != nil {
+		glog.Error("GetTransactionFromMempoolTx error ", err, " for ", tx.Txid)
+		return
+	}
+	gtx := grpcTx(atx)
+	for _, addrDesc := range subscribed {
+		addr, _, err := s.chainParser.GetAddressesFromAddrDesc(addrDesc)
+		if err != nil || len(addr) != 1 {
+			continue
+		}
+		data := &grpcapi.AddressNotification{
+			Address: addr[0],
+			Tx:      gtx,
+		}
+		s.addressSubscriptionsLock.Lock()
+		as := s.addressSubscriptions[string(addrDesc)]
+		for st := range as {
+			st.send(data)
+		}
+		s.addressSubscriptionsLock.Unlock()
+		glog.Info("grpc: streaming new tx ", tx.Txid, ", addr ", addr[0], " to ", len(as), " streams")
+	}
+}
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"math/big"
+
+	"github.com/scryptachain/blockbook-scrypta/api"
+	"github.com/scryptachain/blockbook-scrypta/db"
+	"github.com/scryptachain/blockbook-scrypta/grpcapi"
+)
+
+// conversion of the api types to the messages of the gRPC interface, the amounts are passed as decimal strings
+
+func bigIntString(b *big.Int) string {
+	if b == nil {
+		return ""
+	}
+	return b.String()
+}
+
+func grpcFiatValue(v *api.FiatValue) *grpcapi.FiatValue {
+	if v == nil {
+		return nil
+	}
+	return &grpcapi.FiatValue{Value: v.Value, CurrentValue: v.CurrentValue}
+}
+
+func grpcTx(tx *api.Tx) *grpcapi.Tx {
+	if tx == nil {
+		return nil
+	}
+	t := &grpcapi.Tx{
+		Txid:          tx.Txid,
+		Version:       tx.Version,
+		LockTime:      tx.Locktime,
+		Vin:           make([]*grpcapi.Vin, len(tx.Vin)),
+		Vout:          make([]*grpcapi.Vout, len(tx.Vout)),
+		BlockHash:     tx.Blockhash,
+		BlockHeight:   int32(tx.Blockheight),
+		Confirmations: tx.Confirmations,
+		BlockTime:     tx.Blocktime,
+		Size:          int32(tx.Size),
+		Value:         tx.ValueOutSat.String(),
+		ValueIn:       tx.ValueInSat.String(),
+		Fees:          tx.FeesSat.String(),
+		Hex:           tx.Hex,
+		Rbf:           tx.Rbf,
+	}
+	for i := range tx.Vin {
+		vin := &tx.Vin[i]
+		t.Vin[i] = &grpcapi.Vin{
+			Txid:      vin.Txid,
+			Vout:      vin.Vout,
+			Sequence:  vin.Sequence,
+			N:         int32(vin.N),
+			Addresses: vin.Addresses,
+			IsAddress: vin.IsAddress,
+			Value:     vin.ValueSat.String(),
+			Hex:       vin.Hex,
+			Asm:       vin.Asm,
+			Coinbase:  vin.Coinbase,
+			Fiat:      grpcFiatValue(vin.Fiat),
+		}
+	}
+	for i := range tx.Vout {
+		vout := &tx.Vout[i]
+		t.Vout[i] = &grpcapi.Vout{
+			Value:       vout.ValueSat.String(),
+			N:           int32(vout.N),
+			Spent:       vout.Spent,
+			SpentTxid:   vout.SpentTxID,
+			SpentIndex:  int32(vout.SpentIndex),
+			SpentHeight: int32(vout.SpentHeight),
+			Hex:         vout.Hex,
+			Asm:         vout.Asm,
+			Addresses:   vout.Addresses,
+			IsAddress:   vout.IsAddress,
+			Type:        vout.Type,
+			Fiat:        grpcFiatValue(vout.Fiat),
+		}
+	}
+	for i := range tx.TokenTransfers {
+		tt := &tx.TokenTransfers[i]
+		t.TokenTransfers = append(t.TokenTransfers, &grpcapi.TokenTransfer{
+			Type:     string(tt.Type),
+			From:     tt.From,
+			To:       tt.To,
+			Token:    tt.Token,
+			Name:     tt.Name,
+			Symbol:   tt.Symbol,
+			Decimals: int32(tt.Decimals),
+			Value:    tt.Value.String(),
+		})
+	}
+	if es := tx.EthereumSpecific; es != nil {
+		t.EthereumSpecific = &grpcapi.EthereumSpecific{
+			Status:   int32(es.Status),
+			Nonce:    es.Nonce,
+			GasLimit: bigIntString(es.GasLimit),
+			GasUsed:  bigIntString(es.GasUsed),
+			GasPrice: es.GasPrice.String(),
+			Data:     es.Data,
+		}
+	}
+	if f := tx.Fiat; f != nil {
+		t.Fiat = &grpcapi.TxFiat{
+			Currency:    f.Currency,
+			Rate:        f.Rate,
+			CurrentRate: f.CurrentRate,
+			Value:       grpcFiatValue(&f.Value),
+			ValueIn:     grpcFiatValue(f.ValueIn),
+			Fees:        grpcFiatValue(f.Fees),
+		}
+	}
+	return t
+}
+
+func grpcTxs(txs []*api.Tx) []*grpcapi.Tx {
+	if len(txs) == 0 {
+		return nil
+	}
+	r := make([]*grpcapi.Tx, len(txs))
+	for i, tx := range txs {
+		r[i] = grpcTx(tx)
+	}
+	return r
+}
+
+func grpcAddress(a *api.Address) *grpcapi.Address {
+	r := &grpcapi.Address{
+		Page:               int32(a.Page),
+		TotalPages:         int32(a.TotalPages),
+		ItemsOnPage:        int32(a.ItemsOnPage),
+		Address:            a.AddrStr,
+		Balance:            a.BalanceSat.String(),
+		TotalReceived:      a.TotalReceivedSat.String(),
+		TotalSent:          a.TotalSentSat.String(),
+		UnconfirmedBalance: a.UnconfirmedBalanceSat.String(),
+		UnconfirmedTxs:     int32(a.UnconfirmedTxs),
+		Txs:                int32(a.Txs),
+		NonTokenTxs:        int32(a.NonTokenTxs),
+		Transactions:       grpcTxs(a.Transactions),
+		Txids:              a.Txids,
+		Nonce:              a.Nonce,
+		UsedTokens:         int32(a.UsedTokens),
+	}
+	for i := range a.Tokens {
+		t := &a.Tokens[i]
+		r.Tokens = append(r.Tokens, &grpcapi.Token{
+			Type:          string(t.Type),
+			Name:          t.Name,
+			Path:          t.Path,
+			Contract:      t.Contract,
+			Transfers:     int32(t.Transfers),
+			Symbol:        t.Symbol,
+			Decimals:      int32(t.Decimals),
+			Balance:       t.BalanceSat.String(),
+			TotalReceived: t.TotalReceivedSat.String(),
+			TotalSent:     t.TotalSentSat.String(),
+		})
+	}
+	if c := a.Erc20Contract; c != nil {
+		r.Erc20Contract = &grpcapi.Erc20Contract{
+			Contract: c.Contract,
+			Name:     c.Name,
+			Symbol:   c.Symbol,
+			Decimals: int32(c.Decimals),
+		}
+	}
+	if f := a.Fiat; f != nil {
+		r.Fiat = &grpcapi.AccountFiat{
+			Currency:           f.Currency,
+			Rate:               f.Rate,
+			Balance:            f.Balance,
+			UnconfirmedBalance: f.UnconfirmedBalance,
+			TotalReceived:      f.TotalReceived,
+			TotalSent:          f.TotalSent,
+			CostBasis:          f.CostBasis,
+			Realized:           f.Realized,
+			Unrealized:         f.Unrealized,
+		}
+	}
+	return r
+}
+
+func grpcUtxos(utxos api.Utxos) *grpcapi.UtxoList {
+	r := &grpcapi.UtxoList{Utxos: make([]*grpcapi.Utxo, len(utxos))}
+	for i := range utxos {
+		u := &utxos[i]
+		r.Utxos[i] = &grpcapi.Utxo{
+			Txid:          u.Txid,
+			Vout:          u.Vout,
+			Value:         u.AmountSat.String(),
+			Height:        int32(u.Height),
+			Confirmations: int32(u.Confirmations),
+			Address:       u.Address,
+			Path:          u.Path,
+			LockTime:      u.Locktime,
+			Coinbase:      u.Coinbase,
+			Fiat:          grpcFiatValue(u.Fiat),
+		}
+	}
+	return r
+}
+
+func grpcBlock(b *api.Block) *grpcapi.Block {
+	r := &grpcapi.Block{
+		Page:              int32(b.Page),
+		TotalPages:        int32(b.TotalPages),
+		ItemsOnPage:       int32(b.ItemsOnPage),
+		Hash:              b.Hash,
+		PreviousBlockHash: b.Prev,
+		NextBlockHash:     b.Next,
+		Height:            b.Height,
+		Confirmations:     int32(b.Confirmations),
+		Size:              int32(b.Size),
+		Time:              b.Time,
+		Version:           string(b.Version),
+		MerkleRoot:        b.MerkleRoot,
+		Nonce:             b.Nonce,
+		Bits:              b.Bits,
+		Difficulty:        b.Difficulty,
+		Txids:             b.Txids,
+		TxCount:           int32(b.TxCount),
+		Txs:               grpcTxs(b.Transactions),
+	}
+	if f := b.Fiat; f != nil {
+		r.Fiat = &grpcapi.FiatRate{Currency: f.Currency, Rate: f.Rate, CurrentRate: f.CurrentRate}
+	}
+	return r
+}
+
+func grpcFiatRates(t *db.ResultTickerAsString) *grpcapi.FiatRates {
+	return &grpcapi.FiatRates{
+		Timestamp: t.Timestamp,
+		Rates:     t.Rates,
+		Derived:   t.Derived,
+		Error:     t.Error,
+	}
+}
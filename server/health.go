@@ -0,0 +1,187 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+	"github.com/scryptachain/blockbook-scrypta/common"
+)
+
+// HealthConfig contains the thresholds of the readiness checks, zero disables the check
+type HealthConfig struct {
+	// MaxBlocksBehind is the maximum number of blocks the index can be behind the backend
+	MaxBlocksBehind int
+	// MaxSyncAge is the maximum time since the last block was connected to the index
+	MaxSyncAge time.Duration
+	// MaxMempoolSyncAge is the maximum time since the last synchronization of the mempool
+	MaxMempoolSyncAge time.Duration
+	// MaxBackendErrors is the maximum number of consecutive failed RPC calls to the backend
+	MaxBackendErrors int
+}
+
+// the chain info is cached so that frequent probes do not load the backend
+const healthChainInfoTTL = 2 * time.Second
+
+type healthCheck struct {
+	Name      string `json:"name"`
+	OK        bool   `json:"ok"`
+	Value     string `json:"value,omitempty"`
+	Threshold string `json:"threshold,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type healthResult struct {
+	Status string        `json:"status"`
+	Checks []healthCheck `json:"checks"`
+}
+
+// healthChecker evaluates the liveness and readiness of blockbook for load balancers
+type healthChecker struct {
+	chain         bchain.BlockChain
+	is            *common.InternalState
+	config        HealthConfig
+	lock          sync.Mutex
+	chainInfo     *bchain.ChainInfo
+	chainInfoErr  error
+	chainInfoTime time.Time
+}
+
+func newHealthChecker(chain bchain.BlockChain, is *common.InternalState) *healthChecker {
+	return &healthChecker{
+		chain: chain,
+		is:    is,
+	}
+}
+
+// SetHealthConfig sets the thresholds of the readiness checks of the endpoint health/ready
+// It must be called before the server starts to accept connections
+func (s *PublicServer) SetHealthConfig(config HealthConfig) {
+	s.health.config = config
+}
+
+func (h *healthChecker) getChainInfo() (*bchain.ChainInfo, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if time.Since(h.chainInfoTime) > healthChainInfoTTL {
+		h.chainInfo, h.chainInfoErr = h.chain.GetChainInfo()
+		h.chainInfoTime = time.Now()
+	}
+	return h.chainInfo, h.chainInfoErr
+}
+
+func durationCheck(name string, since time.Time, max time.Duration) healthCheck {
+	c := healthCheck{Name: name, OK: true, Threshold: max.String()}
+	if since.IsZero() {
+		c.OK = false
+		c.Error = "never"
+		return c
+	}
+	age := time.Since(since).Truncate(time.Second)
+	c.Value = age.String()
+	if age > max {
+		c.OK = false
+	}
+	return c
+}
+
+// database checks the state of the database, it is inconsistent if a write to the index failed
+func (h *healthChecker) database() healthCheck {
+	c := healthCheck{Name: "database", OK: true, Value: "open"}
+	switch h.is.DbState {
+	case common.DbStateClosed:
+		c.OK = false
+		c.Value = "closed"
+	case common.DbStateInconsistent:
+		c.OK = false
+		c.Value = "inconsistent"
+	}
+	return c
+}
+
+// live returns the checks showing that the process is working
+func (h *healthChecker) live() []healthCheck {
+	return []healthCheck{h.database()}
+}
+
+// ready returns the checks showing that the process serves current data
+func (h *healthChecker) ready() []healthCheck {
+	checks := []healthCheck{h.database()}
+	_, bestHeight, lastSync := h.is.GetSyncState()
+	c := healthCheck{Name: "initialSync", OK: !h.is.InitialSync}
+	checks = append(checks, c)
+	ci, err := h.getChainInfo()
+	c = healthCheck{Name: "backend", OK: err == nil}
+	if err != nil {
+		c.Error = err.Error()
+	}
+	checks = append(checks, c)
+	if h.config.MaxBlocksBehind > 0 && err == nil {
+		behind := ci.Blocks - int(bestHeight)
+		c = healthCheck{
+			Name:      "blocksBehind",
+			OK:        behind <= h.config.MaxBlocksBehind,
+			Value:     strconv.Itoa(behind),
+			Threshold: strconv.Itoa(h.config.MaxBlocksBehind),
+		}
+		checks = append(checks, c)
+	}
+	// without the sync mode the index and the mempool are not synchronized by this process
+	if h.config.MaxSyncAge > 0 && h.is.SyncMode {
+		checks = append(checks, durationCheck("lastSync", lastSync, h.config.MaxSyncAge))
+	}
+	if h.config.MaxMempoolSyncAge > 0 && h.is.SyncMode {
+		_, lastMempoolSync, _ := h.is.GetMempoolSyncState()
+		checks = append(checks, durationCheck("mempoolSync", lastMempoolSync, h.config.MaxMempoolSyncAge))
+	}
+	if be, ok := h.chain.(bchain.BackendErrors); ok && h.config.MaxBackendErrors > 0 {
+		count, lastError, _ := be.GetBackendErrors()
+		c = healthCheck{
+			Name:      "backendErrors",
+			OK:        count < h.config.MaxBackendErrors,
+			Value:     strconv.Itoa(count),
+			Threshold: strconv.Itoa(h.config.MaxBackendErrors),
+		}
+		if count > 0 {
+			c.Error = lastError
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+func writeHealth(w http.ResponseWriter, checks []healthCheck) {
+	res := healthResult{Status: "ok", Checks: checks}
+	status := http.StatusOK
+	for i := range checks {
+		if !checks[i].OK {
+			res.Status = "fail"
+			status = http.StatusServiceUnavailable
+			break
+		}
+	}
+	buf, err := json.Marshal(res)
+	if err != nil {
+		glog.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(status)
+	w.Write(buf)
+}
+
+// healthLive returns status 200 if blockbook is running with a usable database, otherwise 503
+func (s *PublicServer) healthLive(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, s.health.live())
+}
+
+// healthReady returns status 200 if blockbook is synchronized with the backend within the configured thresholds, otherwise 503
+func (s *PublicServer) healthReady(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, s.health.ready())
+}
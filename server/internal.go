@@ -2,18 +2,29 @@ package server
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/golang/glog"
+	"github.com/juju/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/scryptachain/blockbook-scrypta/api"
 	"github.com/scryptachain/blockbook-scrypta/bchain"
 	"github.com/scryptachain/blockbook-scrypta/common"
 	"github.com/scryptachain/blockbook-scrypta/db"
+	"github.com/scryptachain/blockbook-scrypta/fiat"
 )
 
+// default pause between requests to the fiat rates provider during the backfill
+const defaultFiatBackfillPause = 1500 * time.Millisecond
+
 // InternalServer is handle to internal http server
 type InternalServer struct {
 	https       *http.Server
@@ -25,10 +36,32 @@ type InternalServer struct {
 	mempool     bchain.Mempool
 	is          *common.InternalState
 	api         *api.Worker
+	broadcasts  *bchain.BroadcastTracker
+	fiatRates   *fiat.RatesDownloader
+	admin       *adminJobs
+	logs        *requestLogs
+	// backfill is the running fiat rates backfill started by the API, nil if none is running
+	backfill     *fiatBackfill
+	backfillLock sync.Mutex
+}
+
+// fiatBackfill is a fiat rates backfill running in background, stopped by closing the channel stop
+type fiatBackfill struct {
+	stop     chan os.Signal
+	canceled bool
+	done     chan struct{}
+}
+
+// cancel signals the backfill to stop, must be called with the lock held
+func (b *fiatBackfill) cancel() {
+	if !b.canceled {
+		b.canceled = true
+		close(b.stop)
+	}
 }
 
 // NewInternalServer creates new internal http interface to blockbook and returns its handle
-func NewInternalServer(binding, certFiles string, db *db.RocksDB, chain bchain.BlockChain, mempool bchain.Mempool, txCache *db.TxCache, is *common.InternalState) (*InternalServer, error) {
+func NewInternalServer(binding, certFiles string, db *db.RocksDB, chain bchain.BlockChain, mempool bchain.Mempool, txCache *db.TxCache, is *common.InternalState, broadcasts *bchain.BroadcastTracker, fiatRates *fiat.RatesDownloader) (*InternalServer, error) {
 	api, err := api.NewWorker(db, chain, mempool, txCache, is)
 	if err != nil {
 		return nil, err
@@ -50,15 +83,59 @@ func NewInternalServer(binding, certFiles string, db *db.RocksDB, chain bchain.B
 		mempool:     mempool,
 		is:          is,
 		api:         api,
+		broadcasts:  broadcasts,
+		fiatRates:   fiatRates,
 	}
 
 	serveMux.Handle(path+"favicon.ico", http.FileServer(http.Dir("./static/")))
 	serveMux.HandleFunc(path+"metrics", promhttp.Handler().ServeHTTP)
+	serveMux.HandleFunc(path+"api/v2/broadcasts", s.apiBroadcasts)
+	serveMux.HandleFunc(path+"api/v2/fiatrates/coverage", s.apiFiatRatesCoverage)
+	serveMux.HandleFunc(path+"api/v2/fiatrates/backfill", s.auditHandler(s.apiFiatRatesBackfill))
+	serveMux.HandleFunc(path+"api/v2/apikeys", s.auditHandler(s.apiAPIKeys))
+	serveMux.HandleFunc(path+"api/v2/admin/jobs", s.auditHandler(s.apiAdminJobs))
+	serveMux.HandleFunc(path+"api/v2/admin/jobs/", s.auditHandler(s.apiAdminJobs))
 	serveMux.HandleFunc(path, s.index)
 
 	return s, nil
 }
 
+// SetAuditLog enables the audit log of the admin actions, nil log is disabled
+// It must be called before the server starts to accept connections
+func (s *InternalServer) SetAuditLog(audit *common.JSONLog) {
+	s.logs = newRequestLogs(nil, audit)
+	if s.admin != nil {
+		s.admin.logs = s.logs
+	}
+}
+
+// auditHandler records the requests changing the state (other than GET) in the audit log
+func (s *InternalServer) auditHandler(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.logs == nil || r.Method == http.MethodGet || r.Method == http.MethodHead {
+			handler(w, r)
+			return
+		}
+		requestID := httpRequestID(r)
+		w.Header().Set(requestIDHeader, requestID)
+		lw := &logResponseWriter{ResponseWriter: w}
+		handler(lw, r)
+		e := auditLogEntry{
+			RequestID: requestID,
+			Interface: "internal",
+			ClientIP:  remoteIP(r.RemoteAddr),
+			Action:    r.Method + " " + r.URL.Path,
+			Params:    redactedParams(r.URL),
+			Status:    "success",
+		}
+		if lw.status >= http.StatusBadRequest {
+			e.Status = "failure"
+			e.Error = strconv.Itoa(lw.status) + " " + http.StatusText(lw.status)
+		}
+		s.logs.logAudit(&e)
+	}
+}
+
 // Run starts the server
 func (s *InternalServer) Run() error {
 	if s.certFiles == "" {
@@ -78,6 +155,10 @@ func (s *InternalServer) Close() error {
 // Shutdown shuts down the server
 func (s *InternalServer) Shutdown(ctx context.Context) error {
 	glog.Infof("internal server: shutdown")
+	if s.admin != nil {
+		s.admin.shutdown(ctx)
+	}
+	s.stopFiatRatesBackfill(ctx)
 	return s.https.Shutdown(ctx)
 }
 
@@ -97,3 +178,240 @@ func (s *InternalServer) index(w http.ResponseWriter, r *http.Request) {
 
 	w.Write(buf)
 }
+
+type resultBroadcasts struct {
+	RebroadcastEnabled bool                    `json:"rebroadcastEnabled"`
+	Broadcasts         []bchain.BroadcastEntry `json:"broadcasts"`
+}
+
+func writeInternalJSON(w http.ResponseWriter, status int, data interface{}) {
+	buf, err := json.MarshalIndent(data, "", "    ")
+	if err != nil {
+		glog.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(buf)
+}
+
+type resultInternalError struct {
+	Error string `json:"error"`
+}
+
+// apiBroadcasts returns status of transactions sent to the backend, optionally filtered by the status parameter
+func (s *InternalServer) apiBroadcasts(w http.ResponseWriter, r *http.Request) {
+	res := resultBroadcasts{Broadcasts: []bchain.BroadcastEntry{}}
+	if s.broadcasts != nil {
+		res.RebroadcastEnabled = true
+		res.Broadcasts = s.broadcasts.GetBroadcasts(bchain.BroadcastStatus(r.URL.Query().Get("status")))
+	}
+	writeInternalJSON(w, http.StatusOK, res)
+}
+
+// fiatRatesRange parses parameters from and to in format YYYYMMDD, to defaults to yesterday
+func fiatRatesRange(r *http.Request) (time.Time, time.Time, error) {
+	from, err := db.FiatRatesConvertDate(r.URL.Query().Get("from"))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to := time.Now().UTC().Add(-24 * time.Hour)
+	if t := r.URL.Query().Get("to"); t != "" {
+		tt, err := db.FiatRatesConvertDate(t)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = *tt
+	}
+	return *from, to, nil
+}
+
+// apiFiatRatesCoverage returns days without stored fiat rates and coverage of the currencies in the range given by parameters from and to
+func (s *InternalServer) apiFiatRatesCoverage(w http.ResponseWriter, r *http.Request) {
+	from, to, err := fiatRatesRange(r)
+	if err != nil {
+		writeInternalJSON(w, http.StatusBadRequest, resultInternalError{err.Error()})
+		return
+	}
+	c, err := fiat.ComputeFiatRatesCoverage(s.db, from, to)
+	if err != nil {
+		writeInternalJSON(w, http.StatusBadRequest, resultInternalError{err.Error()})
+		return
+	}
+	writeInternalJSON(w, http.StatusOK, c)
+}
+
+// startFiatRatesBackfill starts the backfill in background, it returns false if a backfill is already running
+func (s *InternalServer) startFiatRatesBackfill(from, to time.Time, pause time.Duration) bool {
+	s.backfillLock.Lock()
+	defer s.backfillLock.Unlock()
+	if s.backfill != nil {
+		return false
+	}
+	if status := s.fiatRates.BackfillStatus(); status != nil && status.Running {
+		return false
+	}
+	b := &fiatBackfill{
+		stop: make(chan os.Signal),
+		done: make(chan struct{}),
+	}
+	s.backfill = b
+	go func() {
+		defer close(b.done)
+		if _, err := s.fiatRates.Backfill(from, to, pause, b.stop); err != nil {
+			glog.Error("Fiat rates backfill: ", err)
+		}
+		s.backfillLock.Lock()
+		s.backfill = nil
+		s.backfillLock.Unlock()
+	}()
+	return true
+}
+
+// stopFiatRatesBackfill cancels the running backfill and waits until it stops
+// Returns false if no backfill started by the API is running
+func (s *InternalServer) stopFiatRatesBackfill(ctx context.Context) bool {
+	s.backfillLock.Lock()
+	b := s.backfill
+	if b != nil {
+		b.cancel()
+	}
+	s.backfillLock.Unlock()
+	if b == nil {
+		return false
+	}
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		glog.Error("Fiat rates backfill did not stop in time")
+	}
+	return true
+}
+
+// apiFiatRatesBackfill returns the status of the fiat rates backfill,
+// POST request starts the backfill in the range given by parameters from and to, with optional pause in milliseconds,
+// DELETE request stops the running backfill
+func (s *InternalServer) apiFiatRatesBackfill(w http.ResponseWriter, r *http.Request) {
+	if s.fiatRates == nil {
+		writeInternalJSON(w, http.StatusNotFound, resultInternalError{"Fiat rates are not configured"})
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		from, to, err := fiatRatesRange(r)
+		if err != nil {
+			writeInternalJSON(w, http.StatusBadRequest, resultInternalError{err.Error()})
+			return
+		}
+		pause := defaultFiatBackfillPause
+		if p := r.URL.Query().Get("pause"); p != "" {
+			ms, err := strconv.Atoi(p)
+			if err != nil || ms < 0 {
+				writeInternalJSON(w, http.StatusBadRequest, resultInternalError{"Invalid pause " + p})
+				return
+			}
+			pause = time.Duration(ms) * time.Millisecond
+		}
+		if !s.startFiatRatesBackfill(from, to, pause) {
+			writeInternalJSON(w, http.StatusConflict, resultInternalError{fiat.ErrBackfillRunning.Error()})
+			return
+		}
+		writeInternalJSON(w, http.StatusAccepted, struct {
+			Started bool `json:"started"`
+		}{true})
+		return
+	case http.MethodDelete:
+		if !s.stopFiatRatesBackfill(r.Context()) {
+			writeInternalJSON(w, http.StatusNotFound, resultInternalError{"Fiat rates backfill is not running"})
+			return
+		}
+	}
+	status := s.fiatRates.BackfillStatus()
+	if status == nil {
+		writeInternalJSON(w, http.StatusOK, struct{}{})
+		return
+	}
+	writeInternalJSON(w, http.StatusOK, status)
+}
+
+// apiKeyFromRequest creates API key from parameters name, rate, burst and subscriptions
+func apiKeyFromRequest(r *http.Request) (*db.APIKey, error) {
+	q := r.URL.Query()
+	k := db.APIKey{
+		Name:    q.Get("name"),
+		Created: time.Now().Unix(),
+	}
+	var err error
+	if v := q.Get("rate"); v != "" {
+		if k.Rate, err = strconv.ParseFloat(v, 64); err != nil || k.Rate < 0 {
+			return nil, errors.New("Invalid rate " + v)
+		}
+	}
+	if v := q.Get("burst"); v != "" {
+		if k.Burst, err = strconv.ParseFloat(v, 64); err != nil || k.Burst < 0 {
+			return nil, errors.New("Invalid burst " + v)
+		}
+	}
+	if v := q.Get("subscriptions"); v != "" {
+		if k.MaxSubscriptions, err = strconv.Atoi(v); err != nil || k.MaxSubscriptions < 0 {
+			return nil, errors.New("Invalid subscriptions " + v)
+		}
+	}
+	b := make([]byte, apiKeyBytes)
+	if _, err = rand.Read(b); err != nil {
+		return nil, err
+	}
+	k.Key = hex.EncodeToString(b)
+	return &k, nil
+}
+
+// apiAPIKeys returns the API keys of the public interface, only their ids are listed, the keys are not stored,
+// POST request creates a new key with the limits given by parameters name, rate, burst and subscriptions and returns the key,
+// DELETE request deletes the key given by parameter key or by its id in parameter id
+// The changes are applied by the public server within a minute
+func (s *InternalServer) apiAPIKeys(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		k, err := apiKeyFromRequest(r)
+		if err != nil {
+			writeInternalJSON(w, http.StatusBadRequest, resultInternalError{err.Error()})
+			return
+		}
+		if err = s.db.StoreAPIKey(k); err != nil {
+			glog.Error("StoreAPIKey: ", err)
+			writeInternalJSON(w, http.StatusInternalServerError, resultInternalError{err.Error()})
+			return
+		}
+		writeInternalJSON(w, http.StatusOK, k)
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if key := r.URL.Query().Get("key"); key != "" {
+			id = db.APIKeyID(key)
+		}
+		k, err := s.db.GetAPIKeyByID(id)
+		if err == nil && k == nil {
+			writeInternalJSON(w, http.StatusNotFound, resultInternalError{"API key not found"})
+			return
+		}
+		if err == nil {
+			err = s.db.DeleteAPIKey(id)
+		}
+		if err != nil {
+			glog.Error("DeleteAPIKey: ", err)
+			writeInternalJSON(w, http.StatusInternalServerError, resultInternalError{err.Error()})
+			return
+		}
+		writeInternalJSON(w, http.StatusOK, struct {
+			Deleted bool `json:"deleted"`
+		}{true})
+	default:
+		keys, err := s.db.GetAPIKeys()
+		if err != nil {
+			glog.Error("GetAPIKeys: ", err)
+			writeInternalJSON(w, http.StatusInternalServerError, resultInternalError{err.Error()})
+			return
+		}
+		writeInternalJSON(w, http.StatusOK, keys)
+	}
+}
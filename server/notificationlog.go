@@ -0,0 +1,315 @@
+package server
+
+import (
+	"encoding/json"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/scryptachain/blockbook-scrypta/db"
+)
+
+type notificationKind string
+
+const (
+	notificationBlock notificationKind = "block"
+	notificationTx    notificationKind = "tx"
+)
+
+// the events of the keys without subscriptions are logged for this period after the last unsubscribe,
+// so that the clients can resume their subscriptions after reconnect
+const notificationResumeWindow = 10 * time.Minute
+
+// blockTag is the key of the subscriptions of new blocks, the keys of the addresses are given by addrTag
+const blockTag = "b"
+
+// notificationEvent is an entry of the notification log, the notifications are reconstructed from it on replay
+type notificationEvent struct {
+	Seq       uint64           `json:"seq"`
+	Kind      notificationKind `json:"kind"`
+	Height    uint32           `json:"height,omitempty"`
+	Hash      string           `json:"hash,omitempty"`
+	Txid      string           `json:"txid,omitempty"`
+	AddrDescs [][]byte         `json:"addrDescs,omitempty"`
+}
+
+// notificationStore stores the notification events spilled from the notification log, implemented by db.RocksDB
+type notificationStore interface {
+	StoreNotifications(notifications []db.Notification, deleteFrom, deleteTo uint64) error
+	GetNotifications(from, to uint64) ([]db.Notification, error)
+	DeleteNotifications(before uint64) error
+}
+
+// notificationInterest tracks the subscriptions of a key of the notification log
+type notificationInterest struct {
+	// refs is the number of subscriptions of the key
+	refs int
+	// since is the sequence number after which the events of the key are logged
+	since uint64
+	// expires is the time when the events of the key without subscriptions stop to be logged
+	expires time.Time
+}
+
+func (i *notificationInterest) active(now time.Time) bool {
+	return i.refs > 0 || now.Before(i.expires)
+}
+
+// notificationLog keeps the last notification events in memory ring, the events evicted from the ring
+// are optionally spilled to the db, where spillSize events is kept
+// Only the events of the subscribed keys are logged, see subscribe
+// The sequence numbers start at the unix time of the start of the log shifted by 20 bits, so that they
+// increase across restarts and stay within the precision of javascript numbers
+// The db is accessed outside of the lock, the evicted events wait in pending until they are stored
+type notificationLog struct {
+	lock      sync.Mutex
+	db        notificationStore
+	seq       uint64
+	firstSeq  uint64
+	ring      []notificationEvent
+	head      int
+	count     int
+	pending   []notificationEvent
+	spillSize uint64
+	// spilledFirst is the lowest sequence number which may be stored in the db
+	spilledFirst uint64
+	interest     map[string]*notificationInterest
+	// spillLock serializes the writes of the pending events to the db
+	spillLock sync.Mutex
+}
+
+func newNotificationLog(d notificationStore, size int, spillSize int) (*notificationLog, error) {
+	l := &notificationLog{
+		db:       d,
+		seq:      uint64(time.Now().Unix()) << 20,
+		ring:     make([]notificationEvent, size),
+		interest: make(map[string]*notificationInterest),
+	}
+	if spillSize > 0 {
+		// the events spilled before restart cannot be replayed, there is a gap after them
+		if err := d.DeleteNotifications(math.MaxUint64); err != nil {
+			return nil, err
+		}
+		l.spillSize = uint64(spillSize)
+	}
+	return l, nil
+}
+
+// lastSeq returns the sequence number of the last event
+func (l *notificationLog) lastSeq() uint64 {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.seq
+}
+
+// subscribe starts logging of the events of the keys, the log may be nil
+func (l *notificationLog) subscribe(keys ...string) {
+	if l == nil || len(keys) == 0 {
+		return
+	}
+	now := time.Now()
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for _, k := range keys {
+		i, found := l.interest[k]
+		if !found || !i.active(now) {
+			i = &notificationInterest{since: l.seq}
+			l.interest[k] = i
+		}
+		i.refs++
+	}
+}
+
+// unsubscribe stops logging of the events of the keys after notificationResumeWindow if they are not subscribed again,
+// the log may be nil
+func (l *notificationLog) unsubscribe(keys ...string) {
+	if l == nil || len(keys) == 0 {
+		return
+	}
+	expires := time.Now().Add(notificationResumeWindow)
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for _, k := range keys {
+		if i, found := l.interest[k]; found && i.refs > 0 {
+			i.refs--
+			if i.refs == 0 {
+				i.expires = expires
+			}
+		}
+	}
+}
+
+// logged returns true if the events of any of the keys are logged
+func (l *notificationLog) logged(keys ...string) bool {
+	now := time.Now()
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for _, k := range keys {
+		if i, found := l.interest[k]; found && i.active(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// loggedSince returns true if the events of all the keys are logged after the sequence number from
+func (l *notificationLog) loggedSince(keys []string, from uint64) bool {
+	now := time.Now()
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for _, k := range keys {
+		if i, found := l.interest[k]; !found || !i.active(now) || i.since > from {
+			return false
+		}
+	}
+	return true
+}
+
+// pruneInterest removes the keys whose events are not logged anymore
+func (l *notificationLog) pruneInterest() {
+	now := time.Now()
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for k, i := range l.interest {
+		if !i.active(now) {
+			delete(l.interest, k)
+		}
+	}
+}
+
+// append assigns the sequence number to the event, stores it and returns the sequence number
+func (l *notificationLog) append(e notificationEvent) uint64 {
+	l.lock.Lock()
+	l.seq++
+	e.Seq = l.seq
+	if len(l.ring) == 0 {
+		l.lock.Unlock()
+		return e.Seq
+	}
+	if l.firstSeq == 0 {
+		l.firstSeq = e.Seq
+	}
+	if l.count < len(l.ring) {
+		l.ring[(l.head+l.count)%len(l.ring)] = e
+		l.count++
+		l.lock.Unlock()
+		return e.Seq
+	}
+	evicted := l.ring[l.head]
+	l.ring[l.head] = e
+	l.head = (l.head + 1) % len(l.ring)
+	if l.spillSize == 0 {
+		// without spill the event is dropped
+		l.firstSeq = evicted.Seq + 1
+		l.lock.Unlock()
+		return e.Seq
+	}
+	l.pending = append(l.pending, evicted)
+	l.lock.Unlock()
+	l.spill()
+	return e.Seq
+}
+
+// spill stores the pending events to db and deletes the older events, which do not fit to the spill size,
+// in the same write batch; the pending events which cannot be stored are dropped
+func (l *notificationLog) spill() {
+	l.spillLock.Lock()
+	defer l.spillLock.Unlock()
+	l.lock.Lock()
+	events := append([]notificationEvent(nil), l.pending...)
+	if len(events) == 0 {
+		l.lock.Unlock()
+		return
+	}
+	last := events[len(events)-1].Seq
+	if l.spilledFirst == 0 {
+		l.spilledFirst = events[0].Seq
+	}
+	deleteFrom, deleteTo := l.spilledFirst, l.spilledFirst
+	if last-l.spilledFirst >= l.spillSize {
+		deleteTo = last - l.spillSize + 1
+		// the deleted events are not replayed from now
+		if l.firstSeq < deleteTo {
+			l.firstSeq = deleteTo
+		}
+	}
+	l.lock.Unlock()
+	notifications := make([]db.Notification, len(events))
+	var err error
+	for i := range events {
+		notifications[i].Seq = events[i].Seq
+		if notifications[i].Data, err = json.Marshal(&events[i]); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = l.db.StoreNotifications(notifications, deleteFrom, deleteTo)
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	// only spill removes the pending events, the events appended in the meantime stay
+	l.pending = l.pending[len(events):]
+	if err != nil {
+		l.firstSeq = last + 1
+		glog.Error("notificationLog: spill of events up to ", last, " failed: ", err)
+		return
+	}
+	l.spilledFirst = deleteTo
+}
+
+// since returns the events with sequence numbers greater than from and the information
+// if the events are complete, i.e. no event after from has been dropped
+func (l *notificationLog) since(from uint64) ([]notificationEvent, bool) {
+	l.lock.Lock()
+	if from > l.seq {
+		// the sequence number does not come from this log
+		l.lock.Unlock()
+		return nil, false
+	}
+	if from == l.seq {
+		l.lock.Unlock()
+		return nil, true
+	}
+	if l.firstSeq == 0 || from+1 < l.firstSeq {
+		l.lock.Unlock()
+		return nil, false
+	}
+	// copy the events in memory, the older events are read from db after the lock is released
+	var memory []notificationEvent
+	for i := range l.pending {
+		if l.pending[i].Seq > from {
+			memory = append(memory, l.pending[i])
+		}
+	}
+	for i := 0; i < l.count; i++ {
+		e := &l.ring[(l.head+i)%len(l.ring)]
+		if e.Seq > from {
+			memory = append(memory, *e)
+		}
+	}
+	memoryFirst := l.ring[l.head].Seq
+	if len(l.pending) > 0 {
+		memoryFirst = l.pending[0].Seq
+	}
+	l.lock.Unlock()
+	if from+1 >= memoryFirst {
+		return memory, true
+	}
+	spilled, err := l.db.GetNotifications(from+1, memoryFirst-1)
+	if err != nil {
+		glog.Error("notificationLog: read of spilled events failed: ", err)
+		return nil, false
+	}
+	if uint64(len(spilled)) != memoryFirst-from-1 {
+		// the events were deleted in the meantime
+		return nil, false
+	}
+	events := make([]notificationEvent, len(spilled), len(spilled)+len(memory))
+	for i := range spilled {
+		if err := json.Unmarshal(spilled[i].Data, &events[i]); err != nil {
+			glog.Error("notificationLog: unpack of spilled event ", spilled[i].Seq, " failed: ", err)
+			return nil, false
+		}
+	}
+	return append(events, memory...), true
+}
@@ -15,13 +15,16 @@ import (
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/juju/errors"
 	"github.com/scryptachain/blockbook-scrypta/api"
 	"github.com/scryptachain/blockbook-scrypta/bchain"
 	"github.com/scryptachain/blockbook-scrypta/common"
 	"github.com/scryptachain/blockbook-scrypta/db"
+	"github.com/scryptachain/blockbook-scrypta/tracing"
 )
 
 const txsOnPage = 25
@@ -48,12 +51,16 @@ type PublicServer struct {
 	chainParser      bchain.BlockChainParser
 	mempool          bchain.Mempool
 	api              *api.Worker
-	explorerURL      string
+	explorerURL      atomic.Value
 	internalExplorer bool
 	metrics          *common.Metrics
 	is               *common.InternalState
 	templates        []*template.Template
 	debug            bool
+	limiter          *rateLimiter
+	cache            *responseCache
+	health           *healthChecker
+	logs             *requestLogs
 }
 
 // NewPublicServer creates new public server http interface to blockbook and returns its handle
@@ -94,17 +101,21 @@ func NewPublicServer(binding string, certFiles string, db *db.RocksDB, chain bch
 		chain:            chain,
 		chainParser:      chain.GetChainParser(),
 		mempool:          mempool,
-		explorerURL:      explorerURL,
 		internalExplorer: explorerURL == "",
 		metrics:          metrics,
 		is:               is,
 		debug:            debugMode,
+		health:           newHealthChecker(chain, is),
 	}
+	s.explorerURL.Store(explorerURL)
 	s.templates = s.parseTemplates()
 
 	// map only basic functions, the rest is enabled by method MapFullPublicInterface
 	serveMux.Handle(path+"favicon.ico", http.FileServer(http.Dir("./static/")))
 	serveMux.Handle(path+"static/", http.StripPrefix("/static/", http.FileServer(http.Dir("./static/"))))
+	// health checks for load balancers, available also during the initial sync
+	serveMux.HandleFunc(path+"health/live", s.healthLive)
+	serveMux.HandleFunc(path+"health/ready", s.healthReady)
 	// default handler
 	serveMux.HandleFunc(path, s.htmlTemplateHandler(s.explorerIndex))
 	// default API handler
@@ -186,10 +197,13 @@ func (s *PublicServer) ConnectFullPublicInterface() {
 	serveMux.HandleFunc(path+"api/v2/utxo/", s.jsonHandler(s.apiUtxo, apiV2))
 	serveMux.HandleFunc(path+"api/v2/block/", s.jsonHandler(s.apiBlock, apiV2))
 	serveMux.HandleFunc(path+"api/v2/sendtx/", s.jsonHandler(s.apiSendTx, apiV2))
+	serveMux.HandleFunc(path+"api/v2/decodetx/", s.jsonHandler(s.apiDecodeTx, apiV2))
+	serveMux.HandleFunc(path+"api/v2/psbt/", s.jsonHandler(s.apiPsbt, apiV2))
 	serveMux.HandleFunc(path+"api/v2/estimatefee/", s.jsonHandler(s.apiEstimateFee, apiV2))
 	serveMux.HandleFunc(path+"api/v2/feestats/", s.jsonHandler(s.apiFeeStats, apiV2))
 	serveMux.HandleFunc(path+"api/v2/balancehistory/", s.jsonHandler(s.apiBalanceHistory, apiDefault))
 	serveMux.HandleFunc(path+"api/v2/tickers/", s.jsonHandler(s.apiTickers, apiV2))
+	serveMux.HandleFunc(path+"api/v2/tickers/ohlc", s.jsonHandler(s.apiTickersOHLC, apiV2))
 	serveMux.HandleFunc(path+"api/v2/tickers-list/", s.jsonHandler(s.apiTickersList, apiV2))
 	serveMux.HandleFunc(path+"api/v2/masternodes/", s.jsonHandler(s.apiMasternodesList, apiV2))
 	// socket.io interface
@@ -210,8 +224,57 @@ func (s *PublicServer) Shutdown(ctx context.Context) error {
 	return s.https.Shutdown(ctx)
 }
 
+// SetRateLimit enables the access control of the public API, the explorer, the websocket and the socket.io interface
+// It must be called before the server starts to accept connections
+func (s *PublicServer) SetRateLimit(config RateLimitConfig) {
+	s.limiter = newRateLimiter(s.db, s.metrics, config)
+	s.websocket.limiter = s.limiter
+	s.socketio.limiter = s.limiter
+}
+
+// UpdateRateLimit changes the limits of the clients without API key and the maximum number of subscriptions
+// of the running server, the IPHeader of the config is ignored
+func (s *PublicServer) UpdateRateLimit(config RateLimitConfig) {
+	s.limiter.setLimits(config)
+}
+
+// SetRequestLogs enables the access log of the API and websocket requests and the audit log of the sent transactions, nil log is disabled
+// It must be called before the server starts to accept connections
+func (s *PublicServer) SetRequestLogs(access, audit *common.JSONLog) {
+	s.logs = newRequestLogs(access, audit)
+	s.websocket.logs = s.logs
+	s.socketio.logs = s.logs
+}
+
+// clientIP returns the ip of the client, from the header set by the reverse proxy if configured
+func (s *PublicServer) clientIP(r *http.Request) string {
+	if s.limiter != nil {
+		ip, _ := s.limiter.client(r)
+		return ip
+	}
+	return remoteIP(r.RemoteAddr)
+}
+
+// SetResponseCache enables the cache of the public API responses
+// It must be called before the server starts to accept connections
+func (s *PublicServer) SetResponseCache(config ResponseCacheConfig) {
+	if config.Size <= 0 {
+		s.cache = nil
+		return
+	}
+	s.cache = newResponseCache(config, s.metrics)
+}
+
+// SetNotificationLog enables the resume of websocket subscriptions after reconnect, see WebsocketServer.SetNotificationLog
+func (s *PublicServer) SetNotificationLog(size int, spillSize int) error {
+	return s.websocket.SetNotificationLog(size, spillSize)
+}
+
 // OnNewBlock notifies users subscribed to bitcoind/hashblock about new block
 func (s *PublicServer) OnNewBlock(hash string, height uint32) {
+	if s.cache != nil {
+		s.cache.onNewBlock(height)
+	}
 	s.socketio.OnNewBlockHash(hash)
 	s.websocket.OnNewBlock(hash, height)
 }
@@ -228,16 +291,29 @@ func (s *PublicServer) OnNewTxAddr(tx *bchain.Tx, desc bchain.AddressDescriptor)
 
 // OnNewTx notifies users subscribed to notification about new tx
 func (s *PublicServer) OnNewTx(tx *bchain.MempoolTx) {
+	if s.cache != nil {
+		s.cache.onNewTx(tx, s.websocket.getTxAddrDescs(tx))
+	}
 	s.websocket.OnNewTx(tx)
 }
 
+// SetExplorerURL changes the address of the external blockchain explorer
+// The switch between the internal and an external explorer is not possible without restart
+func (s *PublicServer) SetExplorerURL(explorerURL string) error {
+	if s.internalExplorer != (explorerURL == "") {
+		return errors.New("switch between internal and external explorer requires restart")
+	}
+	s.explorerURL.Store(explorerURL)
+	return nil
+}
+
 func (s *PublicServer) txRedirect(w http.ResponseWriter, r *http.Request) {
-	http.Redirect(w, r, joinURL(s.explorerURL, r.URL.Path), 302)
+	http.Redirect(w, r, joinURL(s.explorerURL.Load().(string), r.URL.Path), 302)
 	s.metrics.ExplorerViews.With(common.Labels{"action": "tx-redirect"}).Inc()
 }
 
 func (s *PublicServer) addressRedirect(w http.ResponseWriter, r *http.Request) {
-	http.Redirect(w, r, joinURL(s.explorerURL, r.URL.Path), 302)
+	http.Redirect(w, r, joinURL(s.explorerURL.Load().(string), r.URL.Path), 302)
 	s.metrics.ExplorerViews.With(common.Labels{"action": "address-redirect"}).Inc()
 }
 
@@ -268,9 +344,63 @@ func (s *PublicServer) jsonHandler(handler func(r *http.Request, apiVersion int)
 		Text       string `json:"error"`
 		HTTPStatus int    `json:"-"`
 	}
+	// the name of the handler method, e.g. "apiTx", is used in the tracing spans, metrics and in the access log
+	name := getFunctionName(handler)
+	name = strings.TrimSuffix(name[strings.LastIndexByte(name, '.')+1:], "-fm")
+	spanName := "http " + name
 	return func(w http.ResponseWriter, r *http.Request) {
 		var data interface{}
 		var err error
+		var cacheKey string
+		var cacheGeneration uint64
+		var cached, immutable bool
+		start := time.Now()
+		if s.logs != nil {
+			requestID := httpRequestID(r)
+			w.Header().Set(requestIDHeader, requestID)
+			r = r.WithContext(context.WithValue(r.Context(), requestIDKey{}, requestID))
+		}
+		ctx, span := tracing.Start(tracing.Extract(r.Context(), r.Header), spanName, tracing.KindServer)
+		lw := &logResponseWriter{ResponseWriter: w}
+		w = lw
+		// registered before the writing of the response, runs after it
+		defer func() {
+			if s.metrics != nil {
+				s.metrics.APIReqDuration.With(common.Labels{"handler": name, "status": strconv.Itoa(lw.status)}).Observe(float64(time.Since(start)) / 1e6) // in milliseconds
+			}
+			if !s.logs.accessEnabled() {
+				return
+			}
+			e := accessLogEntry{
+				RequestID:  requestIDFromContext(r.Context()),
+				TraceID:    span.TraceID(),
+				Interface:  "api",
+				ClientIP:   s.clientIP(r),
+				HTTPMethod: r.Method,
+				Method:     name,
+				Params:     redactedQuery(r.URL),
+				Status:     lw.status,
+				Size:       lw.size,
+			}
+			if je, isError := data.(jsonError); isError {
+				e.Error = je.Text
+			}
+			s.logs.logAccess(&e, start)
+		}()
+		if span != nil {
+			r = r.WithContext(ctx)
+			span.SetAttribute("http.method", r.Method)
+			span.SetAttribute("http.target", r.URL.RequestURI())
+			// registered before the writing of the response, runs after it
+			defer func() {
+				if e, isError := data.(jsonError); isError {
+					span.SetAttribute("http.status_code", e.HTTPStatus)
+					span.SetError(errors.New(e.Text))
+				}
+				span.SetAttribute("cached", cached)
+				span.End()
+			}()
+		}
 		defer func() {
 			if e := recover(); e != nil {
 				glog.Error(getFunctionName(handler), " recovered from panic: ", e)
@@ -284,12 +414,29 @@ func (s *PublicServer) jsonHandler(handler func(r *http.Request, apiVersion int)
 			w.Header().Set("Content-Type", "application/json; charset=utf-8")
 			if e, isError := data.(jsonError); isError {
 				w.WriteHeader(e.HTTPStatus)
+			} else if s.cache != nil {
+				s.writeCachedJSON(w, r, data, cacheKey, cacheGeneration, cached, immutable)
+				return
 			}
 			err = json.NewEncoder(w).Encode(data)
 			if err != nil {
 				glog.Warning("json encode ", err)
 			}
 		}()
+		if ae := s.checkRateLimit(w, r, "api"); ae != nil {
+			data = jsonError{ae.Error(), ae.status}
+			return
+		}
+		if s.cache != nil {
+			if cacheKey = getCacheKey(r); cacheKey != "" {
+				_, bestHeight, _ := s.is.GetSyncState()
+				if data, immutable, cached = s.cache.get(cacheKey, bestHeight); cached {
+					return
+				}
+				// the result is not stored if the cache is invalidated while it is computed
+				cacheGeneration = s.cache.generation()
+			}
+		}
 		data, err = handler(r, apiVersion)
 		if err != nil || data == nil {
 			if apiErr, ok := err.(*api.APIError); ok {
@@ -316,6 +463,29 @@ func (s *PublicServer) jsonHandler(handler func(r *http.Request, apiVersion int)
 	}
 }
 
+// writeCachedJSON writes the result with ETag and Cache-Control headers and stores the result computed
+// in the cache generation to the cache
+func (s *PublicServer) writeCachedJSON(w http.ResponseWriter, r *http.Request, data interface{}, cacheKey string, generation uint64, cached, immutable bool) {
+	var tags []string
+	cacheable := cached
+	if !cached && cacheKey != "" {
+		immutable, tags, cacheable = s.cache.classify(r.URL.Path, data, s.chainParser)
+	}
+	buf, notModified, err := encodeWithETag(w, r, data, s.cache.cacheControl(cacheable, immutable))
+	if err != nil {
+		glog.Warning("json encode ", err)
+		return
+	}
+	if !cached && cacheable {
+		s.cache.store(cacheKey, data, len(buf), immutable, tags, generation)
+	}
+	if notModified {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Write(buf)
+}
+
 func (s *PublicServer) newTemplateData() *TemplateData {
 	return &TemplateData{
 		CoinName:         s.is.Coin,
@@ -338,6 +508,7 @@ func (s *PublicServer) htmlTemplateHandler(handler func(w http.ResponseWriter, r
 		var t tpl
 		var data *TemplateData
 		var err error
+		var status int
 		defer func() {
 			if e := recover(); e != nil {
 				glog.Error(getFunctionName(handler), " recovered from panic: ", e)
@@ -355,6 +526,8 @@ func (s *PublicServer) htmlTemplateHandler(handler func(w http.ResponseWriter, r
 				// return 500 Internal Server Error with errorInternalTpl
 				if t == errorInternalTpl {
 					w.WriteHeader(http.StatusInternalServerError)
+				} else if status != 0 {
+					w.WriteHeader(status)
 				}
 				if err := s.templates[t].ExecuteTemplate(w, "base.html", data); err != nil {
 					glog.Error(err)
@@ -366,6 +539,12 @@ func (s *PublicServer) htmlTemplateHandler(handler func(w http.ResponseWriter, r
 			// to reflect changes during development
 			s.templates = s.parseTemplates()
 		}
+		if ae := s.checkRateLimit(w, r, "explorer"); ae != nil {
+			t, status = errorTpl, ae.status
+			data = s.newTemplateData()
+			data.Error = &api.APIError{Text: ae.Error(), Public: true}
+			return
+		}
 		t, data, err = handler(w, r)
 		if err != nil || (data == nil && t != noTpl) {
 			t = errorInternalTpl
@@ -430,6 +609,7 @@ type TemplateData struct {
 	PageParams           template.URL
 	TOSLink              string
 	SendTxHex            string
+	DecodeTx             bool
 	Status               string
 	NonZeroBalanceTokens bool
 }
@@ -488,14 +668,15 @@ func (s *PublicServer) parseTemplates() []*template.Template {
 	t[errorInternalTpl] = createTemplate("./static/templates/error.html", "./static/templates/base.html")
 	t[indexTpl] = createTemplate("./static/templates/index.html", "./static/templates/base.html")
 	t[blocksTpl] = createTemplate("./static/templates/blocks.html", "./static/templates/paging.html", "./static/templates/base.html")
-	t[sendTransactionTpl] = createTemplate("./static/templates/sendtx.html", "./static/templates/base.html")
 	t[masternodesTpl] = createTemplate("./static/templates/masternodes.html", "./static/templates/base.html")
 	if s.chainParser.GetChainType() == bchain.ChainEthereumType {
 		t[txTpl] = createTemplate("./static/templates/tx.html", "./static/templates/txdetail_ethereumtype.html", "./static/templates/base.html")
+		t[sendTransactionTpl] = createTemplate("./static/templates/sendtx.html", "./static/templates/txdetail_ethereumtype.html", "./static/templates/base.html")
 		t[addressTpl] = createTemplate("./static/templates/address.html", "./static/templates/txdetail_ethereumtype.html", "./static/templates/paging.html", "./static/templates/base.html")
 		t[blockTpl] = createTemplate("./static/templates/block.html", "./static/templates/txdetail_ethereumtype.html", "./static/templates/paging.html", "./static/templates/base.html")
 	} else {
 		t[txTpl] = createTemplate("./static/templates/tx.html", "./static/templates/txdetail.html", "./static/templates/base.html")
+		t[sendTransactionTpl] = createTemplate("./static/templates/sendtx.html", "./static/templates/txdetail.html", "./static/templates/base.html")
 		t[addressTpl] = createTemplate("./static/templates/address.html", "./static/templates/txdetail.html", "./static/templates/paging.html", "./static/templates/base.html")
 		t[blockTpl] = createTemplate("./static/templates/block.html", "./static/templates/txdetail.html", "./static/templates/paging.html", "./static/templates/base.html")
 	}
@@ -821,14 +1002,29 @@ func (s *PublicServer) explorerSearch(w http.ResponseWriter, r *http.Request) (t
 func (s *PublicServer) explorerSendTx(w http.ResponseWriter, r *http.Request) (tpl, *TemplateData, error) {
 	s.metrics.ExplorerViews.With(common.Labels{"action": "sendtx"}).Inc()
 	data := s.newTemplateData()
+	data.DecodeTx = r.URL.Query().Get("tab") == "decode"
 	if r.Method == http.MethodPost {
 		err := r.ParseForm()
 		if err != nil {
 			return sendTransactionTpl, data, err
 		}
 		hex := r.FormValue("hex")
-		if len(hex) > 0 {
-			res, err := s.chain.SendRawTransaction(hex)
+		if data.DecodeTx {
+			if len(hex) > 0 {
+				data.SendTxHex = hex
+				tx, err := s.apiWorker(r).DecodeTransaction(hex)
+				if err != nil {
+					if apiErr, ok := err.(*api.APIError); ok {
+						data.Error = apiErr
+						return sendTransactionTpl, data, nil
+					}
+					return errorTpl, nil, err
+				}
+				data.Tx = tx
+			}
+		} else if len(hex) > 0 {
+			res, err := s.api.SendTransaction(hex)
+			s.logs.logSendTx("explorer", "", s.clientIP(r), hex, res, err)
 			if err != nil {
 				data.SendTxHex = hex
 				data.Error = &api.APIError{Text: err.Error(), Public: true}
@@ -923,9 +1119,14 @@ func getPagingRange(page int, total int) ([]int, int, int) {
 	return r, pp, np
 }
 
+// apiWorker returns the api worker recording the tracing spans of the request
+func (s *PublicServer) apiWorker(r *http.Request) *api.Worker {
+	return s.api.WithContext(r.Context())
+}
+
 func (s *PublicServer) apiIndex(r *http.Request, apiVersion int) (interface{}, error) {
 	s.metrics.ExplorerViews.With(common.Labels{"action": "api-index"}).Inc()
-	return s.api.GetSystemInfo(false)
+	return s.apiWorker(r).GetSystemInfo(false)
 }
 
 func (s *PublicServer) apiBlockIndex(r *http.Request, apiVersion int) (interface{}, error) {
@@ -974,10 +1175,13 @@ func (s *PublicServer) apiTx(r *http.Request, apiVersion int) (interface{}, erro
 			return nil, api.NewAPIError("Parameter 'spending' cannot be converted to boolean", true)
 		}
 	}
-	tx, err = s.api.GetTransaction(txid, spendingTxs, false)
+	tx, err = s.apiWorker(r).GetTransaction(txid, spendingTxs, false)
 	if err == nil && apiVersion == apiV1 {
 		return s.api.TxToV1(tx), nil
 	}
+	if currency := r.URL.Query().Get("currency"); err == nil && currency != "" {
+		err = s.api.SetTxFiat(tx, currency)
+	}
 	return tx, err
 }
 
@@ -1010,10 +1214,13 @@ func (s *PublicServer) apiAddress(r *http.Request, apiVersion int) (interface{},
 	var err error
 	s.metrics.ExplorerViews.With(common.Labels{"action": "api-address"}).Inc()
 	page, pageSize, details, filter, _, _ := s.getAddressQueryParams(r, api.AccountDetailsTxidHistory, txsInAPI)
-	address, err = s.api.GetAddress(addressParam, page, pageSize, details, filter)
+	address, err = s.apiWorker(r).GetAddress(addressParam, page, pageSize, details, filter)
 	if err == nil && apiVersion == apiV1 {
 		return s.api.AddressToV1(address), nil
 	}
+	if currency := r.URL.Query().Get("currency"); err == nil && currency != "" {
+		err = s.api.SetAddressFiat(address, currency)
+	}
 	return address, err
 }
 
@@ -1030,13 +1237,16 @@ func (s *PublicServer) apiXpub(r *http.Request, apiVersion int) (interface{}, er
 	var err error
 	s.metrics.ExplorerViews.With(common.Labels{"action": "api-xpub"}).Inc()
 	page, pageSize, details, filter, _, gap := s.getAddressQueryParams(r, api.AccountDetailsTxidHistory, txsInAPI)
-	address, err = s.api.GetXpubAddress(xpub, page, pageSize, details, filter, gap)
+	address, err = s.apiWorker(r).GetXpubAddress(xpub, page, pageSize, details, filter, gap)
 	if err == nil && apiVersion == apiV1 {
 		return s.api.AddressToV1(address), nil
 	}
 	if err == api.ErrUnsupportedXpub {
 		err = api.NewAPIError("XPUB functionality is not supported", true)
 	}
+	if currency := r.URL.Query().Get("currency"); err == nil && currency != "" {
+		err = s.api.SetXpubFiat(address, xpub, gap, currency)
+	}
 	return address, err
 }
 
@@ -1056,16 +1266,19 @@ func (s *PublicServer) apiUtxo(r *http.Request, apiVersion int) (interface{}, er
 		if ec != nil {
 			gap = 0
 		}
-		utxo, err = s.api.GetXpubUtxo(r.URL.Path[i+1:], onlyConfirmed, gap)
+		utxo, err = s.apiWorker(r).GetXpubUtxo(r.URL.Path[i+1:], onlyConfirmed, gap)
 		if err == nil {
 			s.metrics.ExplorerViews.With(common.Labels{"action": "api-xpub-utxo"}).Inc()
 		} else {
-			utxo, err = s.api.GetAddressUtxo(r.URL.Path[i+1:], onlyConfirmed)
+			utxo, err = s.apiWorker(r).GetAddressUtxo(r.URL.Path[i+1:], onlyConfirmed)
 			s.metrics.ExplorerViews.With(common.Labels{"action": "api-address-utxo"}).Inc()
 		}
 		if err == nil && apiVersion == apiV1 {
 			return s.api.AddressUtxoToV1(utxo), nil
 		}
+		if currency := r.URL.Query().Get("currency"); err == nil && currency != "" {
+			err = s.api.SetUtxosFiat(utxo, currency)
+		}
 	}
 	return utxo, err
 }
@@ -1103,11 +1316,11 @@ func (s *PublicServer) apiBalanceHistory(r *http.Request, apiVersion int) (inter
 		if fiat != "" {
 			fiatArray = []string{fiat}
 		}
-		history, err = s.api.GetXpubBalanceHistory(r.URL.Path[i+1:], fromTimestamp, toTimestamp, fiatArray, gap, uint32(groupBy))
+		history, err = s.apiWorker(r).GetXpubBalanceHistory(r.URL.Path[i+1:], fromTimestamp, toTimestamp, fiatArray, gap, uint32(groupBy))
 		if err == nil {
 			s.metrics.ExplorerViews.With(common.Labels{"action": "api-xpub-balancehistory"}).Inc()
 		} else {
-			history, err = s.api.GetBalanceHistory(r.URL.Path[i+1:], fromTimestamp, toTimestamp, fiatArray, uint32(groupBy))
+			history, err = s.apiWorker(r).GetBalanceHistory(r.URL.Path[i+1:], fromTimestamp, toTimestamp, fiatArray, uint32(groupBy))
 			s.metrics.ExplorerViews.With(common.Labels{"action": "api-address-balancehistory"}).Inc()
 		}
 	}
@@ -1123,10 +1336,13 @@ func (s *PublicServer) apiBlock(r *http.Request, apiVersion int) (interface{}, e
 		if ec != nil {
 			page = 0
 		}
-		block, err = s.api.GetBlock(r.URL.Path[i+1:], page, txsInAPI)
+		block, err = s.apiWorker(r).GetBlock(r.URL.Path[i+1:], page, txsInAPI)
 		if err == nil && apiVersion == apiV1 {
 			return s.api.BlockToV1(block), nil
 		}
+		if currency := r.URL.Query().Get("currency"); err == nil && currency != "" {
+			err = s.api.SetBlockFiat(block, currency)
+		}
 	}
 	return block, err
 }
@@ -1136,7 +1352,7 @@ func (s *PublicServer) apiFeeStats(r *http.Request, apiVersion int) (interface{}
 	var err error
 	s.metrics.ExplorerViews.With(common.Labels{"action": "api-feestats"}).Inc()
 	if i := strings.LastIndexByte(r.URL.Path, '/'); i > 0 {
-		feeStats, err = s.api.GetFeeStats(r.URL.Path[i+1:])
+		feeStats, err = s.apiWorker(r).GetFeeStats(r.URL.Path[i+1:])
 	}
 	return feeStats, err
 }
@@ -1162,15 +1378,52 @@ func (s *PublicServer) apiSendTx(r *http.Request, apiVersion int) (interface{},
 		}
 	}
 	if len(hex) > 0 {
-		res.Result, err = s.chain.SendRawTransaction(hex)
+		// in testaccept mode only validate the transaction and return diagnostics, do not broadcast
+		if testAccept, _ := strconv.ParseBool(r.URL.Query().Get("testaccept")); testAccept {
+			return s.apiWorker(r).ValidateTransaction(hex)
+		}
+		res.Result, err = s.apiWorker(r).SendTransaction(hex)
+		s.logs.logSendTx("api", requestIDFromContext(r.Context()), s.clientIP(r), hex, res.Result, err)
 		if err != nil {
-			return nil, api.NewAPIError(err.Error(), true)
+			return nil, err
 		}
 		return res, nil
 	}
 	return nil, api.NewAPIError("Missing tx blob", true)
 }
 
+func (s *PublicServer) apiDecodeTx(r *http.Request, apiVersion int) (interface{}, error) {
+	var hex string
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-decodetx"}).Inc()
+	if r.Method == http.MethodPost {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, api.NewAPIError("Missing tx blob", true)
+		}
+		hex = strings.TrimSpace(string(data))
+	} else {
+		if i := strings.LastIndexByte(r.URL.Path, '/'); i > 0 {
+			hex = r.URL.Path[i+1:]
+		}
+	}
+	if len(hex) > 0 {
+		return s.apiWorker(r).DecodeTransaction(hex)
+	}
+	return nil, api.NewAPIError("Missing tx blob", true)
+}
+
+func (s *PublicServer) apiPsbt(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-psbt"}).Inc()
+	if r.Method != http.MethodPost {
+		return nil, api.NewAPIError("Use POST method with request in JSON format", true)
+	}
+	var req api.PsbtRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, api.NewAPIError("Invalid request: "+err.Error(), true)
+	}
+	return s.apiWorker(r).CreatePsbt(&req)
+}
+
 // apiMasternodesList returns a list of available Masternodes
 
 type resultMasternodeList struct {
@@ -1195,7 +1448,7 @@ func (s *PublicServer) apiTickersList(r *http.Request, apiVersion int) (interfac
 	if err != nil {
 		return nil, api.NewAPIError("Parameter \"timestamp\" is not a valid Unix timestamp.", true)
 	}
-	result, err := s.api.GetFiatRatesTickersList(timestamp)
+	result, err := s.apiWorker(r).GetFiatRatesTickersList(timestamp)
 	return result, err
 }
 
@@ -1213,7 +1466,7 @@ func (s *PublicServer) apiTickers(r *http.Request, apiVersion int) (interface{},
 	if block := r.URL.Query().Get("block"); block != "" {
 		// Get tickers for specified block height or block hash
 		s.metrics.ExplorerViews.With(common.Labels{"action": "api-tickers-block"}).Inc()
-		result, err = s.api.GetFiatRatesForBlockID(block, currencies)
+		result, err = s.apiWorker(r).GetFiatRatesForBlockID(block, currencies)
 	} else if timestampString := r.URL.Query().Get("timestamp"); timestampString != "" {
 		// Get tickers for specified timestamp
 		s.metrics.ExplorerViews.With(common.Labels{"action": "api-tickers-date"}).Inc()
@@ -1223,7 +1476,7 @@ func (s *PublicServer) apiTickers(r *http.Request, apiVersion int) (interface{},
 			return nil, api.NewAPIError("Parameter \"timestamp\" is not a valid Unix timestamp.", true)
 		}
 
-		resultTickers, err := s.api.GetFiatRatesForTimestamps([]int64{timestamp}, currencies)
+		resultTickers, err := s.apiWorker(r).GetFiatRatesForTimestamps([]int64{timestamp}, currencies)
 		if err != nil {
 			return nil, err
 		}
@@ -1231,7 +1484,7 @@ func (s *PublicServer) apiTickers(r *http.Request, apiVersion int) (interface{},
 	} else {
 		// No parameters - get the latest available ticker
 		s.metrics.ExplorerViews.With(common.Labels{"action": "api-tickers-last"}).Inc()
-		result, err = s.api.GetCurrentFiatRates(currencies)
+		result, err = s.apiWorker(r).GetCurrentFiatRates(currencies)
 	}
 	if err != nil {
 		return nil, err
@@ -1239,6 +1492,23 @@ func (s *PublicServer) apiTickers(r *http.Request, apiVersion int) (interface{},
 	return result, nil
 }
 
+func (s *PublicServer) apiTickersOHLC(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-tickers-ohlc"}).Inc()
+	var from, to int64
+	var err error
+	if p := r.URL.Query().Get("from"); p != "" {
+		if from, err = strconv.ParseInt(p, 10, 64); err != nil {
+			return nil, api.NewAPIError("Parameter \"from\" is not a valid Unix timestamp.", true)
+		}
+	}
+	if p := r.URL.Query().Get("to"); p != "" {
+		if to, err = strconv.ParseInt(p, 10, 64); err != nil {
+			return nil, api.NewAPIError("Parameter \"to\" is not a valid Unix timestamp.", true)
+		}
+	}
+	return s.apiWorker(r).GetFiatRatesOHLC(r.URL.Query().Get("currency"), r.URL.Query().Get("interval"), from, to)
+}
+
 type resultEstimateFeeAsString struct {
 	Result string `json:"result"`
 }
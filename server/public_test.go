@@ -3,27 +3,49 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/golang/glog"
 	"github.com/gorilla/websocket"
+	"github.com/martinboehm/btcd/chaincfg/chainhash"
+	"github.com/martinboehm/btcd/wire"
 	"github.com/martinboehm/btcutil/chaincfg"
 	gosocketio "github.com/martinboehm/golang-socketio"
 	"github.com/martinboehm/golang-socketio/transport"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scryptachain/blockbook-scrypta/api"
 	"github.com/scryptachain/blockbook-scrypta/bchain"
 	"github.com/scryptachain/blockbook-scrypta/bchain/coins/btc"
 	"github.com/scryptachain/blockbook-scrypta/common"
 	"github.com/scryptachain/blockbook-scrypta/db"
+	"github.com/scryptachain/blockbook-scrypta/grpcapi"
 	"github.com/scryptachain/blockbook-scrypta/tests/dbtestdata"
+	"github.com/scryptachain/blockbook-scrypta/tracing"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestMain(m *testing.M) {
@@ -166,39 +188,51 @@ func insertFiatRate(date string, rates map[string]float64, d *db.RocksDB) error
 	return d.FiatRatesStoreTicker(ticker)
 }
 
+// insertFiatRateWithOHLC stores the ticker and adds its rates to the OHLC candles
+func insertFiatRateWithOHLC(date string, rates map[string]float64, d *db.RocksDB) error {
+	if err := insertFiatRate(date, rates, d); err != nil {
+		return err
+	}
+	convertedDate, err := db.FiatRatesConvertDate(date)
+	if err != nil {
+		return err
+	}
+	return d.FiatRatesUpdateOHLC(&db.CurrencyRatesTicker{Timestamp: convertedDate, Rates: rates})
+}
+
 // InitTestFiatRates initializes test data for /api/v2/tickers endpoint
 func InitTestFiatRates(d *db.RocksDB) error {
-	if err := insertFiatRate("20180320020000", map[string]float64{
+	if err := insertFiatRateWithOHLC("20180320020000", map[string]float64{
 		"usd": 2000.0,
 		"eur": 1300.0,
 	}, d); err != nil {
 		return err
 	}
-	if err := insertFiatRate("20180320030000", map[string]float64{
+	if err := insertFiatRateWithOHLC("20180320030000", map[string]float64{
 		"usd": 2001.0,
 		"eur": 1301.0,
 	}, d); err != nil {
 		return err
 	}
-	if err := insertFiatRate("20180320040000", map[string]float64{
+	if err := insertFiatRateWithOHLC("20180320040000", map[string]float64{
 		"usd": 2002.0,
 		"eur": 1302.0,
 	}, d); err != nil {
 		return err
 	}
-	if err := insertFiatRate("20180321055521", map[string]float64{
+	if err := insertFiatRateWithOHLC("20180321055521", map[string]float64{
 		"usd": 2003.0,
 		"eur": 1303.0,
 	}, d); err != nil {
 		return err
 	}
-	if err := insertFiatRate("20191121140000", map[string]float64{
+	if err := insertFiatRateWithOHLC("20191121140000", map[string]float64{
 		"usd": 7814.5,
 		"eur": 7100.0,
 	}, d); err != nil {
 		return err
 	}
-	return insertFiatRate("20191121143015", map[string]float64{
+	return insertFiatRateWithOHLC("20191121143015", map[string]float64{
 		"usd": 7914.5,
 		"eur": 7134.1,
 	}, d)
@@ -491,6 +525,24 @@ func httpTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 				`{"txid":"05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","vin":[{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":2,"n":0,"addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"isAddress":true,"value":"9876"}],"vout":[{"value":"9000","n":0,"hex":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"isAddress":true}],"blockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockHeight":225494,"confirmations":1,"blockTime":1521595678,"value":"9000","valueIn":"9876","fees":"876"}`,
 			},
 		},
+		{
+			name:        "apiTx v2 currency=usd",
+			r:           newGetRequest(ts.URL + "/api/v2/tx/05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07?currency=usd"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"txid":"05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","vin":[{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":2,"n":0,"addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"isAddress":true,"value":"9876","fiat":{"value":0.19781627999999998,"currentValue":0.78163602}}],"vout":[{"value":"9000","n":0,"hex":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"isAddress":true,"fiat":{"value":0.18027,"currentValue":0.7123050000000001}}],"blockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockHeight":225494,"confirmations":1,"blockTime":1521595678,"value":"9000","valueIn":"9876","fees":"876","fiat":{"currency":"usd","rate":2003,"currentRate":7914.5,"value":{"value":0.18027,"currentValue":0.7123050000000001},"valueIn":{"value":0.19781627999999998,"currentValue":0.78163602},"fees":{"value":0.01754628,"currentValue":0.06933102000000001}}}`,
+			},
+		},
+		{
+			name:        "apiTx v2 currency=does_not_exist",
+			r:           newGetRequest(ts.URL + "/api/v2/tx/05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07?currency=does_not_exist"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Currency 'does_not_exist' is not available"}`,
+			},
+		},
 		{
 			name:        "apiTx - not found v2",
 			r:           newGetRequest(ts.URL + "/api/v2/tx/1232e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07"),
@@ -617,6 +669,24 @@ func httpTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 				`{"ts":1574346615,"rates":{"does_not_exist":-1}}`,
 			},
 		},
+		{
+			name:        "apiTickersOHLC day",
+			r:           newGetRequest(ts.URL + "/api/v2/tickers/ohlc?currency=usd&interval=day&from=1521504000&to=1521590400"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"currency":"usd","interval":"day","candles":[{"t":1521504000,"o":2000,"h":2002,"l":2000,"c":2002},{"t":1521590400,"o":2003,"h":2003,"l":2003,"c":2003}]}`,
+			},
+		},
+		{
+			name:        "apiTickersOHLC invalid interval",
+			r:           newGetRequest(ts.URL + "/api/v2/tickers/ohlc?currency=usd&interval=month"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Invalid interval month, possible values are hour, day and week"}`,
+			},
+		},
 		{
 			name:        "apiTickerList",
 			r:           newGetRequest(ts.URL + "/api/v2/tickers-list?timestamp=1574346615"),
@@ -860,6 +930,33 @@ func httpTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 				`{"result":"9876"}`,
 			},
 		},
+		{
+			name:        "apiSendTx POST testaccept",
+			r:           newPostRequest(ts.URL+"/api/v2/sendtx/?testaccept=true", "123456"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"valid":false,"size":3,"value":"0","issues":[{"code":"invalid-tx","severity":"error","message":"Cannot parse transaction:`,
+			},
+		},
+		{
+			name:        "apiDecodeTx POST invalid",
+			r:           newPostRequest(ts.URL+"/api/v2/decodetx/", "123456"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Cannot parse transaction, `,
+			},
+		},
+		{
+			name:        "apiPsbt POST unsupported descriptor",
+			r:           newPostRequest(ts.URL+"/api/v2/psbt/", `{"xpub":"tr(tpubDDsBUCwnzsg4rpAmNbJXn7zUabWUbhGb3BkyGvGHGm8HWxeg5r6DTLmsPNMhAFzgzCQrqfrVXQvDqtuemdoBvJ2qLpShDhHSpkPCSQhXkuh)","outputs":[{"address":"mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw","value":"10000"}],"feeRate":1}`),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Unsupported descriptor type tr"}`,
+			},
+		},
 		{
 			name:        "apiSendTx POST empty",
 			r:           newPostRequest(ts.URL+"/api/v2/sendtx", ""),
@@ -1416,6 +1513,47 @@ func websocketTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 			},
 			want: `{"id":"36","data":[{"time":1521514800,"txs":1,"received":"1","sent":"0","sentToSelf":"0","rates":{"eur":1301,"usd":2001}}]}`,
 		},
+		{
+			name: "websocket getFiatRatesOHLC",
+			req: websocketReq{
+				Method: "getFiatRatesOHLC",
+				Params: map[string]interface{}{
+					"currency": "eur",
+					"interval": "week",
+					"from":     1574294400,
+					"to":       1574294400,
+				},
+			},
+			want: `{"id":"37","data":{"currency":"eur","interval":"week","candles":[{"t":1574035200,"o":7100,"h":7134.1,"l":7100,"c":7134.1}]}}`,
+		},
+		{
+			name: "websocket subscribeAccounts",
+			req: websocketReq{
+				Method: "subscribeAccounts",
+				Params: map[string]interface{}{
+					"descriptors": []string{dbtestdata.Xpub},
+				},
+			},
+			want: `{"id":"38","data":{"subscribed":true}}`,
+		},
+		{
+			name: "websocket subscribeAccounts missing descriptors",
+			req: websocketReq{
+				Method: "subscribeAccounts",
+				Params: map[string]interface{}{},
+			},
+			want: `{"id":"39","data":{"error":{"message":"Missing descriptors"}}}`,
+		},
+		{
+			name: "websocket subscribeNewBlock resumeFrom without notification log",
+			req: websocketReq{
+				Method: "subscribeNewBlock",
+				Params: map[string]interface{}{
+					"resumeFrom": 1234,
+				},
+			},
+			want: `{"id":"40","data":{"subscribed":true,"replayed":0,"resync":true}}`,
+		},
 	}
 
 	// send all requests at once
@@ -1464,3 +1602,1081 @@ func websocketTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 		t.Error("Timeout while waiting for websocket responses")
 	}
 }
+
+type testTxOutput struct {
+	script string
+	value  int64
+}
+
+// testTxHex returns the hex of the transaction spending the outpoints txid:vout, with witness if segwit is set
+func testTxHex(t *testing.T, inputs []string, outputs []testTxOutput, segwit bool) string {
+	tx := wire.NewMsgTx(2)
+	for _, in := range inputs {
+		i := strings.IndexByte(in, ':')
+		hash, err := chainhash.NewHashFromStr(in[:i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		vout, _ := strconv.Atoi(in[i+1:])
+		txIn := wire.NewTxIn(wire.NewOutPoint(hash, uint32(vout)), nil, nil)
+		if segwit {
+			txIn.Witness = wire.TxWitness{make([]byte, 71), make([]byte, 33)}
+		} else {
+			txIn.SignatureScript = make([]byte, 106)
+		}
+		tx.AddTxIn(txIn)
+	}
+	for _, o := range outputs {
+		script, err := hex.DecodeString(o.script)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.AddTxOut(wire.NewTxOut(o.value, script))
+	}
+	var b bytes.Buffer
+	if err := tx.Serialize(&b); err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(b.Bytes())
+}
+
+func TestValidateTransaction(t *testing.T) {
+	s, dbpath := setupPublicHTTPServer(t)
+	defer closeAndDestroyPublicServer(t, s, dbpath)
+	p2pkh := dbtestdata.AddressToPubKeyHex(dbtestdata.Addr1, s.chainParser)
+	p2wpkh := "00141c12afc6b2602607fdbc209f2a053c54ecd2c673"
+	unspent := dbtestdata.TxidB2T3 + ":0" // 9000 sat
+	tests := []struct {
+		name    string
+		inputs  []string
+		outputs []testTxOutput
+		segwit  bool
+		valid   bool
+		fee     int64
+		issues  []string
+	}{
+		{
+			name:    "fee",
+			inputs:  []string{unspent},
+			outputs: []testTxOutput{{p2pkh, 8000}},
+			valid:   true,
+			fee:     1000,
+		},
+		{
+			name:    "fee rate by vsize",
+			inputs:  []string{unspent},
+			outputs: []testTxOutput{{p2pkh, 8000}},
+			segwit:  true,
+			valid:   true,
+			fee:     1000,
+		},
+		{
+			name:    "negative fee",
+			inputs:  []string{unspent},
+			outputs: []testTxOutput{{p2pkh, 10000}},
+			valid:   false,
+			fee:     -1000,
+			issues:  []string{"negative-fee error"},
+		},
+		{
+			name:    "absurd fee",
+			inputs:  []string{dbtestdata.TxidB2T1 + ":1"},
+			outputs: []testTxOutput{{p2pkh, 1000}},
+			valid:   false,
+			fee:     917283950061,
+			issues:  []string{"absurd-fee error"},
+		},
+		{
+			name:    "dust by the script type",
+			inputs:  []string{unspent},
+			outputs: []testTxOutput{{p2pkh, 8000}, {p2pkh, 500}, {p2wpkh, 300}, {dbtestdata.TxidB2T1Output3OpReturn, 0}},
+			valid:   true,
+			fee:     200,
+			issues:  []string{"dust-output warning output 1"},
+		},
+		{
+			name:    "double spend of the spent output",
+			inputs:  []string{dbtestdata.TxidB1T2 + ":0"},
+			outputs: []testTxOutput{{p2pkh, 1234567890000}},
+			valid:   false,
+			fee:     123,
+			issues:  []string{"spent-input error input 0"},
+		},
+		{
+			name:    "double spend of the same outpoint",
+			inputs:  []string{unspent, unspent},
+			outputs: []testTxOutput{{p2pkh, 8000}},
+			valid:   false,
+			fee:     1000,
+			issues:  []string{"duplicate-input error input 1"},
+		},
+		{
+			name:    "missing input is left to the backend",
+			inputs:  []string{"1111111111111111111111111111111111111111111111111111111111111111:0", unspent},
+			outputs: []testTxOutput{{p2pkh, 8000}},
+			valid:   true,
+			issues:  []string{"missing-input warning input 0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txHex := testTxHex(t, tt.inputs, tt.outputs, tt.segwit)
+			v, err := s.api.ValidateTransaction(txHex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var issues []string
+			for _, i := range v.Issues {
+				d := i.Code + " " + string(i.Severity)
+				if i.Input != nil {
+					d += " input " + strconv.Itoa(*i.Input)
+				}
+				if i.Output != nil {
+					d += " output " + strconv.Itoa(*i.Output)
+				}
+				issues = append(issues, d)
+			}
+			if v.Valid != tt.valid || !reflect.DeepEqual(issues, tt.issues) {
+				t.Errorf("ValidateTransaction() valid %v, issues %v, want %v, %v", v.Valid, issues, tt.valid, tt.issues)
+			}
+			if v.Size != len(txHex)/2 {
+				t.Errorf("ValidateTransaction() size %v, want %v", v.Size, len(txHex)/2)
+			}
+			if tt.segwit != (v.Vsize < v.Size) {
+				t.Errorf("ValidateTransaction() vsize %v, size %v", v.Vsize, v.Size)
+			}
+			if v.FeesSat == nil {
+				if tt.fee != 0 {
+					t.Errorf("ValidateTransaction() fees nil, want %v", tt.fee)
+				}
+				return
+			}
+			if fee := (*big.Int)(v.FeesSat).Int64(); fee != tt.fee {
+				t.Errorf("ValidateTransaction() fees %v, want %v", fee, tt.fee)
+			}
+			if tt.fee > 0 {
+				if feePerKb := (*big.Int)(v.FeePerKb).Int64(); feePerKb != tt.fee*1000/int64(v.Vsize) {
+					t.Errorf("ValidateTransaction() feePerKb %v, want %v", feePerKb, tt.fee*1000/int64(v.Vsize))
+				}
+			}
+		})
+	}
+}
+
+func TestNotificationLog(t *testing.T) {
+	l, err := newNotificationLog(nil, 3, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := l.lastSeq()
+	if _, complete := l.since(start); !complete {
+		t.Fatal("since(lastSeq) must be complete")
+	}
+	if _, complete := l.since(start - 1); complete {
+		t.Fatal("since before the first event must not be complete")
+	}
+	for i := 1; i <= 5; i++ {
+		if seq := l.append(notificationEvent{Kind: notificationBlock, Height: uint32(i)}); seq != start+uint64(i) {
+			t.Fatalf("append %d: got seq %d, want %d", i, seq, start+uint64(i))
+		}
+	}
+	// the ring keeps the last 3 events
+	events, complete := l.since(start + 2)
+	if !complete {
+		t.Fatal("since(start+2) must be complete")
+	}
+	var heights []uint32
+	for _, e := range events {
+		heights = append(heights, e.Height)
+	}
+	if !reflect.DeepEqual(heights, []uint32{3, 4, 5}) {
+		t.Errorf("since(start+2) = %v, want [3 4 5]", heights)
+	}
+	events, complete = l.since(start + 4)
+	if !complete || len(events) != 1 || events[0].Seq != start+5 {
+		t.Errorf("since(start+4) = %+v, %v", events, complete)
+	}
+	if _, complete = l.since(start + 1); complete {
+		t.Error("since(start+1) must not be complete, the event start+2 was dropped")
+	}
+	if _, complete = l.since(start + 6); complete {
+		t.Error("since in the future must not be complete")
+	}
+}
+
+// testNotificationStore keeps the spilled notifications in memory and counts the calls made under the lock of the log
+type testNotificationStore struct {
+	l             *notificationLog
+	notifications map[uint64][]byte
+	locked        int
+	deleted       int
+}
+
+func (m *testNotificationStore) checkLock() {
+	if m.l == nil {
+		return
+	}
+	acquired := make(chan struct{})
+	go func() {
+		m.l.lock.Lock()
+		m.l.lock.Unlock()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		m.locked++
+	}
+}
+
+func (m *testNotificationStore) StoreNotifications(notifications []db.Notification, deleteFrom, deleteTo uint64) error {
+	m.checkLock()
+	m.deleted += int(deleteTo - deleteFrom)
+	for seq := deleteFrom; seq < deleteTo; seq++ {
+		delete(m.notifications, seq)
+	}
+	for _, n := range notifications {
+		m.notifications[n.Seq] = n.Data
+	}
+	return nil
+}
+
+func (m *testNotificationStore) GetNotifications(from, to uint64) ([]db.Notification, error) {
+	m.checkLock()
+	var r []db.Notification
+	for seq := from; seq <= to; seq++ {
+		if data, found := m.notifications[seq]; found {
+			r = append(r, db.Notification{Seq: seq, Data: data})
+		}
+	}
+	return r, nil
+}
+
+func (m *testNotificationStore) DeleteNotifications(before uint64) error {
+	m.checkLock()
+	for seq := range m.notifications {
+		if seq < before {
+			delete(m.notifications, seq)
+		}
+	}
+	return nil
+}
+
+func TestNotificationLog_Spill(t *testing.T) {
+	store := &testNotificationStore{notifications: make(map[uint64][]byte)}
+	l, err := newNotificationLog(store, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.l = l
+	start := l.lastSeq()
+	heights := func(events []notificationEvent) []uint32 {
+		var r []uint32
+		for _, e := range events {
+			r = append(r, e.Height)
+		}
+		return r
+	}
+	for i := 1; i <= 5; i++ {
+		l.append(notificationEvent{Kind: notificationBlock, Height: uint32(i)})
+	}
+	// the ring keeps the last 2 events, the older are spilled
+	if len(store.notifications) != 3 {
+		t.Errorf("spilled %d events, want 3", len(store.notifications))
+	}
+	events, complete := l.since(start)
+	if want := []uint32{1, 2, 3, 4, 5}; !complete || !reflect.DeepEqual(heights(events), want) {
+		t.Errorf("since(start) = %v, %v, want %v", heights(events), complete, want)
+	}
+	// the spilled events over the spill size are deleted in the write batch of the spill
+	for i := 6; i <= 1010; i++ {
+		l.append(notificationEvent{Kind: notificationBlock, Height: uint32(i)})
+	}
+	if len(store.notifications) != 3 {
+		t.Errorf("kept %d spilled events, want 3", len(store.notifications))
+	}
+	if store.deleted != 1005 {
+		t.Errorf("deleted %d spilled events, want 1005", store.deleted)
+	}
+	if _, complete = l.since(start + 1004); complete {
+		t.Error("since(start+1004) must not be complete, the events were deleted")
+	}
+	events, complete = l.since(start + 1005)
+	if want := []uint32{1006, 1007, 1008, 1009, 1010}; !complete || !reflect.DeepEqual(heights(events), want) {
+		t.Errorf("since(start+1005) = %v, %v, want %v", heights(events), complete, want)
+	}
+	if _, found := store.notifications[start+1005]; found {
+		t.Error("event start+1005 not deleted from db")
+	}
+	if store.locked > 0 {
+		t.Errorf("db accessed %d times under the lock of the log", store.locked)
+	}
+}
+
+func TestNotificationLog_Interest(t *testing.T) {
+	l, err := newNotificationLog(nil, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a1, a2 := addrTag(bchain.AddressDescriptor("a1")), addrTag(bchain.AddressDescriptor("a2"))
+	if l.logged(a1) {
+		t.Error("key without subscription logged")
+	}
+	l.subscribe(a1, a1)
+	since := l.lastSeq()
+	l.append(notificationEvent{Kind: notificationTx})
+	if !l.logged(a2, a1) {
+		t.Error("subscribed key not logged")
+	}
+	if !l.loggedSince([]string{a1}, since) || l.loggedSince([]string{a1}, since-1) || l.loggedSince([]string{a1, a2}, since) {
+		t.Error("wrong loggedSince of the subscribed key")
+	}
+	// the key stays logged after the unsubscribe of all its subscriptions for the resume window
+	l.unsubscribe(a1)
+	l.unsubscribe(a1)
+	if !l.logged(a1) || !l.loggedSince([]string{a1}, since) {
+		t.Error("key not logged in the resume window")
+	}
+	l.interest[a1].expires = time.Now().Add(-time.Second)
+	if l.logged(a1) || l.loggedSince([]string{a1}, since) {
+		t.Error("key logged after the resume window")
+	}
+	// the subscription after the resume window starts a new range of logged events
+	l.append(notificationEvent{Kind: notificationTx})
+	l.subscribe(a1)
+	if l.loggedSince([]string{a1}, since) || !l.loggedSince([]string{a1}, l.lastSeq()) {
+		t.Error("wrong loggedSince of the subscription after the resume window")
+	}
+	l.unsubscribe(a1)
+	l.interest[a1].expires = time.Now().Add(-time.Second)
+	l.pruneInterest()
+	if len(l.interest) != 0 {
+		t.Errorf("expired keys not pruned: %v", l.interest)
+	}
+	var nilLog *notificationLog
+	nilLog.subscribe(a1)
+	nilLog.unsubscribe(a1)
+}
+
+func TestWebsocketResume(t *testing.T) {
+	s := &WebsocketServer{
+		metrics: &common.Metrics{
+			WebsocketRequests:    prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_websocket_requests"}, []string{"method", "status"}),
+			WebsocketReqDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_websocket_req_duration"}, []string{"method"}),
+		},
+		newBlockSubscriptions: make(map[*websocketChannel]string),
+	}
+	if err := s.SetNotificationLog(10, 0); err != nil {
+		t.Fatal(err)
+	}
+	// the blocks are logged while there is a subscription
+	s.notifications.subscribe(blockTag)
+	start := s.notifications.lastSeq()
+	for i := 1; i <= 2; i++ {
+		s.notifications.append(notificationEvent{Kind: notificationBlock, Height: uint32(i), Hash: "hash" + strconv.Itoa(i)})
+	}
+	s.notifications.unsubscribe(blockTag)
+	c := &websocketChannel{out: make(chan *websocketRes, outChannelSize), alive: true, done: make(chan struct{})}
+	s.onRequest(c, &websocketReq{ID: "1", Method: "subscribeNewBlock", Params: json.RawMessage(`{"resumeFrom":` + strconv.FormatUint(start, 10) + `}`)})
+	if len(c.out) != 3 {
+		t.Fatalf("sent %d messages, want 3", len(c.out))
+	}
+	// the response goes before the replayed notifications
+	m := <-c.out
+	if r, ok := m.Data.(*resumedSubscriptionResponse); !ok || !r.Subscribed || r.Replayed != 2 || r.Seq != start+2 || m.Seq != 0 {
+		t.Fatalf("first message %+v, want the subscribe response", m)
+	}
+	for i := 1; i <= 2; i++ {
+		m = <-c.out
+		if n, ok := m.Data.(*newBlockNotification); !ok || n.Height != uint32(i) || m.Seq != start+uint64(i) || m.ID != "1" {
+			t.Errorf("message %d %+v, want the notification of block %d", i, m, i)
+		}
+	}
+	// the send to the closed channel is refused without panic
+	close(c.done)
+	if c.send("1", 0, nil) {
+		t.Error("send to the closed channel succeeded")
+	}
+}
+
+func TestWebsocketSendOnNewTxAccounts(t *testing.T) {
+	s := &WebsocketServer{
+		accountSubscriptions: make(map[*websocketChannel][]*accountSubscription),
+		accountAddresses:     make(map[string]map[*accountSubscription]struct{}),
+	}
+	// the client does not read its notifications
+	c := &websocketChannel{out: make(chan *websocketRes), alive: true, done: make(chan struct{})}
+	a := &accountAddress{address: "addr", path: "m/0/1", index: 1}
+	as := &accountSubscription{c: c, id: "1", descriptor: "xpub", count: [2]int{10, 10}, addresses: map[string]*accountAddress{"ad": a}}
+	s.accountSubscriptions[c] = []*accountSubscription{as}
+	sent := make(chan struct{})
+	go func() {
+		s.sendOnNewTxAccounts(map[*accountSubscription][]*accountAddress{as: {a}}, &api.Tx{Txid: "txid"}, 1)
+		close(sent)
+	}()
+	// the blocked send must not hold the lock of the subscriptions
+	acquired := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-sent:
+				close(acquired)
+				return
+			default:
+			}
+			s.accountSubscriptionsLock.Lock()
+			blocked := as.lastUsed[0] == 1
+			s.accountSubscriptionsLock.Unlock()
+			if blocked {
+				close(acquired)
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("lock of the account subscriptions held during the send")
+	}
+	select {
+	case <-sent:
+		t.Fatal("notification sent to the client which does not read")
+	default:
+	}
+	close(c.done)
+	<-sent
+}
+
+func TestRateLimiter(t *testing.T) {
+	metrics := &common.Metrics{
+		RateLimitRejections: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_ratelimit_rejections"}, []string{"interface", "reason"}),
+	}
+	l := newRateLimiter(nil, metrics, RateLimitConfig{Rate: 1, Burst: 12, MaxSubscriptions: 2})
+	// the xpub request takes 10 units of the burst
+	if err := l.allow("1.2.3.4", "", accountRequestCost(true, "txids"), "api"); err != nil {
+		t.Fatal("first xpub request rejected: ", err)
+	}
+	if err := l.allow("1.2.3.4", "", costDefault, "api"); err != nil {
+		t.Fatal("cheap request rejected: ", err)
+	}
+	err := l.allow("1.2.3.4", "", accountRequestCost(true, "txs"), "api")
+	if err == nil || err.status != http.StatusTooManyRequests || err.retryAfter <= 0 {
+		t.Fatalf("second xpub request not rejected: %+v", err)
+	}
+	// other clients have their own bucket
+	if err := l.allow("5.6.7.8", "", costXpub, "api"); err != nil {
+		t.Fatal("request of other client rejected: ", err)
+	}
+	// simulate passing time
+	l.buckets["ip:1.2.3.4"].last = time.Now().Add(-20 * time.Second)
+	if err := l.allow("1.2.3.4", "", costXpub, "api"); err != nil {
+		t.Fatal("request after refill rejected: ", err)
+	}
+	if err := l.checkSubscriptions("", 2, "websocket"); err != nil {
+		t.Fatal("subscriptions within limit rejected: ", err)
+	}
+	if err := l.checkSubscriptions("", 3, "websocket"); err == nil || err.Error() != "Too many subscriptions, the limit is 2" {
+		t.Fatalf("subscriptions over limit not rejected: %v", err)
+	}
+	// the limits changed by reload of the configuration apply to the existing buckets
+	l.setLimits(RateLimitConfig{Rate: 1, MaxSubscriptions: 3, IPHeader: "X-Real-Ip"})
+	if l.config.Burst != 1 || l.config.IPHeader != "" {
+		t.Errorf("config after setLimits %+v, want burst 1 and unchanged IPHeader", l.config)
+	}
+	if err := l.checkSubscriptions("", 3, "websocket"); err != nil {
+		t.Fatal("subscriptions within changed limit rejected: ", err)
+	}
+	l.buckets["ip:5.6.7.8"].last = time.Now().Add(-20 * time.Second)
+	if err := l.allow("5.6.7.8", "", costDefault, "api"); err != nil {
+		t.Fatal("request within changed limit rejected: ", err)
+	}
+	if err := l.allow("5.6.7.8", "", costDefault, "api"); err == nil {
+		t.Fatal("request over changed burst not rejected")
+	}
+	var nilLimiter *rateLimiter
+	if err := nilLimiter.allow("1.2.3.4", "", costXpub, "api"); err != nil {
+		t.Fatal("disabled limiter rejected request: ", err)
+	}
+	if got := socketioRequestCost("getAddressHistory", json.RawMessage(`[["a","b"],{"start":0,"end":10}]`)); got != 4 {
+		t.Errorf("socketioRequestCost(getAddressHistory of 2 addresses) = %v, want 4", got)
+	}
+}
+
+func TestRateLimiterAPIKeys(t *testing.T) {
+	metrics := &common.Metrics{
+		RateLimitRejections: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_ratelimit_apikey_rejections"}, []string{"interface", "reason"}),
+	}
+	// the malformed keys are rejected without the lookup in db, which is nil here
+	l := newRateLimiter(nil, metrics, RateLimitConfig{Rate: 1})
+	for _, key := range []string{"key1", "0123456789abcdef", "0123456789ABCDEF0123456789abcdef", "0123456789abcdef0123456789abcdef0"} {
+		if err := l.allow("1.2.3.4", key, costDefault, "api"); err == nil || err.status != http.StatusForbidden {
+			t.Errorf("allow(%v) = %v, want Invalid API key", key, err)
+		}
+		if err := l.checkAPIKey(key, "websocket"); err == nil {
+			t.Errorf("checkAPIKey(%v) not rejected", key)
+		}
+	}
+	if len(l.keys) != 0 {
+		t.Errorf("%d malformed keys cached, want 0", len(l.keys))
+	}
+	// valid keys are cached, when the cache is full, a single entry is removed, the expired entries first
+	now := time.Now()
+	for i := 0; i < maxRateLimitBuckets; i++ {
+		l.cacheAPIKey(fmt.Sprintf("%032x", i), nil, now)
+	}
+	expired := fmt.Sprintf("%032x", 7)
+	l.keys[expired] = cachedAPIKey{loaded: now.Add(-2 * apiKeyCacheTTL)}
+	known := &db.APIKey{Name: "known"}
+	l.cacheAPIKey(fmt.Sprintf("%032x", maxRateLimitBuckets), known, now)
+	if len(l.keys) != maxRateLimitBuckets {
+		t.Fatalf("%d cached keys, want %d", len(l.keys), maxRateLimitBuckets)
+	}
+	if _, found := l.keys[expired]; found {
+		t.Error("expired key not removed from the full cache")
+	}
+	if got := l.getAPIKey(fmt.Sprintf("%032x", maxRateLimitBuckets)); got != known {
+		t.Errorf("getAPIKey = %+v, want the cached key", got)
+	}
+	l.cacheAPIKey(fmt.Sprintf("%032x", maxRateLimitBuckets+1), nil, now)
+	if len(l.keys) != maxRateLimitBuckets {
+		t.Fatalf("%d cached keys, want %d", len(l.keys), maxRateLimitBuckets)
+	}
+	if db.APIKeyID("0123456789abcdef0123456789abcdef") == "0123456789abcdef0123456789abcdef" {
+		t.Error("APIKeyID returns the key")
+	}
+}
+
+func TestRateLimiterClient(t *testing.T) {
+	tests := []struct {
+		name     string
+		ipHeader string
+		header   []string
+		want     string
+	}{
+		{"no IP header configured", "", []string{"9.9.9.9"}, "1.2.3.4"},
+		{"missing IP header", "X-Forwarded-For", nil, "1.2.3.4"},
+		{"single entry", "X-Forwarded-For", []string{"5.6.7.8"}, "5.6.7.8"},
+		{"entry forged by the client", "X-Forwarded-For", []string{"9.9.9.9, 5.6.7.8"}, "5.6.7.8"},
+		{"multiple headers", "X-Forwarded-For", []string{"9.9.9.9", "8.8.8.8,5.6.7.8 "}, "5.6.7.8"},
+		{"empty last entry", "X-Forwarded-For", []string{"9.9.9.9,"}, "1.2.3.4"},
+	}
+	for _, tt := range tests {
+		l := newRateLimiter(nil, nil, RateLimitConfig{Rate: 1, IPHeader: tt.ipHeader})
+		r := httptest.NewRequest("GET", "/api/v2/tx/abcd?apikey=key1", nil)
+		r.RemoteAddr = "1.2.3.4:5678"
+		for _, h := range tt.header {
+			r.Header.Add("X-Forwarded-For", h)
+		}
+		ip, key := l.client(r)
+		if ip != tt.want || key != "key1" {
+			t.Errorf("%s: client() = %v, %v, want %v, key1", tt.name, ip, key, tt.want)
+		}
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	metrics := &common.Metrics{
+		APICacheEfficiency: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_apicache_efficiency"}, []string{"status"}),
+	}
+	c := newResponseCache(ResponseCacheConfig{Size: 1600, TTL: time.Minute, Confirmations: 6}, metrics)
+	c.onNewBlock(100)
+	confirmed := &api.Tx{Txid: "confirmed", Blockheight: 90, Confirmations: 11}
+	immutable, tags, ok := c.classify("/api/v2/tx/confirmed", confirmed, nil)
+	if !ok || !immutable || !reflect.DeepEqual(tags, []string{"tconfirmed"}) {
+		t.Fatalf("classify confirmed tx = %v, %v, %v", immutable, tags, ok)
+	}
+	c.store("confirmed", confirmed, 10, immutable, tags, c.generation())
+	recent := &api.Tx{Txid: "recent", Blockheight: 99, Confirmations: 2}
+	immutable, tags, _ = c.classify("/api/v2/tx/recent", recent, nil)
+	if immutable {
+		t.Fatal("tx with 2 confirmations classified as immutable")
+	}
+	c.store("recent", recent, 10, immutable, tags, c.generation())
+	spent := &api.Tx{Txid: "spent", Blockheight: 50, Confirmations: 51}
+	c.store("spent", spent, 10, true, []string{"tspent"}, c.generation())
+
+	c.onNewBlock(101)
+	data, immutable, found := c.get("confirmed", 101)
+	if !found || !immutable || data.(*api.Tx).Confirmations != 12 || confirmed.Confirmations != 11 {
+		t.Fatalf("get confirmed = %+v, %v, %v", data, immutable, found)
+	}
+	if _, _, found = c.get("recent", 101); found {
+		t.Fatal("not immutable tx found after new block")
+	}
+	// mempool tx spending the output invalidates the tx now and after the next block
+	c.onNewTx(&bchain.MempoolTx{Txid: "new", Vin: []bchain.MempoolVin{{Vin: bchain.Vin{Txid: "spent"}}}}, nil)
+	if _, _, found = c.get("spent", 101); found {
+		t.Fatal("spent tx found after mempool tx")
+	}
+	c.store("spent", spent, 10, true, []string{"tspent"}, c.generation())
+	c.onNewBlock(102)
+	if _, _, found = c.get("spent", 102); found {
+		t.Fatal("spent tx found after the block confirming the spending tx")
+	}
+	// reorg removes everything
+	c.onNewBlock(102)
+	if _, _, found = c.get("confirmed", 102); found {
+		t.Fatal("tx found after reorg")
+	}
+	// the least recently used entries are removed when the size is exceeded
+	for i := 0; i < 20; i++ {
+		k := strconv.Itoa(i)
+		c.store(k, &api.Tx{Txid: k}, 100, true, []string{txTag(k)}, c.generation())
+	}
+	if c.size > 1600 || len(c.entries) != 16 {
+		t.Fatalf("cache size %d, entries %d", c.size, len(c.entries))
+	}
+	if _, _, found = c.get("3", 102); found {
+		t.Fatal("least recently used entry not removed")
+	}
+	if _, _, found = c.get("19", 102); !found {
+		t.Fatal("recently used entry removed")
+	}
+	// the results computed before the invalidation are not stored
+	gen := c.generation()
+	c.onNewTx(&bchain.MempoolTx{Txid: "new2", Vin: []bchain.MempoolVin{{Vin: bchain.Vin{Txid: "stale"}}}}, nil)
+	c.store("stale", &api.Tx{Txid: "stale"}, 10, true, []string{"tstale"}, gen)
+	if _, _, found = c.get("stale", 102); found {
+		t.Fatal("result computed before the invalidation of its tag stored")
+	}
+	c.store("other", &api.Tx{Txid: "other"}, 10, true, []string{"tother"}, gen)
+	if _, _, found = c.get("other", 102); !found {
+		t.Fatal("result with tags not invalidated not stored")
+	}
+	gen = c.generation()
+	c.onNewBlock(103)
+	c.store("before", &api.Tx{Txid: "before"}, 10, true, []string{"tbefore"}, gen)
+	if _, _, found = c.get("before", 103); found {
+		t.Fatal("result computed before the block stored")
+	}
+}
+
+func TestGRPCServer(t *testing.T) {
+	parser := btc.NewBitcoinParser(btc.GetChainParams("test"), &btc.Configuration{BlockAddressesToKeep: 1})
+	chain, err := dbtestdata.NewFakeBlockChain(parser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := api.NewWorker(nil, chain, nil, nil, &common.InternalState{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics := &common.Metrics{
+		GRPCRequests:        prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_grpc_requests"}, []string{"method", "status"}),
+		GRPCStreams:         prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_grpc_streams"}),
+		RateLimitRejections: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_grpc_ratelimit_rejections"}, []string{"interface", "reason"}),
+	}
+	s := &GRPCServer{
+		chain:                 chain,
+		chainParser:           parser,
+		metrics:               metrics,
+		api:                   w,
+		closing:               make(chan struct{}),
+		newBlockSubscriptions: make(map[*grpcStream]struct{}),
+		addressSubscriptions:  make(map[string]map[*grpcStream]struct{}),
+	}
+	s.SetRateLimit(RateLimitConfig{MaxSubscriptions: 2})
+	s.initServer()
+	l := bufconn.Listen(1 << 20)
+	go s.server.Serve(l)
+	defer s.server.Stop()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "bufconn", grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return l.Dial()
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := grpcapi.NewBlockbookClient(conn)
+
+	fees, err := c.EstimateFee(ctx, &grpcapi.EstimateFeeRequest{Blocks: []int32{1, 2}, Economical: true, TxSize: 250})
+	if err != nil {
+		t.Fatal("EstimateFee: ", err)
+	}
+	wantFees := []*grpcapi.Fee{{FeePerUnit: "99", FeePerTx: "25"}, {FeePerUnit: "199", FeePerTx: "50"}}
+	if len(fees.Fees) != len(wantFees) {
+		t.Fatalf("EstimateFee = %v, want %v", fees.Fees, wantFees)
+	}
+	for i := range wantFees {
+		if !proto.Equal(fees.Fees[i], wantFees[i]) {
+			t.Errorf("EstimateFee[%d] = %v, want %v", i, fees.Fees[i], wantFees[i])
+		}
+	}
+
+	// an invalid descriptor is looked up as an address, the xpub is not tried
+	_, err = c.GetUtxo(ctx, &grpcapi.UtxoRequest{Account: "invalid"})
+	if st, _ := status.FromError(err); st.Code() != codes.InvalidArgument || !strings.HasPrefix(st.Message(), "Invalid address 'invalid'") {
+		t.Fatalf("GetUtxo invalid address: %v", err)
+	}
+
+	st, err := c.SubscribeAddresses(ctx, &grpcapi.SubscribeAddressesRequest{Addresses: []string{dbtestdata.AddrA, "invalid 100%"}})
+	if err == nil {
+		_, err = st.Recv()
+	}
+	if e, _ := status.FromError(err); e.Code() != codes.InvalidArgument || e.Message() != "Invalid address invalid 100%" {
+		t.Fatalf("SubscribeAddresses invalid address: %v", err)
+	}
+	st, err = c.SubscribeAddresses(ctx, &grpcapi.SubscribeAddressesRequest{Addresses: []string{dbtestdata.Addr1, dbtestdata.Addr2, dbtestdata.Addr3}})
+	if err == nil {
+		_, err = st.Recv()
+	}
+	if e, _ := status.FromError(err); e.Code() != codes.ResourceExhausted {
+		t.Fatalf("SubscribeAddresses over the limit: %v", err)
+	}
+	if len(s.addressSubscriptions) != 0 {
+		t.Fatal("subscription of invalid request stored")
+	}
+
+	nb, err := c.SubscribeNewBlock(ctx, &grpcapi.SubscribeNewBlockRequest{})
+	if err != nil {
+		t.Fatal("SubscribeNewBlock: ", err)
+	}
+	// the response headers are sent after the stream is registered
+	if _, err = nb.Header(); err != nil {
+		t.Fatal("Header: ", err)
+	}
+	s.newBlockSubscriptionsLock.Lock()
+	subscribed := len(s.newBlockSubscriptions)
+	s.newBlockSubscriptionsLock.Unlock()
+	if subscribed != 1 {
+		t.Fatal("stream not subscribed")
+	}
+	s.OnNewBlock("00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6", 225494)
+	n, err := nb.Recv()
+	if err != nil {
+		t.Fatal("Recv: ", err)
+	}
+	want := &grpcapi.NewBlockNotification{Height: 225494, Hash: "00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6"}
+	if !proto.Equal(n, want) {
+		t.Errorf("Recv = %v, want %v", n, want)
+	}
+	s.closeStreams()
+	_, err = nb.Recv()
+	if e, _ := status.FromError(err); e.Code() != codes.Unavailable {
+		t.Fatalf("Recv after close: %v", err)
+	}
+
+	// the calls over the rate limit are rejected
+	s.UpdateRateLimit(RateLimitConfig{Rate: 1, Burst: 1})
+	if _, err = c.EstimateFee(ctx, &grpcapi.EstimateFeeRequest{Blocks: []int32{1}}); err != nil {
+		t.Fatal("EstimateFee: ", err)
+	}
+	_, err = c.EstimateFee(ctx, &grpcapi.EstimateFeeRequest{Blocks: []int32{1}})
+	if e, _ := status.FromError(err); e.Code() != codes.ResourceExhausted {
+		t.Fatalf("EstimateFee over the rate limit: %v", err)
+	}
+}
+
+func TestAdminJobs(t *testing.T) {
+	s := &InternalServer{}
+	paused := 0
+	s.SetAdmin("secret", func() func() {
+		paused++
+		return func() { paused-- }
+	})
+	s.RegisterAdminJob("wait", true, func(params url.Values, progress db.ProgressFunc, stop chan os.Signal) error {
+		progress(1, 10)
+		<-stop
+		return db.ErrOperationInterrupted
+	})
+	s.RegisterAdminJob("fail", false, func(params url.Values, progress db.ProgressFunc, stop chan os.Signal) error {
+		return errors.New("failed " + params.Get("x"))
+	})
+	do := func(method, target, token string) (int, AdminJob) {
+		r := httptest.NewRequest(method, target, nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		s.apiAdminJobs(w, r)
+		var j AdminJob
+		json.Unmarshal(w.Body.Bytes(), &j)
+		return w.Code, j
+	}
+	wait := func(id string, status AdminJobStatus) AdminJob {
+		for i := 0; i < 100; i++ {
+			if _, j := do("GET", "/api/v2/admin/jobs/"+id, "secret"); j.Status == status {
+				return j
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatal("job ", id, " did not reach status ", status)
+		return AdminJob{}
+	}
+	if code, _ := do("POST", "/api/v2/admin/jobs?kind=wait", "wrong"); code != http.StatusUnauthorized {
+		t.Fatal("wrong token accepted, status ", code)
+	}
+	if code, _ := do("POST", "/api/v2/admin/jobs?kind=unknown", "secret"); code != http.StatusBadRequest {
+		t.Fatal("unknown job kind accepted, status ", code)
+	}
+	code, j := do("POST", "/api/v2/admin/jobs?kind=wait", "secret")
+	if code != http.StatusAccepted || j.ID != "1" || j.Status != AdminJobRunning {
+		t.Fatalf("start job: %d %+v", code, j)
+	}
+	if code, _ := do("POST", "/api/v2/admin/jobs?kind=fail", "secret"); code != http.StatusConflict {
+		t.Fatal("second job started, status ", code)
+	}
+	do("DELETE", "/api/v2/admin/jobs/1", "secret")
+	j = wait("1", AdminJobCanceled)
+	if j.Done != 1 || j.Total != 10 || j.Finished == nil {
+		t.Errorf("canceled job: %+v", j)
+	}
+	if paused != 0 {
+		t.Error("sync not resumed after job")
+	}
+	do("POST", "/api/v2/admin/jobs?kind=fail&x=y", "secret")
+	j = wait("2", AdminJobFailed)
+	if j.Error != "failed y" || !reflect.DeepEqual(j.Params, map[string]string{"x": "y"}) {
+		t.Errorf("failed job: %+v", j)
+	}
+	if code, _ := do("GET", "/api/v2/admin/jobs/3", "secret"); code != http.StatusNotFound {
+		t.Error("unknown job found, status ", code)
+	}
+}
+
+type healthTestChain struct {
+	bchain.BlockChain
+	blocks int
+	errors int
+}
+
+func (c *healthTestChain) GetChainInfo() (*bchain.ChainInfo, error) {
+	return &bchain.ChainInfo{Blocks: c.blocks}, nil
+}
+
+func (c *healthTestChain) GetBackendErrors() (int, string, time.Time) {
+	return c.errors, "connection refused", time.Now()
+}
+
+func TestHealthChecker(t *testing.T) {
+	chain := &healthTestChain{blocks: 100}
+	is := &common.InternalState{DbState: common.DbStateOpen, SyncMode: true}
+	is.FinishedSync(99)
+	is.FinishedMempoolSync(10)
+	s := &PublicServer{health: newHealthChecker(chain, is)}
+	s.SetHealthConfig(HealthConfig{
+		MaxBlocksBehind:   2,
+		MaxSyncAge:        time.Minute,
+		MaxMempoolSyncAge: time.Minute,
+		MaxBackendErrors:  3,
+	})
+	do := func(handler http.HandlerFunc) (int, healthResult) {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest("GET", "/health", nil))
+		var res healthResult
+		if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, res
+	}
+	failed := func(res healthResult) []string {
+		var names []string
+		for _, c := range res.Checks {
+			if !c.OK {
+				names = append(names, c.Name)
+			}
+		}
+		return names
+	}
+	code, res := do(s.healthReady)
+	if code != http.StatusOK || res.Status != "ok" || len(res.Checks) != 7 {
+		t.Fatalf("ready: %d %+v", code, res)
+	}
+	// the chain info is cached, expire it to see the new height
+	chain.blocks = 105
+	chain.errors = 3
+	s.health.chainInfoTime = time.Time{}
+	code, res = do(s.healthReady)
+	if code != http.StatusServiceUnavailable || res.Status != "fail" || !reflect.DeepEqual(failed(res), []string{"blocksBehind", "backendErrors"}) {
+		t.Fatalf("not ready: %d %+v", code, res)
+	}
+	if code, _ = do(s.healthLive); code != http.StatusOK {
+		t.Error("live: status ", code)
+	}
+	is.DbState = common.DbStateInconsistent
+	code, res = do(s.healthLive)
+	if code != http.StatusServiceUnavailable || !reflect.DeepEqual(failed(res), []string{"database"}) {
+		t.Errorf("not live: %d %+v", code, res)
+	}
+}
+
+func TestRequestLogs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "requestlogs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	access, err := common.NewJSONLog(dir+"/access.log", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit, err := common.NewJSONLog(dir+"/audit.log", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readLog := func(l *common.JSONLog, name string) []map[string]interface{} {
+		l.Close()
+		b, err := ioutil.ReadFile(dir + "/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var entries []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+			var e map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				t.Fatal(err)
+			}
+			entries = append(entries, e)
+		}
+		return entries
+	}
+
+	s := &PublicServer{logs: newRequestLogs(access, audit)}
+	handler := s.jsonHandler(func(r *http.Request, apiVersion int) (interface{}, error) {
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			return nil, api.NewAPIError("Transaction not found", true)
+		}
+		s.logs.logSendTx("api", requestIDFromContext(r.Context()), s.clientIP(r), "0011", "txid", nil)
+		return map[string]string{"result": "txid"}, nil
+	}, apiV2)
+	do := func(target, requestID string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", target, nil)
+		r.RemoteAddr = "10.0.0.1:4321"
+		if requestID != "" {
+			r.Header.Set(requestIDHeader, requestID)
+		}
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+	w := do("/api/v2/sendtx/0011?apikey=0123456789abcdef", "client-1")
+	if got := w.Header().Get(requestIDHeader); got != "client-1" {
+		t.Errorf("request id header %v, want client-1", got)
+	}
+	size := w.Body.Len()
+	w = do("/api/v2/tx/missing", "")
+	generatedID := w.Header().Get(requestIDHeader)
+	if !strings.HasPrefix(generatedID, requestIDPrefix) {
+		t.Errorf("request id header %v, want generated id", generatedID)
+	}
+
+	// internal server audits only the requests changing the state
+	is := &InternalServer{}
+	is.SetAuditLog(audit)
+	internalHandler := is.auditHandler(func(w http.ResponseWriter, r *http.Request) {
+		writeInternalJSON(w, http.StatusConflict, resultInternalError{"Job 1 is running"})
+	})
+	internalHandler(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v2/admin/jobs", nil))
+	internalHandler(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v2/admin/jobs?kind=rollback&height=100", nil))
+
+	entries := readLog(access, "access.log")
+	if len(entries) != 2 {
+		t.Fatalf("access log has %d entries, want 2", len(entries))
+	}
+	want := map[string]interface{}{
+		"requestId":  "client-1",
+		"interface":  "api",
+		"clientIp":   "10.0.0.1",
+		"httpMethod": "GET",
+		"params":     "/api/v2/sendtx/0011?apikey=012345...",
+		"status":     float64(200),
+		"size":       float64(size),
+	}
+	for k, v := range want {
+		if entries[0][k] != v {
+			t.Errorf("access log %v = %v, want %v", k, entries[0][k], v)
+		}
+	}
+	if entries[1]["requestId"] != generatedID || entries[1]["status"] != float64(400) || entries[1]["error"] != "Transaction not found" {
+		t.Errorf("access log entry %+v, want status 400 with error", entries[1])
+	}
+
+	entries = readLog(audit, "audit.log")
+	if len(entries) != 2 {
+		t.Fatalf("audit log has %d entries, want 2", len(entries))
+	}
+	if entries[0]["action"] != "sendtx" || entries[0]["requestId"] != "client-1" || entries[0]["txid"] != "txid" || entries[0]["txSize"] != float64(2) || entries[0]["status"] != "success" {
+		t.Errorf("audit log entry %+v, want sendtx", entries[0])
+	}
+	if entries[1]["action"] != "POST /api/v2/admin/jobs" || entries[1]["params"] != "height=100&kind=rollback" || entries[1]["status"] != "failure" || entries[1]["error"] != "409 Conflict" {
+		t.Errorf("audit log entry %+v, want admin job", entries[1])
+	}
+}
+
+func TestTracing(t *testing.T) {
+	var lock sync.Mutex
+	var spans []*tracepb.Span
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		var req coltracepb.ExportTraceServiceRequest
+		if err = proto.Unmarshal(b, &req); err != nil {
+			t.Error(err)
+		}
+		lock.Lock()
+		for _, rs := range req.ResourceSpans {
+			for _, ils := range rs.InstrumentationLibrarySpans {
+				spans = append(spans, ils.Spans...)
+			}
+		}
+		lock.Unlock()
+	}))
+	defer collector.Close()
+	if err := tracing.Init(tracing.Config{Endpoint: collector.URL + "/v1/traces", ServiceName: "blockbook-test", SampleRatio: 1}); err != nil {
+		t.Fatal(err)
+	}
+	defer tracing.Shutdown(context.Background())
+
+	s, dbpath := setupPublicHTTPServer(t)
+	defer closeAndDestroyPublicServer(t, s, dbpath)
+	s.ConnectFullPublicInterface()
+	ts := httptest.NewServer(s.https.Handler)
+	defer ts.Close()
+
+	// the reads of the db go through the traced wrappers of the worker
+	for _, u := range []string{"/api/v2/address/" + dbtestdata.AddrA + "?details=txs", "/api/v2/tx/" + dbtestdata.TxidB2T1} {
+		resp, err := http.Get(ts.URL + u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%v: status %v", u, resp.StatusCode)
+		}
+	}
+	if err := tracing.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	byID := make(map[string]*tracepb.Span)
+	count := make(map[string]int)
+	for _, span := range spans {
+		byID[string(span.SpanId)] = span
+		count[span.Name]++
+	}
+	for _, name := range []string{"http apiAddress", "http apiTx", "api.GetAddress", "api.GetTransaction", "db.GetAddrDescTransactions", "db.GetTxAddresses"} {
+		if count[name] == 0 {
+			t.Errorf("span %v not exported, exported %v", name, count)
+		}
+	}
+	// the db spans are descendants of the request spans
+	for _, span := range spans {
+		if !strings.HasPrefix(span.Name, "db.") {
+			continue
+		}
+		root := span
+		for len(root.ParentSpanId) > 0 && byID[string(root.ParentSpanId)] != nil {
+			root = byID[string(root.ParentSpanId)]
+		}
+		if !strings.HasPrefix(root.Name, "http ") || string(root.TraceId) != string(span.TraceId) {
+			t.Errorf("span %v has root %v, want http request span", span.Name, root.Name)
+		}
+	}
+}
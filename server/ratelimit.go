@@ -0,0 +1,416 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/scryptachain/blockbook-scrypta/bchain"
+	"github.com/scryptachain/blockbook-scrypta/common"
+	"github.com/scryptachain/blockbook-scrypta/db"
+	"github.com/scryptachain/blockbook-scrypta/grpcapi"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// RateLimitConfig configures the access control of the public interface
+type RateLimitConfig struct {
+	// Rate is the number of request units per second available to a client without API key (identified by IP), 0 disables the rate limiting
+	Rate float64
+	// Burst is the maximum number of request units available to a client at once, defaults to Rate
+	Burst float64
+	// MaxSubscriptions limits the number of addresses in subscribeAddresses and descriptors in subscribeAccounts per websocket connection, 0 means no limit
+	MaxSubscriptions int
+	// IPHeader is the header with the client IP set by a reverse proxy, if empty the remote address of the connection is used
+	IPHeader string
+}
+
+const (
+	apiKeyHeader = "X-Api-Key"
+	apiKeyParam  = "apikey"
+	// API keys are hex encoded random bytes
+	apiKeyBytes = 16
+	// API keys are cached, changes done by the internal server are applied after this period
+	apiKeyCacheTTL = time.Minute
+	// when there are more buckets, the buckets of clients which have the full burst available are removed
+	maxRateLimitBuckets = 10000
+)
+
+// costs of requests in units, the requests for xpubs and transaction history cost more
+const (
+	costDefault           = 1
+	costXpub              = 10
+	costHistoryMultiplier = 2
+)
+
+// accessError is returned if the request is rejected by the access control
+type accessError struct {
+	msg        string
+	reason     string
+	status     int
+	retryAfter time.Duration
+}
+
+func (e *accessError) Error() string {
+	return e.msg
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	rate   float64
+	burst  float64
+}
+
+// refill adds the tokens accumulated since the last refill, returns true if the bucket is full
+func (b *tokenBucket) refill(now time.Time) bool {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	b.last = now
+	if b.tokens >= b.burst {
+		b.tokens = b.burst
+		return true
+	}
+	return false
+}
+
+type cachedAPIKey struct {
+	key    *db.APIKey
+	loaded time.Time
+}
+
+// rateLimiter limits the requests of the clients by token buckets, one per API key or per IP for clients without API key
+type rateLimiter struct {
+	lock    sync.Mutex
+	config  RateLimitConfig
+	db      *db.RocksDB
+	metrics *common.Metrics
+	keys    map[string]cachedAPIKey
+	buckets map[string]*tokenBucket
+}
+
+func newRateLimiter(d *db.RocksDB, metrics *common.Metrics, config RateLimitConfig) *rateLimiter {
+	if config.Burst <= 0 {
+		config.Burst = config.Rate
+	}
+	return &rateLimiter{
+		config:  config,
+		db:      d,
+		metrics: metrics,
+		keys:    make(map[string]cachedAPIKey),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// setLimits changes the limits of the running limiter, the buckets of the clients adapt at their next request
+func (l *rateLimiter) setLimits(config RateLimitConfig) {
+	if l == nil {
+		return
+	}
+	if config.Burst <= 0 {
+		config.Burst = config.Rate
+	}
+	l.lock.Lock()
+	l.config.Rate = config.Rate
+	l.config.Burst = config.Burst
+	l.config.MaxSubscriptions = config.MaxSubscriptions
+	l.lock.Unlock()
+}
+
+// client returns the IP and the API key of the client sending the request
+func (l *rateLimiter) client(r *http.Request) (string, string) {
+	key := r.Header.Get(apiKeyHeader)
+	if key == "" {
+		key = r.URL.Query().Get(apiKeyParam)
+	}
+	return l.clientIP(r.RemoteAddr, r.Header), key
+}
+
+// grpcClient returns the IP and the API key of the client of the gRPC call, the IP header is read from the metadata
+func (l *rateLimiter) grpcClient(ctx context.Context) (string, string) {
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	h := make(http.Header, len(md))
+	for k, v := range md {
+		h[http.CanonicalHeaderKey(k)] = v
+	}
+	return l.clientIP(remoteAddr, h), h.Get(apiKeyHeader)
+}
+
+// clientIP returns the IP of the client from the IP header if configured, otherwise from the remote address
+// The proxy appends the address of its client to the entries received from the client, which can be forged,
+// therefore the rightmost entry is used
+func (l *rateLimiter) clientIP(remoteAddr string, h http.Header) string {
+	if l.config.IPHeader != "" {
+		if values := h[http.CanonicalHeaderKey(l.config.IPHeader)]; len(values) > 0 {
+			ip := values[len(values)-1]
+			if i := strings.LastIndexByte(ip, ','); i >= 0 {
+				ip = ip[i+1:]
+			}
+			if ip = strings.TrimSpace(ip); ip != "" {
+				return ip
+			}
+		}
+	}
+	return remoteIP(remoteAddr)
+}
+
+// validAPIKey checks the format of the key, the malformed keys are rejected without looking them up
+func validAPIKey(key string) bool {
+	if len(key) != 2*apiKeyBytes {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// getAPIKey returns the API key from the cache or from db, nil if the key does not exist,
+// must be called without the lock, the db is read outside of the lock
+func (l *rateLimiter) getAPIKey(key string) *db.APIKey {
+	if !validAPIKey(key) {
+		return nil
+	}
+	now := time.Now()
+	l.lock.Lock()
+	c, found := l.keys[key]
+	l.lock.Unlock()
+	if found && now.Sub(c.loaded) < apiKeyCacheTTL {
+		return c.key
+	}
+	k, err := l.db.GetAPIKey(key)
+	if err != nil {
+		glog.Error("rateLimiter: GetAPIKey error ", err)
+		// keep the previous state of the key until the db can be read
+		return c.key
+	}
+	l.lock.Lock()
+	l.cacheAPIKey(key, k, now)
+	l.lock.Unlock()
+	return k
+}
+
+// cacheAPIKey stores the key to the cache, if the cache is full, an expired entry is removed or,
+// if there is none, a random one, must be called with the lock held
+func (l *rateLimiter) cacheAPIKey(key string, k *db.APIKey, now time.Time) {
+	if _, found := l.keys[key]; !found && len(l.keys) >= maxRateLimitBuckets {
+		evict := ""
+		for ck, c := range l.keys {
+			if evict == "" {
+				evict = ck
+			}
+			if now.Sub(c.loaded) >= apiKeyCacheTTL {
+				evict = ck
+				break
+			}
+		}
+		delete(l.keys, evict)
+	}
+	l.keys[key] = cachedAPIKey{key: k, loaded: now}
+}
+
+func (l *rateLimiter) reject(iface string, e *accessError) *accessError {
+	l.metrics.RateLimitRejections.With(common.Labels{"interface": iface, "reason": e.reason}).Inc()
+	return e
+}
+
+// checkAPIKey returns error if the client sent API key which does not exist
+func (l *rateLimiter) checkAPIKey(key string, iface string) *accessError {
+	if l == nil || key == "" {
+		return nil
+	}
+	if l.getAPIKey(key) == nil {
+		return l.reject(iface, &accessError{msg: "Invalid API key", reason: "apikey", status: http.StatusForbidden})
+	}
+	return nil
+}
+
+// allow takes the cost of the request from the bucket of the client, returns error if the request is rejected
+func (l *rateLimiter) allow(ip, key string, cost float64, iface string) *accessError {
+	if l == nil {
+		return nil
+	}
+	var k *db.APIKey
+	if key != "" {
+		if k = l.getAPIKey(key); k == nil {
+			return l.reject(iface, &accessError{msg: "Invalid API key", reason: "apikey", status: http.StatusForbidden})
+		}
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := time.Now()
+	rate, burst := l.config.Rate, l.config.Burst
+	id := "ip:" + ip
+	if k != nil {
+		id = "key:" + key
+		if k.Rate > 0 {
+			rate = k.Rate
+			burst = k.Burst
+			if burst <= 0 {
+				burst = rate
+			}
+		}
+	}
+	if rate <= 0 {
+		return nil
+	}
+	// a request more expensive than the burst would never pass
+	if cost > burst {
+		cost = burst
+	}
+	b, found := l.buckets[id]
+	if !found {
+		if len(l.buckets) >= maxRateLimitBuckets {
+			l.removeFullBuckets(now)
+		}
+		b = &tokenBucket{tokens: burst, last: now}
+		l.buckets[id] = b
+	}
+	b.rate, b.burst = rate, burst
+	b.refill(now)
+	if b.tokens < cost {
+		retryAfter := time.Duration((cost - b.tokens) / rate * float64(time.Second))
+		return l.reject(iface, &accessError{msg: "Rate limit exceeded", reason: "rate", status: http.StatusTooManyRequests, retryAfter: retryAfter})
+	}
+	b.tokens -= cost
+	return nil
+}
+
+// removeFullBuckets removes the buckets of the clients which were idle long enough to have full burst available
+func (l *rateLimiter) removeFullBuckets(now time.Time) {
+	for id, b := range l.buckets {
+		if b.refill(now) {
+			delete(l.buckets, id)
+		}
+	}
+}
+
+// checkSubscriptions returns error if the number of subscribed items exceeds the limit of the client
+func (l *rateLimiter) checkSubscriptions(key string, count int, iface string) *accessError {
+	if l == nil {
+		return nil
+	}
+	var k *db.APIKey
+	if key != "" {
+		k = l.getAPIKey(key)
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	max := l.config.MaxSubscriptions
+	if k != nil && k.MaxSubscriptions > 0 {
+		max = k.MaxSubscriptions
+	}
+	if max > 0 && count > max {
+		return l.reject(iface, &accessError{msg: "Too many subscriptions, the limit is " + strconv.Itoa(max), reason: "subscriptions", status: http.StatusTooManyRequests})
+	}
+	return nil
+}
+
+// writeRetryAfter sets the Retry-After header if the request was rejected by the rate limit
+func (e *accessError) writeRetryAfter(w http.ResponseWriter) {
+	if e.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(e.retryAfter.Seconds()))))
+	}
+}
+
+func isXpub(parser bchain.BlockChainParser, descriptor string) bool {
+	_, err := parser.DerivationBasePath(descriptor)
+	return err == nil
+}
+
+// accountRequestCost returns the cost of a request for an address or an xpub with the given details
+func accountRequestCost(xpub bool, details string) float64 {
+	cost := float64(costDefault)
+	if xpub {
+		cost = costXpub
+	}
+	if details == "txs" || details == "txslight" {
+		cost *= costHistoryMultiplier
+	}
+	return cost
+}
+
+// httpRequestCost returns the cost of the public API request
+func (s *PublicServer) httpRequestCost(r *http.Request) float64 {
+	path := r.URL.Path
+	switch {
+	case strings.Contains(path, "/xpub/"):
+		return accountRequestCost(true, r.URL.Query().Get("details"))
+	case strings.Contains(path, "/address/"):
+		return accountRequestCost(false, r.URL.Query().Get("details"))
+	case strings.Contains(path, "/utxo/"), strings.Contains(path, "/balancehistory/"):
+		if i := strings.LastIndexByte(path, '/'); i > 0 {
+			return accountRequestCost(isXpub(s.chainParser, path[i+1:]), "")
+		}
+	}
+	return costDefault
+}
+
+// checkRateLimit returns error if the request to the public API or to the explorer is rejected by the access control
+func (s *PublicServer) checkRateLimit(w http.ResponseWriter, r *http.Request, iface string) *accessError {
+	if s.limiter == nil {
+		return nil
+	}
+	ip, key := s.limiter.client(r)
+	err := s.limiter.allow(ip, key, s.httpRequestCost(r), iface)
+	if err != nil {
+		err.writeRetryAfter(w)
+	}
+	return err
+}
+
+// websocketRequestCost returns the cost of the websocket request
+func (s *WebsocketServer) websocketRequestCost(req *websocketReq) float64 {
+	switch req.Method {
+	case "getAccountInfo", "getAccountUtxo", "getBalanceHistory":
+		r := struct {
+			Descriptor string `json:"descriptor"`
+			Details    string `json:"details"`
+		}{}
+		if err := json.Unmarshal(req.Params, &r); err == nil {
+			return accountRequestCost(isXpub(s.chainParser, r.Descriptor), r.Details)
+		}
+	case "subscribeAccounts":
+		r := struct {
+			Descriptors []string `json:"descriptors"`
+		}{}
+		if err := json.Unmarshal(req.Params, &r); err == nil && len(r.Descriptors) > 0 {
+			return float64(costXpub * len(r.Descriptors))
+		}
+	}
+	return costDefault
+}
+
+// socketioRequestCost returns the cost of the socket.io request, the address history costs per address
+func socketioRequestCost(method string, params json.RawMessage) float64 {
+	switch method {
+	case "getAddressTxids", "getAddressHistory":
+		if addr, _, err := unmarshalGetAddressRequest(params); err == nil && len(addr) > 0 {
+			return float64(len(addr)) * accountRequestCost(false, "txs")
+		}
+	}
+	return costDefault
+}
+
+// grpcRequestCost returns the cost of the gRPC call, req is nil for the streaming calls
+func grpcRequestCost(parser bchain.BlockChainParser, method string, req interface{}) float64 {
+	switch r := req.(type) {
+	case *grpcapi.AddressRequest:
+		return accountRequestCost(method == grpcapi.MethodGetXpub, r.Details)
+	case *grpcapi.UtxoRequest:
+		return accountRequestCost(isXpub(parser, r.Account), "")
+	}
+	return costDefault
+}
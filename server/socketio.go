@@ -30,8 +30,14 @@ type SocketIoServer struct {
 	metrics     *common.Metrics
 	is          *common.InternalState
 	api         *api.Worker
+	logs        *requestLogs
+	limiter     *rateLimiter
 }
 
+// the socket.io channel keeps only the headers of the connecting request,
+// the client identified by the limiter is passed to the channel in this header
+const socketioClientIPHeader = "X-Blockbook-Client-Ip"
+
 // NewSocketIoServer creates new SocketIo interface to blockbook and returns its handle
 func NewSocketIoServer(db *db.RocksDB, chain bchain.BlockChain, mempool bchain.Mempool, txCache *db.TxCache, metrics *common.Metrics, is *common.InternalState) (*SocketIoServer, error) {
 	api, err := api.NewWorker(db, chain, mempool, txCache, is)
@@ -79,7 +85,40 @@ func NewSocketIoServer(db *db.RocksDB, chain bchain.BlockChain, mempool bchain.M
 
 // GetHandler returns socket.io http handler
 func (s *SocketIoServer) GetHandler() http.Handler {
-	return s.server
+	return s
+}
+
+// ServeHTTP checks the API key of the connecting client and passes the connection to the socket.io server
+func (s *SocketIoServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.limiter != nil {
+		ip, key := s.limiter.client(r)
+		if err := s.limiter.checkAPIKey(key, "socketio"); err != nil {
+			http.Error(w, err.Error(), err.status)
+			return
+		}
+		// overwrite the headers possibly sent by the client
+		h := make(http.Header, len(r.Header)+2)
+		for k, v := range r.Header {
+			h[k] = v
+		}
+		h.Set(socketioClientIPHeader, ip)
+		h.Set(apiKeyHeader, key)
+		r = r.WithContext(r.Context())
+		r.Header = h
+	}
+	s.server.ServeHTTP(w, r)
+}
+
+// checkRateLimit returns error if the request of the channel is rejected by the access control
+func (s *SocketIoServer) checkRateLimit(c *gosocketio.Channel, cost float64) error {
+	if s.limiter == nil {
+		return nil
+	}
+	h := c.RequestHeader()
+	if ae := s.limiter.allow(h.Get(socketioClientIPHeader), h.Get(apiKeyHeader), cost, "socketio"); ae != nil {
+		return ae
+	}
+	return nil
 }
 
 type addrOpts struct {
@@ -175,7 +214,16 @@ func (s *SocketIoServer) onMessage(c *gosocketio.Channel, req map[string]json.Ra
 	defer s.metrics.SocketIOReqDuration.With(common.Labels{"method": method}).Observe(float64(time.Since(t)) / 1e3) // in microseconds
 	f, ok := onMessageHandlers[method]
 	if ok {
+		if err = s.checkRateLimit(c, socketioRequestCost(method, params)); err != nil {
+			s.metrics.SocketIORequests.With(common.Labels{"method": method, "status": "failure"}).Inc()
+			e := resultError{}
+			e.Error.Message = err.Error()
+			return e
+		}
 		rv, err = f(s, params)
+		if method == "sendTransaction" {
+			s.logSendTx(c, params, rv, err)
+		}
 	} else {
 		err = errors.New("unknown method")
 	}
@@ -636,7 +684,7 @@ func (s *SocketIoServer) getDetailedTransaction(txid string) (res resultGetDetai
 }
 
 func (s *SocketIoServer) sendTransaction(tx string) (res resultSendTransaction, err error) {
-	txid, err := s.chain.SendRawTransaction(tx)
+	txid, err := s.api.SendTransaction(tx)
 	if err != nil {
 		return res, err
 	}
@@ -644,6 +692,20 @@ func (s *SocketIoServer) sendTransaction(tx string) (res resultSendTransaction,
 	return
 }
 
+// logSendTx records the sent transaction in the audit log
+func (s *SocketIoServer) logSendTx(c *gosocketio.Channel, params json.RawMessage, rv interface{}, err error) {
+	if s.logs == nil {
+		return
+	}
+	tx, _ := unmarshalStringParameter(params)
+	res, _ := rv.(resultSendTransaction)
+	ip := c.RequestHeader().Get(socketioClientIPHeader)
+	if ip == "" {
+		ip = remoteIP(c.Ip())
+	}
+	s.logs.logSendTx("socketio", "", ip, tx, res.Result, err)
+}
+
 type resultGetMempoolEntry struct {
 	Result *bchain.MempoolEntry `json:"result"`
 }
@@ -689,6 +751,10 @@ func (s *SocketIoServer) onSubscribe(c *gosocketio.Channel, req []byte) interfac
 			onError(c.Id(), sc, "invalid data", err.Error()+", req: "+r)
 			return nil
 		}
+		if err = s.checkRateLimit(c, float64(len(addrs))); err != nil {
+			onError(c.Id(), sc, err.Error(), "req: "+r)
+			return nil
+		}
 		// normalize the addresses to AddressDescriptor
 		descs := make([]bchain.AddressDescriptor, len(addrs))
 		for i, a := range addrs {
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"math/big"
 	"net/http"
@@ -18,6 +19,7 @@ import (
 	"github.com/scryptachain/blockbook-scrypta/bchain"
 	"github.com/scryptachain/blockbook-scrypta/common"
 	"github.com/scryptachain/blockbook-scrypta/db"
+	"github.com/scryptachain/blockbook-scrypta/tracing"
 )
 
 const upgradeFailed = "Upgrade failed: "
@@ -38,10 +40,16 @@ type websocketReq struct {
 	ID     string          `json:"id"`
 	Method string          `json:"method"`
 	Params json.RawMessage `json:"params"`
+	// ctx is the context of the request containing its tracing span
+	ctx       context.Context
+	requestID string
+	// afterResponse is called after the response is sent to the channel
+	afterResponse func()
 }
 
 type websocketRes struct {
 	ID   string      `json:"id"`
+	Seq  uint64      `json:"seq,omitempty"`
 	Data interface{} `json:"data"`
 }
 
@@ -51,8 +59,12 @@ type websocketChannel struct {
 	out           chan *websocketRes
 	ip            string
 	requestHeader http.Header
+	clientIP      string
+	apiKey        string
 	alive         bool
 	aliveLock     sync.Mutex
+	// done is closed when the channel is closed, out is never closed so that the senders do not panic
+	done chan struct{}
 }
 
 // WebsocketServer is a handle to websocket server
@@ -74,6 +86,15 @@ type WebsocketServer struct {
 	addressSubscriptionsLock   sync.Mutex
 	fiatRatesSubscriptions     map[string]map[*websocketChannel]string
 	fiatRatesSubscriptionsLock sync.Mutex
+	accountSubscriptions       map[*websocketChannel][]*accountSubscription
+	accountAddresses           map[string]map[*accountSubscription]struct{}
+	accountSubscriptionsLock   sync.Mutex
+	notifications              *notificationLog
+	limiter                    *rateLimiter
+	logs                       *requestLogs
+	// accountsRefreshRunning is set while refreshAccountSubscriptions runs, accountsRefreshPending requests another run
+	accountsRefreshRunning int32
+	accountsRefreshPending int32
 }
 
 // NewWebsocketServer creates new websocket interface to blockbook and returns its handle
@@ -104,10 +125,28 @@ func NewWebsocketServer(db *db.RocksDB, chain bchain.BlockChain, mempool bchain.
 		newBlockSubscriptions:  make(map[*websocketChannel]string),
 		addressSubscriptions:   make(map[string]map[*websocketChannel]string),
 		fiatRatesSubscriptions: make(map[string]map[*websocketChannel]string),
+		accountSubscriptions:   make(map[*websocketChannel][]*accountSubscription),
+		accountAddresses:       make(map[string]map[*accountSubscription]struct{}),
 	}
 	return s, nil
 }
 
+// SetNotificationLog enables the log of notifications, which allows the clients to resume the subscriptions after reconnect
+// size is the number of notifications kept in memory, spillSize the number of older notifications kept in db
+// It must be called before the server starts to accept connections
+func (s *WebsocketServer) SetNotificationLog(size int, spillSize int) error {
+	if size <= 0 {
+		s.notifications = nil
+		return nil
+	}
+	l, err := newNotificationLog(s.db, size, spillSize)
+	if err != nil {
+		return err
+	}
+	s.notifications = l
+	return nil
+}
+
 // allow all origins
 func checkOrigin(r *http.Request) bool {
 	return true
@@ -119,6 +158,16 @@ func (s *WebsocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, upgradeFailed+ErrorMethodNotAllowed.Error(), 503)
 		return
 	}
+	var clientIP, apiKey string
+	if s.limiter != nil {
+		clientIP, apiKey = s.limiter.client(r)
+		if err := s.limiter.checkAPIKey(apiKey, "websocket"); err != nil {
+			http.Error(w, upgradeFailed+err.Error(), err.status)
+			return
+		}
+	} else {
+		clientIP = remoteIP(r.RemoteAddr)
+	}
 	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		http.Error(w, upgradeFailed+err.Error(), 503)
@@ -130,7 +179,10 @@ func (s *WebsocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		out:           make(chan *websocketRes, outChannelSize),
 		ip:            r.RemoteAddr,
 		requestHeader: r.Header,
+		clientIP:      clientIP,
+		apiKey:        apiKey,
 		alive:         true,
+		done:          make(chan struct{}),
 	}
 	go s.inputLoop(c)
 	go s.outputLoop(c)
@@ -148,11 +200,7 @@ func (s *WebsocketServer) closeChannel(c *websocketChannel) {
 	if c.alive {
 		c.conn.Close()
 		c.alive = false
-		//clean out
-		close(c.out)
-		for len(c.out) > 0 {
-			<-c.out
-		}
+		close(c.done)
 		s.onDisconnect(c)
 	}
 }
@@ -204,11 +252,16 @@ func (s *WebsocketServer) inputLoop(c *websocketChannel) {
 }
 
 func (s *WebsocketServer) outputLoop(c *websocketChannel) {
-	for m := range c.out {
-		err := c.conn.WriteJSON(m)
-		if err != nil {
-			glog.Error("Error sending message to ", c.id, ", ", err)
-			s.closeChannel(c)
+	for {
+		select {
+		case m := <-c.out:
+			err := c.conn.WriteJSON(m)
+			if err != nil {
+				glog.Error("Error sending message to ", c.id, ", ", err)
+				s.closeChannel(c)
+			}
+		case <-c.done:
+			return
 		}
 	}
 }
@@ -221,6 +274,7 @@ func (s *WebsocketServer) onConnect(c *websocketChannel) {
 func (s *WebsocketServer) onDisconnect(c *websocketChannel) {
 	s.unsubscribeNewBlock(c)
 	s.unsubscribeAddresses(c)
+	s.unsubscribeAccounts(c)
 	s.unsubscribeFiatRates(c)
 	glog.Info("Client disconnected ", c.id, ", ", c.ip)
 	s.metrics.WebsocketClients.Dec()
@@ -230,7 +284,7 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *webs
 	"getAccountInfo": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
 		r, err := unmarshalGetAccountInfoRequest(req.Params)
 		if err == nil {
-			rv, err = s.getAccountInfo(r)
+			rv, err = s.getAccountInfo(req.ctx, r)
 		}
 		return
 	},
@@ -253,7 +307,7 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *webs
 		}{}
 		err = json.Unmarshal(req.Params, &r)
 		if err == nil {
-			rv, err = s.getAccountUtxo(r.Descriptor)
+			rv, err = s.getAccountUtxo(req.ctx, r.Descriptor)
 		}
 		return
 	},
@@ -277,9 +331,10 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *webs
 			if r.GroupBy <= 0 {
 				r.GroupBy = 3600
 			}
-			rv, err = s.api.GetXpubBalanceHistory(r.Descriptor, r.From, r.To, r.Currencies, r.Gap, r.GroupBy)
+			w := s.api.WithContext(req.ctx)
+			rv, err = w.GetXpubBalanceHistory(r.Descriptor, r.From, r.To, r.Currencies, r.Gap, r.GroupBy)
 			if err != nil {
-				rv, err = s.api.GetBalanceHistory(r.Descriptor, r.From, r.To, r.Currencies, r.GroupBy)
+				rv, err = w.GetBalanceHistory(r.Descriptor, r.From, r.To, r.Currencies, r.GroupBy)
 			}
 		}
 		return
@@ -290,7 +345,7 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *webs
 		}{}
 		err = json.Unmarshal(req.Params, &r)
 		if err == nil {
-			rv, err = s.getTransaction(r.Txid)
+			rv, err = s.getTransaction(req.ctx, r.Txid)
 		}
 		return
 	},
@@ -308,17 +363,46 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *webs
 		return s.estimateFee(c, req.Params)
 	},
 	"sendTransaction": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
+		r := struct {
+			Hex        string `json:"hex"`
+			TestAccept bool   `json:"testAccept"`
+		}{}
+		err = json.Unmarshal(req.Params, &r)
+		if err == nil {
+			if r.TestAccept {
+				rv, err = s.api.WithContext(req.ctx).ValidateTransaction(r.Hex)
+			} else {
+				rv, err = s.sendTransaction(req.ctx, r.Hex)
+				res, _ := rv.(resultSendTransaction)
+				s.logs.logSendTx("websocket", req.requestID, c.clientIP, r.Hex, res.Result, err)
+			}
+		}
+		return
+	},
+	"decodeTransaction": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
 		r := struct {
 			Hex string `json:"hex"`
 		}{}
 		err = json.Unmarshal(req.Params, &r)
 		if err == nil {
-			rv, err = s.sendTransaction(r.Hex)
+			rv, err = s.api.WithContext(req.ctx).DecodeTransaction(r.Hex)
+		}
+		return
+	},
+	"createPsbt": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
+		r := api.PsbtRequest{}
+		err = json.Unmarshal(req.Params, &r)
+		if err == nil {
+			rv, err = s.api.WithContext(req.ctx).CreatePsbt(&r)
 		}
 		return
 	},
 	"subscribeNewBlock": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
-		return s.subscribeNewBlock(c, req)
+		rv, err = s.subscribeNewBlock(c, req)
+		if err == nil {
+			rv = s.resumeNewBlock(c, req, rv)
+		}
+		return
 	},
 	"unsubscribeNewBlock": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
 		return s.unsubscribeNewBlock(c)
@@ -326,13 +410,39 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *webs
 	"subscribeAddresses": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
 		ad, err := s.unmarshalAddresses(req.Params)
 		if err == nil {
+			if ae := s.limiter.checkSubscriptions(c.apiKey, len(ad), "websocket"); ae != nil {
+				return nil, ae
+			}
 			rv, err = s.subscribeAddresses(c, ad, req)
+			if err == nil {
+				rv = s.resumeAddresses(c, req, ad, rv)
+			}
 		}
 		return
 	},
 	"unsubscribeAddresses": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
 		return s.unsubscribeAddresses(c)
 	},
+	"subscribeAccounts": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
+		r := struct {
+			Descriptors []string `json:"descriptors"`
+			Gap         int      `json:"gap"`
+		}{}
+		err = json.Unmarshal(req.Params, &r)
+		if err == nil {
+			if ae := s.limiter.checkSubscriptions(c.apiKey, len(r.Descriptors), "websocket"); ae != nil {
+				return nil, ae
+			}
+			rv, err = s.subscribeAccounts(c, r.Descriptors, r.Gap, req)
+			if err == nil {
+				rv = s.resumeAccounts(c, req, rv)
+			}
+		}
+		return
+	},
+	"unsubscribeAccounts": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
+		return s.unsubscribeAccounts(c)
+	},
 	"subscribeFiatRates": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
 		r := struct {
 			Currency string `json:"currency"`
@@ -356,7 +466,7 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *webs
 		}{}
 		err = json.Unmarshal(req.Params, &r)
 		if err == nil {
-			rv, err = s.getCurrentFiatRates(r.Currencies)
+			rv, err = s.getCurrentFiatRates(req.ctx, r.Currencies)
 		}
 		return
 	},
@@ -367,7 +477,20 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *webs
 		}{}
 		err = json.Unmarshal(req.Params, &r)
 		if err == nil {
-			rv, err = s.getFiatRatesForTimestamps(r.Timestamps, r.Currencies)
+			rv, err = s.getFiatRatesForTimestamps(req.ctx, r.Timestamps, r.Currencies)
+		}
+		return
+	},
+	"getFiatRatesOHLC": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
+		r := struct {
+			Currency string `json:"currency"`
+			Interval string `json:"interval"`
+			From     int64  `json:"from"`
+			To       int64  `json:"to"`
+		}{}
+		err = json.Unmarshal(req.Params, &r)
+		if err == nil {
+			rv, err = s.api.WithContext(req.ctx).GetFiatRatesOHLC(r.Currency, r.Interval, r.From, r.To)
 		}
 		return
 	},
@@ -377,27 +500,48 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *webs
 		}{}
 		err = json.Unmarshal(req.Params, &r)
 		if err == nil {
-			rv, err = s.getFiatRatesTickersList(r.Timestamp)
+			rv, err = s.getFiatRatesTickersList(req.ctx, r.Timestamp)
 		}
 		return
 	},
 }
 
 func sendResponse(c *websocketChannel, req *websocketReq, data interface{}) {
-	defer func() {
-		if r := recover(); r != nil {
-			glog.Error("Client ", c.id, ", onRequest ", req.Method, " recovered from panic: ", r)
-		}
-	}()
-	c.out <- &websocketRes{
-		ID:   req.ID,
-		Data: data,
-	}
+	c.send(req.ID, 0, data)
 }
 
 func (s *WebsocketServer) onRequest(c *websocketChannel, req *websocketReq) {
 	var err error
 	var data interface{}
+	start := time.Now()
+	ctx, span := tracing.Start(context.Background(), "websocket "+req.Method, tracing.KindServer)
+	req.ctx = ctx
+	defer func() {
+		span.SetError(err)
+		span.End()
+	}()
+	var size int
+	var errMsg string
+	if s.logs != nil {
+		req.requestID = newRequestID()
+	}
+	if s.logs.accessEnabled() {
+		// registered before the sending of the response, runs after it
+		defer func() {
+			e := accessLogEntry{
+				RequestID:  req.requestID,
+				TraceID:    span.TraceID(),
+				Interface:  "websocket",
+				Connection: c.id,
+				ClientIP:   c.clientIP,
+				Method:     req.Method,
+				Params:     string(req.Params),
+				Size:       size,
+				Error:      errMsg,
+			}
+			s.logs.logAccess(&e, start)
+		}()
+	}
 	defer func() {
 		if r := recover(); r != nil {
 			glog.Error("Client ", c.id, ", onRequest ", req.Method, " recovered from panic: ", r)
@@ -407,14 +551,35 @@ func (s *WebsocketServer) onRequest(c *websocketChannel, req *websocketReq) {
 			data = e
 		}
 		// nil data means no response
+		if data != nil && s.logs.accessEnabled() {
+			if re, isError := data.(resultError); isError {
+				errMsg = re.Error.Message
+			}
+			// encode the response here to get its size, the encoded response is sent as is
+			if b, err := json.Marshal(data); err == nil {
+				size = len(b)
+				data = json.RawMessage(b)
+			}
+		}
 		if data != nil {
 			sendResponse(c, req, data)
 		}
+		if req.afterResponse != nil {
+			req.afterResponse()
+		}
 	}()
 	t := time.Now()
 	defer s.metrics.WebsocketReqDuration.With(common.Labels{"method": req.Method}).Observe(float64(time.Since(t)) / 1e3) // in microseconds
 	f, ok := requestHandlers[req.Method]
 	if ok {
+		if ae := s.limiter.allow(c.clientIP, c.apiKey, s.websocketRequestCost(req), "websocket"); ae != nil {
+			glog.V(1).Info("Client ", c.id, " onRequest ", req.Method, " rejected: ", ae)
+			s.metrics.WebsocketRequests.With(common.Labels{"method": req.Method, "status": "rejected"}).Inc()
+			e := resultError{}
+			e.Error.Message = ae.Error()
+			data = e
+			return
+		}
 		data, err = f(s, c, req)
 		if err == nil {
 			glog.V(1).Info("Client ", c.id, " onRequest ", req.Method, " success")
@@ -443,6 +608,7 @@ type accountInfoReq struct {
 	ToHeight       int    `json:"to"`
 	ContractFilter string `json:"contractFilter"`
 	Gap            int    `json:"gap"`
+	Currency       string `json:"currency"`
 }
 
 func unmarshalGetAccountInfoRequest(params []byte) (*accountInfoReq, error) {
@@ -454,7 +620,7 @@ func unmarshalGetAccountInfoRequest(params []byte) (*accountInfoReq, error) {
 	return &r, nil
 }
 
-func (s *WebsocketServer) getAccountInfo(req *accountInfoReq) (res *api.Address, err error) {
+func (s *WebsocketServer) getAccountInfo(ctx context.Context, req *accountInfoReq) (res *api.Address, err error) {
 	var opt api.AccountDetails
 	switch req.Details {
 	case "tokens":
@@ -489,23 +655,32 @@ func (s *WebsocketServer) getAccountInfo(req *accountInfoReq) (res *api.Address,
 	if req.PageSize == 0 {
 		req.PageSize = txsOnPage
 	}
-	a, err := s.api.GetXpubAddress(req.Descriptor, req.Page, req.PageSize, opt, &filter, req.Gap)
+	w := s.api.WithContext(ctx)
+	a, err := w.GetXpubAddress(req.Descriptor, req.Page, req.PageSize, opt, &filter, req.Gap)
 	if err != nil {
-		return s.api.GetAddress(req.Descriptor, req.Page, req.PageSize, opt, &filter)
+		a, err = w.GetAddress(req.Descriptor, req.Page, req.PageSize, opt, &filter)
+		if err == nil && req.Currency != "" {
+			err = w.SetAddressFiat(a, req.Currency)
+		}
+		return a, err
+	}
+	if req.Currency != "" {
+		err = w.SetXpubFiat(a, req.Descriptor, req.Gap, req.Currency)
 	}
-	return a, nil
+	return a, err
 }
 
-func (s *WebsocketServer) getAccountUtxo(descriptor string) (interface{}, error) {
-	utxo, err := s.api.GetXpubUtxo(descriptor, false, 0)
+func (s *WebsocketServer) getAccountUtxo(ctx context.Context, descriptor string) (interface{}, error) {
+	w := s.api.WithContext(ctx)
+	utxo, err := w.GetXpubUtxo(descriptor, false, 0)
 	if err != nil {
-		return s.api.GetAddressUtxo(descriptor, false)
+		return w.GetAddressUtxo(descriptor, false)
 	}
 	return utxo, nil
 }
 
-func (s *WebsocketServer) getTransaction(txid string) (interface{}, error) {
-	return s.api.GetTransaction(txid, false, false)
+func (s *WebsocketServer) getTransaction(ctx context.Context, txid string) (interface{}, error) {
+	return s.api.WithContext(ctx).GetTransaction(txid, false, false)
 }
 
 func (s *WebsocketServer) getTransactionSpecific(txid string) (interface{}, error) {
@@ -553,30 +728,37 @@ func (s *WebsocketServer) getBlockHash(height int) (interface{}, error) {
 	}, nil
 }
 
+type estimateFeeReq struct {
+	Blocks   []int                  `json:"blocks"`
+	Specific map[string]interface{} `json:"specific"`
+}
+
+type estimateFeeRes struct {
+	FeePerTx   string `json:"feePerTx,omitempty"`
+	FeePerUnit string `json:"feePerUnit,omitempty"`
+	FeeLimit   string `json:"feeLimit,omitempty"`
+}
+
 func (s *WebsocketServer) estimateFee(c *websocketChannel, params []byte) (interface{}, error) {
-	type estimateFeeReq struct {
-		Blocks   []int                  `json:"blocks"`
-		Specific map[string]interface{} `json:"specific"`
-	}
-	type estimateFeeRes struct {
-		FeePerTx   string `json:"feePerTx,omitempty"`
-		FeePerUnit string `json:"feePerUnit,omitempty"`
-		FeeLimit   string `json:"feeLimit,omitempty"`
-	}
 	var r estimateFeeReq
 	err := json.Unmarshal(params, &r)
 	if err != nil {
 		return nil, err
 	}
+	return estimateFee(s.chain, &r)
+}
+
+// estimateFee returns the fee estimates for the requested numbers of blocks, shared by the websocket and gRPC interfaces
+func estimateFee(chain bchain.BlockChain, r *estimateFeeReq) ([]estimateFeeRes, error) {
 	res := make([]estimateFeeRes, len(r.Blocks))
-	if s.chainParser.GetChainType() == bchain.ChainEthereumType {
-		gas, err := s.chain.EthereumTypeEstimateGas(r.Specific)
+	if chain.GetChainParser().GetChainType() == bchain.ChainEthereumType {
+		gas, err := chain.EthereumTypeEstimateGas(r.Specific)
 		if err != nil {
 			return nil, err
 		}
 		sg := strconv.FormatUint(gas, 10)
 		for i, b := range r.Blocks {
-			fee, err := s.chain.EstimateSmartFee(b, true)
+			fee, err := chain.EstimateSmartFee(b, true)
 			if err != nil {
 				return nil, err
 			}
@@ -603,7 +785,7 @@ func (s *WebsocketServer) estimateFee(c *websocketChannel, params []byte) (inter
 			}
 		}
 		for i, b := range r.Blocks {
-			fee, err := s.chain.EstimateSmartFee(b, conservative)
+			fee, err := chain.EstimateSmartFee(b, conservative)
 			if err != nil {
 				return nil, err
 			}
@@ -619,8 +801,8 @@ func (s *WebsocketServer) estimateFee(c *websocketChannel, params []byte) (inter
 	return res, nil
 }
 
-func (s *WebsocketServer) sendTransaction(tx string) (res resultSendTransaction, err error) {
-	txid, err := s.chain.SendRawTransaction(tx)
+func (s *WebsocketServer) sendTransaction(ctx context.Context, tx string) (res resultSendTransaction, err error) {
+	txid, err := s.api.WithContext(ctx).SendTransaction(tx)
 	if err != nil {
 		return res, err
 	}
@@ -632,9 +814,217 @@ type subscriptionResponse struct {
 	Subscribed bool `json:"subscribed"`
 }
 
+// resumedSubscriptionResponse is returned if the client asks to resume the subscription by the parameter resumeFrom
+type resumedSubscriptionResponse struct {
+	Subscribed bool   `json:"subscribed"`
+	Seq        uint64 `json:"seq,omitempty"`
+	Replayed   int    `json:"replayed"`
+	Resync     bool   `json:"resync,omitempty"`
+}
+
+type newBlockNotification struct {
+	Height uint32 `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+type addressTxNotification struct {
+	Address string  `json:"address"`
+	Tx      *api.Tx `json:"tx"`
+}
+
+type accountNotificationAddress struct {
+	Address string `json:"address"`
+	Path    string `json:"path"`
+}
+
+type accountTxNotification struct {
+	Descriptor string                       `json:"descriptor"`
+	Addresses  []accountNotificationAddress `json:"addresses"`
+	Tx         *api.Tx                      `json:"tx"`
+}
+
+// unmarshalResumeFrom returns the optional parameter resumeFrom of the subscribe methods, 0 if not present
+func unmarshalResumeFrom(params []byte) uint64 {
+	r := struct {
+		ResumeFrom uint64 `json:"resumeFrom"`
+	}{}
+	if len(params) == 0 || json.Unmarshal(params, &r) != nil {
+		return 0
+	}
+	return r.ResumeFrom
+}
+
+// replayedNotification is a notification constructed from the logged event
+type replayedNotification struct {
+	seq  uint64
+	data interface{}
+}
+
+// resume sends to the channel the notifications emitted after the sequence number in the parameter resumeFrom,
+// the notifications are constructed from the logged events by the function replay
+// The response rv of the subscribe method is returned unchanged if the client does not ask to resume, otherwise
+// the replayed notifications are sent after the response
+// The notifications are complete only if the events of all keys of the subscription were logged since resumeFrom
+// The replayed notifications may overlap with the notifications sent after the subscription, the client should ignore
+// the notifications with already processed sequence numbers
+// If the notifications after resumeFrom are not available anymore, the client must resync its state
+func (s *WebsocketServer) resume(c *websocketChannel, req *websocketReq, rv interface{}, keys []string, replay func(e *notificationEvent) []interface{}) interface{} {
+	from := unmarshalResumeFrom(req.Params)
+	if from == 0 {
+		return rv
+	}
+	if s.notifications == nil {
+		return &resumedSubscriptionResponse{Subscribed: true, Resync: true}
+	}
+	r := &resumedSubscriptionResponse{Subscribed: true, Seq: s.notifications.lastSeq()}
+	events, complete := s.notifications.since(from)
+	// the events of the keys are logged only during and shortly after their subscriptions
+	if complete && !s.notifications.loggedSince(keys, from) {
+		complete = false
+	}
+	if !complete {
+		r.Resync = true
+		return r
+	}
+	var notifications []replayedNotification
+	for i := range events {
+		if !c.IsAlive() {
+			return nil
+		}
+		for _, data := range replay(&events[i]) {
+			notifications = append(notifications, replayedNotification{seq: events[i].Seq, data: data})
+		}
+	}
+	r.Replayed = len(notifications)
+	req.afterResponse = func() {
+		sent := 0
+		for i := range notifications {
+			if !c.send(req.ID, notifications[i].seq, notifications[i].data) {
+				break
+			}
+			sent++
+		}
+		glog.Info("Client ", c.id, " ", req.Method, " resumed from ", from, ", replayed ", sent, " of ", len(notifications), " notifications")
+	}
+	return r
+}
+
+// send sends the message to the channel, it returns false without sending if the channel is closed,
+// also if it is closed while waiting for the space in the output buffer
+func (c *websocketChannel) send(id string, seq uint64, data interface{}) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+	select {
+	case c.out <- &websocketRes{
+		ID:   id,
+		Seq:  seq,
+		Data: data,
+	}:
+		return true
+	case <-c.done:
+		return false
+	}
+}
+
+func (s *WebsocketServer) resumeNewBlock(c *websocketChannel, req *websocketReq, rv interface{}) interface{} {
+	return s.resume(c, req, rv, []string{blockTag}, func(e *notificationEvent) []interface{} {
+		if e.Kind != notificationBlock {
+			return nil
+		}
+		return []interface{}{&newBlockNotification{Height: e.Height, Hash: e.Hash}}
+	})
+}
+
+// getReplayedTx returns the transaction of the event or nil if the transaction does not exist anymore
+func (s *WebsocketServer) getReplayedTx(e *notificationEvent) *api.Tx {
+	tx, err := s.api.GetTransaction(e.Txid, false, false)
+	if err != nil {
+		glog.Warning("Replay of notification ", e.Seq, ", tx ", e.Txid, ": ", err)
+		return nil
+	}
+	return tx
+}
+
+func (s *WebsocketServer) resumeAddresses(c *websocketChannel, req *websocketReq, addrDescs []bchain.AddressDescriptor, rv interface{}) interface{} {
+	subscribed := make(map[string]struct{}, len(addrDescs))
+	keys := make([]string, len(addrDescs))
+	for i, ad := range addrDescs {
+		subscribed[string(ad)] = struct{}{}
+		keys[i] = addrTag(ad)
+	}
+	return s.resume(c, req, rv, keys, func(e *notificationEvent) []interface{} {
+		if e.Kind != notificationTx {
+			return nil
+		}
+		var tx *api.Tx
+		var n []interface{}
+		for _, ad := range e.AddrDescs {
+			if _, found := subscribed[string(ad)]; !found {
+				continue
+			}
+			addr, _, err := s.chainParser.GetAddressesFromAddrDesc(ad)
+			if err != nil || len(addr) != 1 {
+				continue
+			}
+			if tx == nil {
+				if tx = s.getReplayedTx(e); tx == nil {
+					return n
+				}
+			}
+			n = append(n, &addressTxNotification{Address: addr[0], Tx: tx})
+		}
+		return n
+	})
+}
+
+func (s *WebsocketServer) resumeAccounts(c *websocketChannel, req *websocketReq, rv interface{}) interface{} {
+	s.accountSubscriptionsLock.Lock()
+	subscriptions := append([]*accountSubscription(nil), s.accountSubscriptions[c]...)
+	var keys []string
+	for _, as := range subscriptions {
+		for ads := range as.addresses {
+			keys = append(keys, addrTag(bchain.AddressDescriptor(ads)))
+		}
+	}
+	s.accountSubscriptionsLock.Unlock()
+	return s.resume(c, req, rv, keys, func(e *notificationEvent) []interface{} {
+		if e.Kind != notificationTx {
+			return nil
+		}
+		var tx *api.Tx
+		var n []interface{}
+		for _, as := range subscriptions {
+			var addresses []accountNotificationAddress
+			s.accountSubscriptionsLock.Lock()
+			for _, ad := range e.AddrDescs {
+				if a, found := as.addresses[string(ad)]; found {
+					addresses = append(addresses, accountNotificationAddress{Address: a.address, Path: a.path})
+				}
+			}
+			s.accountSubscriptionsLock.Unlock()
+			if len(addresses) == 0 {
+				continue
+			}
+			if tx == nil {
+				if tx = s.getReplayedTx(e); tx == nil {
+					return n
+				}
+			}
+			n = append(n, &accountTxNotification{Descriptor: as.descriptor, Addresses: addresses, Tx: tx})
+		}
+		return n
+	})
+}
+
 func (s *WebsocketServer) subscribeNewBlock(c *websocketChannel, req *websocketReq) (res interface{}, err error) {
 	s.newBlockSubscriptionsLock.Lock()
 	defer s.newBlockSubscriptionsLock.Unlock()
+	if _, found := s.newBlockSubscriptions[c]; !found {
+		s.notifications.subscribe(blockTag)
+	}
 	s.newBlockSubscriptions[c] = req.ID
 	return &subscriptionResponse{true}, nil
 }
@@ -642,7 +1032,10 @@ func (s *WebsocketServer) subscribeNewBlock(c *websocketChannel, req *websocketR
 func (s *WebsocketServer) unsubscribeNewBlock(c *websocketChannel) (res interface{}, err error) {
 	s.newBlockSubscriptionsLock.Lock()
 	defer s.newBlockSubscriptionsLock.Unlock()
-	delete(s.newBlockSubscriptions, c)
+	if _, found := s.newBlockSubscriptions[c]; found {
+		s.notifications.unsubscribe(blockTag)
+		delete(s.newBlockSubscriptions, c)
+	}
 	return &subscriptionResponse{false}, nil
 }
 
@@ -670,6 +1063,7 @@ func (s *WebsocketServer) subscribeAddresses(c *websocketChannel, addrDesc []bch
 	s.unsubscribeAddresses(c)
 	s.addressSubscriptionsLock.Lock()
 	defer s.addressSubscriptionsLock.Unlock()
+	keys := make([]string, 0, len(addrDesc))
 	for i := range addrDesc {
 		ads := string(addrDesc[i])
 		as, ok := s.addressSubscriptions[ads]
@@ -677,8 +1071,12 @@ func (s *WebsocketServer) subscribeAddresses(c *websocketChannel, addrDesc []bch
 			as = make(map[*websocketChannel]string)
 			s.addressSubscriptions[ads] = as
 		}
+		if _, found := as[c]; !found {
+			keys = append(keys, addrTag(addrDesc[i]))
+		}
 		as[c] = req.ID
 	}
+	s.notifications.subscribe(keys...)
 	return &subscriptionResponse{true}, nil
 }
 
@@ -686,19 +1084,290 @@ func (s *WebsocketServer) subscribeAddresses(c *websocketChannel, addrDesc []bch
 func (s *WebsocketServer) unsubscribeAddresses(c *websocketChannel) (res interface{}, err error) {
 	s.addressSubscriptionsLock.Lock()
 	defer s.addressSubscriptionsLock.Unlock()
+	var keys []string
 	for ads, sa := range s.addressSubscriptions {
-		for sc := range sa {
-			if sc == c {
-				delete(sa, c)
-			}
+		if _, found := sa[c]; found {
+			delete(sa, c)
+			keys = append(keys, addrTag(bchain.AddressDescriptor(ads)))
 		}
 		if len(sa) == 0 {
 			delete(s.addressSubscriptions, ads)
 		}
 	}
+	s.notifications.unsubscribe(keys...)
+	return &subscriptionResponse{false}, nil
+}
+
+// accountAddress is an address of a subscribed account
+type accountAddress struct {
+	address string
+	path    string
+	change  int
+	index   int
+}
+
+// accountSubscription watches addresses derived from an xpub, the watched set is extended
+// so that there are always at least gap unused addresses after the last used address on each chain
+type accountSubscription struct {
+	c          *websocketChannel
+	id         string
+	descriptor string
+	gap        int
+	lastUsed   [2]int
+	count      [2]int
+	addresses  map[string]*accountAddress
+}
+
+// addAddresses adds derived addresses to the subscription and returns address descriptors of the added addresses
+func (as *accountSubscription) addAddresses(addresses []api.XpubDerivedAddress) []string {
+	added := make([]string, 0, len(addresses))
+	for i := range addresses {
+		a := &addresses[i]
+		if a.Change < 0 || a.Change > 1 {
+			continue
+		}
+		if a.Used && a.Index > as.lastUsed[a.Change] {
+			as.lastUsed[a.Change] = a.Index
+		}
+		if a.Index >= as.count[a.Change] {
+			as.count[a.Change] = a.Index + 1
+		}
+		ads := string(a.AddrDesc)
+		if _, found := as.addresses[ads]; !found {
+			as.addresses[ads] = &accountAddress{address: a.Address, path: a.Path, change: a.Change, index: a.Index}
+			added = append(added, ads)
+		}
+	}
+	return added
+}
+
+// markUsed marks the address as used and returns the range of indexes of addresses on the chain of the address,
+// which must be derived to keep the gap
+func (as *accountSubscription) markUsed(a *accountAddress) (int, int) {
+	if a.index > as.lastUsed[a.change] {
+		as.lastUsed[a.change] = a.index
+	}
+	if to := as.lastUsed[a.change] + as.gap + 1; to > as.count[a.change] {
+		return as.count[a.change], to
+	}
+	return 0, 0
+}
+
+// isAccountSubscribed checks if the subscription is still active, must be called with accountSubscriptionsLock held
+func (s *WebsocketServer) isAccountSubscribed(as *accountSubscription) bool {
+	for _, a := range s.accountSubscriptions[as.c] {
+		if a == as {
+			return true
+		}
+	}
+	return false
+}
+
+// indexAccountAddresses adds the addresses to the lookup of subscribed addresses, must be called with accountSubscriptionsLock held
+func (s *WebsocketServer) indexAccountAddresses(as *accountSubscription, addrDescs []string) {
+	keys := make([]string, 0, len(addrDescs))
+	for _, ads := range addrDescs {
+		sa, found := s.accountAddresses[ads]
+		if !found {
+			sa = make(map[*accountSubscription]struct{})
+			s.accountAddresses[ads] = sa
+		}
+		if _, found = sa[as]; !found {
+			keys = append(keys, addrTag(bchain.AddressDescriptor(ads)))
+		}
+		sa[as] = struct{}{}
+	}
+	s.notifications.subscribe(keys...)
+}
+
+// subscribeAccounts subscribes notifications about transactions of the addresses derived from the descriptors (xpubs)
+func (s *WebsocketServer) subscribeAccounts(c *websocketChannel, descriptors []string, gap int, req *websocketReq) (res interface{}, err error) {
+	if len(descriptors) == 0 {
+		return nil, errors.New("Missing descriptors")
+	}
+	subscriptions := make([]*accountSubscription, len(descriptors))
+	for i, d := range descriptors {
+		da, err := s.api.WithContext(req.ctx).GetXpubDerivedAddresses(d, gap)
+		if err != nil {
+			if err == api.ErrUnsupportedXpub {
+				err = errors.New("XPUB functionality is not supported")
+			}
+			return nil, err
+		}
+		as := &accountSubscription{
+			c:          c,
+			id:         req.ID,
+			descriptor: d,
+			gap:        da.Gap,
+			addresses:  make(map[string]*accountAddress, len(da.Addresses)),
+		}
+		as.addAddresses(da.Addresses)
+		subscriptions[i] = as
+	}
+	// unsubscribe all previous subscriptions
+	s.unsubscribeAccounts(c)
+	s.accountSubscriptionsLock.Lock()
+	defer s.accountSubscriptionsLock.Unlock()
+	s.accountSubscriptions[c] = subscriptions
+	for _, as := range subscriptions {
+		addrDescs := make([]string, 0, len(as.addresses))
+		for ads := range as.addresses {
+			addrDescs = append(addrDescs, ads)
+		}
+		s.indexAccountAddresses(as, addrDescs)
+	}
+	return &subscriptionResponse{true}, nil
+}
+
+// unsubscribeAccounts unsubscribes all account subscriptions by this channel
+func (s *WebsocketServer) unsubscribeAccounts(c *websocketChannel) (res interface{}, err error) {
+	s.accountSubscriptionsLock.Lock()
+	defer s.accountSubscriptionsLock.Unlock()
+	var keys []string
+	for _, as := range s.accountSubscriptions[c] {
+		for ads := range as.addresses {
+			if sa, found := s.accountAddresses[ads]; found {
+				if _, found = sa[as]; found {
+					delete(sa, as)
+					keys = append(keys, addrTag(bchain.AddressDescriptor(ads)))
+				}
+				if len(sa) == 0 {
+					delete(s.accountAddresses, ads)
+				}
+			}
+		}
+	}
+	s.notifications.unsubscribe(keys...)
+	delete(s.accountSubscriptions, c)
 	return &subscriptionResponse{false}, nil
 }
 
+// extendAccount derives addresses of the account on the chain in the range [from, to) and adds them to the subscription
+func (s *WebsocketServer) extendAccount(as *accountSubscription, change, from, to int) {
+	addresses, err := s.api.DeriveXpubAddresses(as.descriptor, change, from, to)
+	if err != nil {
+		glog.Error("DeriveXpubAddresses error ", err, " for ", as.descriptor)
+		return
+	}
+	s.accountSubscriptionsLock.Lock()
+	defer s.accountSubscriptionsLock.Unlock()
+	if s.isAccountSubscribed(as) {
+		s.indexAccountAddresses(as, as.addAddresses(addresses))
+	}
+}
+
+// accountExtension is a range of indexes [from, to) of addresses on the chain of the account, which must be derived
+type accountExtension struct {
+	as       *accountSubscription
+	change   int
+	from, to int
+}
+
+// requestAccountsRefresh runs refreshAccountSubscriptions in background, the requests coming while it runs
+// are coalesced to one more run
+func (s *WebsocketServer) requestAccountsRefresh() {
+	atomic.StoreInt32(&s.accountsRefreshPending, 1)
+	if !atomic.CompareAndSwapInt32(&s.accountsRefreshRunning, 0, 1) {
+		return
+	}
+	go func() {
+		for {
+			for atomic.SwapInt32(&s.accountsRefreshPending, 0) == 1 {
+				s.refreshAccountSubscriptions()
+			}
+			atomic.StoreInt32(&s.accountsRefreshRunning, 0)
+			// the request may have come after the last check of pending
+			if atomic.LoadInt32(&s.accountsRefreshPending) == 0 || !atomic.CompareAndSwapInt32(&s.accountsRefreshRunning, 0, 1) {
+				return
+			}
+		}
+	}()
+}
+
+// refreshAccountSubscriptions extends the subscriptions by the addresses after the addresses used by the confirmed transactions,
+// only the addresses from the last used address to the end of the gap are checked
+func (s *WebsocketServer) refreshAccountSubscriptions() {
+	type candidate struct {
+		as *accountSubscription
+		a  *accountAddress
+		ad bchain.AddressDescriptor
+	}
+	var candidates []candidate
+	s.accountSubscriptionsLock.Lock()
+	for _, sa := range s.accountSubscriptions {
+		for _, as := range sa {
+			for ads, a := range as.addresses {
+				if a.index >= as.lastUsed[a.change] {
+					candidates = append(candidates, candidate{as, a, bchain.AddressDescriptor(ads)})
+				}
+			}
+		}
+	}
+	s.accountSubscriptionsLock.Unlock()
+	var used []candidate
+	for _, c := range candidates {
+		ba, err := s.db.GetAddrDescBalance(c.ad, db.AddressBalanceDetailNoUTXO)
+		if err != nil {
+			glog.Error("GetAddrDescBalance error ", err, " for ", c.a.address)
+			continue
+		}
+		if ba != nil {
+			used = append(used, c)
+		}
+	}
+	var extensions []accountExtension
+	s.accountSubscriptionsLock.Lock()
+	for _, c := range used {
+		if !s.isAccountSubscribed(c.as) {
+			continue
+		}
+		if from, to := c.as.markUsed(c.a); to > from {
+			extensions = append(extensions, accountExtension{c.as, c.a.change, from, to})
+		}
+	}
+	s.accountSubscriptionsLock.Unlock()
+	for _, e := range extensions {
+		s.extendAccount(e.as, e.change, e.from, e.to)
+	}
+}
+
+// sendOnNewTxAccounts notifies the accounts about the tx, marks the addresses as used and extends the accounts if necessary
+// The notifications are sent after the lock of the subscriptions is released, so that a slow client does not block the others
+func (s *WebsocketServer) sendOnNewTxAccounts(accounts map[*accountSubscription][]*accountAddress, tx *api.Tx, seq uint64) {
+	type target struct {
+		as   *accountSubscription
+		data *accountTxNotification
+	}
+	var targets []target
+	var extensions []accountExtension
+	s.accountSubscriptionsLock.Lock()
+	for as, addresses := range accounts {
+		if !s.isAccountSubscribed(as) {
+			continue
+		}
+		data := &accountTxNotification{
+			Descriptor: as.descriptor,
+			Addresses:  make([]accountNotificationAddress, len(addresses)),
+			Tx:         tx,
+		}
+		for i, a := range addresses {
+			data.Addresses[i] = accountNotificationAddress{Address: a.address, Path: a.path}
+			if from, to := as.markUsed(a); to > from {
+				extensions = append(extensions, accountExtension{as, a.change, from, to})
+			}
+		}
+		targets = append(targets, target{as, data})
+	}
+	s.accountSubscriptionsLock.Unlock()
+	for _, t := range targets {
+		t.as.c.send(t.as.id, seq, t.data)
+	}
+	glog.Info("broadcasting new tx ", tx.Txid, " to ", len(targets), " accounts")
+	for _, e := range extensions {
+		s.extendAccount(e.as, e.change, e.from, e.to)
+	}
+}
+
 // subscribeFiatRates subscribes all FiatRates subscriptions by this channel
 func (s *WebsocketServer) subscribeFiatRates(c *websocketChannel, currency string, req *websocketReq) (res interface{}, err error) {
 	// unsubscribe all previous subscriptions
@@ -737,27 +1406,33 @@ func (s *WebsocketServer) unsubscribeFiatRates(c *websocketChannel) (res interfa
 
 // OnNewBlock is a callback that broadcasts info about new block to subscribed clients
 func (s *WebsocketServer) OnNewBlock(hash string, height uint32) {
+	var seq uint64
+	if s.notifications != nil {
+		if s.notifications.logged(blockTag) {
+			seq = s.notifications.append(notificationEvent{Kind: notificationBlock, Height: height, Hash: hash})
+		}
+		s.notifications.pruneInterest()
+	}
 	s.newBlockSubscriptionsLock.Lock()
 	defer s.newBlockSubscriptionsLock.Unlock()
-	data := struct {
-		Height uint32 `json:"height"`
-		Hash   string `json:"hash"`
-	}{
+	data := newBlockNotification{
 		Height: height,
 		Hash:   hash,
 	}
 	for c, id := range s.newBlockSubscriptions {
-		if c.IsAlive() {
-			c.out <- &websocketRes{
-				ID:   id,
-				Data: &data,
-			}
-		}
+		c.send(id, seq, &data)
 	}
 	glog.Info("broadcasting new block ", height, " ", hash, " to ", len(s.newBlockSubscriptions), " channels")
+	// the confirmed transactions may have used new addresses of the subscribed accounts
+	s.accountSubscriptionsLock.Lock()
+	refresh := len(s.accountSubscriptions) > 0
+	s.accountSubscriptionsLock.Unlock()
+	if refresh {
+		s.requestAccountsRefresh()
+	}
 }
 
-func (s *WebsocketServer) sendOnNewTxAddr(stringAddressDescriptor string, tx *api.Tx) {
+func (s *WebsocketServer) sendOnNewTxAddr(stringAddressDescriptor string, tx *api.Tx, seq uint64) {
 	addrDesc := bchain.AddressDescriptor(stringAddressDescriptor)
 	addr, _, err := s.chainParser.GetAddressesFromAddrDesc(addrDesc)
 	if err != nil {
@@ -765,10 +1440,7 @@ func (s *WebsocketServer) sendOnNewTxAddr(stringAddressDescriptor string, tx *ap
 		return
 	}
 	if len(addr) == 1 {
-		data := struct {
-			Address string  `json:"address"`
-			Tx      *api.Tx `json:"tx"`
-		}{
+		data := addressTxNotification{
 			Address: addr[0],
 			Tx:      tx,
 		}
@@ -778,72 +1450,116 @@ func (s *WebsocketServer) sendOnNewTxAddr(stringAddressDescriptor string, tx *ap
 		as, ok := s.addressSubscriptions[stringAddressDescriptor]
 		if ok {
 			for c, id := range as {
-				if c.IsAlive() {
-					c.out <- &websocketRes{
-						ID:   id,
-						Data: &data,
-					}
-				}
+				c.send(id, seq, &data)
 			}
 			glog.Info("broadcasting new tx ", tx.Txid, ", addr ", addr[0], " to ", len(as), " channels")
 		}
 	}
 }
 
-// OnNewTx is a callback that broadcasts info about a tx affecting subscribed address
-func (s *WebsocketServer) OnNewTx(tx *bchain.MempoolTx) {
-	// check if there is any subscription in inputs, outputs and erc20
-	// release the lock immediately, GetTransactionFromMempoolTx is potentially slow
-	subscribed := make(map[string]struct{})
-	s.addressSubscriptionsLock.Lock()
-	for i := range tx.Vin {
-		sad := string(tx.Vin[i].AddrDesc)
-		if len(sad) > 0 {
-			as, ok := s.addressSubscriptions[sad]
-			if ok && len(as) > 0 {
-				subscribed[sad] = struct{}{}
-			}
+// getTxAddrDescs returns unique address descriptors of inputs, outputs and erc20 transfers of the tx
+func (s *WebsocketServer) getTxAddrDescs(tx *bchain.MempoolTx) []bchain.AddressDescriptor {
+	return getTxAddrDescs(s.chainParser, tx)
+}
+
+// getTxAddrDescs returns unique address descriptors of inputs, outputs and erc20 transfers of the tx using the parser,
+// it is shared by the websocket and gRPC interfaces
+func getTxAddrDescs(parser bchain.BlockChainParser, tx *bchain.MempoolTx) []bchain.AddressDescriptor {
+	var addrDescs []bchain.AddressDescriptor
+	seen := make(map[string]struct{})
+	add := func(addrDesc bchain.AddressDescriptor) {
+		sad := string(addrDesc)
+		if len(sad) == 0 {
+			return
 		}
+		if _, found := seen[sad]; !found {
+			seen[sad] = struct{}{}
+			addrDescs = append(addrDescs, addrDesc)
+		}
+	}
+	for i := range tx.Vin {
+		add(tx.Vin[i].AddrDesc)
 	}
 	for i := range tx.Vout {
-		addrDesc, err := s.chainParser.GetAddrDescFromVout(&tx.Vout[i])
-		if err == nil && len(addrDesc) > 0 {
-			sad := string(addrDesc)
-			as, ok := s.addressSubscriptions[sad]
-			if ok && len(as) > 0 {
-				subscribed[sad] = struct{}{}
-			}
+		addrDesc, err := parser.GetAddrDescFromVout(&tx.Vout[i])
+		if err == nil {
+			add(addrDesc)
 		}
 	}
 	for i := range tx.Erc20 {
-		addrDesc, err := s.chainParser.GetAddrDescFromAddress(tx.Erc20[i].From)
-		if err == nil && len(addrDesc) > 0 {
-			sad := string(addrDesc)
-			as, ok := s.addressSubscriptions[sad]
-			if ok && len(as) > 0 {
-				subscribed[sad] = struct{}{}
-			}
+		addrDesc, err := parser.GetAddrDescFromAddress(tx.Erc20[i].From)
+		if err == nil {
+			add(addrDesc)
 		}
-		addrDesc, err = s.chainParser.GetAddrDescFromAddress(tx.Erc20[i].To)
-		if err == nil && len(addrDesc) > 0 {
-			sad := string(addrDesc)
-			as, ok := s.addressSubscriptions[sad]
-			if ok && len(as) > 0 {
-				subscribed[sad] = struct{}{}
+		addrDesc, err = parser.GetAddrDescFromAddress(tx.Erc20[i].To)
+		if err == nil {
+			add(addrDesc)
+		}
+	}
+	return addrDescs
+}
+
+// OnNewTx is a callback that broadcasts info about a tx affecting subscribed address
+func (s *WebsocketServer) OnNewTx(tx *bchain.MempoolTx) {
+	addrDescs := s.getTxAddrDescs(tx)
+	var seq uint64
+	if s.notifications != nil && len(addrDescs) > 0 {
+		keys := make([]string, len(addrDescs))
+		for i := range addrDescs {
+			keys[i] = addrTag(addrDescs[i])
+		}
+		// log only the transactions of the subscribed addresses, which can be resumed
+		if s.notifications.logged(keys...) {
+			e := notificationEvent{Kind: notificationTx, Txid: tx.Txid, AddrDescs: make([][]byte, len(addrDescs))}
+			for i := range addrDescs {
+				e.AddrDescs[i] = addrDescs[i]
 			}
+			seq = s.notifications.append(e)
+		}
+	}
+	// check if there is any subscription in inputs, outputs and erc20
+	// release the lock immediately, GetTransactionFromMempoolTx is potentially slow
+	subscribed := make(map[string]struct{})
+	s.addressSubscriptionsLock.Lock()
+	for _, addrDesc := range addrDescs {
+		sad := string(addrDesc)
+		as, ok := s.addressSubscriptions[sad]
+		if ok && len(as) > 0 {
+			subscribed[sad] = struct{}{}
 		}
 	}
 	s.addressSubscriptionsLock.Unlock()
-	if len(subscribed) > 0 {
+	accounts := s.getTxAccounts(addrDescs)
+	if len(subscribed) > 0 || len(accounts) > 0 {
 		atx, err := s.api.GetTransactionFromMempoolTx(tx)
 		if err != nil {
 			glog.Error("GetTransactionFromMempoolTx error ", err, " for ", tx.Txid)
 			return
 		}
 		for stringAddressDescriptor := range subscribed {
-			s.sendOnNewTxAddr(stringAddressDescriptor, atx)
+			s.sendOnNewTxAddr(stringAddressDescriptor, atx, seq)
+		}
+		if len(accounts) > 0 {
+			s.sendOnNewTxAccounts(accounts, atx, seq)
+		}
+	}
+}
+
+// getTxAccounts returns the subscribed accounts with their addresses affected by the tx
+func (s *WebsocketServer) getTxAccounts(addrDescs []bchain.AddressDescriptor) map[*accountSubscription][]*accountAddress {
+	s.accountSubscriptionsLock.Lock()
+	defer s.accountSubscriptionsLock.Unlock()
+	if len(s.accountAddresses) == 0 {
+		return nil
+	}
+	accounts := make(map[*accountSubscription][]*accountAddress)
+	for _, addrDesc := range addrDescs {
+		sad := string(addrDesc)
+		for as := range s.accountAddresses[sad] {
+			accounts[as] = append(accounts[as], as.addresses[sad])
 		}
 	}
+	return accounts
 }
 
 func (s *WebsocketServer) broadcastTicker(currency string, rates map[string]float64) {
@@ -860,12 +1576,7 @@ func (s *WebsocketServer) broadcastTicker(currency string, rates map[string]floa
 		as, ok = s.fiatRatesSubscriptions[currency]
 		if ok {
 			for c, id := range as {
-				if c.IsAlive() {
-					c.out <- &websocketRes{
-						ID:   id,
-						Data: &data,
-					}
-				}
+				c.send(id, 0, &data)
 			}
 			glog.Info("broadcasting new rates for currency ", currency, " to ", len(as), " channels")
 		}
@@ -880,17 +1591,17 @@ func (s *WebsocketServer) OnNewFiatRatesTicker(ticker *db.CurrencyRatesTicker) {
 	s.broadcastTicker(allFiatRates, ticker.Rates)
 }
 
-func (s *WebsocketServer) getCurrentFiatRates(currencies []string) (interface{}, error) {
-	ret, err := s.api.GetCurrentFiatRates(currencies)
+func (s *WebsocketServer) getCurrentFiatRates(ctx context.Context, currencies []string) (interface{}, error) {
+	ret, err := s.api.WithContext(ctx).GetCurrentFiatRates(currencies)
 	return ret, err
 }
 
-func (s *WebsocketServer) getFiatRatesForTimestamps(timestamps []int64, currencies []string) (interface{}, error) {
-	ret, err := s.api.GetFiatRatesForTimestamps(timestamps, currencies)
+func (s *WebsocketServer) getFiatRatesForTimestamps(ctx context.Context, timestamps []int64, currencies []string) (interface{}, error) {
+	ret, err := s.api.WithContext(ctx).GetFiatRatesForTimestamps(timestamps, currencies)
 	return ret, err
 }
 
-func (s *WebsocketServer) getFiatRatesTickersList(timestamp int64) (interface{}, error) {
-	ret, err := s.api.GetFiatRatesTickersList(timestamp)
+func (s *WebsocketServer) getFiatRatesTickersList(ctx context.Context, timestamp int64) (interface{}, error) {
+	ret, err := s.api.WithContext(ctx).GetFiatRatesTickersList(timestamp)
 	return ret, err
 }
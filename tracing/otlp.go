@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlphttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+)
+
+const (
+	// maximum number of spans waiting for the export, the spans over the limit are dropped
+	exportQueueSize = 4096
+	// maximum number of spans in one export request
+	exportBatchSize = 512
+	// interval of export of the waiting spans
+	exportInterval = 5 * time.Second
+	// timeout of one export request
+	exportTimeout = 10 * time.Second
+)
+
+// Config is the configuration of tracing
+type Config struct {
+	// Endpoint is the url of the OTLP/HTTP traces receiver of the collector, for example http://localhost:4318/v1/traces
+	Endpoint string
+	// ServiceName is reported as the resource attribute service.name
+	ServiceName string
+	// Attributes are additional resource attributes
+	Attributes map[string]string
+	// SampleRatio is the ratio of the sampled traces, it applies also to the traces continuing a remote parent,
+	// so that the clients cannot force the tracing of all their requests
+	SampleRatio float64
+}
+
+type errorHandler struct{}
+
+func (errorHandler) Handle(err error) {
+	glog.Warning("tracing: ", err)
+}
+
+// Init enables the tracing with the exporter of the spans to the collector
+// It must be called before the spans are started, typically at the start of the program
+func Init(config Config) error {
+	if config.Endpoint == "" {
+		return errors.New("Missing endpoint")
+	}
+	if config.SampleRatio <= 0 || config.SampleRatio > 1 {
+		return errors.Errorf("Invalid sample ratio %v", config.SampleRatio)
+	}
+	u, err := url.Parse(config.Endpoint)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.Errorf("Invalid endpoint %v", config.Endpoint)
+	}
+	opts := []otlphttp.Option{otlphttp.WithEndpoint(u.Host), otlphttp.WithTimeout(exportTimeout)}
+	if u.Path != "" {
+		opts = append(opts, otlphttp.WithTracesURLPath(u.Path))
+	}
+	if u.Scheme == "http" {
+		opts = append(opts, otlphttp.WithInsecure())
+	}
+	exporter, err := otlp.NewExporter(context.Background(), otlphttp.NewDriver(opts...))
+	if err != nil {
+		return err
+	}
+	attributes := []attribute.KeyValue{semconv.ServiceNameKey.String(config.ServiceName)}
+	for k, v := range config.Attributes {
+		attributes = append(attributes, attribute.String(k, v))
+	}
+	sampler := sdktrace.TraceIDRatioBased(config.SampleRatio)
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler,
+			sdktrace.WithRemoteParentSampled(sampler),
+			sdktrace.WithRemoteParentNotSampled(sampler),
+		)),
+		sdktrace.WithResource(resource.NewWithAttributes(attributes...)),
+		sdktrace.WithBatcher(exporter,
+			sdktrace.WithMaxQueueSize(exportQueueSize),
+			sdktrace.WithMaxExportBatchSize(exportBatchSize),
+			sdktrace.WithBatchTimeout(exportInterval),
+		),
+	)
+	otel.SetErrorHandler(errorHandler{})
+	current.Store(&provider{tp: tp, tracer: tp.Tracer("blockbook")})
+	glog.Info("tracing: exporting ", config.SampleRatio*100, "% of traces to ", config.Endpoint)
+	return nil
+}
+
+// Shutdown exports the waiting spans and disables the tracing
+func Shutdown(ctx context.Context) error {
+	p := getProvider()
+	if p == nil {
+		return nil
+	}
+	current.Store((*provider)(nil))
+	return p.tp.Shutdown(ctx)
+}
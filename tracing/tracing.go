@@ -0,0 +1,144 @@
+// Package tracing records spans of the processing of requests using the OpenTelemetry SDK
+// and exports them to a collector by the OTLP/HTTP protocol.
+//
+// Tracing is disabled until Init is called, the disabled functions return nil spans and have negligible cost.
+// The methods of Span can be called on nil spans.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanKind is the kind of the span as defined by OpenTelemetry
+type SpanKind int
+
+// Kinds of spans
+const (
+	KindInternal SpanKind = SpanKind(trace.SpanKindInternal)
+	KindServer   SpanKind = SpanKind(trace.SpanKindServer)
+	KindClient   SpanKind = SpanKind(trace.SpanKindClient)
+)
+
+// Span is a timed operation of a trace
+type Span struct {
+	span trace.Span
+}
+
+type spanContextKey struct{}
+
+// provider is the tracer provider set by Init with the tracer of blockbook
+type provider struct {
+	tp     *sdktrace.TracerProvider
+	tracer trace.Tracer
+}
+
+// current provider is set by Init, nil provider means that the tracing is disabled
+var current atomic.Value
+
+func getProvider() *provider {
+	p, _ := current.Load().(*provider)
+	return p
+}
+
+// Enabled returns true if the tracing is enabled
+func Enabled() bool {
+	return getProvider() != nil
+}
+
+// Start starts a span, child of the span in the context, and returns the context containing the new span
+// If there is no span in the context, the span starts a new trace, continuing the remote parent from Extract if present
+func Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	p := getProvider()
+	if p == nil {
+		return ctx, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := p.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKind(kind)))
+	s := &Span{span: span}
+	return context.WithValue(ctx, spanContextKey{}, s), s
+}
+
+// StartChild starts a span only if the context already contains a span, otherwise it returns nil
+func StartChild(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	if ctx == nil || FromContext(ctx) == nil {
+		return ctx, nil
+	}
+	return Start(ctx, name, kind)
+}
+
+// FromContext returns the span of the context started by Start, nil if there is none
+func FromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(spanContextKey{}).(*Span)
+	return s
+}
+
+// SetAttribute sets an attribute of the span, the value can be a string, bool, integer or float
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil || !s.span.IsRecording() {
+		return
+	}
+	var kv attribute.KeyValue
+	switch v := value.(type) {
+	case string:
+		kv = attribute.String(key, v)
+	case bool:
+		kv = attribute.Bool(key, v)
+	case int:
+		kv = attribute.Int(key, v)
+	case int64:
+		kv = attribute.Int64(key, v)
+	case uint32:
+		kv = attribute.Int64(key, int64(v))
+	case float64:
+		kv = attribute.Float64(key, v)
+	default:
+		kv = attribute.Any(key, v)
+	}
+	s.span.SetAttributes(kv)
+}
+
+// SetError marks the span as failed with the error, nil error is ignored
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil || !s.span.IsRecording() {
+		return
+	}
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End ends the span and passes it to the exporter
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.span.End()
+}
+
+// TraceID returns the hex encoded id of the trace of the span, empty string for nil span
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return s.span.SpanContext().TraceID().String()
+}
+
+// Extract returns the context with the remote parent from the W3C traceparent header of the request
+// The sampling decision of the remote parent is not followed, the traces are sampled by the local sampler
+func Extract(ctx context.Context, h http.Header) context.Context {
+	if !Enabled() {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.HeaderCarrier(h))
+}
@@ -0,0 +1,175 @@
+// +build unittest
+
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDisabled(t *testing.T) {
+	ctx, span := Start(context.Background(), "test", KindServer)
+	if span != nil {
+		t.Fatal("span started with disabled tracing")
+	}
+	if FromContext(ctx) != nil {
+		t.Error("context contains a span")
+	}
+	// the methods must be callable on nil span
+	span.SetAttribute("key", "value")
+	span.SetError(errors.New("error"))
+	span.End()
+}
+
+// testCollector is the OTLP/HTTP traces receiver collecting the exported spans
+type testCollector struct {
+	*httptest.Server
+	lock     sync.Mutex
+	spans    []*tracepb.Span
+	resource []*commonpb.KeyValue
+}
+
+func newTestCollector(t *testing.T) *testCollector {
+	c := &testCollector{}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("export to path %v, want /v1/traces", r.URL.Path)
+		}
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		var req coltracepb.ExportTraceServiceRequest
+		if err = proto.Unmarshal(b, &req); err != nil {
+			t.Error(err)
+		}
+		c.lock.Lock()
+		for _, rs := range req.ResourceSpans {
+			c.resource = rs.Resource.GetAttributes()
+			for _, ils := range rs.InstrumentationLibrarySpans {
+				c.spans = append(c.spans, ils.Spans...)
+			}
+		}
+		c.lock.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	return c
+}
+
+func TestExport(t *testing.T) {
+	collector := newTestCollector(t)
+	defer collector.Close()
+
+	if err := Init(Config{Endpoint: collector.URL + "/v1/traces", ServiceName: "blockbook-test", SampleRatio: 1.5}); err == nil {
+		t.Fatal("Init accepted invalid sample ratio")
+	}
+	if err := Init(Config{Endpoint: "localhost:4318", ServiceName: "blockbook-test", SampleRatio: 1}); err == nil {
+		t.Fatal("Init accepted endpoint without scheme")
+	}
+	if err := Init(Config{Endpoint: collector.URL + "/v1/traces", ServiceName: "blockbook-test", SampleRatio: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	h := http.Header{}
+	h.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, parent := Start(Extract(context.Background(), h), "http apiTx", KindServer)
+	if got := parent.TraceID(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("TraceID() = %v, want 4bf92f3577b34da6a3ce929d0e0e4736", got)
+	}
+	parent.SetAttribute("http.status_code", 400)
+	_, child := StartChild(ctx, "rpc.GetTransaction", KindClient)
+	child.SetError(errors.New("tx not found"))
+	child.End()
+	parent.End()
+	if _, span := StartChild(context.Background(), "db.GetTxAddresses", KindClient); span != nil {
+		t.Error("StartChild started a span without parent")
+	}
+
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if Enabled() {
+		t.Error("tracing enabled after Shutdown")
+	}
+
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+	if len(collector.spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(collector.spans))
+	}
+	c, p := collector.spans[0], collector.spans[1]
+	if c.Name != "rpc.GetTransaction" || p.Name != "http apiTx" {
+		t.Errorf("exported spans %v and %v, want rpc.GetTransaction and http apiTx", c.Name, p.Name)
+	}
+	if c.Kind != tracepb.Span_SPAN_KIND_CLIENT || p.Kind != tracepb.Span_SPAN_KIND_SERVER {
+		t.Errorf("kinds %v and %v, want client and server", c.Kind, p.Kind)
+	}
+	if hex.EncodeToString(p.TraceId) != "4bf92f3577b34da6a3ce929d0e0e4736" || hex.EncodeToString(c.TraceId) != hex.EncodeToString(p.TraceId) {
+		t.Errorf("trace ids %x and %x, want 4bf92f3577b34da6a3ce929d0e0e4736", c.TraceId, p.TraceId)
+	}
+	if hex.EncodeToString(p.ParentSpanId) != "00f067aa0ba902b7" || hex.EncodeToString(c.ParentSpanId) != hex.EncodeToString(p.SpanId) {
+		t.Errorf("parent ids %x and %x, want %x and 00f067aa0ba902b7", c.ParentSpanId, p.ParentSpanId, p.SpanId)
+	}
+	if c.Status.GetCode() != tracepb.Status_STATUS_CODE_ERROR || c.Status.GetMessage() != "tx not found" {
+		t.Errorf("status %+v, want error tx not found", c.Status)
+	}
+	if len(p.Attributes) != 1 || p.Attributes[0].Key != "http.status_code" || p.Attributes[0].Value.GetIntValue() != 400 {
+		t.Errorf("attributes %+v, want http.status_code 400", p.Attributes)
+	}
+	var service string
+	for _, a := range collector.resource {
+		if a.Key == "service.name" {
+			service = a.Value.GetStringValue()
+		}
+	}
+	if service != "blockbook-test" {
+		t.Errorf("resource %+v, want service.name blockbook-test", collector.resource)
+	}
+}
+
+func TestRemoteParentSampling(t *testing.T) {
+	collector := newTestCollector(t)
+	defer collector.Close()
+	if err := Init(Config{Endpoint: collector.URL + "/v1/traces", ServiceName: "blockbook-test", SampleRatio: 0.25}); err != nil {
+		t.Fatal(err)
+	}
+	defer Shutdown(context.Background())
+
+	tests := []struct {
+		name        string
+		traceparent string
+		want        bool
+	}{
+		// the local sampler samples the traces by their id, the sampled flag of the remote parent is ignored
+		{"sampled remote parent outside of ratio", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"not sampled remote parent within ratio", "00-0000000000000001a3ce929d0e0e4736-00f067aa0ba902b7-00", true},
+		{"sampled remote parent within ratio", "00-0000000000000001a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			h.Set("traceparent", tt.traceparent)
+			ctx, span := Start(Extract(context.Background(), h), "http apiTx", KindServer)
+			if got := span.span.SpanContext().IsSampled(); got != tt.want {
+				t.Errorf("sampled = %v, want %v", got, tt.want)
+			}
+			_, child := StartChild(ctx, "rpc.GetTransaction", KindClient)
+			if got := child.span.SpanContext().IsSampled(); got != tt.want {
+				t.Errorf("child sampled = %v, want %v", got, tt.want)
+			}
+			child.End()
+			span.End()
+		})
+	}
+}